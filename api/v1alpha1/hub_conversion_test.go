@@ -0,0 +1,134 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha1
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"sigs.k8s.io/randfill"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
+)
+
+const fuzzIterations = 100
+
+func newFiller(t *testing.T) *randfill.Filler {
+	scheme := runtime.NewScheme()
+	require.NoError(t, AddToScheme(scheme))
+	require.NoError(t, v1alpha3.AddToScheme(scheme))
+	return fuzzer.FuzzerFor(metafuzzer.Funcs, rand.NewSource(rand.Int63()), //nolint:gosec // not used for security
+		serializer.NewCodecFactory(scheme)).NilChance(0.2)
+}
+
+// The hub version (v1alpha3) should not lose anything on top of what is lost when converting to v1alpha2
+func TestPaasHubRoundTrip(t *testing.T) {
+	filler := newFiller(t)
+	for range fuzzIterations {
+		src := &Paas{}
+		filler.Fill(src)
+
+		hub := &v1alpha3.Paas{}
+		require.NoError(t, src.DeepCopy().ConvertTo(hub))
+		viaHub := &Paas{}
+		require.NoError(t, viaHub.ConvertFrom(hub))
+
+		v2Paas := &v1alpha2.Paas{}
+		require.NoError(t, src.DeepCopy().convertTo(v2Paas))
+		viaV2 := &Paas{}
+		viaV2.convertFrom(v2Paas)
+
+		assert.True(t, equality.Semantic.DeepEqual(viaV2, viaHub), diff.Diff(viaV2, viaHub))
+	}
+}
+
+// Fields which do not exist in v1alpha1 should survive a round trip through v1alpha1
+func TestPaasV1alpha2FieldsRoundTrip(t *testing.T) {
+	hub := &v1alpha3.Paas{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-paas", Annotations: map[string]string{"foo": "bar"}},
+		Spec: v1alpha3.PaasSpec{
+			Capabilities: v1alpha3.PaasCapabilities{"argocd": {Version: "v2"}},
+			Groups: v1alpha3.PaasGroups{
+				"shared":  {Definition: "shared-group"},
+				"regular": {Users: []string{"jdoe"}},
+			},
+		},
+	}
+	v1 := &Paas{}
+	require.NoError(t, v1.ConvertFrom(hub))
+	assert.JSONEq(t, `{"groupDefinitions":{"shared":"shared-group"},"capabilityVersions":{"argocd":"v2"}}`,
+		v1.Annotations[v1alpha3.V1alpha2FieldsAnnotation])
+	assert.NotContains(t, hub.Annotations, v1alpha3.V1alpha2FieldsAnnotation)
+
+	viaV1 := &v1alpha3.Paas{}
+	require.NoError(t, v1.DeepCopy().ConvertTo(viaV1))
+	assert.Equal(t, map[string]string{"foo": "bar"}, viaV1.Annotations)
+	assert.Equal(t, "shared-group", viaV1.Spec.Groups["shared"].Definition)
+	assert.Empty(t, viaV1.Spec.Groups["regular"].Definition)
+	assert.Equal(t, "v2", viaV1.Spec.Capabilities["argocd"].Version)
+
+	// A group or capability which is removed by a v1alpha1 client stays removed
+	delete(v1.Spec.Groups, "shared")
+	delete(v1.Spec.Capabilities, "argocd")
+	viaV1 = &v1alpha3.Paas{}
+	require.NoError(t, v1.DeepCopy().ConvertTo(viaV1))
+	assert.NotContains(t, viaV1.Spec.Groups, "shared")
+	assert.NotContains(t, viaV1.Spec.Capabilities, "argocd")
+
+	v1.Annotations[v1alpha3.V1alpha2FieldsAnnotation] = "invalid"
+	assert.Error(t, v1.ConvertTo(&v1alpha3.Paas{}))
+}
+
+func TestPaasNSHubRoundTrip(t *testing.T) {
+	filler := newFiller(t)
+	for range fuzzIterations {
+		src := &PaasNS{}
+		filler.Fill(src)
+
+		hub := &v1alpha3.PaasNS{}
+		require.NoError(t, src.DeepCopy().ConvertTo(hub))
+		viaHub := &PaasNS{}
+		require.NoError(t, viaHub.ConvertFrom(hub))
+
+		v2PaasNs := &v1alpha2.PaasNS{}
+		src.DeepCopy().convertTo(v2PaasNs)
+		viaV2 := &PaasNS{}
+		viaV2.convertFrom(v2PaasNs)
+
+		assert.True(t, equality.Semantic.DeepEqual(viaV2, viaHub), diff.Diff(viaV2, viaHub))
+	}
+}
+
+func TestPaasConfigHubRoundTrip(t *testing.T) {
+	filler := newFiller(t)
+	for range fuzzIterations {
+		src := &PaasConfig{}
+		filler.Fill(src)
+
+		hub := &v1alpha3.PaasConfig{}
+		require.NoError(t, src.DeepCopy().ConvertTo(hub))
+		viaHub := &PaasConfig{}
+		require.NoError(t, viaHub.ConvertFrom(hub))
+
+		v2Config := &v1alpha2.PaasConfig{}
+		src.DeepCopy().convertTo(v2Config)
+		viaV2 := &PaasConfig{}
+		viaV2.convertFrom(v2Config)
+
+		assert.True(t, equality.Semantic.DeepEqual(viaV2, viaHub), diff.Diff(viaV2, viaHub))
+	}
+}
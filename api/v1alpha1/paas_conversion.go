@@ -8,11 +8,15 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
 	"sort"
 
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
 	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
@@ -22,17 +26,97 @@ const (
 	gitPathKey     = "git_path"
 )
 
-// ConvertFrom converts the Hub version (v1alpha2) to this Paas (v1alpha1).
-func (p *Paas) ConvertFrom(srcRaw conversion.Hub) error {
-	src, ok := srcRaw.(*v1alpha2.Paas)
-	if !ok {
-		return fmt.Errorf("cannot convert to v1alpha1: got %T", srcRaw)
+// v1alpha2Fields holds the fields of a v1alpha2 Paas which do not exist in v1alpha1. They are stored (as json) in the
+// v1alpha3.V1alpha2FieldsAnnotation annotation of a v1alpha1 Paas, so that a v1alpha1 client which updates a Paas
+// does not drop them.
+type v1alpha2Fields struct {
+	// GroupDefinitions holds the definition of groups by group key
+	GroupDefinitions map[string]string `json:"groupDefinitions,omitempty"`
+	// CapabilityVersions holds the version of capabilities by capability name
+	CapabilityVersions map[string]string `json:"capabilityVersions,omitempty"`
+}
+
+// setV1alpha2Fields stores the v1alpha2 fields of a Paas which do not exist in v1alpha1 in an annotation of meta
+func setV1alpha2Fields(meta *metav1.ObjectMeta, src v1alpha2.PaasSpec) {
+	var fields v1alpha2Fields
+	for key, group := range src.Groups {
+		if group.Definition != "" {
+			if fields.GroupDefinitions == nil {
+				fields.GroupDefinitions = map[string]string{}
+			}
+			fields.GroupDefinitions[key] = group.Definition
+		}
+	}
+	for name, capability := range src.Capabilities {
+		if capability.Version != "" {
+			if fields.CapabilityVersions == nil {
+				fields.CapabilityVersions = map[string]string{}
+			}
+			fields.CapabilityVersions[name] = capability.Version
+		}
+	}
+	if fields.GroupDefinitions == nil && fields.CapabilityVersions == nil {
+		return
+	}
+	// Marshalling maps of strings cannot fail
+	data, _ := json.Marshal(fields)
+	meta.Annotations = maps.Clone(meta.Annotations)
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
 	}
+	meta.Annotations[v1alpha3.V1alpha2FieldsAnnotation] = string(data)
+}
 
+// popV1alpha2Fields restores the v1alpha2 fields of a Paas which do not exist in v1alpha1 from an annotation of meta,
+// and removes the annotation. Fields of groups and capabilities which were removed by a v1alpha1 client are dropped.
+func popV1alpha2Fields(meta *metav1.ObjectMeta, dst *v1alpha2.PaasSpec) error {
+	data, exists := meta.Annotations[v1alpha3.V1alpha2FieldsAnnotation]
+	if !exists {
+		return nil
+	}
+	meta.Annotations = maps.Clone(meta.Annotations)
+	delete(meta.Annotations, v1alpha3.V1alpha2FieldsAnnotation)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+	var fields v1alpha2Fields
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return fmt.Errorf("failed to restore v1alpha2 fields from %s: %w", v1alpha3.V1alpha2FieldsAnnotation, err)
+	}
+	for key, definition := range fields.GroupDefinitions {
+		if group, groupExists := dst.Groups[key]; groupExists {
+			group.Definition = definition
+			dst.Groups[key] = group
+		}
+	}
+	for name, version := range fields.CapabilityVersions {
+		if capability, capExists := dst.Capabilities[name]; capExists {
+			capability.Version = version
+			dst.Capabilities[name] = capability
+		}
+	}
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1alpha3) to this Paas (v1alpha1), through v1alpha2.
+func (p *Paas) ConvertFrom(srcRaw conversion.Hub) error {
 	_, logger := logging.GetLogComponent(context.TODO(), logging.ApiComponent)
-	logger.Debug().Msg("Starting conversion from hub (v1alpha2) to spoke (v1alpha1)")
+	logger.Debug().Msg("Starting conversion from hub (v1alpha3) to spoke (v1alpha1)")
+
+	var src v1alpha2.Paas
+	if err := src.ConvertFrom(srcRaw); err != nil {
+		return fmt.Errorf("cannot convert to v1alpha1: %w", err)
+	}
+	p.convertFrom(&src)
 
+	return nil
+}
+
+// convertFrom converts a v1alpha2 Paas to this Paas (v1alpha1). Fields which do not exist in v1alpha1 are stored in
+// an annotation.
+func (p *Paas) convertFrom(src *v1alpha2.Paas) {
 	p.ObjectMeta = src.ObjectMeta
+	setV1alpha2Fields(&p.ObjectMeta, src.Spec)
 	p.Status.Conditions = src.Status.Conditions
 	p.Spec.Requestor = src.Spec.Requestor
 	p.Spec.Quota = src.Spec.Quota
@@ -43,7 +127,7 @@ func (p *Paas) ConvertFrom(srcRaw conversion.Hub) error {
 	p.Spec.ManagedByPaas = src.Spec.ManagedByPaas
 
 	for name, capability := range src.Spec.Capabilities {
-		fields := capability.DeepCopy().CustomFields
+		fields := capability.CustomFields.AsStrings()
 		gitUrl := fields[gitUrlKey]
 		gitRevision := fields[gitRevisionKey]
 		gitPath := fields[gitPathKey]
@@ -72,20 +156,27 @@ func (p *Paas) ConvertFrom(srcRaw conversion.Hub) error {
 		p.Spec.Namespaces = append(p.Spec.Namespaces, name)
 	}
 	sort.Strings(p.Spec.Namespaces)
-
-	return nil
 }
 
-// ConvertTo converts this Paas (v1alpha1) to the Hub version (v1alpha2).
+// ConvertTo converts this Paas (v1alpha1) to the Hub version (v1alpha3), through v1alpha2.
 func (p *Paas) ConvertTo(dstRaw conversion.Hub) error {
-	dst, ok := dstRaw.(*v1alpha2.Paas)
-	if !ok {
-		return fmt.Errorf("cannot convert from v1alpha1: got %T", dstRaw)
+	_, logger := logging.GetLogComponent(context.TODO(), logging.ApiComponent)
+	logger.Debug().Msg("Starting conversion from spoke (v1alpha1) to hub (v1alpha3)")
+
+	var dst v1alpha2.Paas
+	if err := p.convertTo(&dst); err != nil {
+		return fmt.Errorf("cannot convert from v1alpha1: %w", err)
+	}
+	if err := dst.ConvertTo(dstRaw); err != nil {
+		return fmt.Errorf("cannot convert from v1alpha1: %w", err)
 	}
 
-	_, logger := logging.GetLogComponent(context.TODO(), logging.ApiComponent)
-	logger.Debug().Msg("Starting conversion from spoke (v1alpha1) to hub (v1alpha2)")
+	return nil
+}
 
+// convertTo converts this Paas (v1alpha1) to a v1alpha2 Paas. Fields which do not exist in v1alpha1 are restored from
+// an annotation.
+func (p *Paas) convertTo(dst *v1alpha2.Paas) error {
 	dst.ObjectMeta = p.ObjectMeta
 	dst.Status.Conditions = p.Status.Conditions
 	dst.Spec.Requestor = p.Spec.Requestor
@@ -113,7 +204,7 @@ func (p *Paas) ConvertTo(dstRaw conversion.Hub) error {
 			}
 
 			dst.Spec.Capabilities[name] = v1alpha2.PaasCapability{
-				CustomFields:     fields,
+				CustomFields:     v1alpha2.PaasCustomFieldsFromStrings(fields),
 				Quota:            capability.Quota.DeepCopy(),
 				Secrets:          capability.SSHSecrets,
 				ExtraPermissions: capability.ExtraPermissions,
@@ -132,6 +223,5 @@ func (p *Paas) ConvertTo(dstRaw conversion.Hub) error {
 	for _, name := range p.Spec.Namespaces {
 		dst.Spec.Namespaces[name] = v1alpha2.PaasNamespace{}
 	}
-
-	return nil
+	return popV1alpha2Fields(&dst.ObjectMeta, &dst.Spec)
 }
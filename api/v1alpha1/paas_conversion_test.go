@@ -12,6 +12,7 @@ import (
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v3/pkg/quota"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -90,12 +91,12 @@ var exV1Alpha2 = &v1alpha2.Paas{
 		},
 		Capabilities: v1alpha2.PaasCapabilities{
 			"argocd": {
-				CustomFields: map[string]string{
+				CustomFields: v1alpha2.PaasCustomFieldsFromStrings(map[string]string{
 					"field1":       "value",
 					"git_url":      "ssh://git@example.com/some-repo.git",
 					"git_revision": "main",
 					"git_path":     ".",
-				},
+				}),
 				Quota: quota.Quota{
 					corev1.ResourceRequestsCPU: resource.MustParse("250m"),
 				},
@@ -137,14 +138,13 @@ func TestConvertTo(t *testing.T) {
 	src := exV1Alpha2.DeepCopy()
 	dst := &Paas{}
 
-	err := dst.ConvertFrom(src)
+	dst.convertFrom(src)
 
 	expectedV1Alpha1 := exV1Alpha1.DeepCopy()
 	expectedFields := expectedV1Alpha1.Spec.Capabilities["argocd"].CustomFields
 	expectedFields["git_url"] = "ssh://git@example.com/some-repo.git"
 	expectedFields["git_revision"] = "main"
 	expectedFields["git_path"] = "."
-	assert.NoError(t, err)
 	assert.Equal(t, expectedV1Alpha1, dst)
 }
 
@@ -153,8 +153,7 @@ func TestConvertFrom(t *testing.T) {
 	src := exV1Alpha1.DeepCopy()
 	dst := &v1alpha2.Paas{}
 
-	err := src.ConvertTo(dst)
+	require.NoError(t, src.convertTo(dst))
 
-	assert.NoError(t, err)
 	assert.Equal(t, exV1Alpha2, dst)
 }
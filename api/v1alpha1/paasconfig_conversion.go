@@ -18,16 +18,22 @@ import (
 
 // ------ ConvertFrom
 
-// ConvertFrom converts from the Hub version (v1alpha2) to this version (v1alpha1).
+// ConvertFrom converts from the Hub version (v1alpha3) to this version (v1alpha1), through v1alpha2.
 func (pc *PaasConfig) ConvertFrom(srcRaw conversion.Hub) error {
-	src, ok := srcRaw.(*v1alpha2.PaasConfig)
-	if !ok {
-		return fmt.Errorf("cannot convert to v1alpha1: got %T", srcRaw)
+	_, logger := logging.GetLogComponent(context.TODO(), logging.ApiComponent)
+	logger.Debug().Msg("Starting conversion from hub (v1alpha3) to spoke (v1alpha1)")
+
+	var src v1alpha2.PaasConfig
+	if err := src.ConvertFrom(srcRaw); err != nil {
+		return fmt.Errorf("cannot convert to v1alpha1: %w", err)
 	}
+	pc.convertFrom(&src)
 
-	_, logger := logging.GetLogComponent(context.TODO(), logging.ApiComponent)
-	logger.Debug().Msg("Starting conversion from hub (v1alpha2) to spoke (v1alpha1)")
+	return nil
+}
 
+// convertFrom converts a v1alpha2 PaasConfig to this version (v1alpha1).
+func (pc *PaasConfig) convertFrom(src *v1alpha2.PaasConfig) {
 	pc.ObjectMeta = src.ObjectMeta
 	pc.Status.Conditions = src.Status.Conditions
 	pc.Spec = PaasConfigSpec{}
@@ -56,8 +62,6 @@ func (pc *PaasConfig) ConvertFrom(srcRaw conversion.Hub) error {
 	spec.RoleMappings = convertFromRoleMappings(src.Spec.RoleMappings)
 	spec.Validations = convertFromValidations(src.Spec.Validations)
 	spec.ComponentsDebug = src.Spec.ComponentsDebug
-
-	return nil
 }
 
 // convertFromNamespacedName converts a v1alpha2 NamespacedName into a v1alpha1 NamespacedName.
@@ -148,16 +152,22 @@ func convertFromValidations(pcv v1alpha2.PaasConfigValidations) PaasConfigValida
 
 // ---------- convertTo
 
-// ConvertTo converts this PaasConfig (v1alpha1) to the Hub version (v1alpha2).
+// ConvertTo converts this PaasConfig (v1alpha1) to the Hub version (v1alpha3), through v1alpha2.
 func (pc *PaasConfig) ConvertTo(dstRaw conversion.Hub) error {
-	dst, ok := dstRaw.(*v1alpha2.PaasConfig)
-	if !ok {
-		return fmt.Errorf("cannot convert from %T: expected PaasConfig", dstRaw)
+	_, logger := logging.GetLogComponent(context.TODO(), logging.ApiComponent)
+	logger.Debug().Msg("Starting conversion from spoke (v1alpha1) to hub (v1alpha3)")
+
+	var dst v1alpha2.PaasConfig
+	pc.convertTo(&dst)
+	if err := dst.ConvertTo(dstRaw); err != nil {
+		return fmt.Errorf("cannot convert from v1alpha1: %w", err)
 	}
 
-	_, logger := logging.GetLogComponent(context.TODO(), logging.ApiComponent)
-	logger.Debug().Msg("Starting conversion from spoke (v1alpha1) to hub (v1alpha2)")
+	return nil
+}
 
+// convertTo converts this PaasConfig (v1alpha1) to a v1alpha2 PaasConfig.
+func (pc *PaasConfig) convertTo(dst *v1alpha2.PaasConfig) {
 	dst.ObjectMeta = pc.ObjectMeta
 	dst.Status.Conditions = pc.Status.Conditions
 	dst.Spec = v1alpha2.PaasConfigSpec{}
@@ -179,8 +189,6 @@ func (pc *PaasConfig) ConvertTo(dstRaw conversion.Hub) error {
 	spec.RoleMappings = ConfigRoleMappings{}.convertTo(pc.Spec.RoleMappings)
 	spec.Validations = PaasConfigValidations{}.convertTo(pc.Spec.Validations)
 	spec.ComponentsDebug = pc.Spec.ComponentsDebug
-
-	return nil
 }
 
 // convertTo converts the given NamespacedName to v1alpha2.NamespacedName
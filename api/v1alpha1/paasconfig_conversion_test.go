@@ -139,9 +139,8 @@ func TestConvertPaasConfigToV1alpha2Fromv1alpha1(t *testing.T) {
 	src := paasconfigExV1Alpha2.DeepCopy()
 	dst := &PaasConfig{}
 
-	err := dst.ConvertFrom(src)
+	dst.convertFrom(src)
 
-	assert.NoError(t, err)
 	assert.Equal(t, paasconfigExV1Alpha1, dst)
 }
 
@@ -149,8 +148,7 @@ func TestConvertPaasConfigFromV1alpha1ToV1alpha2(t *testing.T) {
 	src := paasconfigExV1Alpha1.DeepCopy()
 	dst := &v1alpha2.PaasConfig{}
 
-	err := src.ConvertTo(dst)
+	src.convertTo(dst)
 
-	assert.NoError(t, err)
 	assert.Equal(t, paasconfigExV1Alpha2, dst)
 }
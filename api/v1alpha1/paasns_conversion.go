@@ -15,39 +15,47 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
-// ConvertFrom converts the Hub version (v1alpha2) to this Paas (v1alpha1).
+// ConvertFrom converts the Hub version (v1alpha3) to this PaasNS (v1alpha1), through v1alpha2.
 func (p *PaasNS) ConvertFrom(srcRaw conversion.Hub) error {
-	src, ok := srcRaw.(*v1alpha2.PaasNS)
-	if !ok {
-		return fmt.Errorf("cannot convert to v1alpha1: got %T", srcRaw)
+	_, logger := logging.GetLogComponent(context.TODO(), logging.ApiComponent)
+	logger.Debug().Msg("Starting conversion from hub (v1alpha3) to spoke (v1alpha1)")
+
+	var src v1alpha2.PaasNS
+	if err := src.ConvertFrom(srcRaw); err != nil {
+		return fmt.Errorf("cannot convert to v1alpha1: %w", err)
 	}
+	p.convertFrom(&src)
 
-	_, logger := logging.GetLogComponent(context.TODO(), logging.ApiComponent)
-	logger.Debug().Msg("Starting conversion from hub (v1alpha2) to spoke (v1alpha1)")
+	return nil
+}
 
+// convertFrom converts a v1alpha2 PaasNS to this PaasNS (v1alpha1).
+func (p *PaasNS) convertFrom(src *v1alpha2.PaasNS) {
 	p.ObjectMeta = src.ObjectMeta
 	// Deprecated: not required once paas controller is managing the PaasNS resources.
 	// The `metadata.name` of the Paas which created the namespace in which this PaasNS is applied
 	p.Spec.Paas = ""
 	p.Spec.Groups = src.Spec.Groups
 	p.Spec.SSHSecrets = src.Spec.Secrets
-
-	return nil
 }
 
-// ConvertTo converts this Paas (v1alpha1) to the Hub version (v1alpha2).
+// ConvertTo converts this PaasNS (v1alpha1) to the Hub version (v1alpha3), through v1alpha2.
 func (p *PaasNS) ConvertTo(dstRaw conversion.Hub) error {
-	dst, ok := dstRaw.(*v1alpha2.PaasNS)
-	if !ok {
-		return fmt.Errorf("cannot convert from v1alpha1: got %T", dstRaw)
+	_, logger := logging.GetLogComponent(context.TODO(), logging.ApiComponent)
+	logger.Debug().Msg("Starting conversion from spoke (v1alpha1) to hub (v1alpha3)")
+
+	var dst v1alpha2.PaasNS
+	p.convertTo(&dst)
+	if err := dst.ConvertTo(dstRaw); err != nil {
+		return fmt.Errorf("cannot convert from v1alpha1: %w", err)
 	}
 
-	_, logger := logging.GetLogComponent(context.TODO(), logging.ApiComponent)
-	logger.Debug().Msg("Starting conversion from spoke (v1alpha1) to hub (v1alpha2)")
+	return nil
+}
 
+// convertTo converts this PaasNS (v1alpha1) to a v1alpha2 PaasNS.
+func (p *PaasNS) convertTo(dst *v1alpha2.PaasNS) {
 	dst.ObjectMeta = p.ObjectMeta
 	dst.Spec.Groups = p.Spec.Groups
 	dst.Spec.Secrets = p.Spec.SSHSecrets
-
-	return nil
 }
@@ -43,9 +43,8 @@ func TestConvertPaasNsTo(t *testing.T) {
 	src := paasNsExV1Alpha2.DeepCopy()
 	dst := &PaasNS{}
 
-	err := dst.ConvertFrom(src)
+	dst.convertFrom(src)
 
-	assert.NoError(t, err)
 	assert.Equal(t, paasNsExV1Alpha1, dst)
 }
 
@@ -54,8 +53,7 @@ func TestConvertPaasNsFrom(t *testing.T) {
 	src := paasNsExV1Alpha1.DeepCopy()
 	dst := &v1alpha2.PaasNS{}
 
-	err := src.ConvertTo(dst)
+	src.convertTo(dst)
 
-	assert.NoError(t, err)
 	assert.Equal(t, paasNsExV1Alpha2, dst)
 }
@@ -0,0 +1,86 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha2
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// convertMap converts all values of a map, where a nil map stays nil
+func convertMap[K comparable, S any, D any](src map[K]S, convert func(S) D) map[K]D {
+	if src == nil {
+		return nil
+	}
+	dst := make(map[K]D, len(src))
+	for key, value := range src {
+		dst[key] = convert(value)
+	}
+	return dst
+}
+
+// convertSlice converts all items of a slice, where a nil slice stays nil
+func convertSlice[S any, D any](src []S, convert func(S) D) []D {
+	if src == nil {
+		return nil
+	}
+	dst := make([]D, 0, len(src))
+	for _, item := range src {
+		dst = append(dst, convert(item))
+	}
+	return dst
+}
+
+// setAnnotation sets an annotation which replaces a field which is removed in the hub version, when the field is set
+func setAnnotation(meta *metav1.ObjectMeta, key string, value string) {
+	if value == "" {
+		return
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[key] = value
+}
+
+// popAnnotation returns the value of an annotation which replaces a field of this version in the hub version, and
+// removes the annotation
+func popAnnotation(meta *metav1.ObjectMeta, key string) string {
+	value := meta.Annotations[key]
+	delete(meta.Annotations, key)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+	return value
+}
+
+// setRemovedFields stores the fields which are removed in the hub version (as json) in an annotation, when any of them
+// is set. All fields of removed should be omitted from json when they are empty.
+func setRemovedFields(meta *metav1.ObjectMeta, key string, removed any) error {
+	data, err := json.Marshal(removed)
+	if err != nil {
+		return fmt.Errorf("failed to store removed fields in %s: %w", key, err)
+	} else if string(data) == "{}" {
+		return nil
+	}
+	setAnnotation(meta, key, string(data))
+	return nil
+}
+
+// popRemovedFields restores the fields which are removed in the hub version from an annotation, and removes the
+// annotation
+func popRemovedFields(meta *metav1.ObjectMeta, key string, removed any) error {
+	data := popAnnotation(meta, key)
+	if data == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(data), removed); err != nil {
+		return fmt.Errorf("failed to restore removed fields from %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha2
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	"sigs.k8s.io/randfill"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
+)
+
+const fuzzIterations = 100
+
+// spoke is a v1alpha2 type which can be converted from and to the hub version
+type spoke interface {
+	conversion.Convertible
+	DeepCopyObject() runtime.Object
+}
+
+func newFiller(t *testing.T) *randfill.Filler {
+	scheme := runtime.NewScheme()
+	require.NoError(t, AddToScheme(scheme))
+	require.NoError(t, v1alpha3.AddToScheme(scheme))
+	return fuzzer.FuzzerFor(metafuzzer.Funcs, rand.NewSource(rand.Int63()), //nolint:gosec // not used for security
+		serializer.NewCodecFactory(scheme)).NilChance(0.2)
+}
+
+// fuzzSpokeHubSpoke converts fuzzed v1alpha2 objects to the hub version and back, and verifies nothing is lost
+func fuzzSpokeHubSpoke(t *testing.T, newSpoke func() spoke, newHub func() conversion.Hub) {
+	filler := newFiller(t)
+	for range fuzzIterations {
+		src := newSpoke()
+		filler.Fill(src)
+		hub := newHub()
+		require.NoError(t, src.ConvertTo(hub))
+		dst := newSpoke()
+		require.NoError(t, dst.ConvertFrom(hub))
+		assert.True(t, equality.Semantic.DeepEqual(src, dst), diff.Diff(src, dst))
+	}
+}
+
+// fuzzHubSpokeHub converts fuzzed hub objects to v1alpha2 and back, and verifies nothing is lost
+func fuzzHubSpokeHub(t *testing.T, newSpoke func() spoke, newHub func() conversion.Hub) {
+	filler := newFiller(t)
+	for range fuzzIterations {
+		src := newHub()
+		filler.Fill(src)
+		converted := newSpoke()
+		require.NoError(t, converted.ConvertFrom(src))
+		dst := newHub()
+		require.NoError(t, converted.ConvertTo(dst))
+		assert.True(t, equality.Semantic.DeepEqual(src, dst), diff.Diff(src, dst))
+	}
+}
+
+func TestPaasConversionRoundTrip(t *testing.T) {
+	newSpoke := func() spoke { return &Paas{} }
+	newHub := func() conversion.Hub { return &v1alpha3.Paas{} }
+	fuzzSpokeHubSpoke(t, newSpoke, newHub)
+	fuzzHubSpokeHub(t, newSpoke, newHub)
+}
+
+func TestPaasNSConversionRoundTrip(t *testing.T) {
+	newSpoke := func() spoke { return &PaasNS{} }
+	newHub := func() conversion.Hub { return &v1alpha3.PaasNS{} }
+	fuzzSpokeHubSpoke(t, newSpoke, newHub)
+	fuzzHubSpokeHub(t, newSpoke, newHub)
+}
+
+func TestPaasConfigConversionRoundTrip(t *testing.T) {
+	newSpoke := func() spoke { return &PaasConfig{} }
+	newHub := func() conversion.Hub { return &v1alpha3.PaasConfig{} }
+	fuzzSpokeHubSpoke(t, newSpoke, newHub)
+	fuzzHubSpokeHub(t, newSpoke, newHub)
+}
+
+func TestPaasConvertToAnnotations(t *testing.T) {
+	src := &Paas{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-paas"},
+		Spec:       PaasSpec{Requestor: "my-team", ManagedByPaas: "my-argo-paas"},
+	}
+	dst := &v1alpha3.Paas{}
+
+	require.NoError(t, src.ConvertTo(dst))
+	assert.Equal(t, map[string]string{
+		v1alpha3.RequestorAnnotation:     "my-team",
+		v1alpha3.ManagedByPaasAnnotation: "my-argo-paas",
+	}, dst.Annotations)
+	assert.Nil(t, src.Annotations, "source Paas should not be changed")
+}
+
+func TestPaasConfigConvertToAnnotations(t *testing.T) {
+	src := &PaasConfig{
+		Spec: PaasConfigSpec{
+			ClusterWideArgoCDNamespace: "argocd",
+			Capabilities: ConfigCapabilities{
+				"argocd": ConfigCapability{AppSet: "argocd"},
+			},
+		},
+	}
+	dst := &v1alpha3.PaasConfig{}
+
+	require.NoError(t, src.ConvertTo(dst))
+	assert.JSONEq(t, `{"clusterwide_argocd_namespace":"argocd","applicationsets":{"argocd":"argocd"}}`,
+		dst.Annotations[v1alpha3.V1alpha2FieldsAnnotation])
+}
+
+func TestPaasConfigConvertFromInvalidAnnotation(t *testing.T) {
+	src := &v1alpha3.PaasConfig{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{v1alpha3.V1alpha2FieldsAnnotation: "invalid"}},
+	}
+	dst := &PaasConfig{}
+
+	assert.Error(t, dst.ConvertFrom(src))
+}
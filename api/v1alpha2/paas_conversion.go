@@ -6,5 +6,105 @@ See LICENSE.md for details.
 
 package v1alpha2
 
-// Hub marks this type as a conversion hub.
-func (*Paas) Hub() {}
+import (
+	"context"
+	"fmt"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this Paas (v1alpha2) to the Hub version (v1alpha3).
+// The deprecated requestor and managedByPaas fields are converted to annotations.
+func (p *Paas) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha3.Paas)
+	if !ok {
+		return fmt.Errorf("cannot convert from v1alpha2: got %T", dstRaw)
+	}
+
+	_, logger := logging.GetLogComponent(context.TODO(), logging.ApiComponent)
+	logger.Debug().Msg("Starting conversion from spoke (v1alpha2) to hub (v1alpha3)")
+
+	src := p.WithHubAnnotations()
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha3.PaasSpec{
+		Quota: src.Spec.Quota,
+		Capabilities: convertMap(src.Spec.Capabilities, func(capability PaasCapability) v1alpha3.PaasCapability {
+			return v1alpha3.PaasCapability{
+				CustomFields: convertMap(capability.CustomFields,
+					func(value CustomFieldValue) v1alpha3.CustomFieldValue { return v1alpha3.CustomFieldValue(value) }),
+				Quota:            capability.Quota,
+				Secrets:          capability.Secrets,
+				ExtraPermissions: capability.ExtraPermissions,
+				Version:          capability.Version,
+			}
+		}),
+		Groups: convertMap(src.Spec.Groups,
+			func(group PaasGroup) v1alpha3.PaasGroup { return v1alpha3.PaasGroup(group) }),
+		Namespaces: convertMap(src.Spec.Namespaces,
+			func(ns PaasNamespace) v1alpha3.PaasNamespace { return v1alpha3.PaasNamespace(ns) }),
+		Secrets: src.Spec.Secrets,
+	}
+	dst.Status = v1alpha3.PaasStatus{
+		Conditions: src.Status.Conditions,
+		ExtraResources: convertSlice(src.Status.ExtraResources,
+			func(resource PaasExtraResource) v1alpha3.PaasExtraResource {
+				return v1alpha3.PaasExtraResource(resource)
+			}),
+		PaasConfig: (*v1alpha3.PaasConfigReference)(src.Status.PaasConfig),
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1alpha3) to this Paas (v1alpha2).
+// The requestor and managedByPaas annotations are converted to the deprecated fields.
+func (p *Paas) ConvertFrom(srcRaw conversion.Hub) error {
+	srcHub, ok := srcRaw.(*v1alpha3.Paas)
+	if !ok {
+		return fmt.Errorf("cannot convert to v1alpha2: got %T", srcRaw)
+	}
+
+	_, logger := logging.GetLogComponent(context.TODO(), logging.ApiComponent)
+	logger.Debug().Msg("Starting conversion from hub (v1alpha3) to spoke (v1alpha2)")
+
+	src := srcHub.DeepCopy()
+	p.ObjectMeta = src.ObjectMeta
+	p.Spec = PaasSpec{
+		Requestor: popAnnotation(&p.ObjectMeta, v1alpha3.RequestorAnnotation),
+		Quota:     src.Spec.Quota,
+		Capabilities: convertMap(src.Spec.Capabilities, func(capability v1alpha3.PaasCapability) PaasCapability {
+			return PaasCapability{
+				CustomFields: convertMap(capability.CustomFields,
+					func(value v1alpha3.CustomFieldValue) CustomFieldValue { return CustomFieldValue(value) }),
+				Quota:            capability.Quota,
+				Secrets:          capability.Secrets,
+				ExtraPermissions: capability.ExtraPermissions,
+				Version:          capability.Version,
+			}
+		}),
+		Groups: convertMap(src.Spec.Groups, func(group v1alpha3.PaasGroup) PaasGroup { return PaasGroup(group) }),
+		Namespaces: convertMap(src.Spec.Namespaces,
+			func(ns v1alpha3.PaasNamespace) PaasNamespace { return PaasNamespace(ns) }),
+		Secrets:       src.Spec.Secrets,
+		ManagedByPaas: popAnnotation(&p.ObjectMeta, v1alpha3.ManagedByPaasAnnotation),
+	}
+	p.Status = PaasStatus{
+		Conditions: src.Status.Conditions,
+		ExtraResources: convertSlice(src.Status.ExtraResources,
+			func(resource v1alpha3.PaasExtraResource) PaasExtraResource { return PaasExtraResource(resource) }),
+		PaasConfig: (*PaasConfigReference)(src.Status.PaasConfig),
+	}
+
+	return nil
+}
+
+// WithHubAnnotations returns a copy of this Paas, where the deprecated requestor and managedByPaas fields are also set
+// as the annotations which replace them in v1alpha3. This allows templates to use the annotations for all versions.
+func (p Paas) WithHubAnnotations() Paas {
+	paas := p.DeepCopy()
+	setAnnotation(&paas.ObjectMeta, v1alpha3.RequestorAnnotation, paas.Spec.Requestor)
+	setAnnotation(&paas.ObjectMeta, v1alpha3.ManagedByPaasAnnotation, paas.Spec.ManagedByPaas)
+	return *paas
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func rawCustomFieldValue(raw string) CustomFieldValue {
+	return CustomFieldValue{JSON: apiextensionsv1.JSON{Raw: []byte(raw)}}
+}
+
+func TestCustomFieldValue_String(t *testing.T) {
+	assert.Equal(t, "main", NewCustomFieldValue("main").String())
+	assert.Equal(t, "5", rawCustomFieldValue("5").String())
+	assert.JSONEq(t, `["a","b"]`, rawCustomFieldValue(`["a", "b"]`).String())
+
+	fields := PaasCustomFieldsFromStrings(map[string]string{"git_path": "."})
+	assert.Equal(t, map[string]string{"git_path": "."}, fields.AsStrings())
+	assert.Nil(t, PaasCustomFieldsFromStrings(nil))
+}
+
+func TestPaasCapability_CapExtraFieldsSchema(t *testing.T) {
+	fieldConfig := map[string]ConfigCustomField{
+		"replicas": {Schema: &apiextensionsv1.JSON{Raw: []byte(`{"type": "integer", "maximum": 10, "default": 2}`)}},
+		"git_path": {Default: "."},
+	}
+
+	capability := PaasCapability{CustomFields: PaasCustomFields{"replicas": rawCustomFieldValue("11")}}
+	elements, err := capability.CapExtraFields(fieldConfig)
+	require.NoError(t, err, "values are not validated against their schema")
+	assert.InDelta(t, 11, elements["replicas"], 0, "structured values should be passed as is")
+	assert.Equal(t, ".", elements["git_path"])
+
+	capability.CustomFields["git_path"] = rawCustomFieldValue(`["not", "a", "string"]`)
+	_, err = capability.CapExtraFields(fieldConfig)
+	assert.ErrorContains(t, err, "value of custom field git_path should be a string")
+}
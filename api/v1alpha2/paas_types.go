@@ -7,6 +7,7 @@ See LICENSE.md for details.
 package v1alpha2
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
@@ -15,6 +16,7 @@ import (
 	"github.com/belastingdienst/opr-paas/v3/internal/argocd-plugin-generator/fields"
 	"github.com/belastingdienst/opr-paas/v3/internal/groups"
 	paasquota "github.com/belastingdienst/opr-paas/v3/pkg/quota"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -66,9 +68,10 @@ type PaasSpec struct {
 
 // PaasCapability holds all information for a capability
 type PaasCapability struct {
-	// Custom fields to configure this specific Capability
+	// Custom fields to configure this specific Capability.
+	// Values are strings, unless a schema is configured for the custom field in the PaasConfig.
 	// +kubebuilder:validation:Optional
-	CustomFields map[string]string `json:"custom_fields"`
+	CustomFields PaasCustomFields `json:"custom_fields"`
 	// This project has its own ClusterResourceQuota settings
 	// +kubebuilder:validation:Optional
 	Quota paasquota.Quota `json:"quota"`
@@ -79,6 +82,76 @@ type PaasCapability struct {
 	// Exact definitions is configured in Paas Configmap
 	// +kubebuilder:validation:Optional
 	ExtraPermissions bool `json:"extra_permissions"`
+	// Version of the capability, which should be one of the versions configured in the PaasConfig.
+	// Defaults to the default version configured in the PaasConfig.
+	// +kubebuilder:validation:Optional
+	Version string `json:"version,omitempty"`
+}
+
+// CustomFieldValue holds the value of a custom field of a capability. Values are strings for custom fields without a
+// schema, but can be any JSON value (e.g. a number, a list or an object) for custom fields with a schema.
+// +kubebuilder:validation:Type=""
+// +kubebuilder:pruning:PreserveUnknownFields
+type CustomFieldValue struct {
+	apiextensionsv1.JSON `json:",inline"`
+}
+
+// NewCustomFieldValue returns a CustomFieldValue holding a string
+func NewCustomFieldValue(value string) CustomFieldValue {
+	// Marshalling a string cannot fail
+	raw, _ := json.Marshal(value)
+	return CustomFieldValue{JSON: apiextensionsv1.JSON{Raw: raw}}
+}
+
+// Value returns the value as unmarshalled from JSON (e.g. a string, a float64, a []any or a map[string]any)
+func (cfv CustomFieldValue) Value() (value any, err error) {
+	if len(cfv.Raw) == 0 {
+		return nil, nil
+	}
+	err = json.Unmarshal(cfv.Raw, &value)
+	return value, err
+}
+
+// StringValue returns the value when it is a string, and an error for all other values
+func (cfv CustomFieldValue) StringValue() (value string, err error) {
+	err = json.Unmarshal(cfv.Raw, &value)
+	return value, err
+}
+
+// String returns the value for string values, and the JSON representation of all other values. This also makes
+// go-templates render string values as before custom fields could hold other values.
+func (cfv CustomFieldValue) String() string {
+	if value, err := cfv.StringValue(); err == nil {
+		return value
+	}
+	return string(cfv.Raw)
+}
+
+// PaasCustomFields holds the values of all custom fields of a capability
+type PaasCustomFields map[string]CustomFieldValue
+
+// PaasCustomFieldsFromStrings returns PaasCustomFields holding the strings in values
+func PaasCustomFieldsFromStrings(values map[string]string) PaasCustomFields {
+	if values == nil {
+		return nil
+	}
+	customFields := make(PaasCustomFields, len(values))
+	for key, value := range values {
+		customFields[key] = NewCustomFieldValue(value)
+	}
+	return customFields
+}
+
+// AsStrings returns all custom fields as strings, where values which are not strings are represented as JSON
+func (pcf PaasCustomFields) AsStrings() map[string]string {
+	if pcf == nil {
+		return nil
+	}
+	values := make(map[string]string, len(pcf))
+	for key, value := range pcf {
+		values[key] = value.String()
+	}
+	return values
 }
 
 // Quotas returns the quota for this capability
@@ -86,20 +159,39 @@ func (pc PaasCapability) Quotas() (pq paasquota.Quota) {
 	return pc.Quota
 }
 
-// CapExtraFields returns all extra fields that are configured for a capability
+// CapExtraFields returns all extra fields that are configured for a capability. Values of custom fields with a schema
+// are parsed, but not validated against (or defaulted from) their schema, as that is up to the operator.
 func (pc *PaasCapability) CapExtraFields(
 	fieldConfig map[string]ConfigCustomField,
 ) (elements fields.Elements, err error) {
 	elements = make(fields.Elements)
 	var issues []error
 	for key, value := range pc.CustomFields {
-		if _, exists := fieldConfig[key]; !exists {
+		fieldConf, exists := fieldConfig[key]
+		switch {
+		case !exists:
 			issues = append(issues, fmt.Errorf("custom field %s is not configured in capability config", key))
-		} else {
-			elements[key] = value
+		case fieldConf.Schema != nil:
+			var element any
+			if element, err = value.Value(); err != nil {
+				issues = append(issues, fmt.Errorf("could not parse value of custom field %s: %w", key, err))
+			} else {
+				elements[key] = element
+			}
+		default:
+			var element string
+			if element, err = value.StringValue(); err != nil {
+				issues = append(issues,
+					fmt.Errorf("value of custom field %s should be a string, as it has no schema configured", key))
+			} else {
+				elements[key] = element
+			}
 		}
 	}
 	for key, fieldConf := range fieldConfig {
+		if fieldConf.Schema != nil {
+			continue
+		}
 		var value string
 		if value, err = elements.TryGetElementAsString(key); err == nil {
 			var matched bool
@@ -142,6 +234,11 @@ type PaasGroup struct {
 	// List of roles, as defined in the `PaasConfig` which the users in this group get assigned via a rolebinding.
 	// +kubebuilder:validation:Optional
 	Roles []string `json:"roles"`
+	// Name of a group definition in the `PaasConfig` (`spec.groupDefinitions`) which defines the query or users
+	// for this group. Therefore, this field is mutually exclusive with `group.query` and `group.users`.
+	// Groups referencing the same definition are shared between Paas'es.
+	// +kubebuilder:validation:Optional
+	Definition string `json:"definition,omitempty"`
 }
 
 // PaasGroups hold all groups in a paas.spec.groups
@@ -167,12 +264,35 @@ type PaasStatus struct {
 	// +kubebuilder:validation:Optional
 	//revive:disable-next-line
 	Conditions []metav1.Condition `json:"conditions" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+	// Extra resources (as templated in the PaasConfig) which are applied for this Paas.
+	// The operator uses this list to prune resources which are no longer rendered.
+	// +kubebuilder:validation:Optional
+	ExtraResources []PaasExtraResource `json:"extraResources,omitempty"`
+	// The PaasConfig which was used for the last reconciliation of this Paas
+	// +kubebuilder:validation:Optional
+	PaasConfig *PaasConfigReference `json:"paasConfig,omitempty"`
+}
+
+// PaasConfigReference references a specific generation of a PaasConfig
+type PaasConfigReference struct {
+	Name       string `json:"name"`
+	Generation int64  `json:"generation"`
+	// The PaasConfigRevision which was used (not set for candidate PaasConfigs)
+	// +kubebuilder:validation:Optional
+	Revision string `json:"revision,omitempty"`
+}
+
+// PaasExtraResource references an extra resource which is applied for a Paas
+type PaasExtraResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
-// +kubebuilder:conversion:hub
 // +kubebuilder:resource:path=paas,scope=Cluster
 
 // Paas is the Schema for the paas API
@@ -243,6 +363,8 @@ func (p Paas) WithoutMe(references []metav1.OwnerReference) (withoutMe []metav1.
 func (p Paas) GroupKey2GroupName(groupKey string) string {
 	if group, exists := p.Spec.Groups[groupKey]; !exists {
 		return ""
+	} else if group.Definition != "" {
+		return group.Definition
 	} else if len(group.Query) > 0 {
 		return group.name(groupKey)
 	}
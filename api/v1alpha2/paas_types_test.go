@@ -25,4 +25,25 @@ var _ = Describe("PaasTypes", func() {
 			})
 		})
 	})
+	Describe("GroupKey2GroupName", func() {
+		BeforeEach(func() {
+			paas.Spec.Groups = v1alpha2.PaasGroups{
+				"users":      {Users: []string{"user1"}},
+				"query":      {Query: "CN=ldapgroup,OU=org,DC=example,DC=com"},
+				"definition": {Definition: "shared-team"},
+			}
+		})
+		It("should prefix groups with users with the Paas name", func() {
+			Expect(paas.GroupKey2GroupName("users")).To(Equal(paasName + "-users"))
+		})
+		It("should derive the name of groups with a query from the CN", func() {
+			Expect(paas.GroupKey2GroupName("query")).To(Equal("ldapgroup"))
+		})
+		It("should use the definition name for groups referencing a group definition", func() {
+			Expect(paas.GroupKey2GroupName("definition")).To(Equal("shared-team"))
+		})
+		It("should return an empty string for unknown keys", func() {
+			Expect(paas.GroupKey2GroupName("unknown")).To(BeEmpty())
+		})
+	})
 })
@@ -6,5 +6,271 @@ See LICENSE.md for details.
 
 package v1alpha2
 
-// Hub marks this type as a conversion hub.
-func (*PaasConfig) Hub() {}
+import (
+	"context"
+	"fmt"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// paasConfigRemovedFields holds the fields of a PaasConfig which are removed in v1alpha3
+type paasConfigRemovedFields struct {
+	ClusterWideArgoCDNamespace string `json:"clusterwide_argocd_namespace,omitempty"`
+	RequestorLabel             string `json:"requestor_label,omitempty"`
+	ManagedByLabel             string `json:"managed_by_label,omitempty"`
+	ManagedBySuffix            string `json:"managed_by_suffix,omitempty"`
+	// The applicationset of the capabilities, by the name of the capability
+	AppSets map[string]string `json:"applicationsets,omitempty"`
+}
+
+// ConvertTo converts this PaasConfig (v1alpha2) to the Hub version (v1alpha3).
+// The deprecated fields are kept in the v1alpha2-fields annotation.
+func (pc *PaasConfig) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha3.PaasConfig)
+	if !ok {
+		return fmt.Errorf("cannot convert from v1alpha2: got %T", dstRaw)
+	}
+
+	_, logger := logging.GetLogComponent(context.TODO(), logging.ApiComponent)
+	logger.Debug().Msg("Starting conversion from spoke (v1alpha2) to hub (v1alpha3)")
+
+	src := pc.DeepCopy()
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha3.PaasConfigSpec{
+		DecryptKeysSecret: v1alpha3.NamespacedName(src.Spec.DecryptKeysSecret),
+		Debug:             src.Spec.Debug,
+		ComponentsDebug:   src.Spec.ComponentsDebug,
+		Capabilities:      convertMap(src.Spec.Capabilities, ConfigCapability.convertTo),
+		QuotaLabel:        src.Spec.QuotaLabel,
+		RoleMappings:      v1alpha3.ConfigRoleMappings(src.Spec.RoleMappings),
+		FeatureFlags:      v1alpha3.ConfigFeatureFlags(src.Spec.FeatureFlags),
+		Validations: convertMap(src.Spec.Validations,
+			func(validations PaasConfigTypeValidations) v1alpha3.PaasConfigTypeValidations {
+				return v1alpha3.PaasConfigTypeValidations(validations)
+			}),
+		ValidationRules: convertSlice(src.Spec.ValidationRules,
+			func(rule ConfigValidationRule) v1alpha3.ConfigValidationRule {
+				return v1alpha3.ConfigValidationRule{
+					Resource:    rule.Resource,
+					Rule:        rule.Rule,
+					Message:     rule.Message,
+					Enforcement: v1alpha3.ValidationEnforcement(rule.Enforcement),
+				}
+			}),
+		ValidationEnforcement: convertMap(src.Spec.ValidationEnforcement,
+			func(mode ValidationEnforcement) v1alpha3.ValidationEnforcement {
+				return v1alpha3.ValidationEnforcement(mode)
+			}),
+		GroupDefinitions: convertMap(src.Spec.GroupDefinitions,
+			func(def ConfigGroupDefinition) v1alpha3.ConfigGroupDefinition {
+				return v1alpha3.ConfigGroupDefinition(def)
+			}),
+		Templating: src.Spec.Templating.convertTo(),
+		Revisions:  v1alpha3.ConfigRevisions(src.Spec.Revisions),
+		Candidate:  (*v1alpha3.ConfigCandidate)(src.Spec.Candidate),
+	}
+	dst.Status = v1alpha3.PaasConfigStatus{
+		Conditions: src.Status.Conditions,
+		FailedPaases: convertSlice(src.Status.FailedPaases,
+			func(failed PaasConfigFailedPaas) v1alpha3.PaasConfigFailedPaas {
+				return v1alpha3.PaasConfigFailedPaas(failed)
+			}),
+		Violations: convertSlice(src.Status.Violations,
+			func(violation PaasConfigViolation) v1alpha3.PaasConfigViolation {
+				return v1alpha3.PaasConfigViolation{
+					Kind:        violation.Kind,
+					Name:        violation.Name,
+					Namespace:   violation.Namespace,
+					Validation:  violation.Validation,
+					Enforcement: v1alpha3.ValidationEnforcement(violation.Enforcement),
+					Message:     violation.Message,
+				}
+			}),
+	}
+
+	removed := paasConfigRemovedFields{
+		ClusterWideArgoCDNamespace: src.Spec.ClusterWideArgoCDNamespace,
+		RequestorLabel:             src.Spec.RequestorLabel,
+		ManagedByLabel:             src.Spec.ManagedByLabel,
+		ManagedBySuffix:            src.Spec.ManagedBySuffix,
+	}
+	for name, capability := range src.Spec.Capabilities {
+		if capability.AppSet == "" {
+			continue
+		}
+		if removed.AppSets == nil {
+			removed.AppSets = map[string]string{}
+		}
+		removed.AppSets[name] = capability.AppSet
+	}
+	return setRemovedFields(&dst.ObjectMeta, v1alpha3.V1alpha2FieldsAnnotation, removed)
+}
+
+// convertTo converts a ConfigCapability to the Hub version (v1alpha3), without the deprecated AppSet
+func (cc ConfigCapability) convertTo() v1alpha3.ConfigCapability {
+	return v1alpha3.ConfigCapability{
+		QuotaSettings:      v1alpha3.ConfigQuotaSettings(cc.QuotaSettings),
+		ExtraPermissions:   v1alpha3.ConfigCapPerm(cc.ExtraPermissions),
+		DefaultPermissions: v1alpha3.ConfigCapPerm(cc.DefaultPermissions),
+		CustomFields: convertMap(cc.CustomFields,
+			func(customField ConfigCustomField) v1alpha3.ConfigCustomField {
+				return v1alpha3.ConfigCustomField(customField)
+			}),
+		Requires:      cc.Requires,
+		ConflictsWith: cc.ConflictsWith,
+		Versions: convertMap(cc.Versions, func(version ConfigCapabilityVersion) v1alpha3.ConfigCapabilityVersion {
+			return v1alpha3.ConfigCapabilityVersion{
+				Deprecation: (*v1alpha3.ConfigCapabilityDeprecation)(version.Deprecation),
+			}
+		}),
+		DefaultVersion: cc.DefaultVersion,
+	}
+}
+
+// convertTo converts ConfigTemplatingItems to the Hub version (v1alpha3)
+func (cti ConfigTemplatingItems) convertTo() v1alpha3.ConfigTemplatingItems {
+	return v1alpha3.ConfigTemplatingItems{
+		GenericCapabilityFields: v1alpha3.ConfigTemplatingItem(cti.GenericCapabilityFields),
+		PaasLabels:              v1alpha3.ConfigTemplatingItem(cti.PaasLabels),
+		ClusterQuotaLabels:      v1alpha3.ConfigTemplatingItem(cti.ClusterQuotaLabels),
+		GroupLabels:             v1alpha3.ConfigTemplatingItem(cti.GroupLabels),
+		NamespaceLabels:         v1alpha3.ConfigTemplatingItem(cti.NamespaceLabels),
+		RoleBindingLabels:       v1alpha3.ConfigTemplatingItem(cti.RoleBindingLabels),
+		ClusterQuotaAnnotations: v1alpha3.ConfigTemplatingItem(cti.ClusterQuotaAnnotations),
+		GroupAnnotations:        v1alpha3.ConfigTemplatingItem(cti.GroupAnnotations),
+		NamespaceAnnotations:    v1alpha3.ConfigTemplatingItem(cti.NamespaceAnnotations),
+		RoleBindingAnnotations:  v1alpha3.ConfigTemplatingItem(cti.RoleBindingAnnotations),
+		SecretLabels:            v1alpha3.ConfigTemplatingItem(cti.SecretLabels),
+		SecretAnnotations:       v1alpha3.ConfigTemplatingItem(cti.SecretAnnotations),
+		NetworkPolicies:         v1alpha3.ConfigTemplatingItem(cti.NetworkPolicies),
+		ExtraResources: convertMap(cti.ExtraResources, func(resource ConfigExtraResource) v1alpha3.ConfigExtraResource {
+			return v1alpha3.ConfigExtraResource{
+				Scope:    v1alpha3.ConfigExtraResourceScope(resource.Scope),
+				Template: resource.Template,
+			}
+		}),
+	}
+}
+
+// ConvertFrom converts the Hub version (v1alpha3) to this PaasConfig (v1alpha2).
+// The deprecated fields are restored from the v1alpha2-fields annotation.
+func (pc *PaasConfig) ConvertFrom(srcRaw conversion.Hub) error {
+	srcHub, ok := srcRaw.(*v1alpha3.PaasConfig)
+	if !ok {
+		return fmt.Errorf("cannot convert to v1alpha2: got %T", srcRaw)
+	}
+
+	_, logger := logging.GetLogComponent(context.TODO(), logging.ApiComponent)
+	logger.Debug().Msg("Starting conversion from hub (v1alpha3) to spoke (v1alpha2)")
+
+	src := srcHub.DeepCopy()
+	pc.ObjectMeta = src.ObjectMeta
+	var removed paasConfigRemovedFields
+	if err := popRemovedFields(&pc.ObjectMeta, v1alpha3.V1alpha2FieldsAnnotation, &removed); err != nil {
+		return err
+	}
+	pc.Spec = PaasConfigSpec{
+		DecryptKeysSecret:          NamespacedName(src.Spec.DecryptKeysSecret),
+		Debug:                      src.Spec.Debug,
+		ComponentsDebug:            src.Spec.ComponentsDebug,
+		Capabilities:               convertMap(src.Spec.Capabilities, convertFromConfigCapability),
+		ClusterWideArgoCDNamespace: removed.ClusterWideArgoCDNamespace,
+		QuotaLabel:                 src.Spec.QuotaLabel,
+		RequestorLabel:             removed.RequestorLabel,
+		ManagedByLabel:             removed.ManagedByLabel,
+		ManagedBySuffix:            removed.ManagedBySuffix,
+		RoleMappings:               ConfigRoleMappings(src.Spec.RoleMappings),
+		FeatureFlags:               ConfigFeatureFlags(src.Spec.FeatureFlags),
+		Validations: convertMap(src.Spec.Validations,
+			func(validations v1alpha3.PaasConfigTypeValidations) PaasConfigTypeValidations {
+				return PaasConfigTypeValidations(validations)
+			}),
+		ValidationRules: convertSlice(src.Spec.ValidationRules,
+			func(rule v1alpha3.ConfigValidationRule) ConfigValidationRule {
+				return ConfigValidationRule{
+					Resource:    rule.Resource,
+					Rule:        rule.Rule,
+					Message:     rule.Message,
+					Enforcement: ValidationEnforcement(rule.Enforcement),
+				}
+			}),
+		ValidationEnforcement: convertMap(src.Spec.ValidationEnforcement,
+			func(mode v1alpha3.ValidationEnforcement) ValidationEnforcement { return ValidationEnforcement(mode) }),
+		GroupDefinitions: convertMap(src.Spec.GroupDefinitions,
+			func(def v1alpha3.ConfigGroupDefinition) ConfigGroupDefinition { return ConfigGroupDefinition(def) }),
+		Templating: convertFromTemplatingItems(src.Spec.Templating),
+		Revisions:  ConfigRevisions(src.Spec.Revisions),
+		Candidate:  (*ConfigCandidate)(src.Spec.Candidate),
+	}
+	for name, appSet := range removed.AppSets {
+		// Capabilities which were removed in the hub version lose their AppSet
+		if capability, exists := pc.Spec.Capabilities[name]; exists {
+			capability.AppSet = appSet
+			pc.Spec.Capabilities[name] = capability
+		}
+	}
+	pc.Status = PaasConfigStatus{
+		Conditions: src.Status.Conditions,
+		FailedPaases: convertSlice(src.Status.FailedPaases,
+			func(failed v1alpha3.PaasConfigFailedPaas) PaasConfigFailedPaas { return PaasConfigFailedPaas(failed) }),
+		Violations: convertSlice(src.Status.Violations,
+			func(violation v1alpha3.PaasConfigViolation) PaasConfigViolation {
+				return PaasConfigViolation{
+					Kind:        violation.Kind,
+					Name:        violation.Name,
+					Namespace:   violation.Namespace,
+					Validation:  violation.Validation,
+					Enforcement: ValidationEnforcement(violation.Enforcement),
+					Message:     violation.Message,
+				}
+			}),
+	}
+
+	return nil
+}
+
+// convertFromConfigCapability converts a v1alpha3.ConfigCapability to a ConfigCapability (without AppSet)
+func convertFromConfigCapability(cc v1alpha3.ConfigCapability) ConfigCapability {
+	return ConfigCapability{
+		QuotaSettings:      ConfigQuotaSettings(cc.QuotaSettings),
+		ExtraPermissions:   ConfigCapPerm(cc.ExtraPermissions),
+		DefaultPermissions: ConfigCapPerm(cc.DefaultPermissions),
+		CustomFields: convertMap(cc.CustomFields,
+			func(customField v1alpha3.ConfigCustomField) ConfigCustomField { return ConfigCustomField(customField) }),
+		Requires:      cc.Requires,
+		ConflictsWith: cc.ConflictsWith,
+		Versions: convertMap(cc.Versions, func(version v1alpha3.ConfigCapabilityVersion) ConfigCapabilityVersion {
+			return ConfigCapabilityVersion{
+				Deprecation: (*ConfigCapabilityDeprecation)(version.Deprecation),
+			}
+		}),
+		DefaultVersion: cc.DefaultVersion,
+	}
+}
+
+// convertFromTemplatingItems converts v1alpha3.ConfigTemplatingItems to ConfigTemplatingItems
+func convertFromTemplatingItems(cti v1alpha3.ConfigTemplatingItems) ConfigTemplatingItems {
+	return ConfigTemplatingItems{
+		GenericCapabilityFields: ConfigTemplatingItem(cti.GenericCapabilityFields),
+		PaasLabels:              ConfigTemplatingItem(cti.PaasLabels),
+		ClusterQuotaLabels:      ConfigTemplatingItem(cti.ClusterQuotaLabels),
+		GroupLabels:             ConfigTemplatingItem(cti.GroupLabels),
+		NamespaceLabels:         ConfigTemplatingItem(cti.NamespaceLabels),
+		RoleBindingLabels:       ConfigTemplatingItem(cti.RoleBindingLabels),
+		ClusterQuotaAnnotations: ConfigTemplatingItem(cti.ClusterQuotaAnnotations),
+		GroupAnnotations:        ConfigTemplatingItem(cti.GroupAnnotations),
+		NamespaceAnnotations:    ConfigTemplatingItem(cti.NamespaceAnnotations),
+		RoleBindingAnnotations:  ConfigTemplatingItem(cti.RoleBindingAnnotations),
+		SecretLabels:            ConfigTemplatingItem(cti.SecretLabels),
+		SecretAnnotations:       ConfigTemplatingItem(cti.SecretAnnotations),
+		NetworkPolicies:         ConfigTemplatingItem(cti.NetworkPolicies),
+		ExtraResources: convertMap(cti.ExtraResources, func(resource v1alpha3.ConfigExtraResource) ConfigExtraResource {
+			return ConfigExtraResource{
+				Scope:    ConfigExtraResourceScope(resource.Scope),
+				Template: resource.Template,
+			}
+		}),
+	}
+}
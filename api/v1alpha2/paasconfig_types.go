@@ -10,10 +10,14 @@ See LICENSE.md for details.
 package v1alpha2
 
 import (
+	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/belastingdienst/opr-paas/v3/api"
 	paasquota "github.com/belastingdienst/opr-paas/v3/pkg/quota"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -32,12 +36,34 @@ const (
 	// TypeDegradedPaasConfig represents the status used when the custom resource is deleted
 	// and the finalizer operations are yet to occur.
 	TypeDegradedPaasConfig = "Degraded"
+	// TypeCandidatePaasConfig represents whether this candidate PaasConfig is being rolled out to canary Paas'es
+	TypeCandidatePaasConfig = "Candidate"
+	// TypeAuditedPaasConfig represents the outcome of the last audit of existing Paas'es and PaasNSs against the
+	// validations in warn or block-on-create-only mode
+	TypeAuditedPaasConfig = "Audited"
+)
+
+// Reasons of the Candidate condition of a candidate PaasConfig
+const (
+	// CandidateReasonSoaking means that the candidate is applied to canary Paas'es and is soaking
+	CandidateReasonSoaking = "Soaking"
+	// CandidateReasonPromoted means that the candidate was promoted to the active PaasConfig
+	CandidateReasonPromoted = "Promoted"
+	// CandidateReasonRejected means that the candidate was rejected, because canary Paas'es failed
+	CandidateReasonRejected = "Rejected"
+)
+
+// Reasons of the Audited condition of the active PaasConfig
+const (
+	// AuditReasonCompliant means that all existing Paas'es and PaasNSs meet the audited validations
+	AuditReasonCompliant = "Compliant"
+	// AuditReasonViolations means that existing Paas'es or PaasNSs do not meet audited validations
+	AuditReasonViolations = "Violations"
 )
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
-// +kubebuilder:conversion:hub
 // +kubebuilder:resource:path=paasconfig,scope=Cluster
 type PaasConfig struct {
 	metav1.TypeMeta   `json:""`
@@ -120,16 +146,70 @@ type PaasConfigSpec struct {
 	// +kubebuilder:validation:Optional
 	Validations PaasConfigValidations `json:"validations"`
 
+	// CEL rules to have the webhooks validate Paas'es and PaasNSs, for validations spanning multiple fields
+	// +kubebuilder:validation:Optional
+	ValidationRules ConfigValidationRules `json:"validationRules,omitempty"`
+
+	// The enforcement mode (warn, block or block-on-create-only) of validations, by the name of the validation (e.g.
+	// paas.groupName). Validations which are not listed are blocking.
+	// +kubebuilder:validation:Optional
+	ValidationEnforcement ConfigValidationEnforcement `json:"validationEnforcement,omitempty"`
+
+	// Reusable group definitions which can be referenced by name from the groups in a Paas.
+	// Groups with users are managed once by the operator and shared between all Paas'es referencing them.
+	// +kubebuilder:validation:Optional
+	GroupDefinitions ConfigGroupDefinitions `json:"groupDefinitions,omitempty"`
+
 	// With templating Administrators can define labels and generic custom fields to be applied on sub resources
 	// +kubebuilder:validation:Optional
 	Templating ConfigTemplatingItems `json:"templating,omitempty"`
+
+	// Settings for the PaasConfigRevisions which are kept for every activated PaasConfig spec
+	// +kubebuilder:validation:Optional
+	Revisions ConfigRevisions `json:"revisions,omitempty"`
+
+	// Candidate marks this PaasConfig as a candidate to replace the active PaasConfig. A candidate is first applied
+	// to canary Paas'es only, and is promoted to the active PaasConfig when all canary Paas'es are Ready after the
+	// soak period.
+	// +kubebuilder:validation:Optional
+	Candidate *ConfigCandidate `json:"candidate,omitempty"`
+}
+
+// ConfigRevisions defines how PaasConfigRevisions are kept, and for how long Paas'es can be pinned to one
+type ConfigRevisions struct {
+	// The number of PaasConfigRevisions to keep. Older revisions are deleted, unless a Paas is pinned to them.
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	HistoryLimit int32 `json:"historyLimit,omitempty"`
+
+	// The maximum time a Paas can be pinned to a PaasConfigRevision (counting from now)
+	// +kubebuilder:default:="168h"
+	// +kubebuilder:validation:Optional
+	MaxPinDuration metav1.Duration `json:"maxPinDuration,omitempty"`
+}
+
+// ConfigCandidate defines how a candidate PaasConfig is rolled out
+type ConfigCandidate struct {
+	// CanarySelector selects the Paas'es to which the candidate PaasConfig is applied first
+	// +kubebuilder:validation:Required
+	CanarySelector metav1.LabelSelector `json:"canarySelector"`
+
+	// SoakPeriod is the time all canary Paas'es should be Ready with the candidate PaasConfig, before the candidate
+	// is promoted to the active PaasConfig
+	// +kubebuilder:default:="1h"
+	// +kubebuilder:validation:Optional
+	SoakPeriod metav1.Duration `json:"soakPeriod,omitempty"`
 }
 
 type ConfigRoleMappings map[string][]string
 
+// DefaultRoleMapping is the role mapping which is used for groups without roles
+const DefaultRoleMapping = "default"
+
 func (crm ConfigRoleMappings) Roles(roleMaps []string) []string {
 	if len(roleMaps) == 0 {
-		roleMaps = []string{"default"}
+		roleMaps = []string{DefaultRoleMapping}
 	}
 	var mappedRoles []string
 	for _, roleMap := range roleMaps {
@@ -158,10 +238,102 @@ type ConfigFeatureFlags struct {
 	// +kubebuilder:validation:Enum=allow;warn;block
 	// +kubebuilder:validation:Optional
 	GroupUserManagement string `json:"group_user_management,omitempty"`
+
+	// Should the mutating webhooks write the defaults from the PaasConfig into Paas and PaasNS resources. With
+	// `implicit`, defaults are applied by the operator when it creates the resources for a Paas, and with
+	// `materialize`, defaults are added to Paas and PaasNS resources when they are created or updated.
+	// +kubebuilder:default:=implicit
+	// +kubebuilder:validation:Enum=implicit;materialize
+	// +kubebuilder:validation:Optional
+	Defaults string `json:"defaults,omitempty"`
+}
+
+const (
+	// DefaultsImplicit is the value of the defaults feature flag to leave defaults implicit
+	DefaultsImplicit = "implicit"
+	// DefaultsMaterialize is the value of the defaults feature flag to write defaults into Paas and PaasNS resources
+	DefaultsMaterialize = "materialize"
+)
+
+// MaterializeDefaults returns true when the mutating webhooks should write defaults into Paas and PaasNS resources
+func (cff ConfigFeatureFlags) MaterializeDefaults() bool {
+	return cff.Defaults == DefaultsMaterialize
+}
+
+// ConfigGroupDefinitions holds all reusable group definitions, by the name of the OpenShift Group
+type ConfigGroupDefinitions map[string]ConfigGroupDefinition
+
+// ConfigGroupDefinition defines a group which can be shared between multiple Paas'es
+type ConfigGroupDefinition struct {
+	// A fully qualified LDAP query which will be used by the Group Sync Operator to sync users to the group.
+	// The CN in the query should match the name of the group definition.
+	// This field is mutually exclusive with `users`.
+	// +kubebuilder:validation:Optional
+	Query string `json:"query,omitempty"`
+	// A list of users which are added to the group.
+	// This field is mutually exclusive with `query`.
+	// +kubebuilder:validation:Optional
+	Users []string `json:"users,omitempty"`
+}
+
+// QueryGroupName returns the name of the group as it will be created by the Group Sync Operator from the query,
+// or defName when the query is empty.
+func (cgd ConfigGroupDefinition) QueryGroupName(defName string) string {
+	return PaasGroup{Query: cgd.Query}.name(defName)
 }
 
 type ConfigCapabilities map[string]ConfigCapability
 
+// Conflicts returns true when one of both capabilities lists the other in ConflictsWith
+func (ccs ConfigCapabilities) Conflicts(capName string, otherName string) bool {
+	return slices.Contains(ccs[capName].ConflictsWith, otherName) ||
+		slices.Contains(ccs[otherName].ConflictsWith, capName)
+}
+
+// DependencyOrder returns capNames ordered in such a way that every capability comes after the capabilities it
+// requires. Capabilities without a dependency relation are ordered by name. An error is returned when the
+// requirements contain a cycle.
+func (ccs ConfigCapabilities) DependencyOrder(capNames []string) ([]string, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	sorted := slices.Clone(capNames)
+	slices.Sort(sorted)
+	state := make(map[string]int, len(sorted))
+	ordered := make([]string, 0, len(sorted))
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("capability requirements contain a cycle: %s",
+				strings.Join(append(path, name), " -> "))
+		}
+		state[name] = visiting
+		requires := slices.Clone(ccs[name].Requires)
+		slices.Sort(requires)
+		for _, required := range requires {
+			if !slices.Contains(sorted, required) {
+				continue
+			}
+			if err := visit(required, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		ordered = append(ordered, name)
+		return nil
+	}
+	for _, name := range sorted {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
 type ConfigCapability struct {
 	// Name of the ArgoCD ApplicationSet which manages this capability
 	// The AppSet is only managed when `clusterwide_argocd_namespace` is set as well.
@@ -184,6 +356,52 @@ type ConfigCapability struct {
 
 	// Settings to allow specific configuration specific to a capability
 	CustomFields map[string]ConfigCustomField `json:"custom_fields,omitempty"`
+
+	// Names of other capabilities which should be enabled in a Paas before this capability can be enabled
+	// +kubebuilder:validation:Optional
+	Requires []string `json:"requires,omitempty"`
+
+	// Names of other capabilities which cannot be enabled in a Paas together with this capability
+	// +kubebuilder:validation:Optional
+	ConflictsWith []string `json:"conflictsWith,omitempty"`
+
+	// Versions of this capability which a Paas can select. When set, the selected version is exposed to the
+	// ApplicationSet as the `version` parameter.
+	// +kubebuilder:validation:Optional
+	Versions map[string]ConfigCapabilityVersion `json:"versions,omitempty"`
+
+	// Version used by Paas'es which do not select a version. Required when versions are configured.
+	// +kubebuilder:validation:Optional
+	DefaultVersion string `json:"defaultVersion,omitempty"`
+}
+
+// ConfigCapabilityVersion describes a version of a capability
+type ConfigCapabilityVersion struct {
+	// When set, this version is deprecated and Paas'es using it get a warning
+	// +kubebuilder:validation:Optional
+	Deprecation *ConfigCapabilityDeprecation `json:"deprecation,omitempty"`
+}
+
+// ConfigCapabilityDeprecation describes the deprecation of a capability version
+type ConfigCapabilityDeprecation struct {
+	// Date after which this version is no longer supported
+	// +kubebuilder:validation:Required
+	EndOfLife metav1.Time `json:"endOfLife"`
+	// Message for Paas'es using this version, e.g. which version to upgrade to
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
+
+// ResolveVersion returns the version a Paas selecting requested gets, which is the default version when requested is
+// empty. An empty string is returned when no versions are configured for this capability.
+func (cc ConfigCapability) ResolveVersion(requested string) string {
+	if len(cc.Versions) == 0 {
+		return ""
+	}
+	if requested == "" {
+		return cc.DefaultVersion
+	}
+	return requested
 }
 
 // For each resource type go templating can be used to derive the labels to be set on the resource when created
@@ -193,6 +411,11 @@ type ConfigTemplatingItems struct {
 	// +kubebuilder:validation:Optional
 	GenericCapabilityFields ConfigTemplatingItem `json:"genericCapabilityFields,omitempty"`
 
+	// Templates to add labels to Paas and PaasNS resources. Labels are only added by the mutating webhooks, when
+	// the defaults feature flag is set to materialize, and only when the label is not set yet.
+	// +kubebuilder:validation:Optional
+	PaasLabels ConfigTemplatingItem `json:"paasLabels,omitempty"`
+
 	// Templates to add labels to cluster quota labels
 	// +kubebuilder:validation:Optional
 	ClusterQuotaLabels ConfigTemplatingItem `json:"clusterQuotaLabels,omitempty"`
@@ -208,11 +431,75 @@ type ConfigTemplatingItems struct {
 	// Templates to describe labels for rolebindings
 	// +kubebuilder:validation:Optional
 	RoleBindingLabels ConfigTemplatingItem `json:"roleBindingLabels,omitempty"`
+
+	// Templates to add annotations to cluster quotas
+	// +kubebuilder:validation:Optional
+	ClusterQuotaAnnotations ConfigTemplatingItem `json:"clusterQuotaAnnotations,omitempty"`
+
+	// Templates to add annotations to groups
+	// +kubebuilder:validation:Optional
+	GroupAnnotations ConfigTemplatingItem `json:"groupAnnotations,omitempty"`
+
+	// Templates to add annotations to namespaces (e.g. `openshift.io/node-selector`)
+	// +kubebuilder:validation:Optional
+	NamespaceAnnotations ConfigTemplatingItem `json:"namespaceAnnotations,omitempty"`
+
+	// Templates to add annotations to rolebindings
+	// +kubebuilder:validation:Optional
+	RoleBindingAnnotations ConfigTemplatingItem `json:"roleBindingAnnotations,omitempty"`
+
+	// Templates to add labels to secrets
+	// +kubebuilder:validation:Optional
+	SecretLabels ConfigTemplatingItem `json:"secretLabels,omitempty"`
+
+	// Templates to add annotations to secrets
+	// +kubebuilder:validation:Optional
+	SecretAnnotations ConfigTemplatingItem `json:"secretAnnotations,omitempty"`
+
+	// Templates to define NetworkPolicies which are created in every namespace of a Paas.
+	// Every template should render the spec of a NetworkPolicy (as yaml), which is created as `paas-<key>`.
+	// When a template renders an empty string for a namespace, no NetworkPolicy is created in that namespace.
+	// +kubebuilder:validation:Optional
+	NetworkPolicies ConfigTemplatingItem `json:"networkPolicies,omitempty"`
+
+	// Templates to define extra namespaced resources (e.g. ConfigMaps, ServiceAccounts) which are created for every
+	// Paas. Every template should render a complete manifest (as yaml), which is applied with server-side apply.
+	// When a template renders an empty string, the resource is not created (or pruned when it was created before).
+	// +kubebuilder:validation:Optional
+	ExtraResources ConfigExtraResources `json:"extraResources,omitempty"`
 }
 
 // go templating can be used to derive the labels to be set on the resource when created
 type ConfigTemplatingItem map[string]string
 
+// ConfigExtraResourceScope defines for which namespaces an extra resource is rendered
+type ConfigExtraResourceScope string
+
+const (
+	// ExtraResourceScopePaas renders the resource once for every Paas, the manifest should set the namespace
+	ExtraResourceScopePaas ConfigExtraResourceScope = "paas"
+	// ExtraResourceScopeNamespace renders the resource for every namespace of a Paas
+	ExtraResourceScopeNamespace ConfigExtraResourceScope = "namespace"
+	// ExtraResourceScopeCapability renders the resource for every capability namespace of a Paas
+	ExtraResourceScopeCapability ConfigExtraResourceScope = "capability"
+)
+
+type ConfigExtraResources map[string]ConfigExtraResource
+
+type ConfigExtraResource struct {
+	// Defines for which namespaces the resource is rendered: once per Paas (`paas`), for every namespace of the Paas
+	// (`namespace`), or for every capability namespace of the Paas (`capability`).
+	// For the namespace and capability scopes the namespace of the resource defaults to the rendered namespace.
+	// +kubebuilder:validation:Enum=paas;namespace;capability
+	// +kubebuilder:default:=namespace
+	// +kubebuilder:validation:Optional
+	Scope ConfigExtraResourceScope `json:"scope,omitempty"`
+	// Go template rendering the manifest of the resource. Next to `.Paas` and `.Config`, the template can use
+	// `.Namespace.Name` and `.Namespace.Capability` for the namespace and capability scopes.
+	// +kubebuilder:validation:Required
+	Template string `json:"template"`
+}
+
 type ConfigCustomField struct {
 	// Regular expression for validating input, defaults to '', which means no validation.
 	// +kubebuilder:validation:Optional
@@ -229,6 +516,12 @@ type ConfigCustomField struct {
 	// When set to false, and no value is set, Default is used.
 	// +kubebuilder:validation:Optional
 	Required bool `json:"required"`
+	// JSON Schema (in the OpenAPI v3 dialect which is also used in CustomResourceDefinitions) which the value should
+	// match. When set, the value can be any JSON value (e.g. an integer, a boolean, a list or an object) instead of a
+	// string. Values are passed to the ApplicationSet as is. Use the `default` of the schema to set a default value,
+	// as Validation and Default can only be used for custom fields without a schema.
+	// +kubebuilder:validation:Optional
+	Schema *apiextensionsv1.JSON `json:"schema,omitempty"`
 }
 
 type ConfigQuotaSettings struct {
@@ -331,6 +624,21 @@ func (pcs PaasConfigSpec) CapabilityK8sName(capName string) types.NamespacedName
 type PaasConfigStatus struct {
 	// Conditions of this resource
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+	// Canary Paas'es which are not Ready with this candidate PaasConfig
+	// +kubebuilder:validation:Optional
+	FailedPaases []PaasConfigFailedPaas `json:"failedPaases,omitempty"`
+	// Existing Paas'es and PaasNSs which do not meet validations in warn or block-on-create-only mode, as found by the
+	// last audit
+	// +kubebuilder:validation:Optional
+	Violations []PaasConfigViolation `json:"violations,omitempty"`
+}
+
+// PaasConfigFailedPaas references a canary Paas which failed with a candidate PaasConfig
+type PaasConfigFailedPaas struct {
+	// Name of the Paas
+	Name string `json:"name"`
+	// Message of the Ready condition of the Paas
+	Message string `json:"message,omitempty"`
 }
 
 // revive:enable:line-length-limit
@@ -401,6 +709,47 @@ func ActivePaasConfigUpdated() predicate.Predicate {
 	}
 }
 
+// CandidatePaasConfigUpdated returns a predicate to be used in watches.
+// It returns changes which start or end the soaking of a candidate PaasConfig, which means that canary Paas'es
+// should be reconciled again with another PaasConfig.
+func CandidatePaasConfigUpdated() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldObj, oldOk := e.ObjectOld.(*PaasConfig)
+			newObj, newOk := e.ObjectNew.(*PaasConfig)
+			if !oldOk || !newOk {
+				return false
+			}
+			return oldObj.IsSoaking() != newObj.IsSoaking()
+		},
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			obj, ok := e.Object.(*PaasConfig)
+			return ok && obj.IsSoaking()
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// IsCandidate returns true if this PaasConfig is a candidate to replace the active PaasConfig.
+func (pc PaasConfig) IsCandidate() bool {
+	return pc.Spec.Candidate != nil
+}
+
+// IsSoaking returns true if this candidate PaasConfig is currently applied to canary Paas'es.
+func (pc PaasConfig) IsSoaking() bool {
+	if !pc.IsCandidate() {
+		return false
+	}
+	cond := meta.FindStatusCondition(pc.Status.Conditions, TypeCandidatePaasConfig)
+	return cond != nil && cond.Status == metav1.ConditionTrue && cond.Reason == CandidateReasonSoaking &&
+		cond.ObservedGeneration == pc.Generation
+}
+
 // IsActive returns true if this PaasConfig is the active one.
 func (pc PaasConfig) IsActive() bool {
 	return meta.IsStatusConditionPresentAndEqual(
@@ -350,3 +350,84 @@ func TestActivePaasConfigUpdated_NonUpdateEvents(t *testing.T) {
 		assert.False(t, pred.Generic(event.GenericEvent{}))
 	})
 }
+
+func TestCandidatePaasConfigUpdated(t *testing.T) {
+	pred := CandidatePaasConfigUpdated()
+	soaking := metav1.Condition{
+		Type:               TypeCandidatePaasConfig,
+		Status:             metav1.ConditionTrue,
+		Reason:             CandidateReasonSoaking,
+		ObservedGeneration: 1,
+	}
+	newCandidate := func(conditions ...metav1.Condition) *PaasConfig {
+		return &PaasConfig{
+			ObjectMeta: metav1.ObjectMeta{Generation: 1},
+			Spec:       PaasConfigSpec{Candidate: &ConfigCandidate{}},
+			Status:     PaasConfigStatus{Conditions: conditions},
+		}
+	}
+	rejected := soaking
+	rejected.Status = metav1.ConditionFalse
+	rejected.Reason = CandidateReasonRejected
+
+	assert.True(t, newCandidate(soaking).IsSoaking())
+	assert.False(t, newCandidate(rejected).IsSoaking())
+	assert.False(t, (&PaasConfig{Status: PaasConfigStatus{Conditions: []metav1.Condition{soaking}}}).IsSoaking(),
+		"a PaasConfig which is not a candidate cannot be soaking")
+
+	assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: newCandidate(), ObjectNew: newCandidate(soaking)}),
+		"start of soaking should be returned")
+	assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: newCandidate(soaking), ObjectNew: newCandidate(rejected)}),
+		"end of soaking should be returned")
+	assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: newCandidate(soaking), ObjectNew: newCandidate(soaking)}),
+		"status updates while soaking should not be returned")
+	assert.True(t, pred.Delete(event.DeleteEvent{Object: newCandidate(soaking)}))
+	assert.False(t, pred.Delete(event.DeleteEvent{Object: newCandidate(rejected)}))
+	assert.False(t, pred.Create(event.CreateEvent{Object: newCandidate(soaking)}))
+}
+
+func TestConfigCapabilities_DependencyOrder(t *testing.T) {
+	capabilities := ConfigCapabilities{
+		"argocd":   {},
+		"tekton":   {Requires: []string{"argocd"}},
+		"sonar":    {Requires: []string{"tekton", "argocd"}},
+		"grafana":  {},
+		"keycloak": {Requires: []string{"not-enabled"}},
+	}
+	order, err := capabilities.DependencyOrder([]string{"sonar", "grafana", "tekton", "argocd"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"argocd", "grafana", "tekton", "sonar"}, order)
+
+	order, err = capabilities.DependencyOrder([]string{"tekton", "keycloak"})
+	assert.NoError(t, err, "requirements which are not in the list should be ignored")
+	assert.Equal(t, []string{"keycloak", "tekton"}, order)
+
+	capabilities["argocd"] = ConfigCapability{Requires: []string{"sonar"}}
+	_, err = capabilities.DependencyOrder([]string{"sonar", "tekton", "argocd"})
+	assert.EqualError(t, err, "capability requirements contain a cycle: argocd -> sonar -> argocd")
+}
+
+func TestConfigCapabilities_Conflicts(t *testing.T) {
+	capabilities := ConfigCapabilities{
+		"sso":        {ConflictsWith: []string{"sso-legacy"}},
+		"sso-legacy": {},
+		"argocd":     {},
+	}
+	assert.True(t, capabilities.Conflicts("sso", "sso-legacy"))
+	assert.True(t, capabilities.Conflicts("sso-legacy", "sso"), "conflicts should be symmetric")
+	assert.False(t, capabilities.Conflicts("sso", "argocd"))
+	assert.False(t, capabilities.Conflicts("unknown", "argocd"))
+}
+
+func TestConfigCapability_ResolveVersion(t *testing.T) {
+	assert.Empty(t, ConfigCapability{}.ResolveVersion(""))
+	assert.Empty(t, ConfigCapability{DefaultVersion: "v1"}.ResolveVersion("v2"),
+		"without versions, no version should be resolved")
+
+	capability := ConfigCapability{
+		Versions:       map[string]ConfigCapabilityVersion{"v1": {}, "v2": {}},
+		DefaultVersion: "v2",
+	}
+	assert.Equal(t, "v2", capability.ResolveVersion(""))
+	assert.Equal(t, "v1", capability.ResolveVersion("v1"))
+}
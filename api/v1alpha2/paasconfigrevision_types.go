@@ -0,0 +1,133 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha2
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PinRevisionAnnotation can be set on a Paas to pin it to a PaasConfigRevision (by name)
+	PinRevisionAnnotation = "cpet.belastingdienst.nl/pin-paasconfig-revision"
+	// PinRevisionUntilAnnotation holds the time (RFC 3339) until which a Paas is pinned to a PaasConfigRevision
+	PinRevisionUntilAnnotation = "cpet.belastingdienst.nl/pin-paasconfig-revision-until"
+	// PaasConfigRevisionLabelKey is the key of the label on a PaasConfigRevision, with the name of the PaasConfig
+	PaasConfigRevisionLabelKey = "cpet.belastingdienst.nl/paasconfig"
+
+	revisionHashLength = 12
+)
+
+// PaasConfigRevisionSpec holds an immutable snapshot of the spec of an activated PaasConfig
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+type PaasConfigRevisionSpec struct {
+	// Name of the PaasConfig this is a revision of
+	// +kubebuilder:validation:Required
+	PaasConfig string `json:"paasConfig"`
+	// Hash of the PaasConfig spec
+	// +kubebuilder:validation:Required
+	Hash string `json:"hash"`
+	// Snapshot of the PaasConfig spec
+	// +kubebuilder:validation:Required
+	Config PaasConfigSpec `json:"config"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=paasconfigrevision,scope=Cluster
+// +kubebuilder:printcolumn:name="PaasConfig",type=string,JSONPath=`.spec.paasConfig`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// PaasConfigRevision is the Schema for the paasconfigrevision API
+type PaasConfigRevision struct {
+	metav1.TypeMeta   `json:""`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PaasConfigRevisionSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PaasConfigRevisionList contains a list of PaasConfigRevision
+type PaasConfigRevisionList struct {
+	metav1.TypeMeta `json:""`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PaasConfigRevision `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PaasConfigRevision{}, &PaasConfigRevisionList{})
+}
+
+// PaasConfigRevisionHash returns the hash of a PaasConfigSpec, which identifies a PaasConfigRevision
+func PaasConfigRevisionHash(spec PaasConfigSpec) string {
+	spec.Candidate = nil
+	// Marshalling a PaasConfigSpec cannot fail, and maps are marshalled in sorted order
+	data, _ := json.Marshal(spec)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// RevisionName returns the name of the PaasConfigRevision for the current spec of this PaasConfig
+func (pc PaasConfig) RevisionName() string {
+	return fmt.Sprintf("%s-%s", pc.Name, PaasConfigRevisionHash(pc.Spec)[:revisionHashLength])
+}
+
+// NewPaasConfigRevision returns a PaasConfigRevision with a snapshot of the spec of a PaasConfig
+func NewPaasConfigRevision(pc PaasConfig) *PaasConfigRevision {
+	spec := *pc.Spec.DeepCopy()
+	spec.Candidate = nil
+	return &PaasConfigRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   pc.RevisionName(),
+			Labels: map[string]string{PaasConfigRevisionLabelKey: pc.Name},
+		},
+		Spec: PaasConfigRevisionSpec{
+			PaasConfig: pc.Name,
+			Hash:       PaasConfigRevisionHash(pc.Spec),
+			Config:     spec,
+		},
+	}
+}
+
+// AsPaasConfig returns the PaasConfig as it was when this revision was activated
+func (pcr PaasConfigRevision) AsPaasConfig() PaasConfig {
+	return PaasConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: pcr.Spec.PaasConfig},
+		Spec:       *pcr.Spec.Config.DeepCopy(),
+	}
+}
+
+// PinnedRevision returns the name of the PaasConfigRevision this Paas is pinned to, and until when. An empty name is
+// returned when the Paas is not pinned, or when the pin expired. An error is returned for invalid pin annotations.
+func (p Paas) PinnedRevision(now time.Time) (name string, until time.Time, err error) {
+	name = p.Annotations[PinRevisionAnnotation]
+	untilValue, hasUntil := p.Annotations[PinRevisionUntilAnnotation]
+	switch {
+	case name == "" && !hasUntil:
+		return "", time.Time{}, nil
+	case name == "":
+		return "", time.Time{}, fmt.Errorf("annotation %s requires annotation %s",
+			PinRevisionUntilAnnotation, PinRevisionAnnotation)
+	case !hasUntil:
+		return "", time.Time{}, fmt.Errorf("annotation %s requires annotation %s",
+			PinRevisionAnnotation, PinRevisionUntilAnnotation)
+	}
+	if until, err = time.Parse(time.RFC3339, untilValue); err != nil {
+		return "", time.Time{}, fmt.Errorf("annotation %s should be a RFC 3339 timestamp: %w",
+			PinRevisionUntilAnnotation, err)
+	}
+	if !now.Before(until) {
+		return "", until, nil
+	}
+	return name, until, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPaasConfigRevisionHash(t *testing.T) {
+	spec := PaasConfigSpec{QuotaLabel: "q", RoleMappings: ConfigRoleMappings{"b": {"view"}, "a": {"edit"}}}
+	hash := PaasConfigRevisionHash(spec)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, PaasConfigRevisionHash(*spec.DeepCopy()), "hash should be deterministic")
+
+	candidate := *spec.DeepCopy()
+	candidate.Candidate = &ConfigCandidate{SoakPeriod: metav1.Duration{Duration: time.Hour}}
+	assert.Equal(t, hash, PaasConfigRevisionHash(candidate), "candidate settings should not change the hash")
+
+	changed := *spec.DeepCopy()
+	changed.QuotaLabel = "other"
+	assert.NotEqual(t, hash, PaasConfigRevisionHash(changed))
+}
+
+func TestNewPaasConfigRevision(t *testing.T) {
+	cfg := PaasConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "paas-config"},
+		Spec:       PaasConfigSpec{QuotaLabel: "q"},
+	}
+	revision := NewPaasConfigRevision(cfg)
+	assert.Equal(t, cfg.RevisionName(), revision.Name)
+	assert.Equal(t, "paas-config-"+revision.Spec.Hash[:12], revision.Name)
+	assert.Equal(t, "paas-config", revision.Labels[PaasConfigRevisionLabelKey])
+	assert.Equal(t, cfg.Spec, revision.Spec.Config)
+
+	restored := revision.AsPaasConfig()
+	assert.Equal(t, "paas-config", restored.Name)
+	assert.Equal(t, cfg.RevisionName(), restored.RevisionName())
+}
+
+func TestPaasPinnedRevision(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	newPaas := func(annotations map[string]string) Paas {
+		return Paas{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+
+	name, _, err := newPaas(nil).PinnedRevision(now)
+	require.NoError(t, err)
+	assert.Empty(t, name)
+
+	name, until, err := newPaas(map[string]string{
+		PinRevisionAnnotation:      "cfg-123",
+		PinRevisionUntilAnnotation: "2025-01-02T12:00:00Z",
+	}).PinnedRevision(now)
+	require.NoError(t, err)
+	assert.Equal(t, "cfg-123", name)
+	assert.Equal(t, now.Add(24*time.Hour), until)
+
+	name, _, err = newPaas(map[string]string{
+		PinRevisionAnnotation:      "cfg-123",
+		PinRevisionUntilAnnotation: "2025-01-01T11:00:00Z",
+	}).PinnedRevision(now)
+	require.NoError(t, err)
+	assert.Empty(t, name, "an expired pin should be ignored")
+
+	_, _, err = newPaas(map[string]string{PinRevisionAnnotation: "cfg-123"}).PinnedRevision(now)
+	require.Error(t, err)
+	_, _, err = newPaas(map[string]string{PinRevisionUntilAnnotation: "2025-01-02T12:00:00Z"}).PinnedRevision(now)
+	require.Error(t, err)
+	_, _, err = newPaas(map[string]string{
+		PinRevisionAnnotation:      "cfg-123",
+		PinRevisionUntilAnnotation: "tomorrow",
+	}).PinnedRevision(now)
+	require.Error(t, err)
+}
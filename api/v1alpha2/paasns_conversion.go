@@ -6,5 +6,65 @@ See LICENSE.md for details.
 
 package v1alpha2
 
-// Hub marks this type as a conversion hub.
-func (*PaasNS) Hub() {}
+import (
+	"context"
+	"fmt"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// paasNsRemovedFields holds the fields of a PaasNS which are removed in v1alpha3
+type paasNsRemovedFields struct {
+	Paas string `json:"paas,omitempty"`
+}
+
+// ConvertTo converts this PaasNS (v1alpha2) to the Hub version (v1alpha3).
+// The deprecated paas field is kept in the v1alpha2-fields annotation.
+func (p *PaasNS) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha3.PaasNS)
+	if !ok {
+		return fmt.Errorf("cannot convert from v1alpha2: got %T", dstRaw)
+	}
+
+	_, logger := logging.GetLogComponent(context.TODO(), logging.ApiComponent)
+	logger.Debug().Msg("Starting conversion from spoke (v1alpha2) to hub (v1alpha3)")
+
+	src := p.DeepCopy()
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha3.PaasNSSpec{
+		Groups:  src.Spec.Groups,
+		Secrets: src.Spec.Secrets,
+	}
+
+	return setRemovedFields(&dst.ObjectMeta, v1alpha3.V1alpha2FieldsAnnotation, paasNsRemovedFields{
+		Paas: src.Spec.Paas,
+	})
+}
+
+// ConvertFrom converts the Hub version (v1alpha3) to this PaasNS (v1alpha2).
+// The deprecated paas field is restored from the v1alpha2-fields annotation.
+func (p *PaasNS) ConvertFrom(srcRaw conversion.Hub) error {
+	srcHub, ok := srcRaw.(*v1alpha3.PaasNS)
+	if !ok {
+		return fmt.Errorf("cannot convert to v1alpha2: got %T", srcRaw)
+	}
+
+	_, logger := logging.GetLogComponent(context.TODO(), logging.ApiComponent)
+	logger.Debug().Msg("Starting conversion from hub (v1alpha3) to spoke (v1alpha2)")
+
+	src := srcHub.DeepCopy()
+	p.ObjectMeta = src.ObjectMeta
+	var removed paasNsRemovedFields
+	if err := popRemovedFields(&p.ObjectMeta, v1alpha3.V1alpha2FieldsAnnotation, &removed); err != nil {
+		return err
+	}
+	p.Spec = PaasNSSpec{
+		Paas:    removed.Paas,
+		Groups:  src.Spec.Groups,
+		Secrets: src.Spec.Secrets,
+	}
+
+	return nil
+}
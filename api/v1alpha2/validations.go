@@ -6,7 +6,10 @@ See LICENSE.md for details.
 
 package v1alpha2
 
-import "regexp"
+import (
+	"regexp"
+	"sync"
+)
 
 // PaasConfigTypeValidations can have custom validations for a specific CRD (e.a. paas, paasConfig or PaasNs).
 // Refer to https://belastingdienst.github.io/opr-paas/latest/administrators-guide/validations/ for more info.
@@ -16,6 +19,9 @@ type PaasConfigTypeValidations map[string]string
 // with key being the (lower case) name of the crd and value being a PaasConfigTypeValidations object.
 type PaasConfigValidations map[string]PaasConfigTypeValidations
 
+// validationRECache holds the compiled regular expressions by expression, so that they are only compiled once
+var validationRECache sync.Map
+
 // getValidationRE is an internal function which checks if a validation RE is configured
 // and returns a Regexp object if it is, or nil if it isn't
 func (pctv PaasConfigTypeValidations) getValidationRE(fieldName string) *regexp.Regexp {
@@ -23,7 +29,14 @@ func (pctv PaasConfigTypeValidations) getValidationRE(fieldName string) *regexp.
 	if !exists {
 		return nil
 	}
-	return regexp.MustCompile(validation)
+	if re, cached := validationRECache.Load(validation); cached {
+		if compiled, ok := re.(*regexp.Regexp); ok {
+			return compiled
+		}
+	}
+	re := regexp.MustCompile(validation)
+	validationRECache.Store(validation, re)
+	return re
 }
 
 // GetValidationRE can be used to get a validation for a crd by name
@@ -46,3 +59,90 @@ func (pc PaasConfig) GetValidationRE(crd string, fieldName string) *regexp.Regex
 	}
 	return pc.Spec.Validations.GetValidationRE(crd, fieldName)
 }
+
+// ConfigValidationRules is a list of CEL validation rules
+type ConfigValidationRules []ConfigValidationRule
+
+// ConfigValidationRule is a CEL expression which is evaluated by the webhooks for every Paas or PaasNS which is created
+// or updated. The variables `object` (the Paas or PaasNS), `paas` (the Paas, or the Paas the PaasNS belongs to) and
+// `paasConfig` are available in the expression. When the expression evaluates to false, the object is rejected.
+type ConfigValidationRule struct {
+	// The resource the rule applies to (paas or paasNs)
+	// +kubebuilder:validation:Enum=paas;paasNs
+	// +kubebuilder:validation:Required
+	Resource string `json:"resource"`
+
+	// The CEL expression, which should evaluate to a bool
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Required
+	Rule string `json:"rule"`
+
+	// The message which is returned when the rule evaluates to false
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+
+	// The enforcement mode of the rule
+	// +kubebuilder:default:=block
+	// +kubebuilder:validation:Optional
+	Enforcement ValidationEnforcement `json:"enforcement,omitempty"`
+}
+
+// ForResource returns the rules which apply to a resource (paas or paasNs)
+func (cvr ConfigValidationRules) ForResource(resource string) (rules ConfigValidationRules) {
+	for _, rule := range cvr {
+		if rule.Resource == resource {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// ValidationEnforcement defines how the webhooks handle a resource which does not meet a validation
+// +kubebuilder:validation:Enum=warn;block;block-on-create-only
+type ValidationEnforcement string
+
+const (
+	// EnforcementWarn allows resources which do not meet the validation, with a warning
+	EnforcementWarn ValidationEnforcement = "warn"
+	// EnforcementBlock rejects resources which do not meet the validation
+	EnforcementBlock ValidationEnforcement = "block"
+	// EnforcementBlockOnCreateOnly rejects new resources which do not meet the validation, and allows updates of
+	// existing resources which do not meet the validation with a warning
+	EnforcementBlockOnCreateOnly ValidationEnforcement = "block-on-create-only"
+)
+
+// ConfigValidationEnforcement holds the enforcement modes of validations, by the name of the validation (e.g.
+// paas.groupName). Validations which are not listed are blocking.
+type ConfigValidationEnforcement map[string]ValidationEnforcement
+
+// Mode returns the enforcement mode of a validation, which is block unless configured otherwise
+func (cve ConfigValidationEnforcement) Mode(validation string) ValidationEnforcement {
+	if mode, exists := cve[validation]; exists && mode != "" {
+		return mode
+	}
+	return EnforcementBlock
+}
+
+// Mode returns the enforcement mode of the rule, which is block unless configured otherwise
+func (cvr ConfigValidationRule) Mode() ValidationEnforcement {
+	if cvr.Enforcement == "" {
+		return EnforcementBlock
+	}
+	return cvr.Enforcement
+}
+
+// PaasConfigViolation references an existing Paas or PaasNS which does not meet a validation
+type PaasConfigViolation struct {
+	// Kind of the resource (Paas or PaasNS)
+	Kind string `json:"kind"`
+	// Name of the resource
+	Name string `json:"name"`
+	// Namespace of the resource (for a PaasNS)
+	Namespace string `json:"namespace,omitempty"`
+	// The validation which the resource does not meet (e.g. paas.groupName)
+	Validation string `json:"validation"`
+	// The enforcement mode of the validation
+	Enforcement ValidationEnforcement `json:"enforcement"`
+	// The error returned by the validation
+	Message string `json:"message"`
+}
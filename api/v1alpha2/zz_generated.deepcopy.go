@@ -11,10 +11,28 @@ See LICENSE.md for details.
 package v1alpha2
 
 import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigCandidate) DeepCopyInto(out *ConfigCandidate) {
+	*out = *in
+	in.CanarySelector.DeepCopyInto(&out.CanarySelector)
+	out.SoakPeriod = in.SoakPeriod
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigCandidate.
+func (in *ConfigCandidate) DeepCopy() *ConfigCandidate {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigCandidate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in ConfigCapPerm) DeepCopyInto(out *ConfigCapPerm) {
 	{
@@ -106,7 +124,24 @@ func (in *ConfigCapability) DeepCopyInto(out *ConfigCapability) {
 		in, out := &in.CustomFields, &out.CustomFields
 		*out = make(map[string]ConfigCustomField, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Requires != nil {
+		in, out := &in.Requires, &out.Requires
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConflictsWith != nil {
+		in, out := &in.ConflictsWith, &out.ConflictsWith
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make(map[string]ConfigCapabilityVersion, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
 }
@@ -121,9 +156,50 @@ func (in *ConfigCapability) DeepCopy() *ConfigCapability {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigCapabilityDeprecation) DeepCopyInto(out *ConfigCapabilityDeprecation) {
+	*out = *in
+	in.EndOfLife.DeepCopyInto(&out.EndOfLife)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigCapabilityDeprecation.
+func (in *ConfigCapabilityDeprecation) DeepCopy() *ConfigCapabilityDeprecation {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigCapabilityDeprecation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigCapabilityVersion) DeepCopyInto(out *ConfigCapabilityVersion) {
+	*out = *in
+	if in.Deprecation != nil {
+		in, out := &in.Deprecation, &out.Deprecation
+		*out = new(ConfigCapabilityDeprecation)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigCapabilityVersion.
+func (in *ConfigCapabilityVersion) DeepCopy() *ConfigCapabilityVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigCapabilityVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigCustomField) DeepCopyInto(out *ConfigCustomField) {
 	*out = *in
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigCustomField.
@@ -136,6 +212,42 @@ func (in *ConfigCustomField) DeepCopy() *ConfigCustomField {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigExtraResource) DeepCopyInto(out *ConfigExtraResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigExtraResource.
+func (in *ConfigExtraResource) DeepCopy() *ConfigExtraResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigExtraResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in ConfigExtraResources) DeepCopyInto(out *ConfigExtraResources) {
+	{
+		in := &in
+		*out = make(ConfigExtraResources, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigExtraResources.
+func (in ConfigExtraResources) DeepCopy() ConfigExtraResources {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigExtraResources)
+	in.DeepCopyInto(out)
+	return *out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigFeatureFlags) DeepCopyInto(out *ConfigFeatureFlags) {
 	*out = *in
@@ -151,6 +263,47 @@ func (in *ConfigFeatureFlags) DeepCopy() *ConfigFeatureFlags {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigGroupDefinition) DeepCopyInto(out *ConfigGroupDefinition) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigGroupDefinition.
+func (in *ConfigGroupDefinition) DeepCopy() *ConfigGroupDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigGroupDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in ConfigGroupDefinitions) DeepCopyInto(out *ConfigGroupDefinitions) {
+	{
+		in := &in
+		*out = make(ConfigGroupDefinitions, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigGroupDefinitions.
+func (in ConfigGroupDefinitions) DeepCopy() ConfigGroupDefinitions {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigGroupDefinitions)
+	in.DeepCopyInto(out)
+	return *out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigLdap) DeepCopyInto(out *ConfigLdap) {
 	*out = *in
@@ -184,6 +337,22 @@ func (in *ConfigQuotaSettings) DeepCopy() *ConfigQuotaSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigRevisions) DeepCopyInto(out *ConfigRevisions) {
+	*out = *in
+	out.MaxPinDuration = in.MaxPinDuration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigRevisions.
+func (in *ConfigRevisions) DeepCopy() *ConfigRevisions {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigRevisions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in ConfigRoleMappings) DeepCopyInto(out *ConfigRoleMappings) {
 	{
@@ -277,6 +446,13 @@ func (in *ConfigTemplatingItems) DeepCopyInto(out *ConfigTemplatingItems) {
 			(*out)[key] = val
 		}
 	}
+	if in.PaasLabels != nil {
+		in, out := &in.PaasLabels, &out.PaasLabels
+		*out = make(ConfigTemplatingItem, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.ClusterQuotaLabels != nil {
 		in, out := &in.ClusterQuotaLabels, &out.ClusterQuotaLabels
 		*out = make(ConfigTemplatingItem, len(*in))
@@ -305,6 +481,62 @@ func (in *ConfigTemplatingItems) DeepCopyInto(out *ConfigTemplatingItems) {
 			(*out)[key] = val
 		}
 	}
+	if in.ClusterQuotaAnnotations != nil {
+		in, out := &in.ClusterQuotaAnnotations, &out.ClusterQuotaAnnotations
+		*out = make(ConfigTemplatingItem, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.GroupAnnotations != nil {
+		in, out := &in.GroupAnnotations, &out.GroupAnnotations
+		*out = make(ConfigTemplatingItem, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NamespaceAnnotations != nil {
+		in, out := &in.NamespaceAnnotations, &out.NamespaceAnnotations
+		*out = make(ConfigTemplatingItem, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RoleBindingAnnotations != nil {
+		in, out := &in.RoleBindingAnnotations, &out.RoleBindingAnnotations
+		*out = make(ConfigTemplatingItem, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecretLabels != nil {
+		in, out := &in.SecretLabels, &out.SecretLabels
+		*out = make(ConfigTemplatingItem, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecretAnnotations != nil {
+		in, out := &in.SecretAnnotations, &out.SecretAnnotations
+		*out = make(ConfigTemplatingItem, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NetworkPolicies != nil {
+		in, out := &in.NetworkPolicies, &out.NetworkPolicies
+		*out = make(ConfigTemplatingItem, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtraResources != nil {
+		in, out := &in.ExtraResources, &out.ExtraResources
+		*out = make(ConfigExtraResources, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigTemplatingItems.
@@ -317,6 +549,77 @@ func (in *ConfigTemplatingItems) DeepCopy() *ConfigTemplatingItems {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in ConfigValidationEnforcement) DeepCopyInto(out *ConfigValidationEnforcement) {
+	{
+		in := &in
+		*out = make(ConfigValidationEnforcement, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigValidationEnforcement.
+func (in ConfigValidationEnforcement) DeepCopy() ConfigValidationEnforcement {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigValidationEnforcement)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigValidationRule) DeepCopyInto(out *ConfigValidationRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigValidationRule.
+func (in *ConfigValidationRule) DeepCopy() *ConfigValidationRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigValidationRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in ConfigValidationRules) DeepCopyInto(out *ConfigValidationRules) {
+	{
+		in := &in
+		*out = make(ConfigValidationRules, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigValidationRules.
+func (in ConfigValidationRules) DeepCopy() ConfigValidationRules {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigValidationRules)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomFieldValue) DeepCopyInto(out *CustomFieldValue) {
+	*out = *in
+	in.JSON.DeepCopyInto(&out.JSON)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomFieldValue.
+func (in *CustomFieldValue) DeepCopy() *CustomFieldValue {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomFieldValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NamespacedName) DeepCopyInto(out *NamespacedName) {
 	*out = *in
@@ -385,9 +688,9 @@ func (in *PaasCapability) DeepCopyInto(out *PaasCapability) {
 	*out = *in
 	if in.CustomFields != nil {
 		in, out := &in.CustomFields, &out.CustomFields
-		*out = make(map[string]string, len(*in))
+		*out = make(PaasCustomFields, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
 	out.Quota = in.Quota.DeepCopy()
@@ -437,6 +740,21 @@ func (in *PaasConfig) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PaasConfigFailedPaas) DeepCopyInto(out *PaasConfigFailedPaas) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasConfigFailedPaas.
+func (in *PaasConfigFailedPaas) DeepCopy() *PaasConfigFailedPaas {
+	if in == nil {
+		return nil
+	}
+	out := new(PaasConfigFailedPaas)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PaasConfigList) DeepCopyInto(out *PaasConfigList) {
 	*out = *in
@@ -469,6 +787,95 @@ func (in *PaasConfigList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PaasConfigReference) DeepCopyInto(out *PaasConfigReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasConfigReference.
+func (in *PaasConfigReference) DeepCopy() *PaasConfigReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PaasConfigReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PaasConfigRevision) DeepCopyInto(out *PaasConfigRevision) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasConfigRevision.
+func (in *PaasConfigRevision) DeepCopy() *PaasConfigRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(PaasConfigRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PaasConfigRevision) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PaasConfigRevisionList) DeepCopyInto(out *PaasConfigRevisionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PaasConfigRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasConfigRevisionList.
+func (in *PaasConfigRevisionList) DeepCopy() *PaasConfigRevisionList {
+	if in == nil {
+		return nil
+	}
+	out := new(PaasConfigRevisionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PaasConfigRevisionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PaasConfigRevisionSpec) DeepCopyInto(out *PaasConfigRevisionSpec) {
+	*out = *in
+	in.Config.DeepCopyInto(&out.Config)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasConfigRevisionSpec.
+func (in *PaasConfigRevisionSpec) DeepCopy() *PaasConfigRevisionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PaasConfigRevisionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PaasConfigSpec) DeepCopyInto(out *PaasConfigSpec) {
 	*out = *in
@@ -522,7 +929,32 @@ func (in *PaasConfigSpec) DeepCopyInto(out *PaasConfigSpec) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.ValidationRules != nil {
+		in, out := &in.ValidationRules, &out.ValidationRules
+		*out = make(ConfigValidationRules, len(*in))
+		copy(*out, *in)
+	}
+	if in.ValidationEnforcement != nil {
+		in, out := &in.ValidationEnforcement, &out.ValidationEnforcement
+		*out = make(ConfigValidationEnforcement, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.GroupDefinitions != nil {
+		in, out := &in.GroupDefinitions, &out.GroupDefinitions
+		*out = make(ConfigGroupDefinitions, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	in.Templating.DeepCopyInto(&out.Templating)
+	out.Revisions = in.Revisions
+	if in.Candidate != nil {
+		in, out := &in.Candidate, &out.Candidate
+		*out = new(ConfigCandidate)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasConfigSpec.
@@ -545,6 +977,16 @@ func (in *PaasConfigStatus) DeepCopyInto(out *PaasConfigStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.FailedPaases != nil {
+		in, out := &in.FailedPaases, &out.FailedPaases
+		*out = make([]PaasConfigFailedPaas, len(*in))
+		copy(*out, *in)
+	}
+	if in.Violations != nil {
+		in, out := &in.Violations, &out.Violations
+		*out = make([]PaasConfigViolation, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasConfigStatus.
@@ -610,6 +1052,57 @@ func (in PaasConfigValidations) DeepCopy() PaasConfigValidations {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PaasConfigViolation) DeepCopyInto(out *PaasConfigViolation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasConfigViolation.
+func (in *PaasConfigViolation) DeepCopy() *PaasConfigViolation {
+	if in == nil {
+		return nil
+	}
+	out := new(PaasConfigViolation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in PaasCustomFields) DeepCopyInto(out *PaasCustomFields) {
+	{
+		in := &in
+		*out = make(PaasCustomFields, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasCustomFields.
+func (in PaasCustomFields) DeepCopy() PaasCustomFields {
+	if in == nil {
+		return nil
+	}
+	out := new(PaasCustomFields)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PaasExtraResource) DeepCopyInto(out *PaasExtraResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasExtraResource.
+func (in *PaasExtraResource) DeepCopy() *PaasExtraResource {
+	if in == nil {
+		return nil
+	}
+	out := new(PaasExtraResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PaasGroup) DeepCopyInto(out *PaasGroup) {
 	*out = *in
@@ -875,6 +1368,16 @@ func (in *PaasStatus) DeepCopyInto(out *PaasStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ExtraResources != nil {
+		in, out := &in.ExtraResources, &out.ExtraResources
+		*out = make([]PaasExtraResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.PaasConfig != nil {
+		in, out := &in.PaasConfig, &out.PaasConfig
+		*out = new(PaasConfigReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasStatus.
@@ -0,0 +1,20 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha3
+
+const (
+	// RequestorAnnotation is the annotation on a Paas which holds the requestor (also application responsible) of the
+	// Paas. It replaces the `spec.requestor` field of v1alpha2.
+	RequestorAnnotation = "cpet.belastingdienst.nl/requestor"
+	// ManagedByPaasAnnotation is the annotation on a Paas which holds the name of the 3rd party Paas by which this Paas
+	// is managed. It replaces the `spec.managedByPaas` field of v1alpha2.
+	ManagedByPaasAnnotation = "cpet.belastingdienst.nl/managed-by-paas"
+	// V1alpha2FieldsAnnotation is the annotation which holds the fields of a v1alpha2 PaasNS or PaasConfig which are
+	// removed in v1alpha3 (as json), so that converting to v1alpha3 and back to v1alpha2 does not lose them. On a
+	// v1alpha1 Paas it holds the fields of a v1alpha2 Paas which do not exist in v1alpha1.
+	V1alpha2FieldsAnnotation = "cpet.belastingdienst.nl/v1alpha2-fields"
+)
@@ -0,0 +1,29 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+// Package v1alpha3 contains API Schema definitions for the v1alpha3 API group.
+// v1alpha3 is the hub version of the Paas, PaasNS and PaasConfig resources: all other versions are converted from and
+// to v1alpha3. The fields which were deprecated in v1alpha2 are removed, and are replaced by annotations and go
+// templating.
+// +kubebuilder:object:generate=true
+// +groupName=cpet.belastingdienst.nl
+package v1alpha3
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+var (
+	// GroupVersion is group version used to register these objects
+	GroupVersion = schema.GroupVersion{Group: "cpet.belastingdienst.nl", Version: "v1alpha3"}
+
+	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
+	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+
+	// AddToScheme adds the types in this group-version to the given scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
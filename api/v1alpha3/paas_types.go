@@ -0,0 +1,173 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha3
+
+import (
+	paasquota "github.com/belastingdienst/opr-paas/v3/pkg/quota"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PaasSpec defines the desired state of Paas
+type PaasSpec struct {
+	// Quota defines the quotas which should be set on the cluster resource quota as used by this Paas project
+	// +kubebuilder:validation:Required
+	Quota paasquota.Quota `json:"quota"`
+
+	// Capabilities is a subset of capabilities that will be available in this Paas Project
+	// +kubebuilder:validation:Optional
+	Capabilities PaasCapabilities `json:"capabilities"`
+
+	// Groups define k8s groups, based on an LDAP query or a list of LDAP users, which get access to the namespaces
+	// belonging to this Paas. Per group, RBAC roles can be defined.
+	// +kubebuilder:validation:Optional
+	Groups PaasGroups `json:"groups"`
+
+	// Namespaces can be used to define extra namespaces to be created as part of this Paas project
+	// +kubebuilder:validation:Optional
+	Namespaces PaasNamespaces `json:"namespaces"`
+
+	// Secrets must be encrypted with a public key, for which the private key should be added to the DecryptKeySecret
+	// +kubebuilder:validation:Optional
+	Secrets map[string]string `json:"secrets"`
+}
+
+// PaasCapability holds all information for a capability
+type PaasCapability struct {
+	// Custom fields to configure this specific Capability.
+	// Values are strings, unless a schema is configured for the custom field in the PaasConfig.
+	// +kubebuilder:validation:Optional
+	CustomFields PaasCustomFields `json:"custom_fields"`
+	// This project has its own ClusterResourceQuota settings
+	// +kubebuilder:validation:Optional
+	Quota paasquota.Quota `json:"quota"`
+	// Secrets must be encrypted with a public key, for which the private key should be added to the DecryptKeySecret
+	// +kubebuilder:validation:Optional
+	Secrets map[string]string `json:"secrets"`
+	// You can enable extra permissions for the service accounts belonging to this capability
+	// Exact definitions is configured in Paas Configmap
+	// +kubebuilder:validation:Optional
+	ExtraPermissions bool `json:"extra_permissions"`
+	// Version of the capability, which should be one of the versions configured in the PaasConfig.
+	// Defaults to the default version configured in the PaasConfig.
+	// +kubebuilder:validation:Optional
+	Version string `json:"version,omitempty"`
+}
+
+// CustomFieldValue holds the value of a custom field of a capability. Values are strings for custom fields without a
+// schema, but can be any JSON value (e.g. a number, a list or an object) for custom fields with a schema.
+// +kubebuilder:validation:Type=""
+// +kubebuilder:pruning:PreserveUnknownFields
+type CustomFieldValue struct {
+	apiextensionsv1.JSON `json:",inline"`
+}
+
+// PaasCustomFields holds the values of all custom fields of a capability
+type PaasCustomFields map[string]CustomFieldValue
+
+// PaasCapabilities holds all capabilities enabled in a Paas
+type PaasCapabilities map[string]PaasCapability
+
+// PaasGroup can hold information about a group in the paas.spec.groups block
+type PaasGroup struct {
+	// A fully qualified LDAP query which will be used by the Group Sync Operator to sync users to the defined group.
+	//
+	// When set in combination with `users`, the Group Sync Operator will overwrite the manually assigned users.
+	// Therefore, this field is mutually exclusive with `group.users`.
+	// +kubebuilder:validation:Optional
+	Query string `json:"query"`
+	// A list of LDAP users which are added to the defined group.
+	//
+	// When set in combination with `users`, the Group Sync Operator will overwrite the manually assigned users.
+	// Therefore, this field is mutually exclusive with `group.query`.
+	// +kubebuilder:validation:Optional
+	Users []string `json:"users"`
+	// List of roles, as defined in the `PaasConfig` which the users in this group get assigned via a rolebinding.
+	// +kubebuilder:validation:Optional
+	Roles []string `json:"roles"`
+	// Name of a group definition in the `PaasConfig` (`spec.groupDefinitions`) which defines the query or users
+	// for this group. Therefore, this field is mutually exclusive with `group.query` and `group.users`.
+	// Groups referencing the same definition are shared between Paas'es.
+	// +kubebuilder:validation:Optional
+	Definition string `json:"definition,omitempty"`
+}
+
+// PaasGroups hold all groups in a paas.spec.groups
+type PaasGroups map[string]PaasGroup
+
+// PaasNamespaces is a key, value store of all defined Namespaces
+type PaasNamespaces map[string]PaasNamespace
+
+// PaasNamespace holds all info regarding a Paas managed Namespace (groups and secrets)
+type PaasNamespace struct {
+	// Keys of groups which should get access to this namespace. When not set it defaults to all groups listed in
+	// `spec.groups`.
+	// +kubebuilder:validation:Optional
+	Groups []string `json:"groups"`
+	// Secrets which should exist in this namespace, the values must be encrypted with a key pair referenced by
+	// `spec.decryptKeySecret` from the active PaasConfig.
+	// +kubebuilder:validation:Optional
+	Secrets map[string]string `json:"secrets"`
+}
+
+// PaasStatus defines the observed state of Paas
+type PaasStatus struct {
+	// +kubebuilder:validation:Optional
+	//revive:disable-next-line
+	Conditions []metav1.Condition `json:"conditions" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+	// Extra resources (as templated in the PaasConfig) which are applied for this Paas.
+	// The operator uses this list to prune resources which are no longer rendered.
+	// +kubebuilder:validation:Optional
+	ExtraResources []PaasExtraResource `json:"extraResources,omitempty"`
+	// The PaasConfig which was used for the last reconciliation of this Paas
+	// +kubebuilder:validation:Optional
+	PaasConfig *PaasConfigReference `json:"paasConfig,omitempty"`
+}
+
+// PaasConfigReference references a specific generation of a PaasConfig
+type PaasConfigReference struct {
+	Name       string `json:"name"`
+	Generation int64  `json:"generation"`
+	// The PaasConfigRevision which was used (not set for candidate PaasConfigs)
+	// +kubebuilder:validation:Optional
+	Revision string `json:"revision,omitempty"`
+}
+
+// PaasExtraResource references an extra resource which is applied for a Paas
+type PaasExtraResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:conversion:hub
+// +kubebuilder:resource:path=paas,scope=Cluster
+
+// Paas is the Schema for the paas API
+type Paas struct {
+	metav1.TypeMeta   `json:""`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PaasSpec   `json:"spec,omitempty"`
+	Status PaasStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PaasList contains a list of Paas
+type PaasList struct {
+	metav1.TypeMeta `json:""`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Paas `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Paas{}, &PaasList{})
+}
@@ -0,0 +1,10 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha3
+
+// Hub marks this type as a conversion hub.
+func (*PaasConfig) Hub() {}
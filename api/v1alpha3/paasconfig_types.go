@@ -0,0 +1,394 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+//revive:disable:max-public-structs
+
+package v1alpha3
+
+import (
+	paasquota "github.com/belastingdienst/opr-paas/v3/pkg/quota"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:conversion:hub
+// +kubebuilder:resource:path=paasconfig,scope=Cluster
+
+// PaasConfig is the Schema for the paasconfig API
+type PaasConfig struct {
+	metav1.TypeMeta   `json:""`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PaasConfigSpec   `json:"spec,omitempty"`
+	Status PaasConfigStatus `json:"status,omitempty"`
+}
+
+// PaasConfigSpec defines the configuration of the Paas operator
+type PaasConfigSpec struct {
+	// DecryptKeysSecret is a reference to the secret containing the DecryptKeys
+	// +kubebuilder:validation:Required
+	DecryptKeysSecret NamespacedName `json:"decryptKeySecret"`
+
+	// Enable debug information generation or not
+	// +kubebuilder:default:=false
+	// +kubebuilder:validation:Optional
+	Debug bool `json:"debug"`
+
+	// Switch component debugging on or of for a specific component
+	// +kubebuilder:validation:Optional
+	ComponentsDebug map[string]bool `json:"components_debug"`
+
+	// A map with zero or more ConfigCapability
+	// +kubebuilder:validation:Optional
+	Capabilities ConfigCapabilities `json:"capabilities"`
+
+	// Label which is added to clusterquotas
+	// +kubebuilder:default:=clusterquotagroup
+	// +kubebuilder:validation:Optional
+	QuotaLabel string `json:"quota_label"`
+
+	// Grant permissions to all groups according to config in configmap and role selected per group in paas.
+	// +kubebuilder:validation:Optional
+	RoleMappings ConfigRoleMappings `json:"rolemappings"`
+
+	// Enable, disable, and tune operator features
+	// +kubebuilder:validation:Optional
+	FeatureFlags ConfigFeatureFlags `json:"feature_flags"`
+
+	// Set regular expressions to have the webhooks validate the fields
+	// +kubebuilder:validation:Optional
+	Validations PaasConfigValidations `json:"validations"`
+
+	// CEL rules to have the webhooks validate Paas'es and PaasNSs, for validations spanning multiple fields
+	// +kubebuilder:validation:Optional
+	ValidationRules ConfigValidationRules `json:"validationRules,omitempty"`
+
+	// The enforcement mode (warn, block or block-on-create-only) of validations, by the name of the validation (e.g.
+	// paas.groupName). Validations which are not listed are blocking.
+	// +kubebuilder:validation:Optional
+	ValidationEnforcement ConfigValidationEnforcement `json:"validationEnforcement,omitempty"`
+
+	// Reusable group definitions which can be referenced by name from the groups in a Paas.
+	// Groups with users are managed once by the operator and shared between all Paas'es referencing them.
+	// +kubebuilder:validation:Optional
+	GroupDefinitions ConfigGroupDefinitions `json:"groupDefinitions,omitempty"`
+
+	// With templating Administrators can define labels and generic custom fields to be applied on sub resources
+	// +kubebuilder:validation:Optional
+	Templating ConfigTemplatingItems `json:"templating,omitempty"`
+
+	// Settings for the PaasConfigRevisions which are kept for every activated PaasConfig spec
+	// +kubebuilder:validation:Optional
+	Revisions ConfigRevisions `json:"revisions,omitempty"`
+
+	// Candidate marks this PaasConfig as a candidate to replace the active PaasConfig. A candidate is first applied
+	// to canary Paas'es only, and is promoted to the active PaasConfig when all canary Paas'es are Ready after the
+	// soak period.
+	// +kubebuilder:validation:Optional
+	Candidate *ConfigCandidate `json:"candidate,omitempty"`
+}
+
+// ConfigRevisions defines how PaasConfigRevisions are kept, and for how long Paas'es can be pinned to one
+type ConfigRevisions struct {
+	// The number of PaasConfigRevisions to keep. Older revisions are deleted, unless a Paas is pinned to them.
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	HistoryLimit int32 `json:"historyLimit,omitempty"`
+
+	// The maximum time a Paas can be pinned to a PaasConfigRevision (counting from now)
+	// +kubebuilder:default:="168h"
+	// +kubebuilder:validation:Optional
+	MaxPinDuration metav1.Duration `json:"maxPinDuration,omitempty"`
+}
+
+// ConfigCandidate defines how a candidate PaasConfig is rolled out
+type ConfigCandidate struct {
+	// CanarySelector selects the Paas'es to which the candidate PaasConfig is applied first
+	// +kubebuilder:validation:Required
+	CanarySelector metav1.LabelSelector `json:"canarySelector"`
+
+	// SoakPeriod is the time all canary Paas'es should be Ready with the candidate PaasConfig, before the candidate
+	// is promoted to the active PaasConfig
+	// +kubebuilder:default:="1h"
+	// +kubebuilder:validation:Optional
+	SoakPeriod metav1.Duration `json:"soakPeriod,omitempty"`
+}
+
+// ConfigRoleMappings maps the roles of groups in a Paas to the cluster roles which the groups are bound to
+type ConfigRoleMappings map[string][]string
+
+// ConfigFeatureFlags can be used to enable, disable, and tune operator features
+type ConfigFeatureFlags struct {
+	// Should the operator manage group users
+	// +kubebuilder:default:=allow
+	// +kubebuilder:validation:Enum=allow;warn;block
+	// +kubebuilder:validation:Optional
+	GroupUserManagement string `json:"group_user_management,omitempty"`
+
+	// Should the mutating webhooks write the defaults from the PaasConfig into Paas and PaasNS resources. With
+	// `implicit`, defaults are applied by the operator when it creates the resources for a Paas, and with
+	// `materialize`, defaults are added to Paas and PaasNS resources when they are created or updated.
+	// +kubebuilder:default:=implicit
+	// +kubebuilder:validation:Enum=implicit;materialize
+	// +kubebuilder:validation:Optional
+	Defaults string `json:"defaults,omitempty"`
+}
+
+// ConfigGroupDefinitions holds all reusable group definitions, by the name of the OpenShift Group
+type ConfigGroupDefinitions map[string]ConfigGroupDefinition
+
+// ConfigGroupDefinition defines a group which can be shared between multiple Paas'es
+type ConfigGroupDefinition struct {
+	// A fully qualified LDAP query which will be used by the Group Sync Operator to sync users to the group.
+	// The CN in the query should match the name of the group definition.
+	// This field is mutually exclusive with `users`.
+	// +kubebuilder:validation:Optional
+	Query string `json:"query,omitempty"`
+	// A list of users which are added to the group.
+	// This field is mutually exclusive with `query`.
+	// +kubebuilder:validation:Optional
+	Users []string `json:"users,omitempty"`
+}
+
+// ConfigCapabilities holds the configuration of all capabilities, by the name of the capability
+type ConfigCapabilities map[string]ConfigCapability
+
+// ConfigCapability holds the configuration of a capability
+type ConfigCapability struct {
+	// Quota settings for this capability
+	// +kubebuilder:validation:Required
+	QuotaSettings ConfigQuotaSettings `json:"quotas"`
+
+	// Extra permissions set for this capability
+	// +kubebuilder:validation:Optional
+	ExtraPermissions ConfigCapPerm `json:"extra_permissions"`
+
+	// Default permissions set for this capability
+	// +kubebuilder:validation:Optional
+	DefaultPermissions ConfigCapPerm `json:"default_permissions"`
+
+	// Settings to allow specific configuration specific to a capability
+	CustomFields map[string]ConfigCustomField `json:"custom_fields,omitempty"`
+
+	// Names of other capabilities which should be enabled in a Paas before this capability can be enabled
+	// +kubebuilder:validation:Optional
+	Requires []string `json:"requires,omitempty"`
+
+	// Names of other capabilities which cannot be enabled in a Paas together with this capability
+	// +kubebuilder:validation:Optional
+	ConflictsWith []string `json:"conflictsWith,omitempty"`
+
+	// Versions of this capability which a Paas can select. When set, the selected version is exposed to the
+	// ApplicationSet as the `version` parameter.
+	// +kubebuilder:validation:Optional
+	Versions map[string]ConfigCapabilityVersion `json:"versions,omitempty"`
+
+	// Version used by Paas'es which do not select a version. Required when versions are configured.
+	// +kubebuilder:validation:Optional
+	DefaultVersion string `json:"defaultVersion,omitempty"`
+}
+
+// ConfigCapabilityVersion describes a version of a capability
+type ConfigCapabilityVersion struct {
+	// When set, this version is deprecated and Paas'es using it get a warning
+	// +kubebuilder:validation:Optional
+	Deprecation *ConfigCapabilityDeprecation `json:"deprecation,omitempty"`
+}
+
+// ConfigCapabilityDeprecation describes the deprecation of a capability version
+type ConfigCapabilityDeprecation struct {
+	// Date after which this version is no longer supported
+	// +kubebuilder:validation:Required
+	EndOfLife metav1.Time `json:"endOfLife"`
+	// Message for Paas'es using this version, e.g. which version to upgrade to
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
+
+// ConfigTemplatingItems holds the go templates which derive labels, annotations, fields and resources for the
+// resources which are created for a Paas
+type ConfigTemplatingItems struct {
+	// Templates to add fields to all capabilities
+	// +kubebuilder:validation:Optional
+	GenericCapabilityFields ConfigTemplatingItem `json:"genericCapabilityFields,omitempty"`
+
+	// Templates to add labels to Paas and PaasNS resources. Labels are only added by the mutating webhooks, when
+	// the defaults feature flag is set to materialize, and only when the label is not set yet.
+	// +kubebuilder:validation:Optional
+	PaasLabels ConfigTemplatingItem `json:"paasLabels,omitempty"`
+
+	// Templates to add labels to cluster quota labels
+	// +kubebuilder:validation:Optional
+	ClusterQuotaLabels ConfigTemplatingItem `json:"clusterQuotaLabels,omitempty"`
+
+	// Templates to add labels to group labels
+	// +kubebuilder:validation:Optional
+	GroupLabels ConfigTemplatingItem `json:"groupLabels,omitempty"`
+
+	// Templates to add labels to namespace labels
+	// +kubebuilder:validation:Optional
+	NamespaceLabels ConfigTemplatingItem `json:"namespaceLabels,omitempty"`
+
+	// Templates to describe labels for rolebindings
+	// +kubebuilder:validation:Optional
+	RoleBindingLabels ConfigTemplatingItem `json:"roleBindingLabels,omitempty"`
+
+	// Templates to add annotations to cluster quotas
+	// +kubebuilder:validation:Optional
+	ClusterQuotaAnnotations ConfigTemplatingItem `json:"clusterQuotaAnnotations,omitempty"`
+
+	// Templates to add annotations to groups
+	// +kubebuilder:validation:Optional
+	GroupAnnotations ConfigTemplatingItem `json:"groupAnnotations,omitempty"`
+
+	// Templates to add annotations to namespaces (e.g. `openshift.io/node-selector`)
+	// +kubebuilder:validation:Optional
+	NamespaceAnnotations ConfigTemplatingItem `json:"namespaceAnnotations,omitempty"`
+
+	// Templates to add annotations to rolebindings
+	// +kubebuilder:validation:Optional
+	RoleBindingAnnotations ConfigTemplatingItem `json:"roleBindingAnnotations,omitempty"`
+
+	// Templates to add labels to secrets
+	// +kubebuilder:validation:Optional
+	SecretLabels ConfigTemplatingItem `json:"secretLabels,omitempty"`
+
+	// Templates to add annotations to secrets
+	// +kubebuilder:validation:Optional
+	SecretAnnotations ConfigTemplatingItem `json:"secretAnnotations,omitempty"`
+
+	// Templates to define NetworkPolicies which are created in every namespace of a Paas.
+	// Every template should render the spec of a NetworkPolicy (as yaml), which is created as `paas-<key>`.
+	// When a template renders an empty string for a namespace, no NetworkPolicy is created in that namespace.
+	// +kubebuilder:validation:Optional
+	NetworkPolicies ConfigTemplatingItem `json:"networkPolicies,omitempty"`
+
+	// Templates to define extra namespaced resources (e.g. ConfigMaps, ServiceAccounts) which are created for every
+	// Paas. Every template should render a complete manifest (as yaml), which is applied with server-side apply.
+	// When a template renders an empty string, the resource is not created (or pruned when it was created before).
+	// +kubebuilder:validation:Optional
+	ExtraResources ConfigExtraResources `json:"extraResources,omitempty"`
+}
+
+// ConfigTemplatingItem holds go templates by the key of the label, annotation, field or resource they derive
+type ConfigTemplatingItem map[string]string
+
+// ConfigExtraResourceScope defines for which namespaces an extra resource is rendered
+type ConfigExtraResourceScope string
+
+// ConfigExtraResources holds the templates of extra resources, by a name of the resource
+type ConfigExtraResources map[string]ConfigExtraResource
+
+// ConfigExtraResource holds the template of an extra resource which is created for every Paas
+type ConfigExtraResource struct {
+	// Defines for which namespaces the resource is rendered: once per Paas (`paas`), for every namespace of the Paas
+	// (`namespace`), or for every capability namespace of the Paas (`capability`).
+	// For the namespace and capability scopes the namespace of the resource defaults to the rendered namespace.
+	// +kubebuilder:validation:Enum=paas;namespace;capability
+	// +kubebuilder:default:=namespace
+	// +kubebuilder:validation:Optional
+	Scope ConfigExtraResourceScope `json:"scope,omitempty"`
+	// Go template rendering the manifest of the resource. Next to `.Paas` and `.Config`, the template can use
+	// `.Namespace.Name` and `.Namespace.Capability` for the namespace and capability scopes.
+	// +kubebuilder:validation:Required
+	Template string `json:"template"`
+}
+
+// ConfigCustomField holds the configuration of a custom field of a capability
+type ConfigCustomField struct {
+	// Regular expression for validating input, defaults to '', which means no validation.
+	// +kubebuilder:validation:Optional
+	Validation string `json:"validation"`
+	// Set a default when no value is specified, defaults to ''.
+	// Only applies when Required is false.
+	// +kubebuilder:validation:Optional
+	Default string `json:"default"`
+	// You can now use a go-template string to use Paas and PaasConfig variables and compile a value
+	// +kubebuilder:validation:Optional
+	Template string `json:"template"`
+	// Define if the value must be specified in the PaaS.
+	// When set to true, and no value is set, PaasNs has error in status field, and capability is not built.
+	// When set to false, and no value is set, Default is used.
+	// +kubebuilder:validation:Optional
+	Required bool `json:"required"`
+	// JSON Schema (in the OpenAPI v3 dialect which is also used in CustomResourceDefinitions) which the value should
+	// match. When set, the value can be any JSON value (e.g. an integer, a boolean, a list or an object) instead of a
+	// string. Values are passed to the ApplicationSet as is. Use the `default` of the schema to set a default value,
+	// as Validation and Default can only be used for custom fields without a schema.
+	// +kubebuilder:validation:Optional
+	Schema *apiextensionsv1.JSON `json:"schema,omitempty"`
+}
+
+// ConfigQuotaSettings holds the quota settings of a capability
+type ConfigQuotaSettings struct {
+	// Is this a clusterwide quota or not
+	// +kubebuilder:default:=false
+	// +kubebuilder:validation:Optional
+	Clusterwide bool `json:"clusterwide"`
+
+	// The ratio of the requested quota which will be applied to the total quota
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Format:=float
+	// +kubebuilder:validation:Minimum:=0.0
+	// +kubebuilder:validation:Maximum:=1.0
+	Ratio float64 `json:"ratio"`
+
+	// The default quota which the enabled capability gets
+	// +kubebuilder:validation:Required
+	DefQuota paasquota.Quota `json:"defaults"`
+
+	// The minimum quota which the enabled capability gets
+	// +kubebuilder:validation:Optional
+	MinQuotas paasquota.Quota `json:"min"`
+
+	// The maximum quota which the capability gets
+	// +kubebuilder:validation:Optional
+	MaxQuotas paasquota.Quota `json:"max"`
+}
+
+// ConfigCapPerm holds the cluster roles which service accounts of a capability are bound to, by service account
+type ConfigCapPerm map[string][]string
+
+// revive:disable:line-length-limit
+
+// PaasConfigStatus defines the observed state of PaasConfig
+type PaasConfigStatus struct {
+	// Conditions of this resource
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+	// Canary Paas'es which are not Ready with this candidate PaasConfig
+	// +kubebuilder:validation:Optional
+	FailedPaases []PaasConfigFailedPaas `json:"failedPaases,omitempty"`
+	// Existing Paas'es and PaasNSs which do not meet validations in warn or block-on-create-only mode, as found by the
+	// last audit
+	// +kubebuilder:validation:Optional
+	Violations []PaasConfigViolation `json:"violations,omitempty"`
+}
+
+// PaasConfigFailedPaas references a canary Paas which failed with a candidate PaasConfig
+type PaasConfigFailedPaas struct {
+	// Name of the Paas
+	Name string `json:"name"`
+	// Message of the Ready condition of the Paas
+	Message string `json:"message,omitempty"`
+}
+
+// revive:enable:line-length-limit
+
+// +kubebuilder:object:root=true
+
+// PaasConfigList contains a list of PaasConfig
+type PaasConfigList struct {
+	metav1.TypeMeta `json:""`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PaasConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PaasConfig{}, &PaasConfigList{})
+}
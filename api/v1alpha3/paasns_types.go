@@ -0,0 +1,50 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha3
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PaasNSSpec defines the desired state of PaasNS
+type PaasNSSpec struct {
+	// Keys of the groups, as defined in the related `paas`, which should get access to
+	// the namespace created by this PaasNS. When not set, all groups as defined in the related
+	// `paas` get access to the namespace created by this PaasNS.
+	// +kubebuilder:validation:Optional
+	Groups []string `json:"groups,omitempty"`
+	// Secrets which should exist in the namespace created through this PaasNS,
+	// the values are the encrypted secrets through Crypt
+	// +kubebuilder:validation:Optional
+	Secrets map[string]string `json:"secrets,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:conversion:hub
+// +kubebuilder:resource:path=paasns,scope=Namespaced
+
+// PaasNS is the Schema for the PaasNS API
+type PaasNS struct {
+	metav1.TypeMeta   `json:""`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PaasNSSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PaasNSList contains a list of PaasNS
+type PaasNSList struct {
+	metav1.TypeMeta `json:""`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PaasNS `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PaasNS{}, &PaasNSList{})
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha3
+
+// PaasConfigTypeValidations can have custom validations for a specific CRD (e.a. paas, paasConfig or PaasNs).
+// Refer to https://belastingdienst.github.io/opr-paas/latest/administrators-guide/validations/ for more info.
+type PaasConfigTypeValidations map[string]string
+
+// PaasConfigValidations is a map which holds all validations,
+// with key being the (lower case) name of the crd and value being a PaasConfigTypeValidations object.
+type PaasConfigValidations map[string]PaasConfigTypeValidations
+
+// ConfigValidationRules is a list of CEL validation rules
+type ConfigValidationRules []ConfigValidationRule
+
+// ConfigValidationRule is a CEL expression which is evaluated by the webhooks for every Paas or PaasNS which is created
+// or updated. The variables `object` (the Paas or PaasNS), `paas` (the Paas, or the Paas the PaasNS belongs to) and
+// `paasConfig` are available in the expression. When the expression evaluates to false, the object is rejected.
+type ConfigValidationRule struct {
+	// The resource the rule applies to (paas or paasNs)
+	// +kubebuilder:validation:Enum=paas;paasNs
+	// +kubebuilder:validation:Required
+	Resource string `json:"resource"`
+
+	// The CEL expression, which should evaluate to a bool
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Required
+	Rule string `json:"rule"`
+
+	// The message which is returned when the rule evaluates to false
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+
+	// The enforcement mode of the rule
+	// +kubebuilder:default:=block
+	// +kubebuilder:validation:Optional
+	Enforcement ValidationEnforcement `json:"enforcement,omitempty"`
+}
+
+// ValidationEnforcement defines how the webhooks handle a resource which does not meet a validation
+// +kubebuilder:validation:Enum=warn;block;block-on-create-only
+type ValidationEnforcement string
+
+// ConfigValidationEnforcement holds the enforcement modes of validations, by the name of the validation (e.g.
+// paas.groupName). Validations which are not listed are blocking.
+type ConfigValidationEnforcement map[string]ValidationEnforcement
+
+// PaasConfigViolation references an existing Paas or PaasNS which does not meet a validation
+type PaasConfigViolation struct {
+	// Kind of the resource (Paas or PaasNS)
+	Kind string `json:"kind"`
+	// Name of the resource
+	Name string `json:"name"`
+	// Namespace of the resource (for a PaasNS)
+	Namespace string `json:"namespace,omitempty"`
+	// The validation which the resource does not meet (e.g. paas.groupName)
+	Validation string `json:"validation"`
+	// The enforcement mode of the validation
+	Enforcement ValidationEnforcement `json:"enforcement"`
+	// The error returned by the validation
+	Message string `json:"message"`
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/belastingdienst/opr-paas/v3/internal/accessreport"
+	"github.com/rs/zerolog/log"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const accessReportCommand = "access-report"
+
+func setupAccessReport(f *flags, manager ctrl.Manager) {
+	if f.accessReportAddr == "0" {
+		return
+	}
+	server := accessreport.New(manager.GetClient(), f.accessReportAddr)
+	if err := manager.Add(server); err != nil {
+		log.Fatal().Msgf("failed to add access report server: %v", err)
+	}
+	if err := manager.AddReadyzCheck("access report", server.StartedChecker()); err != nil {
+		log.Fatal().Msgf("failed to add access report readiness check: %v", err)
+	}
+}
+
+// runAccessReport runs the access-report subcommand, which writes the access report for the cluster in the current
+// kubeconfig context to out. It returns the exit code for the process.
+func runAccessReport(args []string, out io.Writer) int {
+	var (
+		filter accessreport.Filter
+		format string
+	)
+	fs := flag.NewFlagSet(accessReportCommand, flag.ContinueOnError)
+	fs.StringVar(&filter.Paas, "paas", "", "Only report on this Paas")
+	fs.StringVar(&filter.Namespace, "namespace", "", "Only report on this namespace")
+	fs.StringVar(&filter.User, "user", "", "Only report on this user (or system:serviceaccount:<ns>:<name>)")
+	fs.StringVar(&filter.Role, "role", "", "Only report on this role")
+	fs.StringVar(&format, "format", accessreport.FormatJSON, "Output format (json or csv)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	configureLogging(false, false, "", false)
+
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to get kubeconfig: %v\n", err)
+		return 1
+	}
+	kclient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to create client: %v\n", err)
+		return 1
+	}
+	report, err := accessreport.Build(context.Background(), kclient, filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to build access report: %v\n", err)
+		return 1
+	}
+	if err = report.Write(out, format); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to write access report: %v\n", err)
+		return 1
+	}
+	return 0
+}
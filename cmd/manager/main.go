@@ -14,6 +14,9 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	argocdplugingenerator "github.com/belastingdienst/opr-paas/v3/internal/argocd-plugin-generator"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
@@ -28,6 +31,7 @@ import (
 
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha1"
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
 	"github.com/belastingdienst/opr-paas/v3/internal/config"
 	"github.com/belastingdienst/opr-paas/v3/internal/controller"
 	"github.com/belastingdienst/opr-paas/v3/internal/logging"
@@ -35,11 +39,13 @@ import (
 	"github.com/belastingdienst/opr-paas/v3/internal/version"
 	webhookv1alpha1 "github.com/belastingdienst/opr-paas/v3/internal/webhook/v1alpha1"
 	webhookv1alpha2 "github.com/belastingdienst/opr-paas/v3/internal/webhook/v1alpha2"
+	webhookv1alpha3 "github.com/belastingdienst/opr-paas/v3/internal/webhook/v1alpha3"
 	"github.com/go-logr/zerologr"
 	quotav1 "github.com/openshift/api/quota/v1"
 	userv1 "github.com/openshift/api/user/v1"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -66,6 +72,18 @@ type flags struct {
 	metricsCertPath, metricsCertName, metricsCertKey string
 	webhookCertPath, webhookCertName, webhookCertKey string
 	argocdPluginGenAddr                              string
+	argocdPluginGenTokenSecret                       string
+	argocdPluginGenCertPath                          string
+	argocdPluginGenCertName                          string
+	argocdPluginGenCertKey                           string
+	argocdPluginGenClientCA                          string
+	accessReportAddr                                 string
+	maxConcurrentReconciles                          int
+	rolloutRate                                      float64
+	rolloutCanarySelector                            string
+	shardCount                                       int
+	shardIndex                                       int
+	auditInterval                                    time.Duration
 }
 
 func init() {
@@ -75,10 +93,14 @@ func init() {
 	utilruntime.Must(argoresources.AddToScheme(scheme))
 	utilruntime.Must(v1alpha1.AddToScheme(scheme))
 	utilruntime.Must(v1alpha2.AddToScheme(scheme))
+	utilruntime.Must(v1alpha3.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == accessReportCommand {
+		os.Exit(runAccessReport(os.Args[2:], os.Stdout))
+	}
 	f := configureFlags()
 	configureLogging(f.pretty, f.debug, f.componentDebugList, f.splitLogOutput)
 
@@ -116,6 +138,17 @@ func configureFlags() *flags {
 		"The name of the metrics server certificate file.")
 	flag.StringVar(&f.metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.StringVar(&f.argocdPluginGenAddr, "argocd-plugin-generator-bind-address", "0", "The address the argocd plugin generator endpoint binds to. Use :4355 for HTTP, or leave as 0 to disable the argocd plugin generator service.") // nolint:revive
+	flag.StringVar(&f.argocdPluginGenTokenSecret, "argocd-plugin-generator-token-secret", "",
+		"The Secret (as namespace/name) holding additional argocd plugin generator tokens, each scoped to capabilities.")
+	flag.StringVar(&f.argocdPluginGenCertPath, "argocd-plugin-generator-cert-path", "",
+		"The directory that contains the argocd plugin generator certificate. Leave empty to serve without TLS.")
+	flag.StringVar(&f.argocdPluginGenCertName, "argocd-plugin-generator-cert-name", "tls.crt",
+		"The name of the argocd plugin generator certificate file.")
+	flag.StringVar(&f.argocdPluginGenCertKey, "argocd-plugin-generator-cert-key", "tls.key",
+		"The name of the argocd plugin generator key file.")
+	flag.StringVar(&f.argocdPluginGenClientCA, "argocd-plugin-generator-client-ca", "",
+		"The CA bundle file used to require and verify argocd plugin generator client certificates (mTLS).")
+	flag.StringVar(&f.accessReportAddr, "access-report-bind-address", "0", "The address the access report endpoint binds to. Use :4356 for HTTP, or leave as 0 to disable the access report service.") // nolint:revive
 	flag.BoolVar(&f.enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
 	flag.BoolVar(&f.pretty, "pretty", false, "Pretty-print logging output")
@@ -127,6 +160,19 @@ func configureFlags() *flags {
 		"Comma-separated list of components to log debug messages for.",
 	)
 	flag.BoolVar(&f.splitLogOutput, "split-log-output", false, "Send error logs to stderr, and the rest to stdout.")
+	flag.IntVar(&f.maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The maximum number of Paas'es which are reconciled in parallel.")
+	flag.Float64Var(&f.rolloutRate, "paasconfig-rollout-rate", 0,
+		"The number of Paas'es per second which are reconciled after a PaasConfig change. Use 0 for no limit.")
+	flag.StringVar(&f.rolloutCanarySelector, "paasconfig-rollout-canary-selector", "",
+		"Label selector for canary Paas'es, which are reconciled first after a PaasConfig change.")
+	flag.IntVar(&f.shardCount, "shard-count", 1,
+		"The number of shards. When higher than 1, every replica only reconciles the Paas'es in its own shard.")
+	flag.IntVar(&f.shardIndex, "shard-index", -1,
+		"The shard handled by this replica. Derived from the ordinal of the hostname (e.g. opr-paas-2) when unset.")
+	flag.DurationVar(&f.auditInterval, "audit-interval", time.Hour,
+		"The interval in which existing Paas'es and PaasNSs are audited against validations in warn or "+
+			"block-on-create-only mode. Use 0 to disable the audit.")
 	flag.Parse()
 
 	return f
@@ -181,10 +227,12 @@ func configureManager(f *flags) ctrl.Manager {
 	tlsOpts := configureTLSOptions(f)
 	metricsCertWatcher, metricsServerOptions := setupMetricsTLS(f, tlsOpts)
 	webhookCertWatcher, webhookTLSOpts := setupWebhookTLS(f, tlsOpts)
-	mgr := createManager(f, metricsServerOptions, webhookTLSOpts)
+	shard := shardOptions(f)
+	mgr := createManager(f, shard, metricsServerOptions, webhookTLSOpts)
 	addCertWatchers(mgr, metricsCertWatcher, webhookCertWatcher)
 	setupPluginGenerator(f, mgr)
-	setupControllers(mgr)
+	setupAccessReport(f, mgr)
+	setupControllers(f, shard, mgr)
 	setupWebhooks(mgr)
 	setupHealthChecks(mgr)
 
@@ -193,7 +241,16 @@ func configureManager(f *flags) ctrl.Manager {
 
 func setupPluginGenerator(f *flags, manager ctrl.Manager) {
 	if f.argocdPluginGenAddr != "0" {
-		pluginGenerator := argocdplugingenerator.New(manager.GetClient(), f.argocdPluginGenAddr)
+		opts := argocdplugingenerator.ServerOptions{
+			Addr:         f.argocdPluginGenAddr,
+			TokenSecret:  f.argocdPluginGenTokenSecret,
+			ClientCAFile: f.argocdPluginGenClientCA,
+		}
+		if f.argocdPluginGenCertPath != "" {
+			opts.CertFile = filepath.Join(f.argocdPluginGenCertPath, f.argocdPluginGenCertName)
+			opts.KeyFile = filepath.Join(f.argocdPluginGenCertPath, f.argocdPluginGenCertKey)
+		}
+		pluginGenerator := argocdplugingenerator.New(manager.GetCache(), opts)
 		if err := manager.Add(pluginGenerator); err != nil {
 			log.Fatal().Msgf("failed to add plugin generator: %v", err)
 		}
@@ -284,7 +341,7 @@ func setupMetricsTLS(f *flags, tlsOpts []func(*tls.Config)) (*certwatcher.CertWa
 	return watcher, options
 }
 
-func createManager(f *flags, metricsServerOptions metricsserver.Options,
+func createManager(f *flags, shard controller.ShardOptions, metricsServerOptions metricsserver.Options,
 	webhookTLSOpts []func(*tls.Config)) ctrl.Manager {
 	webhookServer := webhook.NewServer(webhook.Options{TLSOpts: webhookTLSOpts})
 
@@ -294,7 +351,7 @@ func createManager(f *flags, metricsServerOptions metricsserver.Options,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: f.probeAddr,
 		LeaderElection:         f.enableLeaderElection,
-		LeaderElectionID:       "74669540.cpet.belastingdienst.nl",
+		LeaderElectionID:       leaderElectionID(shard),
 	})
 	if err != nil {
 		log.Fatal().Err(err).Msg("unable to create manager")
@@ -317,21 +374,76 @@ func addCertWatchers(mgr ctrl.Manager, metricsWatcher, webhookWatcher *certwatch
 	}
 }
 
-func setupControllers(mgr ctrl.Manager) {
+// shardOptions returns the shard options from the flags. When sharding is enabled without a shard index, the index
+// is derived from the ordinal of the hostname, which is set by a StatefulSet (e.g. opr-paas-2 handles shard 2).
+func shardOptions(f *flags) controller.ShardOptions {
+	shard := controller.ShardOptions{Count: f.shardCount, Index: f.shardIndex}
+	if !shard.Enabled() {
+		return controller.ShardOptions{}
+	}
+	if shard.Index < 0 {
+		hostname, err := os.Hostname()
+		if err != nil {
+			log.Fatal().Err(err).Msg("unable to determine hostname for shard index")
+		}
+		ordinal := hostname[strings.LastIndex(hostname, "-")+1:]
+		if shard.Index, err = strconv.Atoi(ordinal); err != nil {
+			log.Fatal().Err(err).Msgf("unable to derive shard index from hostname %s", hostname)
+		}
+	}
+	if shard.Index >= shard.Count {
+		log.Fatal().Msgf("shard index %d should be lower than shard count %d", shard.Index, shard.Count)
+	}
+	log.Info().Msgf("reconciling shard %d of %d", shard.Index, shard.Count)
+	return shard
+}
+
+// leaderElectionID returns a leader election id per shard, so that every shard elects its own leader
+func leaderElectionID(shard controller.ShardOptions) string {
+	const id = "74669540.cpet.belastingdienst.nl"
+	if !shard.Enabled() {
+		return id
+	}
+	return fmt.Sprintf("%s-shard-%d", id, shard.Index)
+}
+
+func setupControllers(f *flags, shard controller.ShardOptions, mgr ctrl.Manager) {
 	if err := config.SetupPaasConfigInformer(mgr); err != nil {
 		log.Fatal().Err(err).Msg("unable to set up PaasConfig informer")
 	}
 
-	if err := (&controller.PaasConfigReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
-		log.Fatal().Err(err).Str("controller", "PaasConfig").Msg("unable to create controller")
+	// The PaasConfig and audit controllers are not sharded, and only run for the first shard
+	if !shard.Enabled() || shard.Index == 0 {
+		if err := (&controller.PaasConfigReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			log.Fatal().Err(err).Str("controller", "PaasConfig").Msg("unable to create controller")
+		}
+		if f.auditInterval > 0 {
+			if err := (&controller.AuditReconciler{
+				Client:   mgr.GetClient(),
+				Scheme:   mgr.GetScheme(),
+				Interval: f.auditInterval,
+			}).SetupWithManager(mgr); err != nil {
+				log.Fatal().Err(err).Str("controller", "PaasConfigAudit").Msg("unable to create controller")
+			}
+		}
 	}
 
-	if err := (&controller.PaasReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+	canarySelector, err := labels.Parse(f.rolloutCanarySelector)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid PaasConfig rollout canary selector")
+	}
+	if err = (&controller.PaasReconciler{
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		MaxConcurrentReconciles: f.maxConcurrentReconciles,
+		Rollout: controller.RolloutOptions{
+			Rate:           f.rolloutRate,
+			CanarySelector: canarySelector,
+		},
+		Shard: shard,
 	}).SetupWithManager(mgr); err != nil {
 		log.Fatal().Err(err).Str("controller", "Paas").Msg("unable to create controller")
 	}
@@ -354,17 +466,26 @@ func setupWebhooks(mgr ctrl.Manager) {
 		if err := webhookv1alpha2.SetupPaasWebhookWithManager(mgr); err != nil {
 			log.Fatal().Err(err).Str("webhook", "Paas").Msg(webhookErrMsg)
 		}
+		if err := webhookv1alpha3.SetupPaasWebhookWithManager(mgr); err != nil {
+			log.Fatal().Err(err).Str("webhook", "Paas").Msg(webhookErrMsg)
+		}
 		if err := webhookv1alpha1.SetupPaasConfigWebhookWithManager(mgr); err != nil {
 			log.Fatal().Err(err).Str("webhook", "PaasConfig").Msg(webhookErrMsg)
 		}
 		if err := webhookv1alpha2.SetupPaasConfigWebhookWithManager(mgr); err != nil {
 			log.Fatal().Err(err).Str("webhook", "PaasConfig").Msg(webhookErrMsg)
 		}
+		if err := webhookv1alpha3.SetupPaasConfigWebhookWithManager(mgr); err != nil {
+			log.Fatal().Err(err).Str("webhook", "PaasConfig").Msg(webhookErrMsg)
+		}
 		if err := webhookv1alpha1.SetupPaasNsWebhookWithManager(mgr); err != nil {
 			log.Fatal().Err(err).Str("webhook", "PaasNS").Msg(webhookErrMsg)
 		}
 		if err := webhookv1alpha2.SetupPaasNsWebhookWithManager(mgr); err != nil {
 			log.Fatal().Err(err).Str("webhook", "PaasNS").Msg(webhookErrMsg)
 		}
+		if err := webhookv1alpha3.SetupPaasNsWebhookWithManager(mgr); err != nil {
+			log.Fatal().Err(err).Str("webhook", "PaasNS").Msg(webhookErrMsg)
+		}
 	}
 }
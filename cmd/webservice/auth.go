@@ -0,0 +1,229 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// userContextKey is the key of the authenticated user in the gin context
+	userContextKey = "user"
+	anonymousUser  = "anonymous"
+	bearerPrefix   = "Bearer "
+)
+
+// Actions which are logged in the audit log
+const (
+	actionEncrypt   = "encrypt"
+	actionCheckPaas = "checkpaas"
+	actionCheckNS   = "checkpaasns"
+)
+
+// errNotAuthorized is returned when a user is not a member of any of the groups of a Paas
+var errNotAuthorized = errors.New("not authorized for this paas")
+
+var (
+	_auth     *webserviceAuth
+	_authLock sync.Mutex
+)
+
+// userInfo holds the name and groups of an authenticated user
+type userInfo struct {
+	Username string
+	Groups   []string
+}
+
+// authenticator authenticates a bearer token, and returns the user it belongs to
+type authenticator interface {
+	authenticate(ctx context.Context, token string) (*userInfo, error)
+}
+
+// webserviceAuth authenticates requests, and authorizes users for the Paas they encrypt or check secrets for
+type webserviceAuth struct {
+	authn       authenticator
+	kclient     client.Reader
+	adminGroups []string
+}
+
+// getAuth returns the authentication and authorization of the webservice, or nil when authentication is disabled.
+// It is created once, and shared by all requests.
+func getAuth() *webserviceAuth {
+	_authLock.Lock()
+	defer _authLock.Unlock()
+	config := getConfig()
+	if _auth != nil || config.AuthMode == authModeNone {
+		return _auth
+	}
+	kclient, err := newKubeClient()
+	if err != nil {
+		panic(fmt.Errorf("unable to create a kubernetes client: %w", err))
+	}
+	_auth = &webserviceAuth{kclient: kclient, adminGroups: config.AdminGroups}
+	if config.AuthMode == authModeOIDC {
+		_auth.authn = newOIDCAuthenticator(config)
+	} else {
+		_auth.authn = &tokenReviewAuthenticator{kclient: kclient, audiences: config.TokenAudiences}
+	}
+	return _auth
+}
+
+// newKubeClient returns a client for the cluster the webservice is running on, which is used to read Paas resources
+// and to create TokenReviews
+func newKubeClient() (client.Client, error) {
+	scheme, err := newScheme()
+	if err != nil {
+		return nil, err
+	}
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	return client.New(restConfig, client.Options{Scheme: scheme})
+}
+
+// newScheme returns a scheme with all types the webservice reads from the cluster
+func newScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		authenticationv1.AddToScheme,
+		corev1.AddToScheme,
+		v1alpha2.AddToScheme,
+	} {
+		if err := addToScheme(scheme); err != nil {
+			return nil, err
+		}
+	}
+	return scheme, nil
+}
+
+// tokenReviewAuthenticator authenticates bearer tokens (e.g. service account tokens or OpenShift user tokens) with a
+// TokenReview against the cluster
+type tokenReviewAuthenticator struct {
+	kclient   client.Client
+	audiences []string
+}
+
+func (a *tokenReviewAuthenticator) authenticate(ctx context.Context, token string) (*userInfo, error) {
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token, Audiences: a.audiences},
+	}
+	if err := a.kclient.Create(ctx, review); err != nil {
+		return nil, fmt.Errorf("token review failed: %w", err)
+	}
+	if !review.Status.Authenticated {
+		if review.Status.Error != "" {
+			return nil, errors.New(review.Status.Error)
+		}
+		return nil, errors.New("token is not authenticated")
+	}
+	return &userInfo{Username: review.Status.User.Username, Groups: review.Status.User.Groups}, nil
+}
+
+// middleware authenticates the bearer token of a request, and stores the user in the gin context. Requests without a
+// valid token are rejected with a 401 (Unauthorized).
+func (wa *webserviceAuth) middleware(c *gin.Context) {
+	header := c.GetHeader("Authorization")
+	if !strings.HasPrefix(header, bearerPrefix) || strings.TrimPrefix(header, bearerPrefix) == "" {
+		c.Header("WWW-Authenticate", "Bearer")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "a bearer token is required"})
+		return
+	}
+	user, err := wa.authn.authenticate(c.Request.Context(), strings.TrimPrefix(header, bearerPrefix))
+	if err != nil {
+		logrus.Warnf("authentication failed from %s: %v", c.ClientIP(), err)
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid bearer token"})
+		return
+	}
+	c.Set(userContextKey, user)
+	c.Next()
+}
+
+// authorize returns nil when the user is a member of one of the admin groups, is one of the users of a group of the
+// Paas, or is a member of the cluster group of a group of the Paas. It returns errNotAuthorized otherwise, which is
+// also returned when the Paas does not exist, so that the existence of a Paas is not revealed.
+func (wa *webserviceAuth) authorize(ctx context.Context, user *userInfo, paasName string) error {
+	if slices.ContainsFunc(user.Groups, func(group string) bool { return slices.Contains(wa.adminGroups, group) }) {
+		return nil
+	}
+	if paasName == "" {
+		return errNotAuthorized
+	}
+	paas := &v1alpha2.Paas{}
+	if err := wa.kclient.Get(ctx, types.NamespacedName{Name: paasName}, paas); err != nil {
+		if apierrors.IsNotFound(err) {
+			return errNotAuthorized
+		}
+		return fmt.Errorf("failed to get paas %s: %w", paasName, err)
+	}
+	for key, group := range paas.Spec.Groups {
+		if slices.Contains(group.Users, user.Username) || slices.Contains(user.Groups, paas.GroupKey2GroupName(key)) {
+			return nil
+		}
+	}
+	return errNotAuthorized
+}
+
+// requestUser returns the authenticated user of a request, or an anonymous user when authentication is disabled
+func requestUser(c *gin.Context) *userInfo {
+	if value, exists := c.Get(userContextKey); exists {
+		if user, isUser := value.(*userInfo); isUser {
+			return user
+		}
+	}
+	return &userInfo{Username: anonymousUser}
+}
+
+// auditLog returns a log entry for the audit log, holding the user, action and Paas of a request
+func auditLog(c *gin.Context, action string, paasName string) *logrus.Entry {
+	return logrus.WithFields(logrus.Fields{
+		"audit":  true,
+		"user":   requestUser(c).Username,
+		"action": action,
+		"paas":   paasName,
+		"client": c.ClientIP(),
+	})
+}
+
+// authorizePaas checks whether the user of a request is authorized for a Paas. When the user is not authorized, the
+// attempt is logged in the audit log, the request is aborted with a 403 (Forbidden) and false is returned. Without
+// authentication, every request is authorized.
+func authorizePaas(c *gin.Context, action string, paasName string) bool {
+	auth := getAuth()
+	if auth == nil {
+		return true
+	}
+	err := auth.authorize(c.Request.Context(), requestUser(c), paasName)
+	if err == nil {
+		return true
+	}
+	auditLog(c, action, paasName).WithError(err).Warn("denied")
+	if errors.Is(err, errNotAuthorized) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": errNotAuthorized.Error()})
+		return false
+	}
+	c.AbortWithStatus(http.StatusInternalServerError)
+	return false
+}
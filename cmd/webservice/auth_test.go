@@ -0,0 +1,168 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const adminGroup = "paas-admins"
+
+// staticAuthenticator authenticates tokens which are the name of a user
+type staticAuthenticator map[string]*userInfo
+
+func (a staticAuthenticator) authenticate(_ context.Context, token string) (*userInfo, error) {
+	if user, exists := a[token]; exists {
+		return user, nil
+	}
+	return nil, errors.New("invalid token")
+}
+
+func newFakeClient(t *testing.T, funcs interceptor.Funcs) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha2.AddToScheme(scheme))
+	require.NoError(t, authenticationv1.AddToScheme(scheme))
+	paas := &v1alpha2.Paas{
+		ObjectMeta: metav1.ObjectMeta{Name: testPaasName},
+		Spec: v1alpha2.PaasSpec{
+			Groups: v1alpha2.PaasGroups{
+				"devs":  {Users: []string{"jdoe"}},
+				"ldap":  {Query: "CN=team-a,OU=groups,DC=example"},
+				"other": {},
+			},
+		},
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(paas).WithInterceptorFuncs(funcs).Build()
+}
+
+// setupAuthTest resets the webservice, and returns a router which authenticates the users jdoe (a user of a group of
+// testPaasName), member (member of the team-a group), other (member of another group) and admin
+func setupAuthTest(t *testing.T) http.Handler {
+	t.Setenv(allowedOriginsEnv, allowedOriginsVal)
+	_config = nil
+	_keys = nil
+	_, _, toDefer := makeCrypt(t)
+	t.Cleanup(toDefer)
+	_auth = &webserviceAuth{
+		authn: staticAuthenticator{
+			"jdoe":   {Username: "jdoe"},
+			"member": {Username: "member", Groups: []string{"team-a"}},
+			"other":  {Username: "other", Groups: []string{"team-b"}},
+			"admin":  {Username: "admin", Groups: []string{adminGroup}},
+		},
+		kclient:     newFakeClient(t, interceptor.Funcs{}),
+		adminGroups: []string{adminGroup},
+	}
+	t.Cleanup(func() {
+		_auth = nil
+		_config = nil
+		_keys = nil
+	})
+	return setupRouter()
+}
+
+func TestWebserviceAuth_authorize(t *testing.T) {
+	wa := &webserviceAuth{kclient: newFakeClient(t, interceptor.Funcs{}), adminGroups: []string{adminGroup}}
+	ctx := context.Background()
+
+	assert.NoError(t, wa.authorize(ctx, &userInfo{Username: "jdoe"}, testPaasName))
+	assert.NoError(t, wa.authorize(ctx, &userInfo{Username: "member", Groups: []string{"team-a"}}, testPaasName))
+	assert.NoError(t, wa.authorize(ctx, &userInfo{Username: "x", Groups: []string{testPaasName + "-other"}},
+		testPaasName))
+	assert.NoError(t, wa.authorize(ctx, &userInfo{Username: "admin", Groups: []string{adminGroup}}, "unknown"))
+
+	assert.ErrorIs(t, wa.authorize(ctx, &userInfo{Username: "other", Groups: []string{"team-b"}}, testPaasName),
+		errNotAuthorized)
+	assert.ErrorIs(t, wa.authorize(ctx, &userInfo{Username: "jdoe"}, "unknown"), errNotAuthorized)
+	assert.ErrorIs(t, wa.authorize(ctx, &userInfo{Username: "jdoe"}, ""), errNotAuthorized)
+
+	wa.kclient = newFakeClient(t, interceptor.Funcs{
+		Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+			return errors.New("connection refused")
+		},
+	})
+	err := wa.authorize(ctx, &userInfo{Username: "jdoe"}, testPaasName)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, errNotAuthorized)
+}
+
+func TestTokenReviewAuthenticator(t *testing.T) {
+	kclient := newFakeClient(t, interceptor.Funcs{
+		Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			review, isReview := obj.(*authenticationv1.TokenReview)
+			require.True(t, isReview)
+			assert.Equal(t, []string{"paas-webservice"}, review.Spec.Audiences)
+			if review.Spec.Token == "valid" {
+				review.Status.Authenticated = true
+				review.Status.User = authenticationv1.UserInfo{Username: "jdoe", Groups: []string{"team-a"}}
+			} else {
+				review.Status.Error = "token expired"
+			}
+			return nil
+		},
+	})
+	authn := &tokenReviewAuthenticator{kclient: kclient, audiences: []string{"paas-webservice"}}
+
+	user, err := authn.authenticate(context.Background(), "valid")
+	require.NoError(t, err)
+	assert.Equal(t, &userInfo{Username: "jdoe", Groups: []string{"team-a"}}, user)
+
+	_, err = authn.authenticate(context.Background(), "invalid")
+	assert.EqualError(t, err, "token expired")
+}
+
+func TestAuthenticatedRouter(t *testing.T) {
+	router := setupAuthTest(t)
+	post := func(path string, token string, body string) int {
+		req, _ := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	encryptBody := `{"paas": "` + testPaasName + `", "type": "token", "secret": "abc", "url": "` + repoName + `"}`
+
+	assert.Equal(t, http.StatusUnauthorized, post("/v2/encrypt", "", encryptBody))
+	assert.Equal(t, http.StatusUnauthorized, post("/v2/encrypt", "unknown", encryptBody))
+	assert.Equal(t, http.StatusForbidden, post("/v2/encrypt", "other", encryptBody))
+	for _, token := range []string{"jdoe", "member", "admin"} {
+		assert.Equal(t, http.StatusOK, post("/v2/encrypt", token, encryptBody), token)
+	}
+	assert.Equal(t, http.StatusForbidden,
+		post("/v1/encrypt", "other", `{"paas": "`+testPaasName+`", "secret": "abc"}`))
+	assert.Equal(t, http.StatusForbidden, post("/v2/checkpaas", "other",
+		"apiVersion: cpet.belastingdienst.nl/v1alpha2\nkind: Paas\nmetadata:\n  name: "+testPaasName))
+	assert.Equal(t, http.StatusOK, post("/v2/checkpaas", "member",
+		"apiVersion: cpet.belastingdienst.nl/v1alpha2\nkind: Paas\nmetadata:\n  name: "+testPaasName))
+	assert.Equal(t, http.StatusForbidden, post("/v2/checkpaasns?paas="+testPaasName, "other",
+		"apiVersion: cpet.belastingdienst.nl/v1alpha2\nkind: PaasNS\nmetadata:\n  name: ns1"))
+
+	// Probes and metadata are not protected
+	for _, path := range []string{"/healthz", "/readyz", "/version"} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, path)
+	}
+}
@@ -40,8 +40,8 @@ func TestCheckPaas(t *testing.T) {
 	getConfig()
 	_config.PublicKeyPath = pub.Name()
 	_config.PrivateKeyPath = priv.Name()
-	assert.Nil(t, _crypt)
-	rsa := getRsa(paasName)
+	assert.Nil(t, _keys)
+	rsa := mustGetRsa(t, paasName)
 
 	encrypted, err := rsa.Encrypt([]byte("My test string"))
 	require.NoError(t, err)
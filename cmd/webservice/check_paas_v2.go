@@ -0,0 +1,226 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/belastingdienst/opr-paas-crypttool/pkg/crypt"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	paasKind   = "Paas"
+	paasNSKind = "PaasNS"
+	// paasQueryParam is the query parameter holding the name of the Paas a PaasNS belongs to
+	paasQueryParam = "paas"
+)
+
+// CheckPaasV2 checks whether all encrypted values in a v1alpha2 Paas can be decrypted using the provided crypt. This
+// includes the secrets of the Paas, its capabilities and its namespaces. It returns a result for every encrypted value,
+// ordered by the field path, which is the same as used by the v1alpha2 webhook.
+func CheckPaasV2(cryptObj *crypt.Crypt, paas *v1alpha2.Paas) []RestCheckFieldResult {
+	specPath := field.NewPath("spec")
+	results := checkSecrets(cryptObj, paas.Name, paas.Spec.Secrets, specPath.Child("secrets"))
+	for capName, capability := range paas.Spec.Capabilities {
+		results = append(results, checkSecrets(cryptObj, paas.Name, capability.Secrets,
+			specPath.Child("capabilities").Key(capName).Child("secrets"))...)
+	}
+	for nsName, ns := range paas.Spec.Namespaces {
+		results = append(results, checkSecrets(cryptObj, paas.Name, ns.Secrets,
+			specPath.Child("namespaces").Key(nsName).Child("secrets"))...)
+	}
+	sortResults(results)
+	return results
+}
+
+// CheckPaasNSV2 checks whether all encrypted values in a v1alpha2 PaasNS can be decrypted using the provided crypt,
+// which should be created for the Paas the PaasNS belongs to. It returns a result for every encrypted value.
+func CheckPaasNSV2(cryptObj *crypt.Crypt, paasns *v1alpha2.PaasNS) []RestCheckFieldResult {
+	results := checkSecrets(cryptObj, paasns.Name, paasns.Spec.Secrets, field.NewPath("spec").Child("secrets"))
+	sortResults(results)
+	return results
+}
+
+// checkSecrets checks whether all encrypted values in a map of secrets can be decrypted
+func checkSecrets(
+	cryptObj *crypt.Crypt,
+	objName string,
+	secrets map[string]string,
+	basePath *field.Path,
+) []RestCheckFieldResult {
+	results := make([]RestCheckFieldResult, 0, len(secrets))
+	for key, secret := range secrets {
+		fldPath := basePath.Key(key).String()
+		decrypted, err := cryptObj.Decrypt(secret)
+		if err != nil {
+			logrus.Errorf("%s: %s, error: %v", objName, fldPath, err)
+			results = append(results, RestCheckFieldResult{
+				Field: fldPath,
+				Error: fmt.Sprintf("cannot be decrypted: %s", err),
+			})
+			continue
+		}
+		logrus.Infof("%s: %s, checksum: %s, len %d", objName, fldPath, hashData(decrypted), len(decrypted))
+		results = append(results, RestCheckFieldResult{Field: fldPath, Decrypted: true})
+	}
+	return results
+}
+
+// sortResults orders results by field path
+func sortResults(results []RestCheckFieldResult) {
+	slices.SortFunc(results, func(a, b RestCheckFieldResult) int {
+		return strings.Compare(a.Field, b.Field)
+	})
+}
+
+// bindV2Object decodes a v1alpha2 object (in YAML or JSON) from the request body, and verifies its apiVersion and kind
+func bindV2Object(c *gin.Context, kind string, obj runtime.Object) error {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read body: %w", err)
+	}
+	if err = yaml.Unmarshal(body, obj); err != nil {
+		return fmt.Errorf("invalid %s: %w", kind, err)
+	}
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if gvk.GroupVersion() != v1alpha2.GroupVersion || gvk.Kind != kind {
+		return fmt.Errorf("expected apiVersion %s and kind %s", v1alpha2.GroupVersion, kind)
+	}
+	return nil
+}
+
+// respondCheckV2 returns the result of a v2 check request, where the status is 422 (Unprocessable Entity) when any
+// of the encrypted values cannot be decrypted
+func respondCheckV2(c *gin.Context, action string, result RestCheckResultV2) {
+	result.Decrypted = true
+	for _, fieldResult := range result.Results {
+		result.Decrypted = result.Decrypted && fieldResult.Decrypted
+	}
+	auditLog(c, action, result.PaasName).WithFields(logrus.Fields{
+		"kind":      result.Kind,
+		"name":      result.Name,
+		"decrypted": result.Decrypted,
+	}).Infof("checked %s", result.Kind)
+	if !result.Decrypted {
+		c.IndentedJSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// v1CheckPaasV2 checks whether a v1alpha2 Paas, delivered in a v1 checkPaas request, can be decrypted. The result is
+// returned in the v1 format, where the errors of all encrypted values are combined.
+func v1CheckPaasV2(c *gin.Context, body []byte) {
+	var input RestCheckPaasInputV2
+	if err := json.Unmarshal(body, &input); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, RestCheckPaasResult{"", false, err.Error()})
+		return
+	}
+	if !authorizePaas(c, actionCheckPaas, input.Paas.Name) {
+		return
+	}
+	rsa := getRsaOrAbort(c, input.Paas.Name)
+	if rsa == nil {
+		return
+	}
+	var allErrors []string
+	for _, result := range CheckPaasV2(rsa, &input.Paas) {
+		if !result.Decrypted {
+			allErrors = append(allErrors,
+				fmt.Sprintf("%s: .%s, error: %s", input.Paas.Name, result.Field, result.Error))
+		}
+	}
+	auditLog(c, actionCheckPaas, input.Paas.Name).WithField("decrypted", len(allErrors) == 0).Info("checked paas")
+	if len(allErrors) > 0 {
+		c.IndentedJSON(http.StatusUnprocessableEntity, RestCheckPaasResult{
+			PaasName:  input.Paas.Name,
+			Decrypted: false,
+			Error:     strings.Join(allErrors, " , "),
+		})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, RestCheckPaasResult{
+		PaasName:  input.Paas.Name,
+		Decrypted: true,
+	})
+}
+
+// v2CheckPaas checks whether all encrypted values in a v1alpha2 Paas (in YAML or JSON) can be decrypted
+func v2CheckPaas(c *gin.Context) {
+	var paas v1alpha2.Paas
+	err := bindV2Object(c, paasKind, &paas)
+	if err == nil && paas.Name == "" {
+		err = errors.New("metadata.name is required")
+	}
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, RestCheckResultV2{
+			Kind:    paasKind,
+			Error:   err.Error(),
+			Results: []RestCheckFieldResult{},
+		})
+		return
+	}
+	if !authorizePaas(c, actionCheckPaas, paas.Name) {
+		return
+	}
+	rsa := getRsaOrAbort(c, paas.Name)
+	if rsa == nil {
+		return
+	}
+	respondCheckV2(c, actionCheckPaas, RestCheckResultV2{
+		Kind:     paasKind,
+		Name:     paas.Name,
+		PaasName: paas.Name,
+		Results:  CheckPaasV2(rsa, &paas),
+	})
+}
+
+// v2CheckPaasNS checks whether all encrypted values in a v1alpha2 PaasNS (in YAML or JSON) can be decrypted. As the
+// values are encrypted for the Paas the PaasNS belongs to, the name of the Paas should be passed as the paas query
+// parameter (or in the deprecated spec.paas field).
+func v2CheckPaasNS(c *gin.Context) {
+	var paasns v1alpha2.PaasNS
+	err := bindV2Object(c, paasNSKind, &paasns)
+	paasName := c.DefaultQuery(paasQueryParam, paasns.Spec.Paas)
+	if err == nil && paasName == "" {
+		err = fmt.Errorf("the name of the Paas is required as the %s query parameter", paasQueryParam)
+	}
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, RestCheckResultV2{
+			Kind:    paasNSKind,
+			Name:    paasns.Name,
+			Error:   err.Error(),
+			Results: []RestCheckFieldResult{},
+		})
+		return
+	}
+	if !authorizePaas(c, actionCheckNS, paasName) {
+		return
+	}
+	rsa := getRsaOrAbort(c, paasName)
+	if rsa == nil {
+		return
+	}
+	respondCheckV2(c, actionCheckNS, RestCheckResultV2{
+		Kind:     paasNSKind,
+		Name:     paasns.Name,
+		PaasName: paasName,
+		Results:  CheckPaasNSV2(rsa, &paasns),
+	})
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const undecryptable = "bm90RGVjcnlwdGFibGU="
+
+// setupV2Test resets the webservice, and returns a router and a value which is encrypted for testPaasName
+func setupV2Test(t *testing.T) (router http.Handler, encrypted string) {
+	t.Setenv(allowedOriginsEnv, allowedOriginsVal)
+	_config = nil
+	_keys = nil
+	_, _, toDefer := makeCrypt(t)
+	t.Cleanup(toDefer)
+
+	encrypted, err := mustGetRsa(t, testPaasName).Encrypt([]byte("My test string"))
+	require.NoError(t, err)
+	return setupRouter(), encrypted
+}
+
+func postV2(router http.Handler, path string, body string) (*httptest.ResponseRecorder, RestCheckResultV2) {
+	req, _ := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var result RestCheckResultV2
+	_ = json.Unmarshal(w.Body.Bytes(), &result)
+	return w, result
+}
+
+func TestCheckPaasV2(t *testing.T) {
+	_, encrypted := setupV2Test(t)
+	paas := &v1alpha2.Paas{
+		ObjectMeta: metav1.ObjectMeta{Name: testPaasName},
+		Spec: v1alpha2.PaasSpec{
+			Secrets: map[string]string{repoName: encrypted},
+			Capabilities: v1alpha2.PaasCapabilities{
+				"argocd": {Secrets: map[string]string{repoName: undecryptable}},
+			},
+			Namespaces: v1alpha2.PaasNamespaces{
+				"ns1": {Secrets: map[string]string{repoName: encrypted}},
+			},
+		},
+	}
+
+	results := CheckPaasV2(mustGetRsa(t, testPaasName), paas)
+	require.Len(t, results, 3)
+	assert.Equal(t, "spec.capabilities[argocd].secrets["+repoName+"]", results[0].Field)
+	assert.False(t, results[0].Decrypted)
+	assert.Contains(t, results[0].Error, "cannot be decrypted")
+	assert.Equal(t, RestCheckFieldResult{
+		Field:     "spec.namespaces[ns1].secrets[" + repoName + "]",
+		Decrypted: true,
+	}, results[1])
+	assert.Equal(t, RestCheckFieldResult{Field: "spec.secrets[" + repoName + "]", Decrypted: true}, results[2])
+}
+
+//revive:disable-next-line
+func Test_v2CheckPaas(t *testing.T) {
+	router, encrypted := setupV2Test(t)
+
+	yamlPaas := fmt.Sprintf(`
+apiVersion: cpet.belastingdienst.nl/v1alpha2
+kind: Paas
+metadata:
+  name: %s
+spec:
+  secrets:
+    %s: %s
+  namespaces:
+    ns1:
+      secrets:
+        %s: %s
+`, testPaasName, repoName, encrypted, repoName, encrypted)
+	w, result := postV2(router, "/v2/checkpaas", yamlPaas)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, result.Decrypted)
+	assert.Equal(t, paasKind, result.Kind)
+	assert.Equal(t, testPaasName, result.PaasName)
+	assert.Len(t, result.Results, 2)
+
+	jsonPaas, _ := json.Marshal(v1alpha2.Paas{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.GroupVersion.String(), Kind: paasKind},
+		ObjectMeta: metav1.ObjectMeta{Name: testPaasName},
+		Spec: v1alpha2.PaasSpec{
+			Capabilities: v1alpha2.PaasCapabilities{
+				"argocd": {Secrets: map[string]string{repoName: undecryptable}},
+			},
+		},
+	})
+	w, result = postV2(router, "/v2/checkpaas", string(jsonPaas))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.False(t, result.Decrypted)
+	require.Len(t, result.Results, 1)
+	assert.Equal(t, "spec.capabilities[argocd].secrets["+repoName+"]", result.Results[0].Field)
+
+	for name, body := range map[string]string{
+		"v1alpha1":   "apiVersion: cpet.belastingdienst.nl/v1alpha1\nkind: Paas\nmetadata:\n  name: paas",
+		"wrong kind": "apiVersion: cpet.belastingdienst.nl/v1alpha2\nkind: PaasNS\nmetadata:\n  name: paas",
+		"no name":    "apiVersion: cpet.belastingdienst.nl/v1alpha2\nkind: Paas",
+		"invalid":    "{",
+	} {
+		w, result = postV2(router, "/v2/checkpaas", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, name)
+		assert.NotEmpty(t, result.Error, name)
+	}
+}
+
+//revive:disable-next-line
+func Test_v2CheckPaasNS(t *testing.T) {
+	router, encrypted := setupV2Test(t)
+
+	yamlPaasNS := fmt.Sprintf(`
+apiVersion: cpet.belastingdienst.nl/v1alpha2
+kind: PaasNS
+metadata:
+  name: ns1
+  namespace: %s-ns1
+spec:
+  secrets:
+    %s: %s
+`, testPaasName, repoName, encrypted)
+	w, result := postV2(router, "/v2/checkpaasns?paas="+testPaasName, yamlPaasNS)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, RestCheckResultV2{
+		Kind:      paasNSKind,
+		Name:      "ns1",
+		PaasName:  testPaasName,
+		Decrypted: true,
+		Results:   []RestCheckFieldResult{{Field: "spec.secrets[" + repoName + "]", Decrypted: true}},
+	}, result)
+
+	// Values are encrypted for a specific Paas
+	w, result = postV2(router, "/v2/checkpaasns?paas=otherPaas", yamlPaasNS)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.False(t, result.Decrypted)
+
+	w, result = postV2(router, "/v2/checkpaasns", yamlPaasNS)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, result.Error, "query parameter")
+}
+
+//revive:disable-next-line
+func Test_v1CheckPaasV1alpha2(t *testing.T) {
+	router, encrypted := setupV2Test(t)
+
+	input, _ := json.Marshal(RestCheckPaasInputV2{Paas: v1alpha2.Paas{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.GroupVersion.String(), Kind: paasKind},
+		ObjectMeta: metav1.ObjectMeta{Name: testPaasName},
+		Spec: v1alpha2.PaasSpec{
+			Secrets: map[string]string{repoName: encrypted},
+			Capabilities: v1alpha2.PaasCapabilities{
+				"sso": {Secrets: map[string]string{repoName: undecryptable}},
+			},
+		},
+	}})
+	req, _ := http.NewRequest(http.MethodPost, "/v1/checkpaas", strings.NewReader(string(input)))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var result RestCheckPaasResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, testPaasName, result.PaasName)
+	assert.False(t, result.Decrypted)
+	assert.Contains(t, result.Error, testPaasName+": .spec.capabilities[sso].secrets["+repoName+"], error:")
+	assert.NotContains(t, result.Error, ".spec.secrets")
+}
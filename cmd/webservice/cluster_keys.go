@@ -0,0 +1,353 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/belastingdienst/opr-paas-crypttool/pkg/crypt"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// publicKeyConfigMapKey is the key of the public key in the public key ConfigMap
+	publicKeyConfigMapKey = "publicKey"
+	publicKeyFileName     = "publicKey"
+	fileModeUserReadWrite = 0o600
+)
+
+// errSecretNotFound is reported until the DecryptKeysSecret has been read
+var errSecretNotFound = errors.New("decrypt keys secret not found")
+
+// clusterKeys keeps the keys of the webservice in sync with the cluster, using informers. The private keys are read
+// from the DecryptKeysSecret of the active PaasConfig, and the public key from a ConfigMap. As the crypts read the
+// public key from a file, it is written to a (private) temporary directory.
+type clusterKeys struct {
+	store         *keyStore
+	publicKeyRef  types.NamespacedName
+	publicKeyPath string
+	// watchSecret starts an informer for the DecryptKeysSecret, which runs until the context is cancelled
+	watchSecret func(ctx context.Context, ref v1alpha2.NamespacedName) error
+
+	mutex       sync.Mutex
+	ctx         context.Context
+	secretRef   v1alpha2.NamespacedName
+	stopSecret  context.CancelFunc
+	hasConfig   bool
+	privateKeys crypt.PrivateKeys
+	secretErr   error
+	publicKey   []byte
+}
+
+// startClusterKeys starts loading the keys from the cluster. Failures are reported through the keyStore.
+func startClusterKeys(ctx context.Context, store *keyStore, config *wsConfig) {
+	ref, err := parseNamespacedName(config.PublicKeyConfigMap)
+	if err != nil {
+		store.setError(err)
+		return
+	}
+	dir, err := os.MkdirTemp("", "paas-webservice")
+	if err != nil {
+		store.setError(fmt.Errorf("unable to create a directory for the public key: %w", err))
+		return
+	}
+	ck := &clusterKeys{
+		store:         store,
+		publicKeyRef:  ref,
+		publicKeyPath: filepath.Join(dir, publicKeyFileName),
+		ctx:           ctx,
+		secretErr:     errSecretNotFound,
+	}
+	ck.watchSecret = ck.startSecretInformer
+	go func() {
+		if err = ck.start(ctx); err != nil {
+			store.setError(err)
+		}
+	}()
+}
+
+// parseNamespacedName parses a reference in the form namespace/name
+func parseNamespacedName(ref string) (types.NamespacedName, error) {
+	namespace, name, found := strings.Cut(ref, string(types.Separator))
+	if !found || namespace == "" || name == "" {
+		return types.NamespacedName{}, fmt.Errorf("invalid reference %q, expected namespace/name", ref)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// start runs the informers for PaasConfigs and the public key ConfigMap until the context is cancelled
+func (ck *clusterKeys) start(ctx context.Context) error {
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return fmt.Errorf("unable to get kubernetes config: %w", err)
+	}
+	scheme, err := newScheme()
+	if err != nil {
+		return err
+	}
+	informers, err := cache.New(restConfig, cache.Options{
+		Scheme: scheme,
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.ConfigMap{}: namespacedNameSelector(ck.publicKeyRef.Namespace, ck.publicKeyRef.Name),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create informers: %w", err)
+	}
+	if err = addEventHandler(ctx, informers, &v1alpha2.PaasConfig{}, ck.paasConfigEventHandler()); err != nil {
+		return err
+	}
+	if err = addEventHandler(ctx, informers, &corev1.ConfigMap{}, ck.configMapEventHandler()); err != nil {
+		return err
+	}
+	log.Printf("watching active PaasConfig and public key configmap %s", ck.publicKeyRef)
+	go func() {
+		// Report what is missing once the informers have synced, e.g. when there is no active PaasConfig
+		if informers.WaitForCacheSync(ctx) {
+			ck.mutex.Lock()
+			defer ck.mutex.Unlock()
+			ck.update()
+		}
+	}()
+	return informers.Start(ctx)
+}
+
+// startSecretInformer starts an informer which only watches the DecryptKeysSecret
+func (ck *clusterKeys) startSecretInformer(ctx context.Context, ref v1alpha2.NamespacedName) error {
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return fmt.Errorf("unable to get kubernetes config: %w", err)
+	}
+	scheme, err := newScheme()
+	if err != nil {
+		return err
+	}
+	informers, err := cache.New(restConfig, cache.Options{
+		Scheme:   scheme,
+		ByObject: map[client.Object]cache.ByObject{&corev1.Secret{}: namespacedNameSelector(ref.Namespace, ref.Name)},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create secret informer: %w", err)
+	}
+	if err = addEventHandler(ctx, informers, &corev1.Secret{}, ck.secretEventHandler()); err != nil {
+		return err
+	}
+	log.Printf("watching decrypt keys secret %s/%s", ref.Namespace, ref.Name)
+	go func() {
+		if startErr := informers.Start(ctx); startErr != nil {
+			ck.store.setError(fmt.Errorf("secret informer failed: %w", startErr))
+		}
+	}()
+	return nil
+}
+
+// namespacedNameSelector limits an informer to a single object
+func namespacedNameSelector(namespace string, name string) cache.ByObject {
+	return cache.ByObject{
+		Namespaces: map[string]cache.Config{namespace: {}},
+		Field:      fields.OneTermEqualSelector("metadata.name", name),
+	}
+}
+
+// addEventHandler adds an event handler to the informer for obj
+func addEventHandler(
+	ctx context.Context,
+	informers cache.Informers,
+	obj client.Object,
+	handler toolscache.ResourceEventHandler,
+) error {
+	informer, err := informers.GetInformer(ctx, obj)
+	if err != nil {
+		return fmt.Errorf("failed to get informer for %T: %w", obj, err)
+	}
+	if _, err = informer.AddEventHandler(handler); err != nil {
+		return fmt.Errorf("failed to add event handler for %T: %w", obj, err)
+	}
+	return nil
+}
+
+// unwrapTombstone returns the deleted object of a tombstone
+func unwrapTombstone(obj interface{}) interface{} {
+	if tombstone, isTombstone := obj.(toolscache.DeletedFinalStateUnknown); isTombstone {
+		return tombstone.Obj
+	}
+	return obj
+}
+
+// paasConfigEventHandler follows the DecryptKeysSecret of the active PaasConfig. The keys are dropped when the active
+// PaasConfig is deleted or becomes inactive.
+func (ck *clusterKeys) paasConfigEventHandler() toolscache.ResourceEventHandler {
+	onActive := func(obj interface{}) {
+		if cfg, isConfig := obj.(*v1alpha2.PaasConfig); isConfig && cfg.IsActive() {
+			ck.setActiveConfig(&cfg.Spec.DecryptKeysSecret)
+		}
+	}
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { onActive(obj) },
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldCfg, oldIsConfig := oldObj.(*v1alpha2.PaasConfig)
+			newCfg, newIsConfig := newObj.(*v1alpha2.PaasConfig)
+			if oldIsConfig && newIsConfig && oldCfg.IsActive() && !newCfg.IsActive() {
+				ck.setActiveConfig(nil)
+				return
+			}
+			onActive(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if cfg, isConfig := unwrapTombstone(obj).(*v1alpha2.PaasConfig); isConfig && cfg.IsActive() {
+				ck.setActiveConfig(nil)
+			}
+		},
+	}
+}
+
+// setActiveConfig (re)starts the informer for the DecryptKeysSecret when the reference changes. A nil reference means
+// that there is no active PaasConfig.
+func (ck *clusterKeys) setActiveConfig(ref *v1alpha2.NamespacedName) {
+	ck.mutex.Lock()
+	defer ck.mutex.Unlock()
+	if ref != nil && ck.hasConfig && *ref == ck.secretRef {
+		return
+	}
+	if ck.stopSecret != nil {
+		ck.stopSecret()
+		ck.stopSecret = nil
+	}
+	ck.hasConfig = ref != nil
+	ck.secretRef = v1alpha2.NamespacedName{}
+	ck.privateKeys = nil
+	ck.secretErr = errSecretNotFound
+	if ref != nil {
+		ck.secretRef = *ref
+		secretCtx, cancel := context.WithCancel(ck.ctx)
+		if err := ck.watchSecret(secretCtx, *ref); err != nil {
+			cancel()
+			ck.secretErr = err
+		} else {
+			ck.stopSecret = cancel
+		}
+	}
+	ck.update()
+}
+
+// secretEventHandler loads the private keys from the DecryptKeysSecret
+func (ck *clusterKeys) secretEventHandler() toolscache.ResourceEventHandler {
+	onSecret := func(obj interface{}) {
+		secret, isSecret := obj.(*corev1.Secret)
+		if !isSecret {
+			return
+		}
+		ck.mutex.Lock()
+		defer ck.mutex.Unlock()
+		if secret.Namespace != ck.secretRef.Namespace || secret.Name != ck.secretRef.Name {
+			return
+		}
+		ck.privateKeys, ck.secretErr = crypt.NewPrivateKeysFromSecretData(secret.Data)
+		ck.update()
+	}
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc: onSecret,
+		UpdateFunc: func(_, newObj interface{}) {
+			onSecret(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			secret, isSecret := unwrapTombstone(obj).(*corev1.Secret)
+			if !isSecret {
+				return
+			}
+			ck.mutex.Lock()
+			defer ck.mutex.Unlock()
+			if secret.Namespace == ck.secretRef.Namespace && secret.Name == ck.secretRef.Name {
+				ck.privateKeys = nil
+				ck.secretErr = errors.New("decrypt keys secret was deleted")
+				ck.update()
+			}
+		},
+	}
+}
+
+// configMapEventHandler loads the public key from the public key ConfigMap
+func (ck *clusterKeys) configMapEventHandler() toolscache.ResourceEventHandler {
+	onConfigMap := func(obj interface{}, deleted bool) {
+		configMap, isConfigMap := unwrapTombstone(obj).(*corev1.ConfigMap)
+		if !isConfigMap || configMap.Namespace != ck.publicKeyRef.Namespace || configMap.Name != ck.publicKeyRef.Name {
+			return
+		}
+		ck.mutex.Lock()
+		defer ck.mutex.Unlock()
+		ck.publicKey = nil
+		if !deleted {
+			ck.publicKey = []byte(configMap.Data[publicKeyConfigMapKey])
+		}
+		ck.update()
+	}
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { onConfigMap(obj, false) },
+		UpdateFunc: func(_, newObj interface{}) {
+			onConfigMap(newObj, false)
+		},
+		DeleteFunc: func(obj interface{}) { onConfigMap(obj, true) },
+	}
+}
+
+// update writes the public key and replaces the keys in the keyStore, or reports why the keys cannot be loaded. It
+// should be called with the mutex locked.
+func (ck *clusterKeys) update() {
+	var err error
+	switch {
+	case !ck.hasConfig:
+		err = errors.New("no active PaasConfig")
+	case ck.secretErr != nil:
+		err = fmt.Errorf("decrypt keys secret %s/%s: %w", ck.secretRef.Namespace, ck.secretRef.Name, ck.secretErr)
+	case len(ck.publicKey) == 0:
+		err = fmt.Errorf("no %s in configmap %s", publicKeyConfigMapKey, ck.publicKeyRef)
+	default:
+		err = validatePublicKey(ck.publicKey)
+	}
+	if err == nil {
+		err = writeFileAtomic(ck.publicKeyPath, ck.publicKey)
+	}
+	if err != nil {
+		ck.store.setError(err)
+		return
+	}
+	ck.store.setKeys(ck.privateKeys, ck.publicKeyPath)
+}
+
+// writeFileAtomic writes data to a temporary file, and renames it to path, so that readers never read a partial file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), fileModeUserReadWrite); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+)
+
+// newTestClusterKeys returns clusterKeys which does not start informers, and keeps track of the watched secrets
+func newTestClusterKeys(t *testing.T) (ck *clusterKeys, watched *[]v1alpha2.NamespacedName) {
+	watched = &[]v1alpha2.NamespacedName{}
+	ck = &clusterKeys{
+		store:         newKeyStore(),
+		publicKeyRef:  types.NamespacedName{Namespace: "paas-system", Name: "paas-secrets-publickey"},
+		publicKeyPath: filepath.Join(t.TempDir(), publicKeyFileName),
+		ctx:           context.Background(),
+		secretErr:     errSecretNotFound,
+		watchSecret: func(_ context.Context, ref v1alpha2.NamespacedName) error {
+			*watched = append(*watched, ref)
+			return nil
+		},
+	}
+	return ck, watched
+}
+
+func newTestPaasConfig(name string, active bool, secret v1alpha2.NamespacedName) *v1alpha2.PaasConfig {
+	cfg := &v1alpha2.PaasConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v1alpha2.PaasConfigSpec{DecryptKeysSecret: secret},
+	}
+	if active {
+		cfg.Status.Conditions = []metav1.Condition{
+			{Type: v1alpha2.TypeActivePaasConfig, Status: metav1.ConditionTrue},
+		}
+	}
+	return cfg
+}
+
+func TestClusterKeys(t *testing.T) {
+	pub, priv, toDefer := makeCrypt(t)
+	defer toDefer()
+	publicKey, err := os.ReadFile(pub.Name())
+	require.NoError(t, err)
+	privateKey, err := os.ReadFile(priv.Name())
+	require.NoError(t, err)
+
+	ck, watched := newTestClusterKeys(t)
+	configs := ck.paasConfigEventHandler()
+	secrets := ck.secretEventHandler()
+	configMaps := ck.configMapEventHandler()
+	secretRef := v1alpha2.NamespacedName{Namespace: "paas-system", Name: "paas-secrets"}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: secretRef.Namespace, Name: secretRef.Name},
+		Data:       map[string][]byte{"privateKey0": privateKey},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ck.publicKeyRef.Namespace, Name: ck.publicKeyRef.Name},
+		Data:       map[string]string{publicKeyConfigMapKey: string(publicKey)},
+	}
+
+	// Inactive PaasConfigs are ignored
+	configs.OnAdd(newTestPaasConfig("inactive", false, secretRef), true)
+	assert.Empty(t, *watched)
+	assert.ErrorIs(t, ck.store.ready(), errKeysNotLoaded)
+
+	active := newTestPaasConfig("active", true, secretRef)
+	configs.OnAdd(active, true)
+	assert.Equal(t, []v1alpha2.NamespacedName{secretRef}, *watched)
+	assert.ErrorIs(t, ck.store.ready(), errSecretNotFound)
+
+	secrets.OnAdd(secret, true)
+	assert.ErrorContains(t, ck.store.ready(), "no publicKey in configmap")
+
+	configMaps.OnAdd(configMap, true)
+	require.NoError(t, ck.store.ready())
+	c, err := ck.store.getCrypt(testPaasName)
+	require.NoError(t, err)
+	encrypted, err := c.Encrypt([]byte("My test string"))
+	require.NoError(t, err)
+	_, err = c.Decrypt(encrypted)
+	require.NoError(t, err)
+
+	// An update of the active PaasConfig which does not change the secret does not restart the secret informer
+	configs.OnUpdate(active, active)
+	assert.Len(t, *watched, 1)
+	require.NoError(t, ck.store.ready())
+
+	// An invalid public key is reported
+	invalid := configMap.DeepCopy()
+	invalid.Data[publicKeyConfigMapKey] = "invalid"
+	configMaps.OnUpdate(configMap, invalid)
+	assert.Error(t, ck.store.ready())
+	configMaps.OnUpdate(invalid, configMap)
+	require.NoError(t, ck.store.ready())
+
+	// Deleting the secret (with a tombstone) removes the keys
+	secrets.OnDelete(toolscache.DeletedFinalStateUnknown{Key: "paas-system/paas-secrets", Obj: secret})
+	assert.ErrorContains(t, ck.store.ready(), "was deleted")
+	secrets.OnAdd(secret, false)
+	require.NoError(t, ck.store.ready())
+
+	// Another secret is watched when the active PaasConfig refers to it
+	otherRef := v1alpha2.NamespacedName{Namespace: "paas-system", Name: "other-secrets"}
+	configs.OnUpdate(active, newTestPaasConfig("active", true, otherRef))
+	assert.Equal(t, []v1alpha2.NamespacedName{secretRef, otherRef}, *watched)
+	assert.ErrorIs(t, ck.store.ready(), errSecretNotFound)
+	secrets.OnAdd(secret, false)
+	assert.ErrorIs(t, ck.store.ready(), errSecretNotFound, "secrets which are not referenced are ignored")
+
+	configs.OnDelete(newTestPaasConfig("active", true, otherRef))
+	assert.ErrorContains(t, ck.store.ready(), "no active PaasConfig")
+
+	// The keys are dropped when the active PaasConfig becomes inactive, and loaded again when it is reactivated
+	configs.OnAdd(active, false)
+	secrets.OnAdd(secret, false)
+	require.NoError(t, ck.store.ready())
+	inactive := newTestPaasConfig("active", false, secretRef)
+	configs.OnUpdate(active, inactive)
+	assert.ErrorContains(t, ck.store.ready(), "no active PaasConfig")
+	configs.OnUpdate(inactive, inactive)
+	assert.ErrorContains(t, ck.store.ready(), "no active PaasConfig")
+	configs.OnUpdate(inactive, active)
+	assert.ErrorIs(t, ck.store.ready(), errSecretNotFound)
+	assert.Len(t, *watched, 4)
+}
+
+func TestParseNamespacedName(t *testing.T) {
+	ref, err := parseNamespacedName("paas-system/paas-secrets-publickey")
+	require.NoError(t, err)
+	assert.Equal(t, types.NamespacedName{Namespace: "paas-system", Name: "paas-secrets-publickey"}, ref)
+
+	for _, invalid := range []string{"", "paas-secrets-publickey", "/paas-secrets-publickey", "paas-system/"} {
+		_, err = parseNamespacedName(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
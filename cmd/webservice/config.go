@@ -22,16 +22,52 @@ const (
 	endpointEnv         = "PAAS_ENDPOINT"
 	defaultEndpointPort = 8080
 	allowedOriginsEnv   = "PAAS_WS_ALLOWED_ORIGINS" // comma separated
+	authModeEnv         = "PAAS_WS_AUTH"
+	oidcIssuerEnv       = "PAAS_WS_OIDC_ISSUER"
+	oidcClientIDEnv     = "PAAS_WS_OIDC_CLIENT_ID"
+	oidcUsernameEnv     = "PAAS_WS_OIDC_USERNAME_CLAIM"
+	oidcGroupsEnv       = "PAAS_WS_OIDC_GROUPS_CLAIM"
+	tokenAudiencesEnv   = "PAAS_WS_TOKENREVIEW_AUDIENCES" // comma separated
+	adminGroupsEnv      = "PAAS_WS_ADMIN_GROUPS"          // comma separated
+	keySourceEnv        = "PAAS_WS_KEY_SOURCE"
+	publicKeyCMEnv      = "PAAS_WS_PUBLIC_KEY_CONFIGMAP" // namespace/name
+	defaultUsername     = "sub"
+	defaultGroups       = "groups"
 	validHostnameSize   = 63
 	maxPort             = 65363
 )
 
+// Sources of the keys of the webservice
+const (
+	keySourceFiles   = "files"
+	keySourceCluster = "cluster"
+)
+
+// Authentication modes of the webservice
+const (
+	authModeNone        = "none"
+	authModeOIDC        = "oidc"
+	authModeTokenReview = "tokenreview"
+)
+
 type wsConfig struct {
 	PublicKeyPath string
 	// comma separated list of privateKeyPaths
 	PrivateKeyPath string
-	Endpoint       string
-	AllowedOrigins []string
+	// KeySource is one of files (default) or cluster
+	KeySource string
+	// PublicKeyConfigMap is the namespace/name of the ConfigMap with the public key, when the KeySource is cluster
+	PublicKeyConfigMap string
+	Endpoint           string
+	AllowedOrigins     []string
+	// AuthMode is one of none (default), oidc or tokenreview
+	AuthMode          string
+	OIDCIssuer        string
+	OIDCClientID      string
+	OIDCUsernameClaim string
+	OIDCGroupsClaim   string
+	TokenAudiences    []string
+	AdminGroups       []string
 }
 
 func formatEndpoint(endpoint string) string {
@@ -83,15 +119,68 @@ func newWSConfig() (config wsConfig) {
 		config.PrivateKeyPath = defaultPrivatePath
 	}
 
+	config.setKeySourceConfig()
+
 	config.Endpoint = formatEndpoint(os.Getenv(endpointEnv))
 	value := os.Getenv(allowedOriginsEnv)
 	if strings.TrimSpace(value) != "" {
 		config.AllowedOrigins = getOriginsAsSlice(value)
 	}
+	config.setAuthConfig()
 
 	return config
 }
 
+// setKeySourceConfig reads where the keys are loaded from, and panics when it is invalid
+func (config *wsConfig) setKeySourceConfig() {
+	config.KeySource = strings.ToLower(getEnvOrDefault(keySourceEnv, keySourceFiles))
+	config.PublicKeyConfigMap = strings.TrimSpace(os.Getenv(publicKeyCMEnv))
+	switch config.KeySource {
+	case keySourceFiles:
+	case keySourceCluster:
+		if _, err := parseNamespacedName(config.PublicKeyConfigMap); err != nil {
+			panic(fmt.Errorf("%s is required for key source %s: %w", publicKeyCMEnv, keySourceCluster, err))
+		}
+	default:
+		panic(fmt.Errorf("invalid %s %s, should be one of %s or %s", keySourceEnv, config.KeySource,
+			keySourceFiles, keySourceCluster))
+	}
+}
+
+// setAuthConfig reads the authentication and authorization settings, and panics when they are invalid
+func (config *wsConfig) setAuthConfig() {
+	config.AuthMode = strings.ToLower(strings.TrimSpace(os.Getenv(authModeEnv)))
+	if config.AuthMode == "" {
+		config.AuthMode = authModeNone
+	}
+	config.OIDCIssuer = strings.TrimSuffix(os.Getenv(oidcIssuerEnv), "/")
+	config.OIDCClientID = os.Getenv(oidcClientIDEnv)
+	config.OIDCUsernameClaim = getEnvOrDefault(oidcUsernameEnv, defaultUsername)
+	config.OIDCGroupsClaim = getEnvOrDefault(oidcGroupsEnv, defaultGroups)
+	config.TokenAudiences = getOriginsAsSlice(os.Getenv(tokenAudiencesEnv))
+	config.AdminGroups = getOriginsAsSlice(os.Getenv(adminGroupsEnv))
+
+	switch config.AuthMode {
+	case authModeNone, authModeTokenReview:
+	case authModeOIDC:
+		if config.OIDCIssuer == "" || config.OIDCClientID == "" {
+			panic(fmt.Errorf("%s and %s are required for %s authentication", oidcIssuerEnv, oidcClientIDEnv,
+				authModeOIDC))
+		}
+	default:
+		panic(fmt.Errorf("invalid %s %s, should be one of %s, %s or %s", authModeEnv, config.AuthMode,
+			authModeNone, authModeOIDC, authModeTokenReview))
+	}
+}
+
+// getEnvOrDefault returns the value of an env var, or the default when it is not set
+func getEnvOrDefault(key string, defaultValue string) string {
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
 // getOriginsAsSlice turns the given value of and env var into a slice of strings.
 // It trims spaces from each value and ignores empty values.
 func getOriginsAsSlice(value string) []string {
@@ -129,3 +129,43 @@ func TestGetOriginsAsSlice(t *testing.T) {
 		assert.Equal(t, "https://example1.com https://example2.com", result[0])
 	})
 }
+
+func TestNewWSConfigAuth(t *testing.T) {
+	config := newWSConfig()
+	assert.Equal(t, authModeNone, config.AuthMode)
+
+	t.Setenv(authModeEnv, "OIDC")
+	t.Setenv(oidcIssuerEnv, "https://issuer.example/")
+	t.Setenv(oidcClientIDEnv, "paas-webservice")
+	t.Setenv(oidcUsernameEnv, "preferred_username")
+	t.Setenv(adminGroupsEnv, "admins, paas-admins")
+	config = newWSConfig()
+	assert.Equal(t, authModeOIDC, config.AuthMode)
+	assert.Equal(t, "https://issuer.example", config.OIDCIssuer)
+	assert.Equal(t, "preferred_username", config.OIDCUsernameClaim)
+	assert.Equal(t, defaultGroups, config.OIDCGroupsClaim)
+	assert.Equal(t, []string{"admins", "paas-admins"}, config.AdminGroups)
+
+	t.Setenv(oidcClientIDEnv, "")
+	assert.Panics(t, func() { newWSConfig() }, "oidc requires a client id")
+
+	t.Setenv(authModeEnv, "basic")
+	assert.Panics(t, func() { newWSConfig() }, "invalid auth mode")
+}
+
+func TestNewWSConfigKeySource(t *testing.T) {
+	config := newWSConfig()
+	assert.Equal(t, keySourceFiles, config.KeySource)
+
+	t.Setenv(keySourceEnv, "Cluster")
+	t.Setenv(publicKeyCMEnv, "paas-system/paas-secrets-publickey")
+	config = newWSConfig()
+	assert.Equal(t, keySourceCluster, config.KeySource)
+	assert.Equal(t, "paas-system/paas-secrets-publickey", config.PublicKeyConfigMap)
+
+	t.Setenv(publicKeyCMEnv, "paas-secrets-publickey")
+	assert.Panics(t, func() { newWSConfig() }, "cluster requires a namespaced configmap")
+
+	t.Setenv(keySourceEnv, "vault")
+	assert.Panics(t, func() { newWSConfig() }, "invalid key source")
+}
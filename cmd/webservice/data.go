@@ -6,7 +6,10 @@ See LICENSE.md for details.
 
 package main
 
-import "github.com/belastingdienst/opr-paas/v3/api/v1alpha1"
+import (
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha1"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+)
 
 // RestEncryptInput can be delivered to the API for encryption requests
 type RestEncryptInput struct {
@@ -21,14 +24,72 @@ type RestEncryptResult struct {
 	Valid     bool   `json:"valid"`
 }
 
+// RestEncryptInputV2 can be delivered to the API for v2 encryption requests, which support several secret types.
+// Depending on the type, the secret is read from Secret (ssh-key, token and opaque), Username and Password
+// (basic-auth), or Certificate and Key (tls). URL, Capability and Namespace are only used for ssh keys.
+type RestEncryptInputV2 struct {
+	PaasName    string `json:"paas"`
+	Type        string `json:"type"`
+	Secret      string `json:"secret,omitempty"`
+	Username    string `json:"username,omitempty"`
+	Password    string `json:"password,omitempty"`
+	Certificate string `json:"certificate,omitempty"`
+	Key         string `json:"key,omitempty"`
+	// URL is the key of the secret in the Paas, e.g. the url of a git repository
+	URL string `json:"url"`
+	// Capability (optional) selects the secrets of a capability in the Paas
+	Capability string `json:"capability,omitempty"`
+	// Namespace (optional) selects the secrets of a namespace in the Paas
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// RestEncryptResultV2 is returned by the API for v2 encryption requests
+type RestEncryptResultV2 struct {
+	PaasName  string `json:"paas"`
+	Type      string `json:"type"`
+	Encrypted string `json:"encrypted"`
+	Valid     bool   `json:"valid"`
+	// Field is the path of the Paas field in which the encrypted value should be set
+	Field string `json:"field,omitempty"`
+	// Snippet is a YAML snippet of a Paas holding the encrypted value, which is ready to be pasted. Field and Snippet
+	// are only set for ssh keys, as the secrets of a Paas are used as ssh private keys for ArgoCD repositories.
+	Snippet string `json:"snippet,omitempty"`
+	// Warning explains why no Field and Snippet are returned for secret types other than ssh keys
+	Warning string `json:"warning,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
 // RestCheckPaasInput can be delivered to the API for checkPaas requests
 type RestCheckPaasInput struct {
 	Paas v1alpha1.Paas `json:"paas"`
 }
 
+// RestCheckPaasInputV2 can be delivered to the API for checkPaas requests with a v1alpha2 Paas
+type RestCheckPaasInputV2 struct {
+	Paas v1alpha2.Paas `json:"paas"`
+}
+
 // RestCheckPaasResult is returned by the API for checkPaas requests
 type RestCheckPaasResult struct {
 	PaasName  string `json:"paas"`
 	Decrypted bool   `json:"decrypted"`
 	Error     string `json:"error"`
 }
+
+// RestCheckFieldResult is the result of checking a single encrypted value, for v2 check requests
+type RestCheckFieldResult struct {
+	// Field is the path of the encrypted value, e.g. spec.capabilities[argocd].secrets[ssh://git@scm/repo.git]
+	Field     string `json:"field"`
+	Decrypted bool   `json:"decrypted"`
+	Error     string `json:"error,omitempty"`
+}
+
+// RestCheckResultV2 is returned by the API for v2 checkPaas and checkPaasNS requests
+type RestCheckResultV2 struct {
+	Kind      string                 `json:"kind"`
+	Name      string                 `json:"name"`
+	PaasName  string                 `json:"paas"`
+	Decrypted bool                   `json:"decrypted"`
+	Error     string                 `json:"error,omitempty"`
+	Results   []RestCheckFieldResult `json:"results"`
+}
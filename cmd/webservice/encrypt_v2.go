@@ -0,0 +1,204 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+// Secret types which can be encrypted with the v2 encrypt endpoint
+const (
+	secretTypeSSHKey    = "ssh-key"
+	secretTypeToken     = "token"
+	secretTypeBasicAuth = "basic-auth"
+	secretTypeTLS       = "tls"
+	secretTypeOpaque    = "opaque"
+)
+
+// secretTypeWarning is returned for secret types other than ssh keys, which cannot be set in the secrets of a Paas
+const secretTypeWarning = "%s secrets cannot be used in the secrets of a Paas, which are only used as ssh private " +
+	"keys for ArgoCD repositories"
+
+// errUnsupportedSecretType is returned for secret types which are not supported
+var errUnsupportedSecretType = fmt.Errorf("type should be one of %s", strings.Join([]string{
+	secretTypeSSHKey, secretTypeToken, secretTypeBasicAuth, secretTypeTLS, secretTypeOpaque,
+}, ", "))
+
+// secretPayload validates the input for the declared secret type, and returns the payload which should be encrypted
+func secretPayload(input RestEncryptInputV2) ([]byte, error) {
+	switch input.Type {
+	case secretTypeSSHKey:
+		if _, err := ssh.ParsePrivateKey([]byte(input.Secret)); err != nil {
+			return nil, fmt.Errorf("invalid ssh private key: %w", err)
+		}
+		return []byte(input.Secret), nil
+	case secretTypeToken:
+		if input.Secret == "" || strings.ContainsFunc(input.Secret, unicode.IsSpace) {
+			return nil, errors.New("token should not be empty or contain whitespace")
+		}
+		return []byte(input.Secret), nil
+	case secretTypeBasicAuth:
+		if input.Username == "" || input.Password == "" {
+			return nil, errors.New("username and password are required")
+		}
+		if strings.Contains(input.Username, ":") {
+			return nil, errors.New("username should not contain a colon")
+		}
+		return []byte(input.Username + ":" + input.Password), nil
+	case secretTypeTLS:
+		return tlsPayload(input.Certificate, input.Key)
+	case secretTypeOpaque:
+		if input.Secret == "" {
+			return nil, errors.New("secret should not be empty")
+		}
+		return []byte(input.Secret), nil
+	default:
+		return nil, errUnsupportedSecretType
+	}
+}
+
+// tlsPayload validates a PEM encoded certificate (chain) and the matching PEM encoded private key, and returns both
+// concatenated
+func tlsPayload(certificate string, key string) ([]byte, error) {
+	keyPair, err := tls.X509KeyPair([]byte(certificate), []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate and key: %w", err)
+	}
+	for _, der := range keyPair.Certificate {
+		if _, err = x509.ParseCertificate(der); err != nil {
+			return nil, fmt.Errorf("invalid certificate: %w", err)
+		}
+	}
+	// Only the PEM blocks are kept, so that any text around them is dropped
+	var payload []byte
+	for _, data := range []string{certificate, key} {
+		rest := []byte(data)
+		for {
+			var block *pem.Block
+			if block, rest = pem.Decode(rest); block == nil {
+				break
+			}
+			payload = append(payload, pem.EncodeToMemory(block)...)
+		}
+	}
+	return payload, nil
+}
+
+// secretField returns the path of the Paas field in which the encrypted secret should be set: the secrets of the
+// capability or namespace (when set), or the secrets of the Paas
+func secretField(input RestEncryptInputV2) (*field.Path, error) {
+	specPath := field.NewPath("spec")
+	switch {
+	case input.Capability != "" && input.Namespace != "":
+		return nil, errors.New("capability and namespace are mutually exclusive")
+	case input.Capability != "":
+		return specPath.Child("capabilities").Key(input.Capability).Child("secrets"), nil
+	case input.Namespace != "":
+		return specPath.Child("namespaces").Key(input.Namespace).Child("secrets"), nil
+	default:
+		return specPath.Child("secrets"), nil
+	}
+}
+
+// secretSnippet returns a YAML snippet of a Paas, holding the encrypted secret in the requested field. It should only
+// be used for ssh keys, as the operator uses every secret of a Paas as ssh private key for an ArgoCD repository.
+func secretSnippet(input RestEncryptInputV2, encrypted string) (string, error) {
+	snippet := map[string]interface{}{input.URL: encrypted}
+	snippet = map[string]interface{}{"secrets": snippet}
+	switch {
+	case input.Capability != "":
+		snippet = map[string]interface{}{"capabilities": map[string]interface{}{input.Capability: snippet}}
+	case input.Namespace != "":
+		snippet = map[string]interface{}{"namespaces": map[string]interface{}{input.Namespace: snippet}}
+	}
+	out, err := yaml.Marshal(map[string]interface{}{"spec": snippet})
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// v2Encrypt validates a secret of a declared type, encrypts it and returns the encrypted value. For ssh keys, it also
+// returns the Paas field and a YAML snippet which can be pasted into a Paas.
+func v2Encrypt(c *gin.Context) {
+	var input RestEncryptInputV2
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, RestEncryptResultV2{Error: err.Error()})
+		return
+	}
+	output := RestEncryptResultV2{PaasName: input.PaasName, Type: input.Type}
+	var (
+		fldPath *field.Path
+		err     error
+	)
+	switch {
+	case input.PaasName == "":
+		err = errors.New("paas is required")
+	case input.Type == "":
+		err = errUnsupportedSecretType
+	case input.Type == secretTypeSSHKey:
+		if fldPath, err = secretField(input); err == nil && input.URL == "" {
+			err = errors.New("url is required for ssh keys")
+		}
+	}
+	if err != nil {
+		output.Error = err.Error()
+		c.IndentedJSON(http.StatusBadRequest, output)
+		return
+	}
+	if fldPath != nil {
+		output.Field = fldPath.Key(input.URL).String()
+	}
+	if !authorizePaas(c, actionEncrypt, input.PaasName) {
+		return
+	}
+
+	payload, err := secretPayload(input)
+	if err != nil {
+		status := http.StatusUnprocessableEntity
+		if errors.Is(err, errUnsupportedSecretType) {
+			status = http.StatusBadRequest
+		}
+		output.Error = err.Error()
+		c.IndentedJSON(status, output)
+		return
+	}
+
+	rsa := getRsaOrAbort(c, input.PaasName)
+	if rsa == nil {
+		return
+	}
+	if output.Encrypted, err = rsa.Encrypt(payload); err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	if fldPath == nil {
+		output.Warning = fmt.Sprintf(secretTypeWarning, input.Type)
+	} else if output.Snippet, err = secretSnippet(input, output.Encrypted); err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	output.Valid = true
+	auditLog(c, actionEncrypt, input.PaasName).WithFields(logrus.Fields{
+		"type":  input.Type,
+		"field": output.Field,
+	}).Info("encrypted secret")
+	c.IndentedJSON(http.StatusOK, output)
+}
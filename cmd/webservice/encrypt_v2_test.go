@@ -0,0 +1,174 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+// generateTLSKeyPairPEM returns a PEM encoded self-signed certificate and its private key
+func generateTLSKeyPairPEM(t *testing.T) (certPEM string, keyPEM string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "paas.example"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+}
+
+func postEncryptV2(t *testing.T, router http.Handler, input RestEncryptInputV2) (int, RestEncryptResultV2) {
+	body, err := json.Marshal(input)
+	require.NoError(t, err)
+	req, _ := http.NewRequest(http.MethodPost, "/v2/encrypt", strings.NewReader(string(body)))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var result RestEncryptResultV2
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	return w.Code, result
+}
+
+func TestSecretPayload(t *testing.T) {
+	sshKey, err := generateRSAPrivateKeyPEM(rsaKeySize)
+	require.NoError(t, err)
+	cert, key := generateTLSKeyPairPEM(t)
+	otherCert, _ := generateTLSKeyPairPEM(t)
+
+	for name, test := range map[string]struct {
+		input    RestEncryptInputV2
+		expected string
+	}{
+		"ssh-key":    {RestEncryptInputV2{Type: secretTypeSSHKey, Secret: sshKey}, sshKey},
+		"token":      {RestEncryptInputV2{Type: secretTypeToken, Secret: "abc123"}, "abc123"},
+		"basic-auth": {RestEncryptInputV2{Type: secretTypeBasicAuth, Username: "user", Password: "p:w"}, "user:p:w"},
+		"tls":        {RestEncryptInputV2{Type: secretTypeTLS, Certificate: "chain:\n" + cert, Key: key}, cert + key},
+		"opaque":     {RestEncryptInputV2{Type: secretTypeOpaque, Secret: "any\nthing"}, "any\nthing"},
+	} {
+		payload, payloadErr := secretPayload(test.input)
+		require.NoError(t, payloadErr, name)
+		assert.Equal(t, test.expected, string(payload), name)
+	}
+
+	for name, input := range map[string]RestEncryptInputV2{
+		"ssh-key":            {Type: secretTypeSSHKey, Secret: "not a key"},
+		"empty token":        {Type: secretTypeToken},
+		"token whitespace":   {Type: secretTypeToken, Secret: "abc 123"},
+		"basic-auth":         {Type: secretTypeBasicAuth, Username: "user"},
+		"basic-auth colon":   {Type: secretTypeBasicAuth, Username: "us:er", Password: "pw"},
+		"tls without key":    {Type: secretTypeTLS, Certificate: cert},
+		"tls mismatched key": {Type: secretTypeTLS, Certificate: otherCert, Key: key},
+		"tls no pem":         {Type: secretTypeTLS, Certificate: "cert", Key: "key"},
+		"empty opaque":       {Type: secretTypeOpaque},
+		"unknown type":       {Type: "password", Secret: "pw"},
+	} {
+		_, err = secretPayload(input)
+		assert.Error(t, err, name)
+	}
+}
+
+//revive:disable-next-line
+func Test_v2Encrypt(t *testing.T) {
+	router, _ := setupV2Test(t)
+	sshKey, err := generateRSAPrivateKeyPEM(rsaKeySize)
+	require.NoError(t, err)
+
+	status, result := postEncryptV2(t, router, RestEncryptInputV2{
+		PaasName:   testPaasName,
+		Type:       secretTypeSSHKey,
+		Secret:     sshKey,
+		URL:        repoName,
+		Capability: "argocd",
+	})
+	require.Equal(t, http.StatusOK, status, result.Error)
+	assert.True(t, result.Valid)
+	assert.Empty(t, result.Warning)
+	assert.Equal(t, "spec.capabilities[argocd].secrets["+repoName+"]", result.Field)
+	decrypted, err := mustGetRsa(t, testPaasName).Decrypt(result.Encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, sshKey, string(decrypted))
+
+	var snippet map[string]map[string]map[string]map[string]map[string]string
+	require.NoError(t, yaml.UnmarshalStrict([]byte(result.Snippet), &snippet))
+	assert.Equal(t, result.Encrypted, snippet["spec"]["capabilities"]["argocd"]["secrets"][repoName])
+
+	status, result = postEncryptV2(t, router, RestEncryptInputV2{
+		PaasName: testPaasName,
+		Type:     secretTypeSSHKey,
+		Secret:   sshKey,
+		URL:      repoName,
+	})
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "spec.secrets["+repoName+"]", result.Field)
+	assert.True(t, strings.HasPrefix(result.Snippet, "spec:\n  secrets:\n"))
+
+	// The secrets of a Paas are used as ssh private keys for ArgoCD repositories, so other secret types are only
+	// encrypted, without a field and snippet
+	for _, input := range []RestEncryptInputV2{
+		{Type: secretTypeBasicAuth, Username: "user", Password: "password", URL: repoName, Capability: "argocd"},
+		{Type: secretTypeToken, Secret: "abc123"},
+		{Type: secretTypeOpaque, Secret: "abc123", URL: repoName},
+	} {
+		input.PaasName = testPaasName
+		status, result = postEncryptV2(t, router, input)
+		require.Equal(t, http.StatusOK, status, input.Type)
+		assert.True(t, result.Valid, input.Type)
+		assert.NotEmpty(t, result.Encrypted, input.Type)
+		assert.Empty(t, result.Field, input.Type)
+		assert.Empty(t, result.Snippet, input.Type)
+		assert.Contains(t, result.Warning, "cannot be used in the secrets of a Paas", input.Type)
+	}
+	decrypted, err = mustGetRsa(t, testPaasName).Decrypt(result.Encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", string(decrypted))
+
+	status, result = postEncryptV2(t, router, RestEncryptInputV2{
+		PaasName: testPaasName,
+		Type:     secretTypeToken,
+		Secret:   "abc 123",
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.False(t, result.Valid)
+	assert.Empty(t, result.Encrypted)
+	assert.NotEmpty(t, result.Error)
+
+	for name, input := range map[string]RestEncryptInputV2{
+		"no paas":      {Type: secretTypeToken, Secret: "abc", URL: repoName},
+		"no url":       {PaasName: testPaasName, Type: secretTypeSSHKey, Secret: sshKey},
+		"no type":      {PaasName: testPaasName, Secret: "abc", URL: repoName},
+		"unknown type": {PaasName: testPaasName, Type: "password", Secret: "abc", URL: repoName},
+		"capability and namespace": {
+			PaasName: testPaasName, Type: secretTypeSSHKey, Secret: sshKey, URL: repoName, Capability: "a", Namespace: "b",
+		},
+	} {
+		status, result = postEncryptV2(t, router, input)
+		assert.Equal(t, http.StatusBadRequest, status, name)
+		assert.NotEmpty(t, result.Error, name)
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package main
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/belastingdienst/opr-paas-crypttool/pkg/crypt"
+	"github.com/belastingdienst/opr-paas/v3/internal/utils"
+)
+
+// errKeysNotLoaded is returned until the keys have been loaded for the first time
+var errKeysNotLoaded = errors.New("keys are not loaded yet")
+
+var (
+	_keys     *keyStore
+	_keysLock sync.Mutex
+)
+
+// keyStore holds the keys of the webservice, and the crypts (one per Paas) which are created from them. The keys are
+// replaced as a whole when they are (re)loaded, which also removes all crypts. When loading the keys fails, the error
+// is kept, so that it can be reported by the readiness probe.
+type keyStore struct {
+	mutex         sync.RWMutex
+	privateKeys   crypt.PrivateKeys
+	publicKeyPath string
+	crypts        map[string]*crypt.Crypt
+	err           error
+}
+
+func newKeyStore() *keyStore {
+	return &keyStore{crypts: map[string]*crypt.Crypt{}, err: errKeysNotLoaded}
+}
+
+// getKeys returns the keyStore of the webservice. The first time, the keys are loaded from the source set in the
+// config: from files (which are reloaded when they change), or from the cluster.
+func getKeys() *keyStore {
+	_keysLock.Lock()
+	defer _keysLock.Unlock()
+	if _keys != nil {
+		return _keys
+	}
+	config := getConfig()
+	_keys = newKeyStore()
+	if config.KeySource == keySourceCluster {
+		startClusterKeys(context.Background(), _keys, config)
+	} else {
+		startFileKeys(_keys, config)
+	}
+	return _keys
+}
+
+// setKeys replaces the keys, after verifying that they are valid
+func (ks *keyStore) setKeys(privateKeys crypt.PrivateKeys, publicKeyPath string) {
+	if err := loadPrivateKeys(privateKeys); err != nil {
+		ks.setError(err)
+		return
+	}
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+	ks.privateKeys = privateKeys
+	ks.publicKeyPath = publicKeyPath
+	ks.crypts = map[string]*crypt.Crypt{}
+	ks.err = nil
+	log.Printf("loaded %d private key(s)", len(privateKeys))
+}
+
+// setError removes the keys, so that they can no longer be used, and keeps the error for the readiness probe
+func (ks *keyStore) setError(err error) {
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+	log.Printf("ERROR: failed to load keys: %s", err)
+	ks.privateKeys = nil
+	ks.crypts = map[string]*crypt.Crypt{}
+	ks.err = err
+}
+
+// ready returns the error of the last attempt to load the keys, or nil when the keys are loaded
+func (ks *keyStore) ready() error {
+	ks.mutex.RLock()
+	defer ks.mutex.RUnlock()
+	return ks.err
+}
+
+// getCrypt returns the crypt for a Paas, which is created when it does not exist yet
+func (ks *keyStore) getCrypt(paasName string) (*crypt.Crypt, error) {
+	ks.mutex.RLock()
+	c, exists := ks.crypts[paasName]
+	err := ks.err
+	ks.mutex.RUnlock()
+	if exists {
+		return c, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+	if ks.err != nil {
+		return nil, ks.err
+	} else if c, exists = ks.crypts[paasName]; exists {
+		return c, nil
+	}
+	if c, err = crypt.NewCryptFromKeys(ks.privateKeys, ks.publicKeyPath, paasName); err != nil {
+		return nil, fmt.Errorf("unable to create a crypt: %w", err)
+	}
+	// A crypt loads the public key on first use, which is done here so that concurrent requests only read it
+	if _, err = c.EncryptRsa(nil); err != nil {
+		return nil, fmt.Errorf("unable to load public key: %w", err)
+	}
+	ks.crypts[paasName] = c
+	return c, nil
+}
+
+// loadPrivateKeys parses all private keys. Private keys are parsed on first use, which is done here so that invalid
+// keys are reported when they are loaded, and so that concurrent requests only read the parsed keys.
+func loadPrivateKeys(privateKeys crypt.PrivateKeys) error {
+	if len(privateKeys) == 0 {
+		return errors.New("no private keys")
+	}
+	for _, privateKey := range privateKeys {
+		if _, err := privateKey.DecryptRsa(nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePublicKey verifies that data holds a PEM encoded RSA public key
+func validatePublicKey(data []byte) error {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return errors.New("cannot decode public key")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("public key invalid: %w", err)
+	}
+	if _, isRsa := publicKey.(*rsa.PublicKey); !isRsa {
+		return errors.New("public key not rsa public key")
+	}
+	return nil
+}
+
+// fileKeys loads the keys from the files in the config, and reloads them when the files change
+type fileKeys struct {
+	store          *keyStore
+	privateKeyPath string
+	publicKeyPath  string
+}
+
+func startFileKeys(store *keyStore, config *wsConfig) {
+	loader := &fileKeys{
+		store:          store,
+		privateKeyPath: config.PrivateKeyPath,
+		publicKeyPath:  config.PublicKeyPath,
+	}
+	loader.load()
+	utils.NewFileWatcherWithCallback(loader.load, config.PrivateKeyPath, config.PublicKeyPath)
+}
+
+// load (re)loads the keys from the files
+func (l *fileKeys) load() {
+	var privateKeys crypt.PrivateKeys
+	publicKey, err := os.ReadFile(l.publicKeyPath)
+	if err == nil {
+		err = validatePublicKey(publicKey)
+	}
+	if err == nil {
+		privateKeys, err = crypt.NewPrivateKeysFromFiles([]string{l.privateKeyPath})
+	}
+	if err != nil {
+		l.store.setError(err)
+		return
+	}
+	l.store.setKeys(privateKeys, l.publicKeyPath)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package main
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/belastingdienst/opr-paas-crypttool/pkg/crypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const keyReloadTimeout = 5 * time.Second
+
+func TestKeyStore(t *testing.T) {
+	pub, priv, toDefer := makeCrypt(t)
+	defer toDefer()
+	privateKeys, err := crypt.NewPrivateKeysFromFiles([]string{priv.Name()})
+	require.NoError(t, err)
+
+	ks := newKeyStore()
+	require.ErrorIs(t, ks.ready(), errKeysNotLoaded)
+	_, err = ks.getCrypt(testPaasName)
+	require.ErrorIs(t, err, errKeysNotLoaded)
+
+	ks.setKeys(privateKeys, pub.Name())
+	require.NoError(t, ks.ready())
+
+	// Concurrent requests for the same Paas get the same crypt
+	var wg sync.WaitGroup
+	crypts := make([]*crypt.Crypt, 10)
+	for i := range crypts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, cryptErr := ks.getCrypt(testPaasName)
+			assert.NoError(t, cryptErr)
+			encrypted, cryptErr := c.Encrypt([]byte("My test string"))
+			assert.NoError(t, cryptErr)
+			_, cryptErr = c.Decrypt(encrypted)
+			assert.NoError(t, cryptErr)
+			crypts[i] = c
+		}()
+	}
+	wg.Wait()
+	for _, c := range crypts {
+		assert.Same(t, crypts[0], c)
+	}
+
+	loadErr := errors.New("failed")
+	ks.setError(loadErr)
+	require.ErrorIs(t, ks.ready(), loadErr)
+	_, err = ks.getCrypt(testPaasName)
+	require.ErrorIs(t, err, loadErr)
+	assert.Empty(t, ks.crypts)
+
+	ks.setKeys(nil, pub.Name())
+	require.Error(t, ks.ready(), "keys without private keys are invalid")
+}
+
+func TestValidatePublicKey(t *testing.T) {
+	pub, _, toDefer := makeCrypt(t)
+	defer toDefer()
+	publicKey, err := os.ReadFile(pub.Name())
+	require.NoError(t, err)
+
+	require.NoError(t, validatePublicKey(publicKey))
+	require.Error(t, validatePublicKey([]byte("not a public key")))
+}
+
+func TestFileKeys(t *testing.T) {
+	pub, priv, toDefer := makeCrypt(t)
+	defer toDefer()
+	ks := newKeyStore()
+	startFileKeys(ks, &wsConfig{PublicKeyPath: pub.Name(), PrivateKeyPath: priv.Name()})
+	require.NoError(t, ks.ready())
+	c, err := ks.getCrypt(testPaasName)
+	require.NoError(t, err)
+	encrypted, err := c.Encrypt([]byte("My test string"))
+	require.NoError(t, err)
+
+	// An invalid public key is reported, instead of causing a panic
+	require.NoError(t, os.WriteFile(pub.Name(), []byte("invalid"), fileModeUserReadWrite))
+	require.Eventually(t, func() bool { return ks.ready() != nil }, keyReloadTimeout, time.Millisecond)
+
+	// New keys are loaded when the files change
+	require.NoError(t, crypt.GenerateKeyPair(priv.Name(), pub.Name()))
+	require.Eventually(t, func() bool {
+		if ks.ready() != nil {
+			return false
+		}
+		c, err = ks.getCrypt(testPaasName)
+		if err != nil {
+			return false
+		}
+		_, err = c.Decrypt(encrypted)
+		return err != nil
+	}, keyReloadTimeout, time.Millisecond)
+}
@@ -7,27 +7,25 @@ See LICENSE.md for details.
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strings"
-	"sync"
 
 	"github.com/belastingdienst/opr-paas-crypttool/pkg/crypt"
-	"github.com/belastingdienst/opr-paas/v3/internal/utils"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v3/internal/version"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
 )
 
-var (
-	_crypt     map[string]*crypt.Crypt
-	_cryptLock sync.RWMutex
-	_config    *wsConfig
-	_fw        *utils.FileWatcher
-)
+var _config *wsConfig
 
 func getConfig() *wsConfig {
 	if _config == nil {
@@ -38,50 +36,23 @@ func getConfig() *wsConfig {
 	return _config
 }
 
-func resetRsa() {
-	log.Println("Resetting RSA")
-	_cryptLock.Lock()
-	defer _cryptLock.Unlock()
-	_crypt = make(map[string]*crypt.Crypt)
-}
-
-func getCrypt(paas string) *crypt.Crypt {
-	_cryptLock.RLock()
-	defer _cryptLock.RUnlock()
-	if c, exists := _crypt[paas]; exists {
-		return c
+// getRsa returns the crypt for a Paas, or an error when the keys are not loaded (correctly)
+func getRsa(paas string) (*crypt.Crypt, error) {
+	c, err := getKeys().getCrypt(paas)
+	if err != nil {
+		log.Printf("ERROR: no crypt for paas %s: %s", paas, err)
 	}
-	return nil
+	return c, err
 }
 
-func getRsa(paas string) *crypt.Crypt {
-	config := getConfig()
-	if _fw == nil {
-		log.Println("Starting watcher")
-		_fw = utils.NewFileWatcher(config.PrivateKeyPath, config.PublicKeyPath)
-	}
-	// It is crucial that we have this first and nil check on _crypt later
-	if _fw.WasTriggered() {
-		log.Println("Files changed")
-		resetRsa()
-	} else if _crypt == nil {
-		log.Println("crypt empty")
-		resetRsa()
-	}
-	if c := getCrypt(paas); c != nil {
-		return c
-	}
-
-	c, err := crypt.NewCryptFromFiles([]string{config.PrivateKeyPath}, config.PublicKeyPath, paas)
+// getRsaOrAbort returns the crypt for a Paas, or responds with an internal server error when there is none
+func getRsaOrAbort(c *gin.Context, paas string) *crypt.Crypt {
+	rsa, err := getRsa(paas)
 	if err != nil {
-		panic(fmt.Errorf("unable to create a crypt: %w", err))
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return nil
 	}
-
-	_cryptLock.Lock()
-	defer _cryptLock.Unlock()
-	_crypt[paas] = c
-
-	return c
+	return rsa
 }
 
 // v1Encrypt encrypts a secret and returns the encrypted value
@@ -90,10 +61,17 @@ func v1Encrypt(c *gin.Context) {
 	if err := c.BindJSON(&input); err != nil {
 		return
 	}
+	if !authorizePaas(c, actionEncrypt, input.PaasName) {
+		return
+	}
 	secret := []byte(input.Secret)
 	if _, err := ssh.ParsePrivateKey(secret); err == nil {
+		rsa := getRsaOrAbort(c, input.PaasName)
+		if rsa == nil {
+			return
+		}
 		var encrypted string
-		encrypted, err = getRsa(input.PaasName).Encrypt(secret)
+		encrypted, err = rsa.Encrypt(secret)
 		if err != nil {
 			return
 		}
@@ -102,6 +80,7 @@ func v1Encrypt(c *gin.Context) {
 			Encrypted: encrypted,
 			Valid:     true,
 		}
+		auditLog(c, actionEncrypt, input.PaasName).Info("encrypted secret")
 		c.IndentedJSON(http.StatusOK, output)
 		return
 	}
@@ -113,15 +92,42 @@ func v1Encrypt(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, output)
 }
 
-// v1CheckPaas checks whether a Paas can be decrypted using provided private/public keys
+// v1CheckPaas checks whether a Paas can be decrypted using provided private/public keys. Both v1alpha1 and v1alpha2
+// Paas'es are supported, based on the apiVersion of the Paas. The request can be either JSON or YAML.
 func v1CheckPaas(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err == nil && len(body) > 0 && !json.Valid(body) {
+		body, err = yaml.YAMLToJSON(body)
+	}
+	var typeMeta struct {
+		Paas metav1.TypeMeta `json:"paas"`
+	}
+	if err == nil {
+		err = json.Unmarshal(body, &typeMeta)
+	}
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, RestCheckPaasResult{"", false, err.Error()})
+		return
+	}
+	if typeMeta.Paas.APIVersion == v1alpha2.GroupVersion.String() {
+		v1CheckPaasV2(c, body)
+		return
+	}
+
 	var input RestCheckPaasInput
-	if err := c.BindJSON(&input); err != nil {
+	if err = json.Unmarshal(body, &input); err != nil {
 		c.IndentedJSON(http.StatusBadRequest, RestCheckPaasResult{"", false, err.Error()})
 		return
 	}
-	rsa := getRsa(input.Paas.Name)
-	err := CheckPaas(rsa, &input.Paas)
+	if !authorizePaas(c, actionCheckPaas, input.Paas.Name) {
+		return
+	}
+	rsa := getRsaOrAbort(c, input.Paas.Name)
+	if rsa == nil {
+		return
+	}
+	err = CheckPaas(rsa, &input.Paas)
+	auditLog(c, actionCheckPaas, input.Paas.Name).WithField("decrypted", err == nil).Info("checked paas")
 	if err != nil {
 		if strings.Contains(err.Error(), "unable to decrypt data with any of the private keys") ||
 			strings.Contains(err.Error(), "base64") {
@@ -158,8 +164,15 @@ func healthz(c *gin.Context) {
 	})
 }
 
-// readyz is a readiness probe.
+// readyz is a readiness probe. The webservice is ready when its keys are loaded.
 func readyz(c *gin.Context) {
+	if err := getKeys().ready(); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"message": "not ready",
+			"error":   err.Error(),
+		})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"message": "ready",
 	})
@@ -174,7 +187,7 @@ func setupRouter() *gin.Engine {
 
 	// Override default config where needed
 	config.AllowMethods = []string{"GET", "POST", "HEAD", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type"}
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Authorization"}
 	// Ensure closed default
 	config.AllowAllOrigins = false
 	config.AllowOrigins = nil
@@ -210,11 +223,21 @@ func setupRouter() *gin.Engine {
 	})
 
 	router.GET("/version", operatorVersion)
-	router.POST("/v1/encrypt", v1Encrypt)
-	router.POST("/v1/checkpaas", v1CheckPaas)
+
+	// Only the endpoints which use the keys require authentication (when enabled)
+	api := router.Group("/")
+	if auth := getAuth(); auth != nil {
+		api.Use(auth.middleware)
+	}
+	api.POST("/v1/encrypt", v1Encrypt)
+	api.POST("/v1/checkpaas", v1CheckPaas)
+	api.POST("/v2/encrypt", v2Encrypt)
+	api.POST("/v2/checkpaas", v2CheckPaas)
+	api.POST("/v2/checkpaasns", v2CheckPaasNS)
 	router.GET("/healthz", healthz)
 	router.GET("/readyz", readyz)
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	registerUI(router)
 
 	return router
 }
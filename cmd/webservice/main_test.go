@@ -48,7 +48,7 @@ func Test_getConfig(t *testing.T) {
 
 	// Reset config if any test before set config
 	_config = nil
-	_crypt = nil
+	_keys = nil
 	config := getConfig()
 
 	assert.NotNil(t, config)
@@ -74,40 +74,47 @@ func Test_getConfig(t *testing.T) {
 	assert.Equal(t, "/some/weird/path", config.PublicKeyPath)
 }
 
+// mustGetRsa returns the crypt for a Paas, and fails the test when there is none
+func mustGetRsa(t *testing.T, paas string) *crypt.Crypt {
+	rsa, err := getRsa(paas)
+	require.NoError(t, err)
+	return rsa
+}
+
 func Test_getRSA(t *testing.T) {
 	// Allow all origins for test
 	t.Setenv(allowedOriginsEnv, allowedOriginsVal)
 
 	// Reset config if any test before set config
 	_config = nil
-	_crypt = nil
+	_keys = nil
 	getConfig()
 
 	pub, priv, toDefer := makeCrypt(t)
 	defer toDefer()
 
-	// test: non-existing public key should panic
+	// test: non-existing public key should return an error
 	getConfig()
 	_config.PublicKeyPath = "/random/non-existing/public/keyfile"
 	assert.Equal(t, "/random/non-existing/public/keyfile", _config.PublicKeyPath)
-	assert.Nil(t, _crypt)
+	assert.Nil(t, _keys)
 	t.Log("getting paasName 1")
-	assert.Panics(t, func() { getRsa("paasName") }, "Failed to panic using non-existing public key")
+	_, err := getRsa("paasName")
+	require.Error(t, err, "Failed to return an error using non-existing public key")
 
 	// reset
-	_crypt = nil
+	_keys = nil
 	_config = nil
 
-	// test: non-existing _crypt results in single entry _crypt
+	// test: non-existing _keys results in single crypt
 	getConfig()
 	_config.PublicKeyPath = pub.Name()
 	_config.PrivateKeyPath = priv.Name()
-	assert.Nil(t, _crypt)
+	assert.Nil(t, _keys)
 	t.Log("getting paasName 2")
-	output := getRsa("paasName")
-	assert.Len(t, _crypt, 1)
+	output := mustGetRsa(t, "paasName")
+	assert.Len(t, _keys.crypts, 1)
 	assert.IsType(t, &crypt.Crypt{}, output)
-	assert.IsType(t, map[string]*crypt.Crypt{}, _crypt)
 
 	encrypted, err := output.Encrypt([]byte("My test string"))
 	require.NoError(t, err)
@@ -115,15 +122,13 @@ func Test_getRSA(t *testing.T) {
 
 	// explicitly didn't reset
 
-	// test: results in two entries in _crypt
-	getConfig()
-	_config.PublicKeyPath = pub.Name()
-	assert.NotNil(t, _crypt)
+	// test: results in two crypts
+	assert.NotNil(t, _keys)
 	t.Log("getting paasName2")
-	output = getRsa("paasName2")
-	assert.Len(t, _crypt, 2)
+	output = mustGetRsa(t, "paasName2")
+	assert.Len(t, _keys.crypts, 2)
 	assert.IsType(t, &crypt.Crypt{}, output)
-	assert.IsType(t, map[string]*crypt.Crypt{}, _crypt)
+	assert.Same(t, output, mustGetRsa(t, "paasName2"))
 
 	encrypted, err = output.Encrypt([]byte("My second string"))
 	require.NoError(t, err)
@@ -186,6 +191,12 @@ func Test_readyz(t *testing.T) {
 	// Allow all origins for test
 	t.Setenv(allowedOriginsEnv, allowedOriginsVal)
 
+	// Reset config if any test before set config
+	_config = nil
+	_keys = nil
+	_, _, toDefer := makeCrypt(t)
+	defer toDefer()
+
 	expected := gin.H{
 		"message": "ready",
 	}
@@ -201,6 +212,15 @@ func Test_readyz(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, exists)
 	assert.Equal(t, expected["message"], value)
+
+	// test: keys which cannot be loaded result in not ready
+	_keys.setError(errKeysNotLoaded)
+	w = performRequest(router, "GET", "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "not ready", response["message"])
+	assert.Equal(t, errKeysNotLoaded.Error(), response["error"])
+	_keys = nil
 }
 
 //revive:disable-next-line
@@ -210,13 +230,13 @@ func Test_v1CheckPaas(t *testing.T) {
 
 	// Reset config if any test before set config
 	_config = nil
-	_crypt = nil
+	_keys = nil
 
 	_, _, toDefer := makeCrypt(t)
 	defer toDefer()
 
 	// Encrypt secret for test
-	retrievedRsa := getRsa(testPaasName)
+	retrievedRsa := mustGetRsa(t, testPaasName)
 
 	encrypted, err := retrievedRsa.Encrypt([]byte("My test string"))
 	require.NoError(t, err)
@@ -315,7 +335,7 @@ func Test_v1Encrypt(t *testing.T) {
 
 	// Reset config if any test before set config
 	_config = nil
-	_crypt = nil
+	_keys = nil
 
 	_, _, toDefer := makeCrypt(t)
 	defer toDefer()
@@ -378,7 +398,7 @@ func Test_v1CheckPaasInternalServerError(t *testing.T) {
 
 	// Reset config if any test before get config
 	_config = nil
-	_crypt = nil
+	_keys = nil
 	getConfig()
 
 	router := setupRouter()
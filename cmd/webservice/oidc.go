@@ -0,0 +1,112 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+)
+
+const oidcHTTPTimeout = 10 * time.Second
+
+// oidcSigningAlgs are the supported signing algorithms. Only asymmetric algorithms are supported, so that a token
+// cannot be signed with the (public) key of the issuer or without a signature at all.
+var oidcSigningAlgs = []string{
+	oidc.RS256, oidc.RS384, oidc.RS512,
+	oidc.PS256, oidc.PS384, oidc.PS512,
+	oidc.ES256, oidc.ES384, oidc.ES512,
+}
+
+// oidcAuthenticator verifies OIDC id tokens (JWTs) which are signed by the configured issuer and issued for the
+// configured client id
+type oidcAuthenticator struct {
+	issuer        string
+	clientID      string
+	usernameClaim string
+	groupsClaim   string
+	// keysCtx is used to discover the issuer and to fetch its keys. It holds the http client, and outlives requests.
+	keysCtx context.Context
+
+	mutex    sync.RWMutex
+	verifier *oidc.IDTokenVerifier
+}
+
+func newOIDCAuthenticator(config *wsConfig) *oidcAuthenticator {
+	return &oidcAuthenticator{
+		issuer:        config.OIDCIssuer,
+		clientID:      config.OIDCClientID,
+		usernameClaim: config.OIDCUsernameClaim,
+		groupsClaim:   config.OIDCGroupsClaim,
+		keysCtx:       oidc.ClientContext(context.Background(), &http.Client{Timeout: oidcHTTPTimeout}),
+	}
+}
+
+// getVerifier returns the verifier for tokens of the issuer. The issuer is discovered on first use (and again after
+// a failed discovery). The discovery is done without holding the lock, so that a slow issuer does not block requests.
+func (a *oidcAuthenticator) getVerifier() (*oidc.IDTokenVerifier, error) {
+	a.mutex.RLock()
+	verifier := a.verifier
+	a.mutex.RUnlock()
+	if verifier != nil {
+		return verifier, nil
+	}
+	provider, err := oidc.NewProvider(a.keysCtx, a.issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover issuer: %w", err)
+	}
+	verifier = provider.Verifier(&oidc.Config{ClientID: a.clientID, SupportedSigningAlgs: oidcSigningAlgs})
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.verifier == nil {
+		a.verifier = verifier
+	}
+	return a.verifier, nil
+}
+
+// authenticate verifies the signature and the standard claims of a token, and returns the user it was issued for
+func (a *oidcAuthenticator) authenticate(ctx context.Context, token string) (*userInfo, error) {
+	verifier, err := a.getVerifier()
+	if err != nil {
+		return nil, err
+	}
+	idToken, err := verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	var claims map[string]interface{}
+	if err = idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+	username, isString := claims[a.usernameClaim].(string)
+	if !isString || username == "" {
+		return nil, fmt.Errorf("token has no %s claim", a.usernameClaim)
+	}
+	return &userInfo{Username: username, Groups: stringsClaim(claims[a.groupsClaim])}, nil
+}
+
+// stringsClaim returns the value of a claim which can either be a string or a list of strings
+func stringsClaim(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		var result []string
+		for _, item := range value {
+			if s, isString := item.(string); isString {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
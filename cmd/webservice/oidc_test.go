@@ -0,0 +1,222 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testClientID  = "paas-webservice"
+	discoveryPath = "/.well-known/openid-configuration"
+	rsaKid        = "rsa-key"
+	ecKid         = "ec-key"
+	// p256Size is the size in bytes of a coordinate on the P-256 curve
+	p256Size = 32
+)
+
+// testIssuer is an OIDC issuer which serves a discovery document and the keys it signs tokens with
+type testIssuer struct {
+	server    *httptest.Server
+	rsaKey    *rsa.PrivateKey
+	ecKey     *ecdsa.PrivateKey
+	jwksCalls atomic.Int32
+	// slowKeys, when set, is waited on before the keys are served
+	slowKeys atomic.Pointer[chan struct{}]
+}
+
+// testHeader is the header of a JWT
+type testHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, rsaKeySize)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecBytes, err := ecKey.PublicKey.Bytes()
+	require.NoError(t, err)
+	issuer := &testIssuer{rsaKey: rsaKey, ecKey: ecKey}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   issuer.server.URL,
+			"jwks_uri": issuer.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		issuer.jwksCalls.Add(1)
+		if slow := issuer.slowKeys.Load(); slow != nil {
+			<-*slow
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": rsaKid,
+				"use": "sig",
+				"n":   b64(rsaKey.N.Bytes()),
+				"e":   b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+			{"kty": "EC", "kid": ecKid, "crv": "P-256", "x": b64(ecBytes[1 : 1+p256Size]), "y": b64(ecBytes[1+p256Size:])},
+			{"kty": "oct", "kid": "symmetric", "k": b64([]byte("secret"))},
+		}})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// sign returns a JWT holding the claims, signed with the RSA (RS256) or EC (ES256) key of the issuer
+func (i *testIssuer) sign(t *testing.T, alg string, kid string, claims map[string]interface{}) string {
+	header, err := json.Marshal(testHeader{Alg: alg, Kid: kid})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := b64(header) + "." + b64(payload)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	if alg == "ES256" {
+		r, s, signErr := ecdsa.Sign(rand.Reader, i.ecKey, digest[:])
+		require.NoError(t, signErr)
+		signature = append(r.FillBytes(make([]byte, p256Size)), s.FillBytes(make([]byte, p256Size))...)
+	} else {
+		signature, err = rsa.SignPKCS1v15(rand.Reader, i.rsaKey, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	}
+	return signed + "." + b64(signature)
+}
+
+func (i *testIssuer) claims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss":    i.server.URL,
+		"aud":    []string{testClientID, "other"},
+		"sub":    "jdoe",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"nbf":    time.Now().Add(-time.Minute).Unix(),
+		"groups": []string{"team-a", "team-b"},
+	}
+}
+
+func (i *testIssuer) authenticator() *oidcAuthenticator {
+	return newOIDCAuthenticator(&wsConfig{
+		OIDCIssuer:        i.server.URL,
+		OIDCClientID:      testClientID,
+		OIDCUsernameClaim: defaultUsername,
+		OIDCGroupsClaim:   defaultGroups,
+	})
+}
+
+func TestOIDCAuthenticator(t *testing.T) {
+	issuer := newTestIssuer(t)
+	authn := issuer.authenticator()
+	ctx := context.Background()
+
+	for _, alg := range []string{"RS256", "ES256"} {
+		kid := rsaKid
+		if alg == "ES256" {
+			kid = ecKid
+		}
+		user, err := authn.authenticate(ctx, issuer.sign(t, alg, kid, issuer.claims()))
+		require.NoError(t, err, alg)
+		assert.Equal(t, &userInfo{Username: "jdoe", Groups: []string{"team-a", "team-b"}}, user, alg)
+	}
+	assert.EqualValues(t, 1, issuer.jwksCalls.Load(), "keys should be cached")
+
+	// A single audience and group can also be passed as a string
+	claims := issuer.claims()
+	claims["aud"] = testClientID
+	claims["groups"] = "team-a"
+	user, err := authn.authenticate(ctx, issuer.sign(t, "RS256", rsaKid, claims))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a"}, user.Groups)
+}
+
+func TestOIDCAuthenticator_invalid(t *testing.T) {
+	issuer := newTestIssuer(t)
+	authn := issuer.authenticator()
+	ctx := context.Background()
+
+	for name, modify := range map[string]func(map[string]interface{}){
+		"other issuer":   func(c map[string]interface{}) { c["iss"] = "https://issuer.example" },
+		"other audience": func(c map[string]interface{}) { c["aud"] = "other" },
+		"expired":        func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"no expiry":      func(c map[string]interface{}) { delete(c, "exp") },
+		"not yet valid":  func(c map[string]interface{}) { c["nbf"] = time.Now().Add(time.Hour).Unix() },
+		"no subject":     func(c map[string]interface{}) { delete(c, "sub") },
+	} {
+		claims := issuer.claims()
+		modify(claims)
+		_, err := authn.authenticate(ctx, issuer.sign(t, "RS256", rsaKid, claims))
+		assert.Error(t, err, name)
+	}
+
+	token := issuer.sign(t, "RS256", rsaKid, issuer.claims())
+	for name, invalid := range map[string]string{
+		"not a jwt":          "token",
+		"tampered signature": token[:len(token)-10] + "AAAAAAAAAA",
+		"wrong key":          issuer.sign(t, "RS256", ecKid, issuer.claims()),
+		"symmetric":          issuer.sign(t, "HS256", "symmetric", issuer.claims()),
+		"unsupported alg":    issuer.sign(t, "none", rsaKid, issuer.claims()),
+		"unknown key id":     issuer.sign(t, "RS256", "unknown", issuer.claims()),
+		"invalid encoding":   token + "A",
+	} {
+		_, err := authn.authenticate(ctx, invalid)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestOIDCAuthenticator_slowKeys(t *testing.T) {
+	issuer := newTestIssuer(t)
+	authn := issuer.authenticator()
+	ctx := context.Background()
+
+	_, err := authn.authenticate(ctx, issuer.sign(t, "RS256", rsaKid, issuer.claims()))
+	require.NoError(t, err)
+
+	// An unknown key id causes the keys to be refetched, which should not block tokens signed with a known key
+	slow := make(chan struct{})
+	issuer.slowKeys.Store(&slow)
+	unknown := issuer.sign(t, "RS256", "unknown", issuer.claims())
+	unknownDone := make(chan error)
+	go func() {
+		_, unknownErr := authn.authenticate(ctx, unknown)
+		unknownDone <- unknownErr
+	}()
+	require.Eventually(t, func() bool { return issuer.jwksCalls.Load() == 2 }, time.Second, 10*time.Millisecond)
+
+	user, err := authn.authenticate(ctx, issuer.sign(t, "ES256", ecKid, issuer.claims()))
+	require.NoError(t, err)
+	assert.Equal(t, "jdoe", user.Username)
+
+	close(slow)
+	assert.Error(t, <-unknownDone)
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package main
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// uiPath is the path the web UI is served on
+const uiPath = "/ui/"
+
+// The web UI only consists of static files without inline scripts or styles, so that it is allowed by the
+// Content-Security-Policy of the webservice (see buildCSP)
+//
+//go:embed ui
+var uiFiles embed.FS
+
+// registerUI serves the web UI on uiPath, and redirects the root of the webservice to it
+func registerUI(router *gin.Engine) {
+	uiFS, err := fs.Sub(uiFiles, "ui")
+	if err != nil {
+		panic(fmt.Errorf("unable to load ui: %w", err))
+	}
+	router.StaticFS(uiPath, http.FS(uiFS))
+	router.GET("/", func(c *gin.Context) {
+		c.Redirect(http.StatusFound, uiPath)
+	})
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+'use strict';
+
+// post sends a request body to an endpoint of the webservice, and returns the status and the decoded response
+async function post(path, body, contentType) {
+  const headers = {'Content-Type': contentType};
+  const token = document.getElementById('token').value.trim();
+  if (token !== '') {
+    headers.Authorization = 'Bearer ' + token;
+  }
+  const response = await fetch(path, {method: 'POST', headers: headers, body: body});
+  let result = {};
+  try {
+    result = await response.json();
+  } catch (e) {
+    // Not every error response holds a JSON body
+  }
+  return {status: response.status, result: result};
+}
+
+// statusMessage returns a message for responses which were not handled by the endpoint itself
+function statusMessage(status, result) {
+  switch (status) {
+    case 401:
+      return 'Authentication is required: provide a valid bearer token.';
+    case 403:
+      return 'You are not authorized for this Paas.';
+    default:
+      return result.error || 'Request failed with status ' + status + '.';
+  }
+}
+
+function showResult(prefix, success, message) {
+  const result = document.getElementById(prefix + '-result');
+  result.hidden = false;
+  result.classList.toggle('success', success);
+  result.classList.toggle('failure', !success);
+  document.getElementById(prefix + '-message').textContent = message;
+}
+
+// showTypeFields only shows the input fields which are used by the selected secret type
+function showTypeFields() {
+  const type = document.getElementById('encrypt-type').value;
+  document.querySelectorAll('.type-fields').forEach((fields) => {
+    fields.hidden = !fields.dataset.types.split(' ').includes(type);
+  });
+}
+
+async function encrypt(event) {
+  event.preventDefault();
+  const form = event.target;
+  const input = {};
+  new FormData(form).forEach((value, key) => {
+    if (value !== '') {
+      input[key] = value;
+    }
+  });
+  const encrypted = document.getElementById('encrypt-encrypted');
+  const snippet = document.getElementById('encrypt-snippet');
+  encrypted.value = '';
+  snippet.value = '';
+
+  const {status, result} = await post('../v2/encrypt', JSON.stringify(input), 'application/json');
+  if (status === 200) {
+    encrypted.value = result.encrypted;
+    if (result.snippet) {
+      snippet.value = result.snippet;
+      showResult('encrypt', true, 'Encrypted for ' + result.field);
+    } else {
+      showResult('encrypt', true, 'Encrypted. Note that ' + result.warning + '.');
+    }
+    return;
+  }
+  showResult('encrypt', false, statusMessage(status, result));
+}
+
+// asCheckPaasInput wraps a Paas (in YAML or JSON) in the paas field of a /v1/checkpaas request
+function asCheckPaasInput(paas) {
+  const indented = paas
+    .replace(/^---\s*\n/, '')
+    .split('\n')
+    .map((line) => '  ' + line)
+    .join('\n');
+  return 'paas:\n' + indented + '\n';
+}
+
+async function check(event) {
+  event.preventDefault();
+  const paas = document.getElementById('check-paas').value;
+  if (paas.trim() === '') {
+    showResult('check', false, 'Select or paste a Paas first.');
+    return;
+  }
+  const {status, result} = await post('../v1/checkpaas', asCheckPaasInput(paas), 'application/yaml');
+  if (status === 200) {
+    showResult('check', true, 'All secrets of ' + result.paas + ' can be decrypted.');
+  } else if (status === 422) {
+    showResult('check', false, 'Not all secrets of ' + result.paas + ' can be decrypted: ' + result.error);
+  } else {
+    showResult('check', false, statusMessage(status, result));
+  }
+}
+
+async function loadFile(event) {
+  const [file] = event.target.files;
+  if (file) {
+    document.getElementById('check-paas').value = await file.text();
+  }
+}
+
+async function loadVersion() {
+  try {
+    const response = await fetch('../version');
+    const result = await response.json();
+    document.getElementById('version').textContent = 'Paas ' + result.version;
+  } catch (e) {
+    // The version is informational only
+  }
+}
+
+document.addEventListener('DOMContentLoaded', () => {
+  document.getElementById('encrypt-type').addEventListener('change', showTypeFields);
+  document.getElementById('encrypt-form').addEventListener('submit', encrypt);
+  document.getElementById('check-file').addEventListener('change', loadFile);
+  document.getElementById('check-form').addEventListener('submit', check);
+  showTypeFields();
+  loadVersion();
+});
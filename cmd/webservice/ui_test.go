@@ -0,0 +1,102 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getUI(router http.Handler, path string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUI(t *testing.T) {
+	router, _ := setupV2Test(t)
+
+	w := getUI(router, "/")
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, uiPath, w.Header().Get("Location"))
+
+	w = getUI(router, uiPath)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Header().Get("Content-Security-Policy"), "script-src 'self'")
+	index := w.Body.String()
+	assert.Contains(t, index, `id="encrypt-form"`)
+	assert.Contains(t, index, `id="check-form"`)
+
+	for path, contentType := range map[string]string{
+		uiPath + "app.js":    "javascript",
+		uiPath + "style.css": "text/css",
+	} {
+		w = getUI(router, path)
+		require.Equal(t, http.StatusOK, w.Code, path)
+		assert.Contains(t, w.Header().Get("Content-Type"), contentType, path)
+	}
+}
+
+// TestUI_CSP verifies that the UI does not use anything which is blocked by the Content-Security-Policy: inline
+// scripts, inline styles, event handler attributes or resources from other origins
+func TestUI_CSP(t *testing.T) {
+	index, err := uiFiles.ReadFile("ui/index.html")
+	require.NoError(t, err)
+
+	for name, pattern := range map[string]string{
+		"inline script":      `<script(\s[^>]*)?>\s*[^<\s]`,
+		"script without src": `<script(\s+(defer|async|type="[^"]*"))*\s*>`,
+		"inline style":       `<style`,
+		"style attribute":    `\sstyle=`,
+		"event handler":      `\son[a-z]+=`,
+		"javascript url":     `javascript:`,
+		"external resource":  `(src|href)="(https?:)?//`,
+	} {
+		assert.NotRegexp(t, regexp.MustCompile(pattern), string(index), name)
+	}
+
+	app, err := uiFiles.ReadFile("ui/app.js")
+	require.NoError(t, err)
+	for _, forbidden := range []string{"eval(", "new Function", "innerHTML", ".style."} {
+		assert.NotContains(t, string(app), forbidden)
+	}
+}
+
+//revive:disable-next-line
+func Test_v1CheckPaasYAML(t *testing.T) {
+	router, encrypted := setupV2Test(t)
+
+	// This is how the UI wraps a Paas in a /v1/checkpaas request
+	body := fmt.Sprintf(`paas:
+  apiVersion: cpet.belastingdienst.nl/v1alpha2
+  kind: Paas
+  metadata:
+    name: %s
+  spec:
+    secrets:
+      %s: %s
+`, testPaasName, repoName, encrypted)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/checkpaas", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/yaml")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var result RestCheckPaasResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, RestCheckPaasResult{PaasName: testPaasName, Decrypted: true}, result)
+}
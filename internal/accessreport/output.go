@@ -0,0 +1,69 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package accessreport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	// FormatJSON can be used to write a report as a JSON list
+	FormatJSON = "json"
+	// FormatCSV can be used to write a report as CSV with a header line
+	FormatCSV = "csv"
+)
+
+var csvHeader = []string{
+	"paas", "namespace", "role", "subjectKind", "subject", "subjectNamespace", "group", "paasGroup", "source", "binding",
+}
+
+// ContentType returns the content type for a report in the specified format
+func ContentType(format string) string {
+	if format == FormatCSV {
+		return "text/csv"
+	}
+	return "application/json"
+}
+
+// Write writes the report to w in the specified format (json or csv)
+func (r Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON, "":
+		return r.WriteJSON(w)
+	case FormatCSV:
+		return r.WriteCSV(w)
+	}
+	return fmt.Errorf("unsupported report format %s, use %s or %s", format, FormatJSON, FormatCSV)
+}
+
+// WriteJSON writes the report as a JSON list
+func (r Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes the report as CSV including a header line
+func (r Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, e := range r {
+		if err := writer.Write([]string{
+			e.Paas, e.Namespace, e.Role, e.SubjectKind, e.Subject, e.SubjectNamespace, e.Group, e.PaasGroup, e.Source,
+			e.Binding,
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
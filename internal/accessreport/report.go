@@ -0,0 +1,259 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+// Package accessreport builds an overview of who has which role in which Paas namespace.
+package accessreport
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/controller"
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	userv1 "github.com/openshift/api/user/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// SourceRoleBinding marks entries originating from a RoleBinding in a Paas namespace
+	SourceRoleBinding = "RoleBinding"
+	// SourceClusterRoleBinding marks entries originating from a capability ClusterRoleBinding
+	SourceClusterRoleBinding = "ClusterRoleBinding"
+)
+
+// Entry is a single line in the access report. Every entry describes one subject having one role
+// in one namespace belonging to a Paas.
+type Entry struct {
+	// Paas is the name of the Paas that owns the namespace
+	Paas string `json:"paas"`
+	// Namespace is the namespace the role applies to
+	Namespace string `json:"namespace"`
+	// Role is the name of the ClusterRole which is bound
+	Role string `json:"role"`
+	// SubjectKind is the kind of the subject (Group, User or ServiceAccount)
+	SubjectKind string `json:"subjectKind"`
+	// Subject is the name of the subject
+	Subject string `json:"subject"`
+	// SubjectNamespace is the namespace of a ServiceAccount subject, which can differ from Namespace
+	SubjectNamespace string `json:"subjectNamespace,omitempty"`
+	// Group is the OpenShift group which gives a User access (empty for direct subjects)
+	Group string `json:"group,omitempty"`
+	// PaasGroup is the key in Paas.Spec.Groups which resulted in this entry (if any)
+	PaasGroup string `json:"paasGroup,omitempty"`
+	// Source is the kind of binding this entry originates from
+	Source string `json:"source"`
+	// Binding is the name of the binding this entry originates from
+	Binding string `json:"binding"`
+}
+
+// Report is a sorted list of entries
+type Report []Entry
+
+// Filter can be used to limit the report to a specific Paas, namespace, user and / or role.
+// Empty fields are ignored.
+type Filter struct {
+	Paas      string
+	Namespace string
+	User      string
+	Role      string
+}
+
+// matches returns true if the entry complies with all non-empty filter fields.
+// The user filter matches both users and service accounts (as `system:serviceaccount:<ns>:<name>`).
+func (f Filter) matches(e Entry) bool {
+	if f.Namespace != "" && f.Namespace != e.Namespace {
+		return false
+	}
+	if f.Role != "" && f.Role != e.Role {
+		return false
+	}
+	if f.User == "" {
+		return true
+	}
+	switch e.SubjectKind {
+	case rbac.UserKind:
+		return f.User == e.Subject
+	case rbac.ServiceAccountKind:
+		return f.User == strings.Join([]string{"system", "serviceaccount", e.SubjectNamespace, e.Subject}, ":")
+	}
+	return false
+}
+
+// Build creates a report from the current state of the cluster.
+// For every Paas it collects the namespaces managed by the Paas, the RoleBindings which the operator created
+// from the Paas groups and PaasConfig rolemappings, the members of the groups in these RoleBindings and the
+// capability ServiceAccounts which are added to ClusterRoleBindings.
+func Build(ctx context.Context, c client.Reader, filter Filter) (Report, error) {
+	ctx, logger := logging.GetLogComponent(ctx, logging.AccessReportComponent)
+	var paasList v1alpha2.PaasList
+	if err := c.List(ctx, &paasList); err != nil {
+		return nil, err
+	}
+	var crbs rbac.ClusterRoleBindingList
+	if err := c.List(ctx, &crbs); err != nil {
+		return nil, err
+	}
+	report := Report{}
+	members := groupMembers{}
+	for _, paas := range paasList.Items {
+		if filter.Paas != "" && filter.Paas != paas.Name {
+			continue
+		}
+		logger.Debug().Str("paas", paas.Name).Msg("collecting access for Paas")
+		entries, err := paasEntries(ctx, c, paas, crbs.Items, members)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if filter.matches(entry) {
+				report = append(report, entry)
+			}
+		}
+	}
+	report.sort()
+	return report, nil
+}
+
+func paasEntries(
+	ctx context.Context,
+	c client.Reader,
+	paas v1alpha2.Paas,
+	crbs []rbac.ClusterRoleBinding,
+	members groupMembers,
+) (entries []Entry, err error) {
+	var nsList corev1.NamespaceList
+	if err = c.List(ctx, &nsList, client.MatchingLabels{controller.ManagedByLabelKey: paas.Name}); err != nil {
+		return nil, err
+	}
+	groupKeys := map[string]string{}
+	for _, key := range paas.Spec.Groups.Keys() {
+		groupKeys[paas.GroupKey2GroupName(key)] = key
+	}
+	namespaces := map[string]bool{}
+	for _, ns := range nsList.Items {
+		namespaces[ns.Name] = true
+		var rbs rbac.RoleBindingList
+		if err = c.List(ctx, &rbs, client.InNamespace(ns.Name)); err != nil {
+			return nil, err
+		}
+		for _, rb := range rbs.Items {
+			if !paas.AmIOwner(rb.OwnerReferences) {
+				continue
+			}
+			var rbEntries []Entry
+			if rbEntries, err = roleBindingEntries(ctx, c, paas.Name, rb, groupKeys, members); err != nil {
+				return nil, err
+			}
+			entries = append(entries, rbEntries...)
+		}
+	}
+	for _, crb := range crbs {
+		for _, subject := range crb.Subjects {
+			if subject.Kind != rbac.ServiceAccountKind || !namespaces[subject.Namespace] {
+				continue
+			}
+			entries = append(entries, Entry{
+				Paas:             paas.Name,
+				Namespace:        subject.Namespace,
+				Role:             crb.RoleRef.Name,
+				SubjectKind:      subject.Kind,
+				Subject:          subject.Name,
+				SubjectNamespace: subject.Namespace,
+				Source:           SourceClusterRoleBinding,
+				Binding:          crb.Name,
+			})
+		}
+	}
+	return entries, nil
+}
+
+func roleBindingEntries(
+	ctx context.Context,
+	c client.Reader,
+	paasName string,
+	rb rbac.RoleBinding,
+	groupKeys map[string]string,
+	members groupMembers,
+) (entries []Entry, err error) {
+	for _, subject := range rb.Subjects {
+		entry := Entry{
+			Paas:        paasName,
+			Namespace:   rb.Namespace,
+			Role:        rb.RoleRef.Name,
+			SubjectKind: subject.Kind,
+			Subject:     subject.Name,
+			Source:      SourceRoleBinding,
+			Binding:     rb.Name,
+		}
+		if subject.Kind == rbac.ServiceAccountKind {
+			entry.SubjectNamespace = subject.Namespace
+		}
+		if subject.Kind != rbac.GroupKind {
+			entries = append(entries, entry)
+			continue
+		}
+		entry.PaasGroup = groupKeys[subject.Name]
+		entries = append(entries, entry)
+		var users []string
+		if users, err = members.get(ctx, c, subject.Name); err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			userEntry := entry
+			userEntry.SubjectKind = rbac.UserKind
+			userEntry.Subject = user
+			userEntry.Group = subject.Name
+			entries = append(entries, userEntry)
+		}
+	}
+	return entries, nil
+}
+
+// groupMembers caches the users of OpenShift groups while building a report
+type groupMembers map[string][]string
+
+func (gm groupMembers) get(ctx context.Context, c client.Reader, groupName string) ([]string, error) {
+	if users, exists := gm[groupName]; exists {
+		return users, nil
+	}
+	var group userv1.Group
+	if err := c.Get(ctx, types.NamespacedName{Name: groupName}, &group); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		// Groups with a query might not (yet) be synchronized
+		gm[groupName] = nil
+		return nil, nil
+	}
+	gm[groupName] = group.Users
+	return group.Users, nil
+}
+
+func (r Report) sort() {
+	slices.SortStableFunc(r, func(a, b Entry) int {
+		for _, cmp := range [][2]string{
+			{a.Paas, b.Paas},
+			{a.Namespace, b.Namespace},
+			{a.Role, b.Role},
+			{a.SubjectKind, b.SubjectKind},
+			{a.Subject, b.Subject},
+			{a.SubjectNamespace, b.SubjectNamespace},
+			{a.Group, b.Group},
+			{a.Binding, b.Binding},
+		} {
+			if c := strings.Compare(cmp[0], cmp[1]); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package accessreport
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/controller"
+	userv1 "github.com/openshift/api/user/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	reportPaas  = "my-paas"
+	reportNs    = "my-paas-argocd"
+	reportGroup = "my-paas-team"
+	reportUser  = "alice"
+	reportSA    = "argocd-application-controller"
+	// reportRemoteSA lives in another namespace than the RoleBinding it is a subject of
+	reportRemoteSA   = "deployer"
+	reportRemoteSANs = "pipelines"
+)
+
+func reportClient(t *testing.T) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, userv1.AddToScheme(scheme))
+	require.NoError(t, v1alpha2.AddToScheme(scheme))
+
+	isController := true
+	paas := &v1alpha2.Paas{
+		ObjectMeta: metav1.ObjectMeta{Name: reportPaas, UID: "paas-uid"},
+		Spec: v1alpha2.PaasSpec{
+			Groups: v1alpha2.PaasGroups{
+				"team": {Users: []string{reportUser, "bob"}, Roles: []string{"admin"}},
+			},
+		},
+	}
+	ownerRef := metav1.OwnerReference{
+		APIVersion: v1alpha2.GroupVersion.String(),
+		Kind:       "Paas",
+		Name:       reportPaas,
+		UID:        paas.UID,
+		Controller: &isController,
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		paas,
+		&v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{Name: "other-paas", UID: "other-uid"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   reportNs,
+			Labels: map[string]string{controller.ManagedByLabelKey: reportPaas},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "unrelated"}},
+		&userv1.Group{ObjectMeta: metav1.ObjectMeta{Name: reportGroup}, Users: []string{reportUser, "bob"}},
+		&rbac.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "paas-admin",
+				Namespace:       reportNs,
+				OwnerReferences: []metav1.OwnerReference{ownerRef},
+			},
+			Subjects: []rbac.Subject{{Kind: rbac.GroupKind, APIGroup: rbac.GroupName, Name: reportGroup}},
+			RoleRef:  rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "ClusterRole", Name: "admin"},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "paas-edit",
+				Namespace:       reportNs,
+				OwnerReferences: []metav1.OwnerReference{ownerRef},
+			},
+			Subjects: []rbac.Subject{
+				{Kind: rbac.ServiceAccountKind, Namespace: reportRemoteSANs, Name: reportRemoteSA},
+			},
+			RoleRef: rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "ClusterRole", Name: "edit"},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "not-managed", Namespace: reportNs},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, APIGroup: rbac.GroupName, Name: "mallory"}},
+			RoleRef:    rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "ClusterRole", Name: "admin"},
+		},
+		&rbac.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "paas-monitoring-edit"},
+			Subjects: []rbac.Subject{
+				{Kind: rbac.ServiceAccountKind, Namespace: reportNs, Name: reportSA},
+				{Kind: rbac.ServiceAccountKind, Namespace: "unrelated", Name: reportSA},
+			},
+			RoleRef: rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "ClusterRole", Name: "monitoring-edit"},
+		},
+	).Build()
+}
+
+func TestBuild(t *testing.T) {
+	report, err := Build(context.TODO(), reportClient(t), Filter{})
+	require.NoError(t, err)
+	require.Len(t, report, 5)
+
+	assert.Equal(t, Entry{
+		Paas:        reportPaas,
+		Namespace:   reportNs,
+		Role:        "admin",
+		SubjectKind: rbac.GroupKind,
+		Subject:     reportGroup,
+		PaasGroup:   "team",
+		Source:      SourceRoleBinding,
+		Binding:     "paas-admin",
+	}, report[0])
+	assert.Equal(t, rbac.UserKind, report[1].SubjectKind)
+	assert.Equal(t, reportUser, report[1].Subject)
+	assert.Equal(t, reportGroup, report[1].Group)
+	assert.Equal(t, "team", report[1].PaasGroup)
+	assert.Equal(t, "bob", report[2].Subject)
+	assert.Equal(t, Entry{
+		Paas:             reportPaas,
+		Namespace:        reportNs,
+		Role:             "edit",
+		SubjectKind:      rbac.ServiceAccountKind,
+		Subject:          reportRemoteSA,
+		SubjectNamespace: reportRemoteSANs,
+		Source:           SourceRoleBinding,
+		Binding:          "paas-edit",
+	}, report[3])
+	assert.Equal(t, Entry{
+		Paas:             reportPaas,
+		Namespace:        reportNs,
+		Role:             "monitoring-edit",
+		SubjectKind:      rbac.ServiceAccountKind,
+		Subject:          reportSA,
+		SubjectNamespace: reportNs,
+		Source:           SourceClusterRoleBinding,
+		Binding:          "paas-monitoring-edit",
+	}, report[4])
+}
+
+func TestBuildFiltered(t *testing.T) {
+	c := reportClient(t)
+	for _, test := range []struct {
+		filter   Filter
+		expected int
+	}{
+		{filter: Filter{Paas: reportPaas}, expected: 5},
+		{filter: Filter{Paas: "other-paas"}, expected: 0},
+		{filter: Filter{Namespace: "unrelated"}, expected: 0},
+		{filter: Filter{Role: "admin"}, expected: 3},
+		{filter: Filter{User: reportUser}, expected: 1},
+		{filter: Filter{User: "system:serviceaccount:" + reportNs + ":" + reportSA}, expected: 1},
+		{filter: Filter{User: "system:serviceaccount:" + reportRemoteSANs + ":" + reportRemoteSA}, expected: 1},
+		{filter: Filter{User: "system:serviceaccount:" + reportNs + ":" + reportRemoteSA}, expected: 0},
+		{filter: Filter{User: "mallory"}, expected: 0},
+		{filter: Filter{User: reportUser, Role: "monitoring-edit"}, expected: 0},
+	} {
+		report, err := Build(context.TODO(), c, test.filter)
+		require.NoError(t, err)
+		assert.Len(t, report, test.expected, "filter %v", test.filter)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	report, err := Build(context.TODO(), reportClient(t), Filter{User: reportUser})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.Write(&buf, FormatJSON))
+	var decoded Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, report, decoded)
+
+	buf.Reset()
+	require.NoError(t, report.Write(&buf, FormatCSV))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, csvHeader, records[0])
+	assert.Equal(t, []string{
+		reportPaas, reportNs, "admin", rbac.UserKind, reportUser, "", reportGroup, "team", SourceRoleBinding, "paas-admin",
+	}, records[1])
+
+	assert.Error(t, report.Write(&buf, "xml"))
+	assert.Equal(t, "text/csv", ContentType(FormatCSV))
+	assert.Equal(t, "application/json", ContentType(FormatJSON))
+}
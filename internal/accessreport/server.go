@@ -0,0 +1,168 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package accessreport
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+const (
+	// TokenEnvVar is the environment variable holding the bearer token for the access report endpoint
+	TokenEnvVar = "ACCESS_REPORT_TOKEN"
+	// ReportPath is the path the access report is served on
+	ReportPath    = "/api/v1/accessreport"
+	serverTimeout = 30 * time.Second
+)
+
+// Handler serves the access report over HTTP.
+//
+// The report can be filtered with the `paas`, `namespace`, `user` and `role` query parameters,
+// and `format` can be set to `json` (default) or `csv`.
+type Handler struct {
+	client      client.Reader
+	bearerToken string
+}
+
+// NewHandler returns a Handler which uses kclient to build reports and bearerToken to authenticate requests
+func NewHandler(kclient client.Reader, bearerToken string) *Handler {
+	return &Handler{
+		client:      kclient,
+		bearerToken: bearerToken,
+	}
+}
+
+// ServeHTTP implements the http.Handler interface
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, componentLogger := logging.GetLogComponent(r.Context(), logging.AccessReportComponent)
+	logger := componentLogger.With().
+		Str("path", r.URL.Path).
+		Str("method", r.Method).
+		Logger()
+
+	if r.Method != http.MethodGet || r.URL.Path != ReportPath {
+		logger.Error().Msg("invalid request method or path")
+		http.NotFound(w, r)
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+h.bearerToken)) != 1 {
+		logger.Error().Msg("invalid authorization header")
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = FormatJSON
+	}
+	if format != FormatJSON && format != FormatCSV {
+		http.Error(w, fmt.Sprintf("unsupported format %s", format), http.StatusBadRequest)
+		return
+	}
+	report, err := Build(ctx, h.client, Filter{
+		Paas:      query.Get("paas"),
+		Namespace: query.Get("namespace"),
+		User:      query.Get("user"),
+		Role:      query.Get("role"),
+	})
+	if err != nil {
+		logger.Error().AnErr("error", err).Msg("failed to build access report")
+		http.Error(w, fmt.Sprintf("report error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType(format))
+	w.WriteHeader(http.StatusOK)
+	if err = report.Write(w, format); err != nil {
+		logger.Error().AnErr("error", err).Msg("failed to write access report")
+		return
+	}
+	logger.Debug().Int("entries", len(report)).Msg("access report served")
+}
+
+// Server is a manager Runnable serving the access report
+type Server struct {
+	addr     string
+	tokenVar string
+	handler  *Handler
+	started  bool
+}
+
+// New returns a Server which serves the access report on bindAddr, using kclient to read cluster state.
+// The bearer token is read from the ACCESS_REPORT_TOKEN environment variable.
+func New(kclient client.Reader, bindAddr string) *Server {
+	return &Server{
+		addr:     bindAddr,
+		tokenVar: TokenEnvVar,
+		handler:  NewHandler(kclient, os.Getenv(TokenEnvVar)),
+	}
+}
+
+// Start satisfies Runnable so that the manager can start the server
+func (s *Server) Start(ctx context.Context) error {
+	ctx, componentLogger := logging.GetLogComponent(ctx, logging.AccessReportComponent)
+	logger := componentLogger.With().Str("server", s.addr).Logger()
+	if s.handler.bearerToken == "" {
+		logger.Error().Msg("token not set")
+		return fmt.Errorf("environment variable %s not set", s.tokenVar)
+	}
+
+	server := &http.Server{
+		Addr:         s.addr,
+		Handler:      s.handler,
+		ReadTimeout:  serverTimeout,
+		WriteTimeout: serverTimeout,
+	}
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		logger.Error().AnErr("error", err).Msg("failed to create listener")
+		return err
+	}
+	s.started = true
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		logger.Debug().Msg("shutting down")
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info().Msg("serving access report")
+	if err = server.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection satisfies LeaderElectionRunnable
+func (s *Server) NeedLeaderElection() bool {
+	// Returning false means that this runnable does not need LeaderElection
+	return false
+}
+
+// StartedChecker returns a healthz.Checker which reports healthy after the server has been started
+func (s *Server) StartedChecker() healthz.Checker {
+	return func(_ *http.Request) error {
+		if !s.started {
+			return errors.New("access report server has not been started yet")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package accessreport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "report-token"
+
+func TestHandler(t *testing.T) {
+	handler := NewHandler(reportClient(t), testToken)
+	for _, test := range []struct {
+		name        string
+		method      string
+		target      string
+		token       string
+		status      int
+		contentType string
+	}{
+		{name: "wrong path", method: http.MethodGet, target: "/other", token: testToken, status: http.StatusNotFound},
+		{name: "wrong method", method: http.MethodPost, target: ReportPath, token: testToken, status: http.StatusNotFound},
+		{name: "no token", method: http.MethodGet, target: ReportPath, status: http.StatusForbidden},
+		{name: "wrong token", method: http.MethodGet, target: ReportPath, token: "wrong", status: http.StatusForbidden},
+		{
+			name:   "wrong format",
+			method: http.MethodGet, target: ReportPath + "?format=xml", token: testToken, status: http.StatusBadRequest,
+		},
+		{
+			name:   "json",
+			method: http.MethodGet, target: ReportPath + "?user=" + reportUser, token: testToken, status: http.StatusOK,
+			contentType: "application/json",
+		},
+		{
+			name:   "csv",
+			method: http.MethodGet, target: ReportPath + "?format=csv", token: testToken, status: http.StatusOK,
+			contentType: "text/csv",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.target, nil)
+			if test.token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, test.status, rec.Code)
+			if test.contentType != "" {
+				assert.Equal(t, test.contentType, rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestHandlerFilters(t *testing.T) {
+	handler := NewHandler(reportClient(t), testToken)
+	req := httptest.NewRequest(http.MethodGet, ReportPath+"?paas="+reportPaas+"&role=admin&user="+reportUser, nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var report Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	require.Len(t, report, 1)
+	assert.Equal(t, reportUser, report[0].Subject)
+}
+
+func TestServerRequiresToken(t *testing.T) {
+	t.Setenv(TokenEnvVar, "")
+	server := New(reportClient(t), "127.0.0.1:0")
+	assert.False(t, server.NeedLeaderElection())
+	assert.Error(t, server.StartedChecker()(nil))
+	assert.Error(t, server.Start(context.TODO()))
+}
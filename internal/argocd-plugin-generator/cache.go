@@ -0,0 +1,162 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package argocd_plugin_generator
+
+import (
+	"sync"
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"k8s.io/client-go/tools/cache"
+)
+
+// resultsCache is an in-memory cache of generated results per capability. Within a capability, results are cached
+// per (serialized) set of input parameters, as filter parameters select different results.
+//
+// Entries are invalidated by Paas and PaasConfig informer events. Additionally, all entries are dropped when the
+// configuration in the config store (the active PaasConfig, the candidate PaasConfig or the PaasConfigRevisions) has
+// changed since they were generated, as the config store is updated by its own informer event handlers, which may run
+// after ours. Entries with results of Paas'es which are pinned to a PaasConfigRevision expire when the first of these
+// pins expires.
+type resultsCache struct {
+	mutex sync.Mutex
+	// version is incremented on every invalidation, so that results which were being generated while an
+	// invalidation took place are not stored
+	version uint64
+	// configGeneration is the generation of the config store which was used to generate all cached results
+	configGeneration uint64
+	entries          map[string]map[string]cacheEntry
+}
+
+// cacheEntry holds generated results, and the time at which they expire (if any)
+type cacheEntry struct {
+	results []map[string]interface{}
+	expires time.Time
+}
+
+// newResultsCache returns an empty resultsCache
+func newResultsCache() *resultsCache {
+	return &resultsCache{
+		entries: map[string]map[string]cacheEntry{},
+	}
+}
+
+// get returns the cached results for a capability and parameters key, generated with this generation of the config
+// store. It also returns the cache version, which should be passed to set when storing newly generated results.
+func (c *resultsCache) get(
+	capName string,
+	key string,
+	configGeneration uint64,
+) (results []map[string]interface{}, version uint64, exists bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.configGeneration != configGeneration {
+		c.reset()
+		c.configGeneration = configGeneration
+	}
+	entry, exists := c.entries[capName][key]
+	if exists && !entry.expires.IsZero() && !time.Now().Before(entry.expires) {
+		delete(c.entries[capName], key)
+		return nil, c.version, false
+	}
+	return entry.results, c.version, exists
+}
+
+// set stores the results for a capability and parameters key until they expire (a zero expires never expires),
+// unless the cache was invalidated after version was returned by get
+func (c *resultsCache) set(
+	capName string,
+	key string,
+	version uint64,
+	results []map[string]interface{},
+	expires time.Time,
+) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.version != version {
+		return
+	}
+	if _, exists := c.entries[capName]; !exists {
+		c.entries[capName] = map[string]cacheEntry{}
+	}
+	c.entries[capName][key] = cacheEntry{results: results, expires: expires}
+}
+
+// invalidate drops all cached results for the capabilities
+func (c *resultsCache) invalidate(capNames ...string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.version++
+	for _, capName := range capNames {
+		delete(c.entries, capName)
+	}
+}
+
+// invalidateAll drops all cached results
+func (c *resultsCache) invalidateAll() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.reset()
+}
+
+// reset drops all cached results. The caller should hold the mutex.
+func (c *resultsCache) reset() {
+	c.version++
+	clear(c.entries)
+}
+
+// paasEventHandler returns informer event handlers which invalidate the cached results of all capabilities of the
+// added, updated or deleted Paas
+func (c *resultsCache) paasEventHandler() cache.ResourceEventHandler {
+	invalidatePaas := func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		paas, ok := obj.(*v1alpha2.Paas)
+		if !ok {
+			c.invalidateAll()
+			return
+		}
+		capNames := make([]string, 0, len(paas.Spec.Capabilities))
+		for capName := range paas.Spec.Capabilities {
+			capNames = append(capNames, capName)
+		}
+		c.invalidate(capNames...)
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: invalidatePaas,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if oldPaas, ok := oldObj.(*v1alpha2.Paas); ok {
+				if newPaas, isPaas := newObj.(*v1alpha2.Paas); isPaas &&
+					oldPaas.ResourceVersion == newPaas.ResourceVersion {
+					// Periodic resyncs don't change anything
+					return
+				}
+			}
+			// Capabilities which were removed from the Paas are only in the old object
+			invalidatePaas(oldObj)
+			invalidatePaas(newObj)
+		},
+		DeleteFunc: invalidatePaas,
+	}
+}
+
+// paasConfigEventHandler returns informer event handlers which invalidate all cached results when a PaasConfig is
+// added, updated or deleted
+func (c *resultsCache) paasConfigEventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(_ interface{}) {
+			c.invalidateAll()
+		},
+		UpdateFunc: func(_, _ interface{}) {
+			c.invalidateAll()
+		},
+		DeleteFunc: func(_ interface{}) {
+			c.invalidateAll()
+		},
+	}
+}
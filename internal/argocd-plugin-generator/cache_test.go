@@ -0,0 +1,207 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package argocd_plugin_generator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestResultsCache(t *testing.T) {
+	const cfg uint64 = 1
+	results := []map[string]interface{}{{"paas": "a"}}
+	c := newResultsCache()
+
+	_, version, exists := c.get("argocd", "key", cfg)
+	assert.False(t, exists)
+	c.set("argocd", "key", version, results, time.Time{})
+	cached, _, exists := c.get("argocd", "key", cfg)
+	assert.True(t, exists)
+	assert.Equal(t, results, cached)
+
+	// Results which were generated while the cache was invalidated are not stored
+	_, version, _ = c.get("other", "key", cfg)
+	c.invalidate("unrelated")
+	c.set("other", "key", version, results, time.Time{})
+	_, _, exists = c.get("other", "key", cfg)
+	assert.False(t, exists)
+
+	// Invalidating a capability keeps the results of other capabilities
+	_, version, _ = c.get("other", "key", cfg)
+	c.set("other", "key", version, results, time.Time{})
+	c.invalidate("argocd")
+	_, _, exists = c.get("argocd", "key", cfg)
+	assert.False(t, exists)
+	_, _, exists = c.get("other", "key", cfg)
+	assert.True(t, exists)
+
+	// A different config generation drops all results
+	_, _, exists = c.get("other", "key", cfg+1)
+	assert.False(t, exists)
+
+	// Results expire
+	_, version, _ = c.get("argocd", "key", cfg+1)
+	c.set("argocd", "key", version, results, time.Now().Add(-time.Second))
+	_, _, exists = c.get("argocd", "key", cfg+1)
+	assert.False(t, exists)
+	c.set("argocd", "key", version, results, time.Now().Add(time.Hour))
+	_, _, exists = c.get("argocd", "key", cfg+1)
+	assert.True(t, exists)
+}
+
+func TestResultsCache_eventHandlers(t *testing.T) {
+	const cfg uint64 = 1
+	results := []map[string]interface{}{{"paas": "a"}}
+	c := newResultsCache()
+	fill := func() {
+		for _, capName := range []string{"argocd", "sso"} {
+			_, version, _ := c.get(capName, "key", cfg)
+			c.set(capName, "key", version, results, time.Time{})
+		}
+	}
+	cached := func(capName string) bool {
+		_, _, exists := c.get(capName, "key", cfg)
+		return exists
+	}
+	paas := func(resourceVersion string, capNames ...string) *v1alpha2.Paas {
+		p := &v1alpha2.Paas{
+			ObjectMeta: metav1.ObjectMeta{Name: "paas", ResourceVersion: resourceVersion},
+			Spec:       v1alpha2.PaasSpec{Capabilities: v1alpha2.PaasCapabilities{}},
+		}
+		for _, capName := range capNames {
+			p.Spec.Capabilities[capName] = v1alpha2.PaasCapability{}
+		}
+		return p
+	}
+	paasHandler := c.paasEventHandler()
+
+	fill()
+	paasHandler.OnAdd(paas("1", "argocd"), false)
+	assert.False(t, cached("argocd"))
+	assert.True(t, cached("sso"))
+
+	fill()
+	paasHandler.OnUpdate(paas("1", "sso"), paas("1", "sso"))
+	assert.True(t, cached("sso"), "resyncs should not invalidate")
+	paasHandler.OnUpdate(paas("1", "sso"), paas("2", "argocd"))
+	assert.False(t, cached("argocd"))
+	assert.False(t, cached("sso"), "removed capabilities should be invalidated")
+
+	fill()
+	paasHandler.OnDelete(cache.DeletedFinalStateUnknown{Obj: paas("2", "sso")})
+	assert.True(t, cached("argocd"))
+	assert.False(t, cached("sso"))
+
+	fill()
+	c.paasConfigEventHandler().OnUpdate(&v1alpha2.PaasConfig{}, &v1alpha2.PaasConfig{})
+	assert.False(t, cached("argocd"))
+	assert.False(t, cached("sso"))
+}
+
+func TestService_GenerateCached(t *testing.T) {
+	config.SetConfig(v1alpha2.PaasConfig{
+		Spec: v1alpha2.PaasConfigSpec{
+			Capabilities: v1alpha2.ConfigCapabilities{"argocd": {}},
+		},
+	})
+	newPaas := func(name string) *v1alpha2.Paas {
+		return &v1alpha2.Paas{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha2.PaasSpec{
+				Capabilities: v1alpha2.PaasCapabilities{"argocd": {}},
+			},
+		}
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha2.AddToScheme(scheme))
+	kclient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newPaas("paas-a")).Build()
+	svc := NewService(kclient)
+	svc.cache = newResultsCache()
+	params := map[string]interface{}{"capability": "argocd"}
+
+	results, err := svc.Generate(params, "appset")
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"paas": "paas-a"}}, results)
+
+	// Without invalidation, the cached results are returned
+	require.NoError(t, kclient.Create(context.Background(), newPaas("paas-b")))
+	results, err = svc.Generate(params, "appset")
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	svc.cache.invalidate("argocd")
+	results, err = svc.Generate(params, "appset")
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"paas": "paas-a"}, {"paas": "paas-b"}}, results)
+}
+
+func TestService_GeneratePinnedRevision(t *testing.T) {
+	capConfig := func(tpl string) v1alpha2.ConfigCapabilities {
+		return v1alpha2.ConfigCapabilities{"argocd": {
+			CustomFields: map[string]v1alpha2.ConfigCustomField{"team": {Template: tpl}},
+		}}
+	}
+	config.SetConfig(v1alpha2.PaasConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "paas-config"},
+		Spec:       v1alpha2.PaasConfigSpec{Capabilities: capConfig("new-{{ .Paas.Name }}")},
+	})
+	defer config.SetConfig(v1alpha2.PaasConfig{})
+	revision := v1alpha2.NewPaasConfigRevision(v1alpha2.PaasConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "paas-config"},
+		Spec:       v1alpha2.PaasConfigSpec{Capabilities: capConfig("old-{{ .Paas.Name }}")},
+	})
+	config.SetRevision(*revision)
+	defer config.DeleteRevision(revision.Name)
+
+	pinned := &v1alpha2.Paas{
+		ObjectMeta: metav1.ObjectMeta{Name: "paas-a", Annotations: map[string]string{
+			v1alpha2.PinRevisionAnnotation:      revision.Name,
+			v1alpha2.PinRevisionUntilAnnotation: time.Now().Add(time.Hour).Format(time.RFC3339),
+		}},
+		Spec: v1alpha2.PaasSpec{Capabilities: v1alpha2.PaasCapabilities{"argocd": {}}},
+	}
+	unpinned := &v1alpha2.Paas{
+		ObjectMeta: metav1.ObjectMeta{Name: "paas-b"},
+		Spec:       v1alpha2.PaasSpec{Capabilities: v1alpha2.PaasCapabilities{"argocd": {}}},
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha2.AddToScheme(scheme))
+	svc := NewService(fake.NewClientBuilder().WithScheme(scheme).WithObjects(pinned, unpinned).Build())
+	svc.cache = newResultsCache()
+	params := map[string]interface{}{"capability": "argocd"}
+
+	results, err := svc.Generate(params, "appset")
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"paas": "paas-a", "team": "old-paas-a"},
+		{"paas": "paas-b", "team": "new-paas-b"},
+	}, results)
+
+	// Changing the revision in the config store drops the cached results
+	changed := *revision.DeepCopy()
+	changed.Spec.Config.Capabilities = capConfig("changed-{{ .Paas.Name }}")
+	config.SetRevision(changed)
+	results, err = svc.Generate(params, "appset")
+	require.NoError(t, err)
+	assert.Equal(t, "changed-paas-a", results[0]["team"])
+
+	// Results with pinned Paas'es expire with the pin
+	_, expires, err := svc.generate(context.Background(), "argocd", paasFilter{labelSelector: labels.Everything()})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expires, time.Minute)
+}
@@ -43,16 +43,22 @@ func (es Elements) GetElementAsString(key string) string {
 	return value
 }
 
-// TryGetElementAsString gets a value and returns as string
+// TryGetElementAsString gets a value and returns as string. Lists and maps are returned as JSON.
 // This should be a method on Element, but a method cannot exist on interface datatypes
 func (es Elements) TryGetElementAsString(key string) (string, error) {
 	element, exists := es[key]
 	if !exists {
 		return "", errors.New("element does not exist")
 	}
-	value, ok := element.(string)
-	if ok {
+	switch value := element.(type) {
+	case string:
 		return value, nil
+	case []any, map[string]any:
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return string(raw), nil
 	}
 	return fmt.Sprintf("%v", element), nil
 }
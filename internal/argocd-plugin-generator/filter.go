@@ -0,0 +1,199 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package argocd_plugin_generator
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/belastingdienst/opr-paas/v3/internal/argocd-plugin-generator/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Names of the optional input parameters which can be used to select a subset of all Paas'es
+const (
+	labelSelectorParam = "labelSelector"
+	namesParam         = "names"
+	excludeNamesParam  = "excludeNames"
+	matchFieldsParam   = "matchFields"
+	offsetParam        = "offset"
+	limitParam         = "limit"
+)
+
+// paasFilter selects which Paas'es (and their generated parameters) are returned by the plugin generator
+type paasFilter struct {
+	// labelSelector selects Paas'es by their labels
+	labelSelector labels.Selector
+	// names holds glob patterns of which the name of a Paas should match at least one (when set)
+	names []string
+	// excludeNames holds glob patterns of which the name of a Paas should match none
+	excludeNames []string
+	// matchFields holds glob patterns which generated parameters (e.g. custom fields) should match
+	matchFields map[string]string
+	// offset is the number of results to skip
+	offset int
+	// limit is the maximum number of results to return, where 0 means all results
+	limit int
+}
+
+// parseFilter parses the optional filter parameters from the input parameters of the plugin generator
+func parseFilter(params map[string]interface{}) (filter paasFilter, err error) {
+	filter.labelSelector = labels.Everything()
+	if value, exists := params[labelSelectorParam]; exists {
+		selector, ok := value.(string)
+		if !ok {
+			return filter, fmt.Errorf("%s should be a string", labelSelectorParam)
+		}
+		if filter.labelSelector, err = labels.Parse(selector); err != nil {
+			return filter, fmt.Errorf("invalid %s: %w", labelSelectorParam, err)
+		}
+	}
+	if filter.names, err = globsParam(params, namesParam); err != nil {
+		return filter, err
+	}
+	if filter.excludeNames, err = globsParam(params, excludeNamesParam); err != nil {
+		return filter, err
+	}
+	if filter.matchFields, err = matchFieldsFromParams(params); err != nil {
+		return filter, err
+	}
+	if filter.offset, err = intParam(params, offsetParam); err != nil {
+		return filter, err
+	}
+	if filter.limit, err = intParam(params, limitParam); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// globsParam returns a list of glob patterns from a parameter, which can be a list or a comma separated string
+func globsParam(params map[string]interface{}, name string) ([]string, error) {
+	var globs []string
+	switch value := params[name].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		for glob := range strings.SplitSeq(value, ",") {
+			if glob = strings.TrimSpace(glob); glob != "" {
+				globs = append(globs, glob)
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			glob, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s should only contain strings", name)
+			}
+			globs = append(globs, glob)
+		}
+	default:
+		return nil, fmt.Errorf("%s should be a list or a comma separated string", name)
+	}
+	for _, glob := range globs {
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %s in %s: %w", glob, name, err)
+		}
+	}
+	return globs, nil
+}
+
+// matchFieldsFromParams returns the glob patterns per generated parameter from the matchFields parameter
+func matchFieldsFromParams(params map[string]interface{}) (map[string]string, error) {
+	value, exists := params[matchFieldsParam]
+	if !exists {
+		return nil, nil
+	}
+	fieldsParam, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s should be a map", matchFieldsParam)
+	}
+	matchFields := make(map[string]string, len(fieldsParam))
+	for key, item := range fieldsParam {
+		glob, isString := item.(string)
+		if !isString {
+			return nil, fmt.Errorf("%s.%s should be a string", matchFieldsParam, key)
+		}
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %s in %s.%s: %w", glob, matchFieldsParam, key, err)
+		}
+		matchFields[key] = glob
+	}
+	return matchFields, nil
+}
+
+// intParam returns a non-negative integer from a parameter, which can be a number or a string
+func intParam(params map[string]interface{}, name string) (int, error) {
+	var result int
+	switch value := params[name].(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		if value != float64(int(value)) {
+			return 0, fmt.Errorf("%s should be an integer", name)
+		}
+		result = int(value)
+	case string:
+		var err error
+		if result, err = strconv.Atoi(value); err != nil {
+			return 0, fmt.Errorf("%s should be an integer", name)
+		}
+	default:
+		return 0, fmt.Errorf("%s should be an integer", name)
+	}
+	if result < 0 {
+		return 0, errors.New(name + " should not be negative")
+	}
+	return result, nil
+}
+
+// matchesAny returns true when name matches at least one of the glob patterns
+func matchesAny(name string, globs []string) bool {
+	for _, glob := range globs {
+		// Patterns are validated while parsing the filter
+		if matched, _ := path.Match(glob, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesName returns true when a Paas with this name should be returned
+func (f paasFilter) matchesName(name string) bool {
+	if len(f.names) > 0 && !matchesAny(name, f.names) {
+		return false
+	}
+	return !matchesAny(name, f.excludeNames)
+}
+
+// matchesElements returns true when the generated parameters of a Paas match all field matchers
+func (f paasFilter) matchesElements(elements fields.Elements) bool {
+	for key, glob := range f.matchFields {
+		value, err := elements.TryGetElementAsString(key)
+		if err != nil {
+			return false
+		}
+		if matched, _ := path.Match(glob, value); !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// page returns the results selected by offset and limit
+func (f paasFilter) page(results []map[string]interface{}) []map[string]interface{} {
+	if f.offset >= len(results) {
+		return nil
+	}
+	results = results[f.offset:]
+	if f.limit > 0 && f.limit < len(results) {
+		results = results[:f.limit]
+	}
+	return results
+}
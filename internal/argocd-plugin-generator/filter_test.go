@@ -0,0 +1,98 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package argocd_plugin_generator
+
+import (
+	"testing"
+
+	"github.com/belastingdienst/opr-paas/v3/internal/argocd-plugin-generator/fields"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestParseFilter(t *testing.T) {
+	filter, err := parseFilter(map[string]interface{}{"capability": "argocd"})
+	require.NoError(t, err)
+	assert.True(t, filter.labelSelector.Empty())
+	assert.Nil(t, filter.names)
+	assert.Nil(t, filter.excludeNames)
+	assert.Nil(t, filter.matchFields)
+	assert.Zero(t, filter.offset)
+	assert.Zero(t, filter.limit)
+
+	filter, err = parseFilter(map[string]interface{}{
+		labelSelectorParam: "team=a,env!=prod",
+		namesParam:         []interface{}{"team-a-*", "team-b-*"},
+		excludeNamesParam:  "*-test, *-dev",
+		matchFieldsParam:   map[string]interface{}{"git_revision": "release-*"},
+		offsetParam:        2.0,
+		limitParam:         "5",
+	})
+	require.NoError(t, err)
+	assert.True(t, filter.labelSelector.Matches(labels.Set{"team": "a", "env": "dev"}))
+	assert.False(t, filter.labelSelector.Matches(labels.Set{"team": "a", "env": "prod"}))
+	assert.Equal(t, []string{"team-a-*", "team-b-*"}, filter.names)
+	assert.Equal(t, []string{"*-test", "*-dev"}, filter.excludeNames)
+	assert.Equal(t, map[string]string{"git_revision": "release-*"}, filter.matchFields)
+	assert.Equal(t, 2, filter.offset)
+	assert.Equal(t, 5, filter.limit)
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	for name, params := range map[string]map[string]interface{}{
+		"selector type":     {labelSelectorParam: 1.0},
+		"selector syntax":   {labelSelectorParam: "team in (a"},
+		"names type":        {namesParam: 1.0},
+		"names item type":   {namesParam: []interface{}{"a", 1.0}},
+		"names pattern":     {namesParam: "team-[a"},
+		"exclude pattern":   {excludeNamesParam: []interface{}{"team-[a"}},
+		"matchFields type":  {matchFieldsParam: "git_revision=main"},
+		"matchFields value": {matchFieldsParam: map[string]interface{}{"git_revision": 1.0}},
+		"matchFields glob":  {matchFieldsParam: map[string]interface{}{"git_revision": "[main"}},
+		"offset fraction":   {offsetParam: 1.5},
+		"offset string":     {offsetParam: "one"},
+		"offset negative":   {offsetParam: -1.0},
+		"limit type":        {limitParam: true},
+		"limit negative":    {limitParam: "-1"},
+	} {
+		_, err := parseFilter(params)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestPaasFilter_matchesName(t *testing.T) {
+	filter := paasFilter{}
+	assert.True(t, filter.matchesName("team-a-app"))
+
+	filter = paasFilter{names: []string{"team-a-*", "team-b-*"}, excludeNames: []string{"*-test"}}
+	assert.True(t, filter.matchesName("team-a-app"))
+	assert.True(t, filter.matchesName("team-b-app"))
+	assert.False(t, filter.matchesName("team-c-app"))
+	assert.False(t, filter.matchesName("team-a-test"))
+}
+
+func TestPaasFilter_matchesElements(t *testing.T) {
+	elements := fields.Elements{"git_revision": "release-1", "replicas": 3.0}
+
+	assert.True(t, paasFilter{}.matchesElements(elements))
+	assert.True(t, paasFilter{matchFields: map[string]string{"git_revision": "release-*"}}.matchesElements(elements))
+	assert.True(t, paasFilter{matchFields: map[string]string{"replicas": "3"}}.matchesElements(elements))
+	assert.False(t, paasFilter{matchFields: map[string]string{"git_revision": "main"}}.matchesElements(elements))
+	assert.False(t, paasFilter{matchFields: map[string]string{"git_path": "*"}}.matchesElements(elements))
+}
+
+func TestPaasFilter_page(t *testing.T) {
+	results := []map[string]interface{}{{"paas": "a"}, {"paas": "b"}, {"paas": "c"}}
+
+	assert.Equal(t, results, paasFilter{}.page(results))
+	assert.Equal(t, results[1:], paasFilter{offset: 1}.page(results))
+	assert.Equal(t, results[:2], paasFilter{limit: 2}.page(results))
+	assert.Equal(t, results[1:2], paasFilter{offset: 1, limit: 1}.page(results))
+	assert.Equal(t, results[2:], paasFilter{offset: 2, limit: 5}.page(results))
+	assert.Empty(t, paasFilter{offset: 3}.page(results))
+}
@@ -8,6 +8,8 @@ package argocd_plugin_generator
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net"
@@ -16,6 +18,7 @@ import (
 	"time"
 
 	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 )
 
@@ -28,11 +31,23 @@ const pluginServerTimeout = 10 * time.Second
 // Use "0" to disable the server entirely.
 //
 // TokenEnvVar specifies the name of the environment variable from which
-// the bearer token will be read. This token is required for authenticating
-// incoming requests from ArgoCD.
+// the bearer token will be read. This token (or a TokenSecret) is required
+// for authenticating incoming requests from ArgoCD.
+//
+// TokenSecret optionally specifies the Secret (as namespace/name) holding
+// additional tokens, each of which may be used for a list of capabilities.
+//
+// CertFile and KeyFile optionally specify a certificate and key, which are
+// used to serve over TLS. They are reloaded when they change on disk.
+// ClientCAFile optionally specifies a CA bundle, which is used to require
+// and verify client certificates (mTLS).
 type ServerOptions struct {
-	Addr        string
-	TokenEnvVar string
+	Addr         string
+	TokenEnvVar  string
+	TokenSecret  string
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
 }
 
 // GeneratorServer represents the HTTP server that exposes the ArgoCD plug-in generator endpoint.
@@ -65,11 +80,17 @@ func (s *GeneratorServer) Start(ctx context.Context) error {
 	ctx, componentLogger := logging.GetLogComponent(ctx, logging.PluginGeneratorComponent)
 	logger := componentLogger.With().Str("server", s.opts.Addr).Logger()
 	token := os.Getenv(s.opts.TokenEnvVar)
-	if token == "" {
+	if token == "" && s.opts.TokenSecret == "" {
 		logger.Error().Msg("token not set")
 		return fmt.Errorf("environment variable %s not set", s.opts.TokenEnvVar)
 	}
 
+	tlsConfig, err := s.tlsConfig(ctx)
+	if err != nil {
+		logger.Error().AnErr("error", err).Msg("failed to configure TLS")
+		return err
+	}
+
 	s.server = &http.Server{
 		Addr:         s.opts.Addr,
 		Handler:      s.handler,
@@ -82,6 +103,9 @@ func (s *GeneratorServer) Start(ctx context.Context) error {
 		logger.Error().AnErr("error", err).Msg("Failed to create listener")
 		return err
 	}
+	if tlsConfig != nil {
+		ln = tls.NewListener(ln, tlsConfig)
+	}
 	s.started = true
 
 	go func() {
@@ -97,6 +121,43 @@ func (s *GeneratorServer) Start(ctx context.Context) error {
 	return s.server.Serve(ln)
 }
 
+// tlsConfig returns the TLS configuration for the server, or nil when the server should not use TLS. The
+// certificate is watched for changes until ctx is done.
+func (s *GeneratorServer) tlsConfig(ctx context.Context) (*tls.Config, error) {
+	if s.opts.CertFile == "" {
+		if s.opts.ClientCAFile != "" {
+			return nil, errors.New("a client CA requires a certificate and key to serve TLS")
+		}
+		return nil, nil
+	}
+	watcher, err := certwatcher.New(s.opts.CertFile, s.opts.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate: %w", err)
+	}
+	go func() {
+		_ = watcher.Start(ctx)
+	}()
+
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: watcher.GetCertificate,
+	}
+	if s.opts.ClientCAFile == "" {
+		return tlsConfig, nil
+	}
+	caPEM, err := os.ReadFile(s.opts.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in client CA %s", s.opts.ClientCAFile)
+	}
+	tlsConfig.ClientCAs = clientCAs
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	return tlsConfig, nil
+}
+
 // StartedChecker returns a healthz.Checker which reports healthy after the
 // server has been started and is reachable over TCP.
 func (s *GeneratorServer) StartedChecker() healthz.Checker {
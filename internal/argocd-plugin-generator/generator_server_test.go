@@ -61,6 +61,24 @@ var _ = Describe("GeneratorServer", func() {
 		Expect(err).To(MatchError(fmt.Sprintf("environment variable %s not set", testTokenEnvVar)))
 	})
 
+	It("returns an error if a client CA is set without a certificate", func() {
+		os.Setenv(testTokenEnvVar, tokenValue)
+		opts.ClientCAFile = "/path/to/ca.crt"
+		server = NewServer(opts, handler)
+		err := server.Start(ctx)
+		Expect(err).To(MatchError("a client CA requires a certificate and key to serve TLS"))
+	})
+
+	It("returns an error if the certificate cannot be loaded", func() {
+		os.Setenv(testTokenEnvVar, tokenValue)
+		opts.CertFile = "/path/to/tls.crt"
+		opts.KeyFile = "/path/to/tls.key"
+		server = NewServer(opts, handler)
+		err := server.Start(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("failed to load certificate"))
+	})
+
 	It("starts the server successfully when the token is set", func() {
 		os.Setenv(testTokenEnvVar, tokenValue)
 		server = NewServer(opts, handler)
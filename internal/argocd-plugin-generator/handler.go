@@ -12,12 +12,16 @@ See LICENSE.md for details.
 package argocd_plugin_generator
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	"github.com/rs/zerolog"
 )
 
 // GeneratorService defines the contract for services that generate data
@@ -31,21 +35,24 @@ type GeneratorService interface {
 // Handler is the HTTP request handler for the plug-in generator.
 //
 // It validates incoming requests, enforces authentication using the
-// configured bearer token, and delegates the core processing logic
-// to the provided GeneratorService implementation.
+// configured bearer tokens, authorizes the requested capability for
+// the token, and delegates the core processing logic to the provided
+// GeneratorService implementation.
 type Handler struct {
-	service     GeneratorService
-	bearerToken string
+	service GeneratorService
+	tokens  *tokenStore
 }
 
 // NewHandler creates a new Handler instance.
 //
 // The service parameter provides the generator's business logic,
-// and bearerToken is used to authenticate incoming HTTP requests.
+// and bearerToken (which allows all capabilities) is used to
+// authenticate incoming HTTP requests. Additional tokens can be
+// loaded from a Secret.
 func NewHandler(service GeneratorService, bearerToken string) *Handler {
 	return &Handler{
-		service:     service,
-		bearerToken: bearerToken,
+		service: service,
+		tokens:  newTokenStore(bearerToken),
 	}
 }
 
@@ -68,12 +75,13 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	authHeader := r.Header.Get("Authorization")
-	if authHeader != "Bearer "+h.bearerToken {
-		logger.Error().Str("header", authHeader).Msg("invalid header")
+	token, ok := h.tokens.authenticate(r.Header.Get("Authorization"))
+	if !ok {
+		logger.Error().Msg("invalid or missing bearer token")
 		http.Error(w, "forbidden", http.StatusForbidden)
 		return
 	}
+	logger = logger.With().Str("token", token.Name).Logger()
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -89,6 +97,14 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A missing capability (or one which is not a string) is only allowed for tokens which allow all capabilities
+	capName, _ := input.Input.Parameters["capability"].(string)
+	if !token.allows(capName) {
+		logger.Error().Str("capability", capName).Msg("capability not allowed for token")
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
 	result, err := h.service.Generate(input.Input.Parameters, input.ApplicationSetName)
 	if err != nil {
 		logger.Error().AnErr("error", err).Msg("generation error")
@@ -96,6 +112,12 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	writeResponse(w, r, logger, result)
+}
+
+// writeResponse encodes the result as a PluginResponse with an ETag. When the request has a matching If-None-Match
+// header, only the ETag is returned with status 304 (Not Modified).
+func writeResponse(w http.ResponseWriter, r *http.Request, logger zerolog.Logger, result []map[string]interface{}) {
 	if result == nil {
 		logger.Debug().Msg("generate returns nil")
 		result = []map[string]interface{}{}
@@ -105,16 +127,41 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	response := PluginResponse{}
 	response.Output.Parameters = result
 
+	var encoded bytes.Buffer
+	if err := json.NewEncoder(&encoded).Encode(response); err != nil {
+		logger.Error().AnErr("error", err).Msg("json encoder failure")
+		http.Error(w, fmt.Sprintf("json encoder failure: %v", err), http.StatusInternalServerError)
+		return
+	}
+	etag := fmt.Sprintf(`"%x"`, sha256.Sum256(encoded.Bytes()))
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		logger.Debug().Msg("not modified")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	err = json.NewEncoder(w).Encode(response)
-	if err != nil {
-		logger.Error().AnErr("error", err).Msg("json encoder failure")
+	if _, err := w.Write(encoded.Bytes()); err != nil {
+		logger.Error().AnErr("error", err).Msg("write failure")
 		return
 	}
 	logger.Debug().Msg("OK")
 }
 
+// etagMatches returns true when the If-None-Match header contains the etag (or "*"). Weak comparison is used, as
+// described in RFC 9110, so a weak validator (W/"...") also matches.
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
 // PluginInput represents the expected request payload for the plug-in generator.
 //
 // The ApplicationSetName identifies the target ApplicationSet in Argo CD.
@@ -71,6 +71,36 @@ var _ = Describe("Handler", func() {
 			Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
 		})
 
+		It("returns 403 if the capability is not allowed for the token", func() {
+			handler.tokens.setSecretTokens([]generatorToken{
+				{Name: "team-a", Token: "team-a-token", Capabilities: []string{"argocd"}},
+			})
+			mockService.generateFunc = func(params map[string]interface{}, appSetName string) (
+				[]map[string]interface{}, error) {
+				return []map[string]interface{}{}, nil
+			}
+			doRequest := func(parameters map[string]interface{}) *http.Response {
+				body, _ := json.Marshal(map[string]interface{}{
+					"input": map[string]interface{}{
+						"parameters": parameters,
+					},
+				})
+				req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/v1/getparams.execute",
+					bytes.NewBuffer(body))
+				req.Header.Set("Authorization", "Bearer team-a-token")
+				resp, err := httpClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				return resp
+			}
+
+			Expect(doRequest(map[string]interface{}{"capability": "argocd"}).StatusCode).To(Equal(http.StatusOK))
+			Expect(doRequest(map[string]interface{}{"capability": "sso"}).StatusCode).To(Equal(http.StatusForbidden))
+			By("denying requests without a capability (or with a capability which is not a string)")
+			Expect(doRequest(map[string]interface{}{}).StatusCode).To(Equal(http.StatusForbidden))
+			Expect(doRequest(map[string]interface{}{"capability": ""}).StatusCode).To(Equal(http.StatusForbidden))
+			Expect(doRequest(map[string]interface{}{"capability": 1}).StatusCode).To(Equal(http.StatusForbidden))
+		})
+
 		It("returns 400 if body cannot be read", func() {
 			mockService.generateFunc = func(params map[string]interface{}, appSetName string) (
 				[]map[string]interface{}, error) {
@@ -158,6 +188,39 @@ var _ = Describe("Handler", func() {
 				map[string]interface{}{"key2": "value2"},
 			}))
 		})
+
+		It("returns 304 when the ETag matches If-None-Match", func() {
+			mockService.generateFunc = func(params map[string]interface{}, appSetName string) (
+				[]map[string]interface{}, error) {
+				return []map[string]interface{}{{"key1": "value1"}}, nil
+			}
+			body := []byte(`{"applicationSetName":"appset1","input":{"parameters":{"foo":"bar"}}}`)
+			doRequest := func(ifNoneMatch string) *http.Response {
+				req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/v1/getparams.execute",
+					bytes.NewBuffer(body))
+				req.Header.Set("Authorization", "Bearer "+bearerToken)
+				if ifNoneMatch != "" {
+					req.Header.Set("If-None-Match", ifNoneMatch)
+				}
+				resp, err := httpClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				return resp
+			}
+
+			resp := doRequest("")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			etag := resp.Header.Get("ETag")
+			Expect(etag).NotTo(BeEmpty())
+
+			resp = doRequest(`"other", ` + etag)
+			Expect(resp.StatusCode).To(Equal(http.StatusNotModified))
+			Expect(resp.Header.Get("ETag")).To(Equal(etag))
+			respBody, _ := io.ReadAll(resp.Body)
+			Expect(respBody).To(BeEmpty())
+
+			resp = doRequest(`"other"`)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		})
 	})
 })
 
@@ -0,0 +1,42 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package argocd_plugin_generator
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "paas"
+	metricsSubsystem = "argocd_plugin_generator"
+	cacheHit         = "hit"
+	cacheMiss        = "miss"
+)
+
+var (
+	// cacheRequests counts generate requests per capability and cache result (hit or miss), from which the cache hit
+	// ratio can be derived
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "cache_requests_total",
+		Help:      "Number of generate requests per capability, by result of the cache lookup (hit or miss)",
+	}, []string{"capability", "result"})
+	// generationDuration observes the time it takes to generate results for a capability on a cache miss
+	generationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "generation_duration_seconds",
+		Help:      "Time it takes to generate the results for a capability when they are not cached",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"capability"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(cacheRequests, generationDuration)
+}
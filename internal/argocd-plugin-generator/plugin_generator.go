@@ -8,9 +8,16 @@ package argocd_plugin_generator
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
 
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 )
@@ -40,42 +47,126 @@ type GeneratorServerInterface interface {
 type PluginGenerator struct {
 	service *Service
 	server  GeneratorServerInterface
+	kclient client.Reader
+	// informers (when set) are used to invalidate the cached results of the service and to reload the tokens
+	informers cache.Informers
+	// tokens are used by the handler to authenticate requests
+	tokens *tokenStore
+	// tokenSecret (as namespace/name) optionally holds additional tokens
+	tokenSecret string
 }
 
 // New creates a new PluginGenerator instance using the provided
-// controller-runtime Client.
+// controller-runtime Reader (typically the informer cache of the manager).
 //
-// The client is passed to the Service for interacting with Kubernetes
+// The reader is passed to the Service for reading Kubernetes
 // objects, and the server will be configured internally to use this service.
-func New(kclient client.Client, bindAddr string) *PluginGenerator {
+// When the reader is an informer cache, generated results are cached until
+// they are invalidated by Paas and PaasConfig events, and tokens from the
+// token Secret (see ServerOptions) are reloaded when the Secret changes.
+func New(kclient client.Reader, opts ServerOptions) *PluginGenerator {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	_, logger := logging.GetLogComponent(ctx, logging.PluginGeneratorComponent)
 	generatorService := NewService(kclient)
 
-	token := os.Getenv(tokenEnvVar)
-	logger.Debug().Str("token", token).Msg("token")
-	handler := NewHandler(generatorService, token)
-
-	server := NewServer(ServerOptions{
-		Addr:        bindAddr,
-		TokenEnvVar: tokenEnvVar,
-	}, handler)
+	if opts.TokenEnvVar == "" {
+		opts.TokenEnvVar = tokenEnvVar
+	}
+	handler := NewHandler(generatorService, os.Getenv(opts.TokenEnvVar))
+	server := NewServer(opts, handler)
 
 	logger.Debug().Msg("New PluginGenerator")
-	return &PluginGenerator{
-		service: generatorService,
-		server:  server,
+	pg := &PluginGenerator{
+		service:     generatorService,
+		server:      server,
+		kclient:     kclient,
+		tokens:      handler.tokens,
+		tokenSecret: opts.TokenSecret,
+	}
+	if informers, ok := kclient.(cache.Informers); ok {
+		pg.informers = informers
+		generatorService.cache = newResultsCache()
 	}
+	return pg
 }
 
 // Start satisfies Runnable so that the manager can start the runnable
 func (pg *PluginGenerator) Start(ctx context.Context) error {
 	_, logger := logging.GetLogComponent(ctx, logging.PluginGeneratorComponent)
 	logger.Debug().Msg("started")
+	if err := pg.addCacheEventHandlers(ctx); err != nil {
+		logger.Error().AnErr("error", err).Msg("failed to add cache event handlers")
+		return err
+	}
+	if err := pg.loadTokenSecret(ctx); err != nil {
+		logger.Error().AnErr("error", err).Msg("failed to load token secret")
+		return err
+	}
 	return pg.server.Start(ctx)
 }
 
+// loadTokenSecret loads the tokens from the token Secret (if configured), and adds an event handler to the Secret
+// informer which reloads them when the Secret changes. A missing or invalid Secret is logged, as it may be fixed
+// later on.
+func (pg *PluginGenerator) loadTokenSecret(ctx context.Context) error {
+	if pg.tokenSecret == "" || pg.tokens == nil {
+		return nil
+	}
+	_, logger := logging.GetLogComponent(ctx, logging.PluginGeneratorComponent)
+	namespace, name, found := strings.Cut(pg.tokenSecret, "/")
+	if !found || namespace == "" || name == "" {
+		return fmt.Errorf("token secret %s should be specified as namespace/name", pg.tokenSecret)
+	}
+	secretName := types.NamespacedName{Namespace: namespace, Name: name}
+
+	var secret corev1.Secret
+	if err := pg.kclient.Get(ctx, secretName, &secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get token secret: %w", err)
+		}
+		logger.Warn().Str("secret", pg.tokenSecret).Msg("token secret not found")
+	} else {
+		// Errors are logged by loadSecret
+		_ = pg.tokens.loadSecret(&secret)
+	}
+
+	if pg.informers == nil {
+		return nil
+	}
+	informer, err := pg.informers.GetInformer(ctx, &corev1.Secret{})
+	if err != nil {
+		return fmt.Errorf("failed to get informer for Secret: %w", err)
+	}
+	if _, err = informer.AddEventHandler(pg.tokens.secretEventHandler(secretName)); err != nil {
+		return fmt.Errorf("failed to add Secret event handler: %w", err)
+	}
+	return nil
+}
+
+// addCacheEventHandlers adds event handlers to the Paas and PaasConfig informers, which invalidate the cached
+// results of the service
+func (pg *PluginGenerator) addCacheEventHandlers(ctx context.Context) error {
+	if pg.informers == nil || pg.service == nil || pg.service.cache == nil {
+		return nil
+	}
+	paasInformer, err := pg.informers.GetInformer(ctx, &v1alpha2.Paas{})
+	if err != nil {
+		return fmt.Errorf("failed to get informer for Paas: %w", err)
+	}
+	if _, err = paasInformer.AddEventHandler(pg.service.cache.paasEventHandler()); err != nil {
+		return fmt.Errorf("failed to add Paas event handler: %w", err)
+	}
+	configInformer, err := pg.informers.GetInformer(ctx, &v1alpha2.PaasConfig{})
+	if err != nil {
+		return fmt.Errorf("failed to get informer for PaasConfig: %w", err)
+	}
+	if _, err = configInformer.AddEventHandler(pg.service.cache.paasConfigEventHandler()); err != nil {
+		return fmt.Errorf("failed to add PaasConfig event handler: %w", err)
+	}
+	return nil
+}
+
 // NeedLeaderElection satisfies LeaderElectionRunnable
 func (pg *PluginGenerator) NeedLeaderElection() bool {
 	// Returning false means that this runnable does not need LeaderElection
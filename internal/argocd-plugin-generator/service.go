@@ -8,11 +8,16 @@ package argocd_plugin_generator
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/belastingdienst/opr-paas/v3/internal/argocd-plugin-generator/fields"
 	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	"github.com/belastingdienst/opr-paas/v3/internal/validate"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/belastingdienst/opr-paas/v3/internal/config"
@@ -24,20 +29,24 @@ import (
 
 // Service provides the business logic for the plug-in generator.
 //
-// It encapsulates the Kubernetes controller-runtime client so that
-// it can interact with cluster resources (e.g., list or get custom
-// resources) as part of processing incoming plug-in requests.
+// It encapsulates a Kubernetes controller-runtime reader so that
+// it can read cluster resources (e.g., list custom resources) as
+// part of processing incoming plug-in requests.
+//
+// When the cache is set, generated results are cached per capability
+// until they are invalidated by informer events (see resultsCache).
 type Service struct {
-	kclient client.Client
+	kclient client.Reader
+	cache   *resultsCache
 }
 
 // NewService creates a new Service instance.
 //
-// The provided controller-runtime Client will be used to read or
-// modify Kubernetes objects. Typically, this client is injected
-// by the controller manager and is backed by the shared informer
-// cache for efficiency.
-func NewService(kclient client.Client) *Service {
+// The provided controller-runtime Reader will be used to read
+// Kubernetes objects. Typically, this is the shared informer cache
+// of the controller manager, so that requests are served from the
+// cache instead of listing all Paas'es from the API server.
+func NewService(kclient client.Reader) *Service {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	_, logger := logging.GetLogComponent(ctx, logging.PluginGeneratorComponent)
@@ -47,47 +56,104 @@ func NewService(kclient client.Client) *Service {
 
 // Generate returns a generated []map[string]interface{} based on the provided map[string]interface. The input map
 // should contain a key: "capability" which stands for the capability, for which a map of parameters is generated.
-// in case the input param is missing, or the generation fails, an error is returned.
+// Optional parameters (labelSelector, names, excludeNames, matchFields, offset and limit) select a subset of all
+// Paas'es. Results are ordered by the name of the Paas. Cached results are shared and should not be modified.
+// in case the input param is missing or invalid, or the generation fails, an error is returned.
 func (s *Service) Generate(params map[string]interface{}, appSetName string) ([]map[string]interface{}, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	_, logger := logging.GetLogComponent(ctx, logging.PluginGeneratorComponent)
 
-	var paasList v1alpha2.PaasList
-	if err := s.kclient.List(ctx, &paasList); err != nil {
-		logger.Error().AnErr("error", err).Msg("List error")
-		return nil, err
-	}
-
-	logger.Debug().Int("num_paases", len(paasList.Items)).Msg("ArgoCD plugin cap")
 	capName, ok := params["capability"].(string)
 	if !ok || capName == "" {
 		logger.Error().Str("name", capName).Msg("invalid capability param")
 		return nil, errors.New("missing or invalid capability param")
 	}
+	filter, err := parseFilter(params)
+	if err != nil {
+		logger.Error().AnErr("error", err).Msg("invalid filter params")
+		return nil, err
+	}
+
+	// The key only depends on the params, as json.Marshal sorts map keys
+	key, err := json.Marshal(params)
+	if s.cache == nil || err != nil {
+		results, _, generateErr := s.generate(ctx, capName, filter)
+		return results, generateErr
+	}
+	results, version, exists := s.cache.get(capName, string(key), config.Generation())
+	if exists {
+		cacheRequests.WithLabelValues(capName, cacheHit).Inc()
+		logger.Debug().Int("num_results", len(results)).Msg("returning cached results")
+		return results, nil
+	}
+	cacheRequests.WithLabelValues(capName, cacheMiss).Inc()
+	results, expires, err := s.generate(ctx, capName, filter)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.set(capName, string(key), version, results, expires)
+	return results, nil
+}
+
+// generate returns the results for a capability for all Paas'es selected by the filter. It also returns the time at
+// which the results expire, because the first pin of a selected Paas to a PaasConfigRevision expires (zero when none
+// of the selected Paas'es is pinned).
+func (s *Service) generate(
+	ctx context.Context,
+	capName string,
+	filter paasFilter,
+) (results []map[string]interface{}, expires time.Time, err error) {
+	_, logger := logging.GetLogComponent(ctx, logging.PluginGeneratorComponent)
+	start := time.Now()
+	defer func() {
+		generationDuration.WithLabelValues(capName).Observe(time.Since(start).Seconds())
+	}()
+
+	var paasList v1alpha2.PaasList
+	if err = s.kclient.List(ctx, &paasList, client.MatchingLabelsSelector{Selector: filter.labelSelector}); err != nil {
+		logger.Error().AnErr("error", err).Msg("List error")
+		return nil, time.Time{}, err
+	}
+	slices.SortFunc(paasList.Items, func(a, b v1alpha2.Paas) int { return strings.Compare(a.Name, b.Name) })
+	logger.Debug().Int("num_paases", len(paasList.Items)).Msg("ArgoCD plugin cap")
 
-	var results []map[string]interface{}
 	for _, paas := range paasList.Items {
-		elements, err := capElementsFromPaas(ctx, &paas, capName)
-		if err != nil {
-			logger.Error().Str("paas_name", paas.Name).AnErr("error", err).Msg("failed to get elements")
+		if !filter.matchesName(paas.Name) {
+			continue
+		}
+		if until := pinExpiry(paas, start); !until.IsZero() && (expires.IsZero() || until.Before(expires)) {
+			expires = until
+		}
+		elements, elementsErr := capElementsFromPaas(ctx, &paas, capName)
+		if elementsErr != nil {
+			logger.Error().Str("paas_name", paas.Name).AnErr("error", elementsErr).Msg("failed to get elements")
 			continue // skip failed ones
 		}
-		if elements == nil {
+		if elements == nil || !filter.matchesElements(elements) {
 			continue
 		}
 
-		strMap := elements.GetElementsAsStringMap()
-
-		inf := make(map[string]interface{}, len(strMap))
-		for k, v := range strMap {
+		// Structured values (e.g. from custom fields with a schema) are passed as is
+		inf := make(map[string]interface{}, len(elements))
+		for k, v := range elements {
 			inf[k] = v
 		}
 		logger.Debug().Str("paas_name", paas.Name).Int("num_elements", len(inf)).Msg("added paas")
 		results = append(results, inf)
 	}
 
-	return results, nil
+	return filter.page(results), expires, nil
+}
+
+// pinExpiry returns the time at which the pin of a Paas to a PaasConfigRevision expires, or a zero time when the Paas
+// is not pinned
+func pinExpiry(paas v1alpha2.Paas, now time.Time) time.Time {
+	revisionName, until, err := paas.PinnedRevision(now)
+	if err != nil || revisionName == "" {
+		return time.Time{}
+	}
+	return until
 }
 
 func capElementsFromPaas(
@@ -97,12 +163,13 @@ func capElementsFromPaas(
 ) (elements fields.Elements, err error) {
 	_, componentLogger := logging.GetLogComponent(ctx, logging.PluginGeneratorComponent)
 	logger := componentLogger.With().Str("paas", paas.Name).Str("capability", capName).Logger()
-	myConfig, err := config.GetConfigWithError()
+	// Pinned and canary Paas'es use the capabilities and templates of their PaasConfigRevision or candidate
+	myConfig, err := config.GetConfigForPaasWithError(paas)
 	if err != nil {
-		logger.Error().AnErr("error", err).Msg("getting error failed")
+		logger.Error().AnErr("error", err).Msg("getting config failed")
 		return nil, err
 	}
-	templater := templating.NewTemplater(*paas, *myConfig)
+	templater := templating.NewTemplater(*paas, myConfig)
 	capConfig, exists := myConfig.Spec.Capabilities[capName]
 	if !exists {
 		logger.Error().Msg("capability is not configured")
@@ -120,7 +187,7 @@ func capElementsFromPaas(
 		return nil, nil
 	}
 
-	capElements, err := capability.CapExtraFields(myConfig.Spec.Capabilities[capName].CustomFields)
+	capElements, err := validate.CapExtraFields(capability, myConfig.Spec.Capabilities[capName].CustomFields)
 	if err != nil {
 		logger.Error().AnErr("error", err).Msg("getting capability custom fields failed")
 		return nil, err
@@ -138,6 +205,9 @@ func capElementsFromPaas(
 		}
 	}
 
+	if version := capConfig.ResolveVersion(capability.Version); version != "" {
+		elements["version"] = version
+	}
 	elements["paas"] = paas.Name
 	logger.Debug().Str("paas", paas.Name).Int("num_elements", len(elements)).Msg("returning elements")
 	return elements, nil
@@ -82,11 +82,11 @@ var _ = Describe("Service", func() {
 					Quota:     quota.Quota{},
 					Capabilities: map[string]v1alpha2.PaasCapability{
 						"argocd": {
-							CustomFields: map[string]string{
+							CustomFields: v1alpha2.PaasCustomFieldsFromStrings(map[string]string{
 								"git_url":      paasArgoGitURL,
 								"git_path":     paasArgoGitPath,
 								"git_revision": paasArgoGitRevision,
-							},
+							}),
 						},
 					},
 				},
@@ -129,5 +129,81 @@ var _ = Describe("Service", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal("missing or invalid capability param"))
 		})
+
+		It("filters, orders and pages results using the optional params", func() {
+			By("Creating Paas'es with labels and custom fields")
+
+			for _, p := range []struct{ name, team, revision string }{
+				{"filter-b-app", "b", "main"},
+				{"filter-a-app", "a", "release-1"},
+				{"filter-a-test", "a", "main"},
+				{"filter-a-web", "a", "release-2"},
+			} {
+				paas := &v1alpha2.Paas{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   p.name,
+						Labels: map[string]string{"team": p.team},
+					},
+					Spec: v1alpha2.PaasSpec{
+						Requestor: paasRequestor,
+						Capabilities: map[string]v1alpha2.PaasCapability{
+							"argocd": {
+								CustomFields: v1alpha2.PaasCustomFieldsFromStrings(map[string]string{
+									"git_url":      paasArgoGitURL,
+									"git_revision": p.revision,
+								}),
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Create(context.Background(), paas)).To(Succeed())
+			}
+
+			paasNames := func(results []map[string]interface{}) (names []string) {
+				for _, result := range results {
+					names = append(names, result["paas"].(string))
+				}
+				return names
+			}
+
+			By("Selecting by label and excluding names")
+
+			results, err := svc.Generate(map[string]interface{}{
+				"capability":    "argocd",
+				"labelSelector": "team=a",
+				"excludeNames":  "*-test",
+			}, "some-app-set")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(paasNames(results)).To(Equal([]string{"filter-a-app", "filter-a-web"}))
+
+			By("Matching names and custom fields")
+
+			results, err = svc.Generate(map[string]interface{}{
+				"capability":  "argocd",
+				"names":       []interface{}{"filter-*"},
+				"matchFields": map[string]interface{}{"git_revision": "release-*"},
+			}, "some-app-set")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(paasNames(results)).To(Equal([]string{"filter-a-app", "filter-a-web"}))
+
+			By("Paging through the results")
+
+			results, err = svc.Generate(map[string]interface{}{
+				"capability": "argocd",
+				"names":      "filter-*",
+				"offset":     1.0,
+				"limit":      2.0,
+			}, "some-app-set")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(paasNames(results)).To(Equal([]string{"filter-a-test", "filter-a-web"}))
+
+			By("Calling Generate with an invalid filter param")
+
+			_, err = svc.Generate(map[string]interface{}{
+				"capability": "argocd",
+				"limit":      -1.0,
+			}, "some-app-set")
+			Expect(err).To(MatchError("limit should not be negative"))
+		})
 	})
 })
@@ -0,0 +1,175 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package argocd_plugin_generator
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// TokensSecretKey is the key in the token Secret which holds the list of tokens
+	TokensSecretKey = "tokens.yaml"
+	// allCapabilities can be used in the capabilities of a token to allow all capabilities
+	allCapabilities = "*"
+	// envTokenName is the name of the token read from the token environment variable
+	envTokenName = "environment"
+)
+
+// generatorToken is a bearer token which may be used to generate parameters for some capabilities
+type generatorToken struct {
+	// Name identifies the token in logging, as the token itself is never logged
+	Name string `json:"name"`
+	// Token is the bearer token
+	Token string `json:"token"`
+	// Capabilities lists the capabilities for which the token may be used, where "*" means all capabilities
+	Capabilities []string `json:"capabilities"`
+}
+
+// allows returns true when the token may be used to generate parameters for the capability. Tokens which are scoped
+// to some capabilities do not allow requests without a capability.
+func (t generatorToken) allows(capName string) bool {
+	if slices.Contains(t.Capabilities, allCapabilities) {
+		return true
+	}
+	return capName != "" && slices.Contains(t.Capabilities, capName)
+}
+
+// parseTokens parses and validates a list of tokens, as stored in the token Secret
+func parseTokens(data []byte) ([]generatorToken, error) {
+	var tokens []generatorToken
+	if err := yaml.UnmarshalStrict(data, &tokens); err != nil {
+		return nil, fmt.Errorf("invalid tokens: %w", err)
+	}
+	seen := map[string]bool{}
+	for i, token := range tokens {
+		if token.Name == "" {
+			return nil, fmt.Errorf("token %d has no name", i)
+		}
+		if token.Token == "" {
+			return nil, fmt.Errorf("token %s has no value", token.Name)
+		}
+		if len(token.Capabilities) == 0 {
+			return nil, fmt.Errorf("token %s has no capabilities", token.Name)
+		}
+		if seen[token.Token] {
+			// Names may be used more than once (e.g. during rotation), but values should be unique
+			return nil, fmt.Errorf("token %s has the same value as another token", token.Name)
+		}
+		seen[token.Token] = true
+	}
+	return tokens, nil
+}
+
+// tokenStore is a thread-safe store of all tokens which may be used to authenticate requests. It holds the token
+// from the environment variable (if set), which allows all capabilities, and the tokens from the token Secret.
+type tokenStore struct {
+	mutex        sync.RWMutex
+	envToken     string
+	secretTokens []generatorToken
+}
+
+// newTokenStore returns a tokenStore holding the token from the environment variable (which may be empty)
+func newTokenStore(envToken string) *tokenStore {
+	return &tokenStore{envToken: envToken}
+}
+
+// setSecretTokens replaces all tokens from the token Secret
+func (s *tokenStore) setSecretTokens(tokens []generatorToken) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.secretTokens = tokens
+}
+
+// authenticate returns the token matching the bearer token in the Authorization header. All tokens are compared in
+// constant time, so that the response time does not reveal (parts of) valid tokens.
+func (s *tokenStore) authenticate(authHeader string) (match generatorToken, ok bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	bearer, hasPrefix := strings.CutPrefix(authHeader, "Bearer ")
+	if !hasPrefix || bearer == "" {
+		return match, false
+	}
+	candidates := s.secretTokens
+	if s.envToken != "" {
+		candidates = append([]generatorToken{{
+			Name:         envTokenName,
+			Token:        s.envToken,
+			Capabilities: []string{allCapabilities},
+		}}, candidates...)
+	}
+	for _, candidate := range candidates {
+		if subtle.ConstantTimeCompare([]byte(bearer), []byte(candidate.Token)) == 1 {
+			match, ok = candidate, true
+		}
+	}
+	return match, ok
+}
+
+// secretEventHandler returns informer event handlers which reload the tokens when the token Secret is added or
+// updated, and remove them when it is deleted
+func (s *tokenStore) secretEventHandler(secretName types.NamespacedName) cache.ResourceEventHandler {
+	isTokenSecret := func(obj interface{}) (*corev1.Secret, bool) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		secret, ok := obj.(*corev1.Secret)
+		return secret, ok && secret.Namespace == secretName.Namespace && secret.Name == secretName.Name
+	}
+	load := func(obj interface{}) {
+		if secret, ok := isTokenSecret(obj); ok {
+			// Errors are logged by loadSecret, and the previous tokens are kept
+			_ = s.loadSecret(secret)
+		}
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: load,
+		UpdateFunc: func(_, newObj interface{}) {
+			load(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if _, ok := isTokenSecret(obj); ok {
+				s.setSecretTokens(nil)
+			}
+		},
+	}
+}
+
+// loadSecret replaces the tokens from the token Secret. When the Secret holds invalid tokens, an error is returned
+// and the previous tokens are kept.
+func (s *tokenStore) loadSecret(secret *corev1.Secret) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, componentLogger := logging.GetLogComponent(ctx, logging.PluginGeneratorComponent)
+	logger := componentLogger.With().Str("secret", secret.Namespace+"/"+secret.Name).Logger()
+
+	data, exists := secret.Data[TokensSecretKey]
+	if !exists {
+		err := errors.New("token secret has no " + TokensSecretKey)
+		logger.Error().AnErr("error", err).Msg("failed to load tokens")
+		return err
+	}
+	tokens, err := parseTokens(data)
+	if err != nil {
+		logger.Error().AnErr("error", err).Msg("failed to load tokens")
+		return err
+	}
+	s.setSecretTokens(tokens)
+	logger.Info().Int("num_tokens", len(tokens)).Msg("loaded tokens")
+	return nil
+}
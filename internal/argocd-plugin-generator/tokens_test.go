@@ -0,0 +1,123 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package argocd_plugin_generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+const validTokens = `
+- name: team-a
+  token: token-a
+  capabilities: [argocd]
+- name: platform
+  token: token-platform
+  capabilities: ["*"]
+`
+
+func TestParseTokens(t *testing.T) {
+	tokens, err := parseTokens([]byte(validTokens))
+	require.NoError(t, err)
+	assert.Equal(t, []generatorToken{
+		{Name: "team-a", Token: "token-a", Capabilities: []string{"argocd"}},
+		{Name: "platform", Token: "token-platform", Capabilities: []string{allCapabilities}},
+	}, tokens)
+
+	for name, data := range map[string]string{
+		"not a list":         "name: team-a",
+		"unknown field":      "- {name: a, token: a, capabilities: [argocd], scope: all}",
+		"no name":            "- {token: a, capabilities: [argocd]}",
+		"no value":           "- {name: a, capabilities: [argocd]}",
+		"no capabilities":    "- {name: a, token: a}",
+		"duplicate value":    "- {name: a, token: a, capabilities: [argocd]}\n- {name: b, token: a, capabilities: [sso]}",
+		"invalid yaml":       "- {name: a",
+		"capabilities value": "- {name: a, token: a, capabilities: argocd}",
+	} {
+		_, err = parseTokens([]byte(data))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestGeneratorToken_allows(t *testing.T) {
+	token := generatorToken{Capabilities: []string{"argocd", "sso"}}
+	assert.True(t, token.allows("argocd"))
+	assert.True(t, token.allows("sso"))
+	assert.False(t, token.allows("tekton"))
+	assert.False(t, token.allows(""), "scoped tokens should not allow requests without a capability")
+	assert.False(t, generatorToken{Capabilities: []string{""}}.allows(""))
+	assert.True(t, generatorToken{Capabilities: []string{allCapabilities}}.allows("tekton"))
+	assert.True(t, generatorToken{Capabilities: []string{allCapabilities}}.allows(""))
+}
+
+func TestTokenStore_authenticate(t *testing.T) {
+	tokens, err := parseTokens([]byte(validTokens))
+	require.NoError(t, err)
+	store := newTokenStore("env-token")
+	store.setSecretTokens(tokens)
+
+	token, ok := store.authenticate("Bearer token-a")
+	assert.True(t, ok)
+	assert.Equal(t, "team-a", token.Name)
+
+	token, ok = store.authenticate("Bearer env-token")
+	assert.True(t, ok)
+	assert.Equal(t, envTokenName, token.Name)
+	assert.True(t, token.allows("argocd"))
+
+	for _, header := range []string{"", "Bearer ", "Bearer token", "Bearer token-a-suffix", "token-a", "Basic token-a"} {
+		_, ok = store.authenticate(header)
+		assert.False(t, ok, header)
+	}
+
+	// Without environment token, an empty token should never authenticate
+	_, ok = newTokenStore("").authenticate("Bearer ")
+	assert.False(t, ok)
+}
+
+func TestTokenStore_secretEventHandler(t *testing.T) {
+	secretName := types.NamespacedName{Namespace: "paas-system", Name: "generator-tokens"}
+	newSecret := func(name string, data string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: secretName.Namespace, Name: name},
+			Data:       map[string][]byte{TokensSecretKey: []byte(data)},
+		}
+	}
+	store := newTokenStore("")
+	handler := store.secretEventHandler(secretName)
+
+	handler.OnAdd(newSecret("other-secret", validTokens), false)
+	_, ok := store.authenticate("Bearer token-a")
+	assert.False(t, ok, "other secrets should be ignored")
+
+	handler.OnAdd(newSecret(secretName.Name, validTokens), false)
+	_, ok = store.authenticate("Bearer token-a")
+	assert.True(t, ok)
+
+	// Invalid tokens are not loaded, and the previous tokens are kept
+	handler.OnUpdate(newSecret(secretName.Name, validTokens), newSecret(secretName.Name, "- {name: a}"))
+	_, ok = store.authenticate("Bearer token-a")
+	assert.True(t, ok)
+
+	// Rotation
+	handler.OnUpdate(newSecret(secretName.Name, validTokens),
+		newSecret(secretName.Name, "- {name: team-a, token: token-a2, capabilities: [argocd]}"))
+	_, ok = store.authenticate("Bearer token-a")
+	assert.False(t, ok)
+	_, ok = store.authenticate("Bearer token-a2")
+	assert.True(t, ok)
+
+	handler.OnDelete(cache.DeletedFinalStateUnknown{Obj: newSecret(secretName.Name, validTokens)})
+	_, ok = store.authenticate("Bearer token-a2")
+	assert.False(t, ok)
+}
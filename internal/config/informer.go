@@ -30,17 +30,38 @@ func (w *configInformer) setInitialConfig(ctx context.Context) error {
 		return fmt.Errorf("failed to retrieve PaasConfigs: %w", err)
 	}
 
-	switch len(list.Items) {
+	var active []v1alpha2.PaasConfig
+	for _, cfg := range list.Items {
+		if cfg.IsCandidate() {
+			UpdateCandidateConfig(&cfg)
+			continue
+		}
+		active = append(active, cfg)
+	}
+
+	switch len(active) {
 	case 0:
 		SetConfig(v1alpha2.PaasConfig{})
 	case 1:
-		SetConfig(list.Items[0])
+		SetConfig(active[0])
 	default:
 		return errors.New("more than one PaasConfig, this should not happen")
 	}
 	return nil
 }
 
+func (w *configInformer) setInitialRevisions(ctx context.Context) error {
+	var list v1alpha2.PaasConfigRevisionList
+
+	if err := w.mgr.GetClient().List(ctx, &list); err != nil {
+		return fmt.Errorf("failed to retrieve PaasConfigRevisions: %w", err)
+	}
+	for _, revision := range list.Items {
+		SetRevision(revision)
+	}
+	return nil
+}
+
 // Start is the runnable for the PaasConfigInformer
 func (w *configInformer) Start(ctx context.Context) error {
 	ctx, logger := logging.GetLogComponent(ctx, logging.ConfigComponent)
@@ -62,14 +83,28 @@ func (w *configInformer) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to add event handler: %w", err)
 	}
 
+	if err = w.setInitialRevisions(ctx); err != nil {
+		logger.Error().AnErr("error", err).Msg("error setting initial revisions")
+		return err
+	}
+	revisionInformer, err := w.mgr.GetCache().GetInformer(ctx, &v1alpha2.PaasConfigRevision{})
+	if err != nil {
+		return fmt.Errorf("failed to get informer for PaasConfigRevision: %w", err)
+	}
+	if err = addPaasConfigRevisionEventHandler(revisionInformer); err != nil {
+		return fmt.Errorf("failed to add revision event handler: %w", err)
+	}
+
 	<-ctx.Done() // Keep the goroutine alive
 	return nil
 }
 
-// addPaasConfigEventHandler adds the update handler to the informer. We're not interested in Additions and Deletions
+// addPaasConfigEventHandler adds the update and delete handlers to the informer. We're not interested in Additions,
+// as the status which makes a PaasConfig active (or a soaking candidate) is always set with an update.
 func addPaasConfigEventHandler(informer cache2.Informer) error {
 	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		UpdateFunc: updateHandler,
+		DeleteFunc: deleteHandler,
 	})
 	return err
 }
@@ -83,6 +118,10 @@ func updateHandler(_, newObj interface{}) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	_, logger := logging.GetLogComponent(ctx, logging.ConfigComponent)
+	if cfg.IsCandidate() {
+		UpdateCandidateConfig(cfg)
+		return
+	}
 	if cfg.IsActive() && !reflect.DeepEqual(cfg.Spec, GetConfig().Spec) {
 		logger.Debug().Any("config", cfg).Msg("updating config")
 		SetConfig(*cfg)
@@ -91,6 +130,44 @@ func updateHandler(_, newObj interface{}) {
 	}
 }
 
+// addPaasConfigRevisionEventHandler adds handlers to the informer, which keep the revisions in the store up to date
+func addPaasConfigRevisionEventHandler(informer cache2.Informer) error {
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: revisionHandler,
+		UpdateFunc: func(_, newObj interface{}) {
+			revisionHandler(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if revision, ok := obj.(*v1alpha2.PaasConfigRevision); ok {
+				DeleteRevision(revision.Name)
+			}
+		},
+	})
+	return err
+}
+
+// revisionHandler adds or updates a PaasConfigRevision in the store
+func revisionHandler(obj interface{}) {
+	if revision, ok := obj.(*v1alpha2.PaasConfigRevision); ok {
+		SetRevision(*revision)
+	}
+}
+
+// deleteHandler clears the candidate PaasConfig when it is deleted
+func deleteHandler(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	cfg, ok := obj.(*v1alpha2.PaasConfig)
+	if !ok || !cfg.IsCandidate() {
+		return
+	}
+	ClearCandidateConfig(cfg.Name)
+}
+
 func (w *configInformer) NeedLeaderElection() bool {
 	// Returning false means that this runnable does not need LeaderElection
 	return false // All replicas need to do this even though they might not be a leader
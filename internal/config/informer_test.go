@@ -55,3 +55,42 @@ func TestHandleUpdate_NoChangeToInactiveConfig(t *testing.T) {
 
 	assert.Equal(t, "unchanged", GetConfig().Spec.RequestorLabel)
 }
+
+func TestHandleUpdate_CandidateConfig(t *testing.T) {
+	SetConfig(v1alpha2.PaasConfig{Spec: v1alpha2.PaasConfigSpec{RequestorLabel: "active"}})
+	defer SetConfig(v1alpha2.PaasConfig{})
+	defer ClearCandidateConfig("candidate")
+
+	candidate := v1alpha2.PaasConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "candidate", Generation: 1},
+		Spec: v1alpha2.PaasConfigSpec{
+			RequestorLabel: "candidate",
+			Candidate: &v1alpha2.ConfigCandidate{
+				CanarySelector: metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+			},
+		},
+	}
+	canary := &v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"canary": "true"}}}
+	other := &v1alpha2.Paas{}
+
+	// not soaking yet
+	updateHandler(nil, &candidate)
+	assert.Nil(t, GetCandidateConfig())
+	assert.Equal(t, "active", GetConfigForPaas(canary).Spec.RequestorLabel)
+
+	candidate.Status.Conditions = []metav1.Condition{{
+		Type:               v1alpha2.TypeCandidatePaasConfig,
+		Status:             metav1.ConditionTrue,
+		Reason:             v1alpha2.CandidateReasonSoaking,
+		ObservedGeneration: 1,
+	}}
+	updateHandler(nil, &candidate)
+	assert.NotNil(t, GetCandidateConfig())
+	assert.Equal(t, "active", GetConfig().Spec.RequestorLabel, "candidate should not replace the active config")
+	assert.Equal(t, "candidate", GetConfigForPaas(canary).Spec.RequestorLabel)
+	assert.Equal(t, "active", GetConfigForPaas(other).Spec.RequestorLabel)
+
+	deleteHandler(&candidate)
+	assert.Nil(t, GetCandidateConfig())
+	assert.Equal(t, "active", GetConfigForPaas(canary).Spec.RequestorLabel)
+}
@@ -9,18 +9,32 @@ See LICENSE.md for details.
 package config
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha1"
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
 	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // PaasConfigStore is a thread-safe store for the current PaasConfig
 type PaasConfigStore struct {
 	mutex sync.RWMutex
 	store *v1alpha2.PaasConfig
+	// candidate is the candidate PaasConfig which is applied to canary Paas'es, if any
+	candidate *v1alpha2.PaasConfig
+	// canaries selects the canary Paas'es for the candidate PaasConfig
+	canaries labels.Selector
+	// revisions holds all PaasConfigRevisions by name, for Paas'es which are pinned to a revision
+	revisions map[string]v1alpha2.PaasConfigRevision
+	// generation is incremented on every change of the current, candidate or revision configurations
+	generation uint64
 }
 
 var cnf PaasConfigStore
@@ -47,6 +61,14 @@ func GetConfigWithError() (*v1alpha2.PaasConfig, error) {
 	return cnf.store, nil
 }
 
+// Generation returns a counter which is incremented on every change of the current, candidate or revision
+// configurations. It can be used to detect that results which were derived from the configuration are outdated.
+func Generation() uint64 {
+	cnf.mutex.RLock()
+	defer cnf.mutex.RUnlock()
+	return cnf.generation
+}
+
 // GetConfigV1 retrieves the current configuration as a v1alpha1.PaasConfig
 func GetConfigV1() (v1alpha1.PaasConfig, error) {
 	cnf.mutex.RLock()
@@ -55,9 +77,12 @@ func GetConfigV1() (v1alpha1.PaasConfig, error) {
 	if cnf.store == nil {
 		return v1alpha1.PaasConfig{}, errors.New("uninitialized paasconfig")
 	}
+	var hub v1alpha3.PaasConfig
+	if err := cnf.store.ConvertTo(&hub); err != nil {
+		return v1alpha1.PaasConfig{}, err
+	}
 	var v1conf v1alpha1.PaasConfig
-	err := v1conf.ConvertFrom(cnf.store)
-	// err := (&cnf.store).ConvertTo(&v1conf)
+	err := v1conf.ConvertFrom(&hub)
 	return v1conf, err
 }
 
@@ -66,6 +91,7 @@ func SetConfig(cfg v1alpha2.PaasConfig) {
 	cnf.mutex.Lock()
 	defer cnf.mutex.Unlock()
 	cnf.store = &cfg
+	cnf.generation++
 	logging.SetDynamicLoggingConfig(cfg.Spec.Debug, logging.NewComponentsFromStringMap(cfg.Spec.ComponentsDebug))
 }
 
@@ -75,7 +101,144 @@ func SetConfigV1(cfg v1alpha1.PaasConfig) error {
 	defer cnf.mutex.Unlock()
 	defer logging.SetDynamicLoggingConfig(cfg.Spec.Debug, logging.NewComponentsFromStringMap(cfg.Spec.ComponentsDebug))
 
+	var hub v1alpha3.PaasConfig
+	if err := cfg.ConvertTo(&hub); err != nil {
+		return err
+	}
 	cnf.store = &v1alpha2.PaasConfig{}
-	return cfg.ConvertTo(cnf.store)
-	// return (&cnf.store).ConvertFrom(&cfg)
+	cnf.generation++
+	return cnf.store.ConvertFrom(&hub)
+}
+
+// GetConfigForPaas retrieves the configuration which should be used for a Paas. This is the PaasConfigRevision the
+// Paas is pinned to (if any), the candidate PaasConfig for canary Paas'es while a candidate is soaking, and the current
+// configuration for all other Paas'es. The current configuration is also returned when the Paas is pinned to a
+// revision which does not exist; use GetConfigForPaasWithError to detect this.
+func GetConfigForPaas(paas *v1alpha2.Paas) v1alpha2.PaasConfig {
+	cfg, err := GetConfigForPaasWithError(paas)
+	if err != nil {
+		return GetConfig()
+	}
+	return cfg
+}
+
+// GetConfigForPaasWithError retrieves the configuration which should be used for a Paas, like GetConfigForPaas does.
+// An error is returned when the Paas has invalid pin annotations, or is pinned to a revision which does not exist.
+func GetConfigForPaasWithError(paas *v1alpha2.Paas) (v1alpha2.PaasConfig, error) {
+	override, err := GetRevisionOrCandidateConfig(paas)
+	if err != nil {
+		return v1alpha2.PaasConfig{}, err
+	}
+	if override != nil {
+		return *override, nil
+	}
+	return GetConfig(), nil
+}
+
+// GetRevisionOrCandidateConfig retrieves the configuration which overrides the current configuration for a Paas: the
+// PaasConfigRevision the Paas is pinned to (if any), or the candidate PaasConfig for canary Paas'es while a candidate
+// is soaking. nil is returned for all other Paas'es. An error is returned when the Paas has invalid pin annotations,
+// or is pinned to a revision which does not exist.
+func GetRevisionOrCandidateConfig(paas *v1alpha2.Paas) (*v1alpha2.PaasConfig, error) {
+	if paas == nil {
+		return nil, nil
+	}
+	revisionName, _, err := paas.PinnedRevision(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	cnf.mutex.RLock()
+	candidate, canaries := cnf.candidate, cnf.canaries
+	revision, revisionExists := cnf.revisions[revisionName]
+	cnf.mutex.RUnlock()
+
+	switch {
+	case revisionName != "" && !revisionExists:
+		return nil, fmt.Errorf("paas is pinned to PaasConfigRevision %s, which does not exist", revisionName)
+	case revisionName != "":
+		cfg := revision.AsPaasConfig()
+		return &cfg, nil
+	case candidate != nil && canaries.Matches(labels.Set(paas.Labels)):
+		cfg := *candidate
+		return &cfg, nil
+	}
+	return nil, nil
+}
+
+// SetRevision adds or updates a PaasConfigRevision in the store
+func SetRevision(revision v1alpha2.PaasConfigRevision) {
+	cnf.mutex.Lock()
+	defer cnf.mutex.Unlock()
+	if cnf.revisions == nil {
+		cnf.revisions = map[string]v1alpha2.PaasConfigRevision{}
+	}
+	cnf.revisions[revision.Name] = revision
+	cnf.generation++
+}
+
+// DeleteRevision removes a PaasConfigRevision from the store
+func DeleteRevision(name string) {
+	cnf.mutex.Lock()
+	defer cnf.mutex.Unlock()
+	delete(cnf.revisions, name)
+	cnf.generation++
+}
+
+// GetCandidateConfig retrieves the candidate PaasConfig which is soaking, or nil when there is none
+func GetCandidateConfig() *v1alpha2.PaasConfig {
+	cnf.mutex.RLock()
+	defer cnf.mutex.RUnlock()
+
+	if cnf.candidate == nil {
+		return nil
+	}
+	candidate := *cnf.candidate
+	return &candidate
+}
+
+// SetCandidateConfig updates the candidate PaasConfig. The candidate is cleared when cfg is nil, or when cfg is not
+// a soaking candidate PaasConfig (e.g. because it was rejected or promoted).
+func SetCandidateConfig(cfg *v1alpha2.PaasConfig) {
+	var canaries labels.Selector
+	if cfg != nil && cfg.IsSoaking() {
+		var err error
+		if canaries, err = metav1.LabelSelectorAsSelector(&cfg.Spec.Candidate.CanarySelector); err != nil {
+			_, logger := logging.GetLogComponent(context.Background(), logging.ConfigComponent)
+			logger.Error().AnErr("error", err).Msgf("invalid canary selector in candidate PaasConfig %s", cfg.Name)
+			cfg = nil
+		}
+	} else {
+		cfg = nil
+	}
+
+	cnf.mutex.Lock()
+	defer cnf.mutex.Unlock()
+	cnf.generation++
+	if cfg == nil {
+		cnf.candidate, cnf.canaries = nil, nil
+		return
+	}
+	candidate := *cfg
+	cnf.candidate, cnf.canaries = &candidate, canaries
+}
+
+// UpdateCandidateConfig updates the candidate PaasConfig from a (changed) candidate PaasConfig: it is set when it is
+// soaking, and cleared otherwise.
+func UpdateCandidateConfig(cfg *v1alpha2.PaasConfig) {
+	if cfg.IsSoaking() {
+		SetCandidateConfig(cfg)
+		return
+	}
+	ClearCandidateConfig(cfg.Name)
+}
+
+// ClearCandidateConfig clears the candidate PaasConfig, but only when it is the candidate with this name
+func ClearCandidateConfig(name string) {
+	cnf.mutex.Lock()
+	defer cnf.mutex.Unlock()
+	if cnf.candidate != nil && cnf.candidate.Name == name {
+		cnf.candidate, cnf.canaries = nil, nil
+		cnf.generation++
+	}
 }
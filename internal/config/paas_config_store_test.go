@@ -8,9 +8,12 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestGetConfigWithEmptyConfigStore(t *testing.T) {
@@ -32,3 +35,58 @@ func TestGetConfig(t *testing.T) {
 	assert.NotEmpty(t, actual)
 	assert.True(t, actual.Spec.Debug)
 }
+
+func TestGetConfigForPaasWithPinnedRevision(t *testing.T) {
+	SetConfig(v1alpha2.PaasConfig{Spec: v1alpha2.PaasConfigSpec{QuotaLabel: "active"}})
+	defer SetConfig(v1alpha2.PaasConfig{})
+	revision := v1alpha2.NewPaasConfigRevision(v1alpha2.PaasConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "paas-config"},
+		Spec:       v1alpha2.PaasConfigSpec{QuotaLabel: "old"},
+	})
+	SetRevision(*revision)
+	defer DeleteRevision(revision.Name)
+
+	pinned := func(name string, until time.Time) *v1alpha2.Paas {
+		return &v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			v1alpha2.PinRevisionAnnotation:      name,
+			v1alpha2.PinRevisionUntilAnnotation: until.Format(time.RFC3339),
+		}}}
+	}
+
+	cfg, err := GetConfigForPaasWithError(pinned(revision.Name, time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	assert.Equal(t, "old", cfg.Spec.QuotaLabel)
+
+	cfg, err = GetConfigForPaasWithError(pinned(revision.Name, time.Now().Add(-time.Hour)))
+	require.NoError(t, err)
+	assert.Equal(t, "active", cfg.Spec.QuotaLabel, "an expired pin should be ignored")
+
+	_, err = GetConfigForPaasWithError(pinned("missing", time.Now().Add(time.Hour)))
+	require.Error(t, err)
+	assert.Equal(t, "active", GetConfigForPaas(pinned("missing", time.Now().Add(time.Hour))).Spec.QuotaLabel)
+
+	DeleteRevision(revision.Name)
+	_, err = GetConfigForPaasWithError(pinned(revision.Name, time.Now().Add(time.Hour)))
+	require.Error(t, err)
+}
+
+func TestGeneration(t *testing.T) {
+	generation := Generation()
+	SetConfig(v1alpha2.PaasConfig{})
+	assert.Greater(t, Generation(), generation)
+
+	generation = Generation()
+	revision := v1alpha2.NewPaasConfigRevision(v1alpha2.PaasConfig{ObjectMeta: metav1.ObjectMeta{Name: "paas-config"}})
+	SetRevision(*revision)
+	assert.Greater(t, Generation(), generation)
+
+	generation = Generation()
+	DeleteRevision(revision.Name)
+	assert.Greater(t, Generation(), generation)
+
+	generation = Generation()
+	ClearCandidateConfig("no-candidate")
+	assert.Equal(t, generation, Generation(), "clearing another candidate should not change the generation")
+	SetCandidateConfig(nil)
+	assert.Greater(t, Generation(), generation)
+}
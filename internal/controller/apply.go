@@ -0,0 +1,113 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	// FieldManager is the field manager the Paas operator uses when applying resources with server-side apply
+	FieldManager = "opr-paas"
+	// legacyFieldManager is the field manager which was (implicitly) used by older versions of the operator, which
+	// created and updated resources with Create and Update calls (client-side apply).
+	legacyFieldManager = "manager"
+)
+
+// toApplyConfiguration converts an object into an apply configuration, which only holds the fields that the
+// operator wants to manage. When resourceVersion is set, it is used as precondition for the apply.
+func toApplyConfiguration(
+	obj client.Object,
+	scheme *runtime.Scheme,
+	resourceVersion string,
+) (runtime.ApplyConfiguration, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	u.SetResourceVersion(resourceVersion)
+	u.SetManagedFields(nil)
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u.Object, "status")
+	return client.ApplyConfigurationFromUnstructured(u), nil
+}
+
+// upgradeManagedFields moves ownership of all fields which were set with client-side apply by older versions of the
+// operator, to the server-side apply field manager. This makes sure that labels and annotations which were set by
+// older versions of the operator are pruned when they are no longer rendered.
+func upgradeManagedFields(ctx context.Context, r Reconciler, existing client.Object, fieldManager string) error {
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(existing, sets.New(legacyFieldManager), fieldManager)
+	if err != nil || patch == nil {
+		return err
+	}
+	_, logger := logging.GetLogComponent(ctx, logging.ControllerPaasComponent)
+	logger.Info().Msgf("upgrading managed fields of %s %s", existing.GetObjectKind().GroupVersionKind().Kind,
+		client.ObjectKeyFromObject(existing))
+	return r.Patch(ctx, existing, client.RawPatch(types.JSONPatchType, patch))
+}
+
+// applyObject ensures the desired state of obj with server-side apply, using FieldManager as field manager.
+// Fields which were applied before but are no longer part of obj (e.g. labels which are no longer templated) are
+// pruned, while fields which are owned by other field managers are left alone.
+func applyObject(ctx context.Context, r Reconciler, obj client.Object) error {
+	return apply(ctx, r, obj, FieldManager, false)
+}
+
+// applySharedObject is like applyObject, but for objects which are shared by multiple Paas'es (e.g. clusterwide
+// quotas), and which hold owner references or subjects for each of them. As these are derived from the existing
+// object, the resource version of obj should be that of the existing object (or empty when it does not exist yet).
+// It is used as precondition, so that the apply fails with a conflict when the object was changed in the mean time.
+func applySharedObject(ctx context.Context, r Reconciler, obj client.Object, fieldManager string) error {
+	return apply(ctx, r, obj, fieldManager, true)
+}
+
+func apply(ctx context.Context, r Reconciler, obj client.Object, fieldManager string, shared bool) error {
+	existing, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	var resourceVersion string
+	err := r.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+	if err == nil {
+		if shared && existing.GetResourceVersion() != obj.GetResourceVersion() {
+			return fmt.Errorf("%s was changed in the mean time", client.ObjectKeyFromObject(obj))
+		}
+		if err = upgradeManagedFields(ctx, r, existing, fieldManager); err != nil {
+			return fmt.Errorf("failed to upgrade managed fields: %w", err)
+		}
+		if shared {
+			resourceVersion = existing.GetResourceVersion()
+		}
+	} else if !errors.IsNotFound(err) {
+		return err
+	} else if shared && obj.GetResourceVersion() != "" {
+		return fmt.Errorf("%s was deleted in the mean time", client.ObjectKeyFromObject(obj))
+	}
+	applyConfig, err := toApplyConfiguration(obj, r.getScheme(), resourceVersion)
+	if err != nil {
+		return err
+	}
+	return r.Apply(ctx, applyConfig, client.FieldOwner(fieldManager), client.ForceOwnership)
+}
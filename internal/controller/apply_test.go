@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Server-side apply", Ordered, func() {
+	const (
+		cmNamespace = "default"
+		otherLabel  = "other-label"
+	)
+	var reconciler *PaasReconciler
+	ctx := context.Background()
+
+	BeforeAll(func() {
+		reconciler = &PaasReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+	})
+
+	desiredConfigMap := func(name string, labels map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cmNamespace,
+				Labels:    labels,
+			},
+			Data: map[string]string{"key": "value"},
+		}
+	}
+	getConfigMap := func(name string) *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: cmNamespace, Name: name}, cm)).To(Succeed())
+		return cm
+	}
+
+	When("applying an object", func() {
+		It("creates it when it does not exist", func() {
+			Expect(applyObject(ctx, reconciler, desiredConfigMap("apply-cm", map[string]string{
+				"a": "1",
+				"b": "2",
+			}))).To(Succeed())
+			cm := getConfigMap("apply-cm")
+			Expect(cm.Labels).To(HaveKeyWithValue("a", "1"))
+			Expect(cm.Labels).To(HaveKeyWithValue("b", "2"))
+			Expect(cm.ManagedFields).To(ContainElement(HaveField("Manager", FieldManager)))
+		})
+		It("prunes labels it no longer applies, and leaves labels of other managers alone", func() {
+			cm := getConfigMap("apply-cm")
+			cm.Labels[otherLabel] = "value"
+			Expect(k8sClient.Update(ctx, cm, client.FieldOwner("someone-else"))).To(Succeed())
+
+			Expect(applyObject(ctx, reconciler, desiredConfigMap("apply-cm", map[string]string{
+				"a": "3",
+			}))).To(Succeed())
+			cm = getConfigMap("apply-cm")
+			Expect(cm.Labels).To(HaveKeyWithValue("a", "3"))
+			Expect(cm.Labels).NotTo(HaveKey("b"))
+			Expect(cm.Labels).To(HaveKeyWithValue(otherLabel, "value"))
+		})
+	})
+
+	When("applying an object which was created by an older version of the operator", func() {
+		It("takes over and prunes fields from the legacy field manager", func() {
+			legacy := desiredConfigMap("legacy-cm", map[string]string{"a": "1", "b": "2"})
+			Expect(k8sClient.Create(ctx, legacy, client.FieldOwner(legacyFieldManager))).To(Succeed())
+
+			Expect(applyObject(ctx, reconciler, desiredConfigMap("legacy-cm", map[string]string{
+				"a": "1",
+			}))).To(Succeed())
+			cm := getConfigMap("legacy-cm")
+			Expect(cm.Labels).To(HaveKeyWithValue("a", "1"))
+			Expect(cm.Labels).NotTo(HaveKey("b"))
+			Expect(cm.ManagedFields).NotTo(ContainElement(HaveField("Manager", legacyFieldManager)))
+		})
+	})
+
+	When("applying a shared object", func() {
+		It("uses the resource version of the existing object as precondition", func() {
+			shared := desiredConfigMap("shared-cm", map[string]string{"a": "1"})
+			Expect(applySharedObject(ctx, reconciler, shared, FieldManager)).To(Succeed())
+			cm := getConfigMap("shared-cm")
+			Expect(cm.ManagedFields).To(ContainElement(HaveField("Manager", FieldManager)))
+
+			stale := desiredConfigMap("shared-cm", map[string]string{"a": "2"})
+			Expect(applySharedObject(ctx, reconciler, stale, FieldManager)).
+				To(MatchError(ContainSubstring("was changed in the mean time")))
+
+			stale.ResourceVersion = cm.ResourceVersion
+			Expect(applySharedObject(ctx, reconciler, stale, FieldManager)).To(Succeed())
+			Expect(getConfigMap("shared-cm").Labels).To(HaveKeyWithValue("a", "2"))
+		})
+	})
+})
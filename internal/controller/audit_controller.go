@@ -0,0 +1,147 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	webhookv1alpha2 "github.com/belastingdienst/opr-paas/v3/internal/webhook/v1alpha2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// maxReportedViolations is the maximum number of violations which is reported in the status of the PaasConfig, so
+// that the status cannot outgrow the maximum size of a resource
+const maxReportedViolations = 500
+
+// AuditReconciler periodically audits existing Paas'es and PaasNSs against the validations of the active PaasConfig
+// which do not block updates (the validations in warn or block-on-create-only mode), and reports the violations in
+// the status of the active PaasConfig. Paas'es which are pinned to a PaasConfigRevision, or which are canaries of a
+// candidate PaasConfig, are audited against the validations of that revision or candidate.
+type AuditReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Interval is the interval in which the audit is repeated
+	Interval time.Duration
+	// secrets are the secrets which were decrypted in earlier audits
+	secrets webhookv1alpha2.AuditSecrets
+}
+
+// +kubebuilder:rbac:groups=cpet.belastingdienst.nl,resources=paasconfig,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cpet.belastingdienst.nl,resources=paasconfig/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cpet.belastingdienst.nl,resources=paas;paasns,verbs=get;list;watch
+
+// SetupWithManager sets up the controller with the Manager.
+func (ar *AuditReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("paasconfig-audit").
+		For(&v1alpha2.PaasConfig{}).
+		WithEventFilter(predicate.Or(
+			predicate.GenerationChangedPredicate{}, // Spec changed
+			v1alpha2.ActivePaasConfigUpdated(),     // PaasConfig became active
+		)).
+		Complete(ar)
+}
+
+// Reconcile audits all Paas'es and PaasNSs when the PaasConfig is the active PaasConfig
+func (ar *AuditReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	cfg := &v1alpha2.PaasConfig{}
+	ctx, _ = logging.SetControllerLogger(ctx, cfg, ar.Scheme, req)
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerAuditComponent)
+
+	if err := ar.Get(ctx, req.NamespacedName, cfg); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !cfg.IsActive() || cfg.IsCandidate() || cfg.DeletionTimestamp != nil {
+		logger.Debug().Msg("not auditing with a PaasConfig which is not active")
+		return ctrl.Result{}, nil
+	}
+
+	violations, err := ar.audit(ctx, *cfg)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	for _, violation := range violations {
+		logger.Debug().Str("kind", violation.Kind).Str("name", violation.Name).
+			Str("namespace", violation.Namespace).Str("validation", violation.Validation).
+			Msgf("violation: %s", violation.Message)
+	}
+	if err = ar.setAuditStatus(ctx, cfg, violations); err != nil {
+		logger.Err(err).Msg("failed to update PaasConfig status")
+		return ctrl.Result{}, err
+	}
+	logger.Info().Msgf("audit found %d violations", len(violations))
+	return ctrl.Result{RequeueAfter: ar.Interval}, nil
+}
+
+// audit returns the (sorted) violations of all Paas'es and PaasNSs against the validations of a PaasConfig
+func (ar *AuditReconciler) audit(
+	ctx context.Context,
+	cfg v1alpha2.PaasConfig,
+) (violations []v1alpha2.PaasConfigViolation, err error) {
+	var paasList v1alpha2.PaasList
+	if err = ar.List(ctx, &paasList); err != nil {
+		return nil, err
+	}
+	var paasNsList v1alpha2.PaasNSList
+	if err = ar.List(ctx, &paasNsList); err != nil {
+		return nil, err
+	}
+	for _, paas := range paasList.Items {
+		violations = append(violations, webhookv1alpha2.AuditPaas(ctx, ar.Client, cfg, &paas, &ar.secrets)...)
+	}
+	for _, paasns := range paasNsList.Items {
+		violations = append(violations, webhookv1alpha2.AuditPaasNS(ctx, ar.Client, cfg, &paasns, &ar.secrets)...)
+	}
+	ar.secrets.Prune()
+
+	slices.SortFunc(violations, func(a, b v1alpha2.PaasConfigViolation) int {
+		return cmp.Or(
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Validation, b.Validation),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+	return violations, nil
+}
+
+// setAuditStatus reports the violations and sets the Audited condition in the status of a PaasConfig
+func (ar *AuditReconciler) setAuditStatus(
+	ctx context.Context,
+	cfg *v1alpha2.PaasConfig,
+	violations []v1alpha2.PaasConfigViolation,
+) error {
+	condition := metav1.Condition{
+		Type:   v1alpha2.TypeAuditedPaasConfig,
+		Status: metav1.ConditionTrue, Reason: v1alpha2.AuditReasonCompliant, ObservedGeneration: cfg.Generation,
+		Message: "All Paas'es and PaasNSs meet the audited validations",
+	}
+	if len(violations) > 0 {
+		condition.Reason = v1alpha2.AuditReasonViolations
+		condition.Message = fmt.Sprintf("Found %d violations of validations in warn or block-on-create-only mode",
+			len(violations))
+	}
+	if len(violations) > maxReportedViolations {
+		condition.Message += fmt.Sprintf(", of which the first %d are reported", maxReportedViolations)
+		violations = violations[:maxReportedViolations]
+	}
+	meta.SetStatusCondition(&cfg.Status.Conditions, condition)
+	cfg.Status.Violations = violations
+	return ar.Status().Update(ctx, cfg)
+}
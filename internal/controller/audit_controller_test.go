@@ -0,0 +1,131 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newAuditReconciler(t *testing.T, cfg *v1alpha2.PaasConfig, paases ...*v1alpha2.Paas) *AuditReconciler {
+	s := runtime.NewScheme()
+	require.NoError(t, v1alpha2.AddToScheme(s))
+	require.NoError(t, corev1.AddToScheme(s))
+	builder := fake.NewClientBuilder().WithScheme(s).WithStatusSubresource(cfg).WithObjects(cfg)
+	for _, paas := range paases {
+		builder = builder.WithObjects(paas)
+	}
+	return &AuditReconciler{Client: builder.Build(), Scheme: s, Interval: time.Hour}
+}
+
+func TestAudit_Reconcile(t *testing.T) {
+	cfg := &v1alpha2.PaasConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "paas-config", Generation: 1},
+		Spec: v1alpha2.PaasConfigSpec{
+			ValidationRules: v1alpha2.ConfigValidationRules{{
+				Resource:    "paas",
+				Rule:        `has(object.metadata.labels) && "costcenter" in object.metadata.labels`,
+				Message:     "Paas'es should have a costcenter label",
+				Enforcement: v1alpha2.EnforcementWarn,
+			}},
+		},
+	}
+	compliant := &v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{
+		Name:   "compliant",
+		Labels: map[string]string{"costcenter": "1234"},
+	}}
+	violating := &v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{Name: "violating"}}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: cfg.Name}}
+
+	r := newAuditReconciler(t, cfg, compliant, violating)
+	result, err := r.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter, "a PaasConfig which is not active should not be audited")
+
+	active := cfg.DeepCopy()
+	meta.SetStatusCondition(&active.Status.Conditions, metav1.Condition{
+		Type: v1alpha2.TypeActivePaasConfig, Status: metav1.ConditionTrue, Reason: "Reconciling",
+	})
+	r = newAuditReconciler(t, active, compliant, violating)
+	result, err = r.Reconcile(context.TODO(), req)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, result.RequeueAfter)
+
+	var audited v1alpha2.PaasConfig
+	require.NoError(t, r.Get(context.TODO(), req.NamespacedName, &audited))
+	assert.Equal(t, []v1alpha2.PaasConfigViolation{{
+		Kind:        "Paas",
+		Name:        "violating",
+		Validation:  "validationRules[0]",
+		Enforcement: v1alpha2.EnforcementWarn,
+		Message:     "spec: Forbidden: Paas'es should have a costcenter label",
+	}}, audited.Status.Violations)
+	cond := meta.FindStatusCondition(audited.Status.Conditions, v1alpha2.TypeAuditedPaasConfig)
+	require.NotNil(t, cond)
+	assert.Equal(t, v1alpha2.AuditReasonViolations, cond.Reason)
+}
+
+// A validation which cannot be run is reported as a violation, and does not prevent auditing other Paas'es
+func TestAudit_validationErrors(t *testing.T) {
+	cfg := &v1alpha2.PaasConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "paas-config"},
+		Spec: v1alpha2.PaasConfigSpec{
+			DecryptKeysSecret:     v1alpha2.NamespacedName{Name: "missing", Namespace: "paas-system"},
+			ValidationEnforcement: v1alpha2.ConfigValidationEnforcement{"paas.secrets": v1alpha2.EnforcementWarn},
+		},
+	}
+	withSecret := &v1alpha2.Paas{
+		ObjectMeta: metav1.ObjectMeta{Name: "with-secret"},
+		Spec:       v1alpha2.PaasSpec{Secrets: map[string]string{"ssh://git.example": "encrypted"}},
+	}
+	withoutSecret := &v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{Name: "without-secret"}}
+
+	r := newAuditReconciler(t, cfg, withSecret, withoutSecret)
+	violations, err := r.audit(context.TODO(), *cfg)
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "with-secret", violations[0].Name)
+	assert.Equal(t, "paas.secrets", violations[0].Validation)
+	assert.Contains(t, violations[0].Message, "validation could not be run: failed to get crypt")
+}
+
+func TestAudit_setAuditStatus(t *testing.T) {
+	cfg := &v1alpha2.PaasConfig{ObjectMeta: metav1.ObjectMeta{Name: "paas-config"}}
+	r := newAuditReconciler(t, cfg)
+
+	require.NoError(t, r.setAuditStatus(context.TODO(), cfg, nil))
+	cond := meta.FindStatusCondition(cfg.Status.Conditions, v1alpha2.TypeAuditedPaasConfig)
+	require.NotNil(t, cond)
+	assert.Equal(t, v1alpha2.AuditReasonCompliant, cond.Reason)
+
+	violations := make([]v1alpha2.PaasConfigViolation, maxReportedViolations+1)
+	for i := range violations {
+		violations[i] = v1alpha2.PaasConfigViolation{Kind: "Paas", Name: fmt.Sprintf("paas-%d", i)}
+	}
+	cfg = &v1alpha2.PaasConfig{ObjectMeta: metav1.ObjectMeta{Name: "paas-config"}}
+	r = newAuditReconciler(t, cfg)
+	require.NoError(t, r.setAuditStatus(context.TODO(), cfg, violations))
+	assert.Len(t, cfg.Status.Violations, maxReportedViolations)
+	cond = meta.FindStatusCondition(cfg.Status.Conditions, v1alpha2.TypeAuditedPaasConfig)
+	require.NotNil(t, cond)
+	assert.Equal(t, v1alpha2.AuditReasonViolations, cond.Reason)
+	assert.Equal(t, "Found 501 violations of validations in warn or block-on-create-only mode, "+
+		"of which the first 500 are reported", cond.Message)
+}
@@ -11,13 +11,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 
 	"github.com/belastingdienst/opr-paas/v3/internal/argocd-plugin-generator/fields"
 	"github.com/belastingdienst/opr-paas/v3/internal/config"
 	"github.com/belastingdienst/opr-paas/v3/internal/logging"
 	appv1 "github.com/belastingdienst/opr-paas/v3/internal/stubs/argoproj/v1alpha1"
 	"github.com/belastingdienst/opr-paas/v3/internal/templating"
+	"github.com/belastingdienst/opr-paas/v3/internal/validate"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
@@ -46,18 +49,37 @@ func getListGen(generators []appv1.ApplicationSetGenerator) *appv1.ApplicationSe
 	return nil
 }
 
-// ensureAppSetCap ensures a list entry in the AppSet for each capability
+// sortedCapabilities returns the names of the capabilities enabled in a Paas, ordered so that every capability comes
+// after the capabilities it requires. An error is returned when a capability is not configured, or when a capability
+// it requires is not enabled.
+func sortedCapabilities(capabilities v1alpha2.ConfigCapabilities, paas *v1alpha2.Paas) ([]string, error) {
+	capNames := make([]string, 0, len(paas.Spec.Capabilities))
+	for capName := range paas.Spec.Capabilities {
+		capConfig, exists := capabilities[capName]
+		if !exists {
+			return nil, errors.New("capability not configured")
+		}
+		for _, required := range capConfig.Requires {
+			if _, enabled := paas.Spec.Capabilities[required]; !enabled {
+				return nil, fmt.Errorf("capability %s requires capability %s", capName, required)
+			}
+		}
+		capNames = append(capNames, capName)
+	}
+	return capabilities.DependencyOrder(capNames)
+}
+
+// ensureAppSetCap ensures a list entry in the AppSet for each capability, reconciling required capabilities first
 func (r *PaasReconciler) ensureAppSetCaps(
 	ctx context.Context,
 	paas *v1alpha2.Paas,
 ) error {
-	paasConfigSpec := config.GetConfig().Spec
-	for capName := range paas.Spec.Capabilities {
-		if _, exists := paasConfigSpec.Capabilities[capName]; !exists {
-			return errors.New("capability not configured")
-		}
-
-		if err := r.ensureAppSetCap(ctx, paas, capName); err != nil {
+	capNames, err := sortedCapabilities(config.GetConfigForPaas(paas).Spec.Capabilities, paas)
+	if err != nil {
+		return err
+	}
+	for _, capName := range capNames {
+		if err = r.ensureAppSetCap(ctx, paas, capName); err != nil {
 			return err
 		}
 	}
@@ -69,7 +91,7 @@ func capElementsFromPaas(
 	paas *v1alpha2.Paas,
 	capName string,
 ) (elements fields.Elements, err error) {
-	myConfig := config.GetConfig()
+	myConfig := config.GetConfigForPaas(paas)
 	templater := templating.NewTemplater(*paas, myConfig)
 	capConfig := myConfig.Spec.Capabilities[capName]
 	templatedElements, err := applyCustomFieldTemplates(capConfig.CustomFields, templater)
@@ -79,7 +101,7 @@ func capElementsFromPaas(
 
 	capability := paas.Spec.Capabilities[capName]
 
-	capElements, err := capability.CapExtraFields(myConfig.Spec.Capabilities[capName].CustomFields)
+	capElements, err := validate.CapExtraFields(capability, myConfig.Spec.Capabilities[capName].CustomFields)
 	if err != nil {
 		return nil, err
 	}
@@ -95,6 +117,9 @@ func capElementsFromPaas(
 		}
 	}
 
+	if version := capConfig.ResolveVersion(capability.Version); version != "" {
+		elements["version"] = version
+	}
 	elements["paas"] = paas.Name
 	return elements, nil
 }
@@ -108,7 +133,7 @@ func (r *PaasReconciler) ensureAppSetCap(
 	var err error
 	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerCapabilitiesComponent)
 	logger.Info().Msgf("reconciling %s Applicationset", capName)
-	myConfig := config.GetConfig()
+	myConfig := config.GetConfigForPaas(paas)
 	namespacedName := myConfig.Spec.CapabilityK8sName(capName)
 	if !reflect.DeepEqual(namespacedName, types.NamespacedName{}) {
 		appSet := &appv1.ApplicationSet{}
@@ -218,19 +243,25 @@ func (r *PaasReconciler) finalizeAllAppSetCaps(
 	return r.finalizeDisabledAppSetCaps(ctx, paasWithoutCaps)
 }
 
-// finalizeAppSetCaps removes this paas from all capability appsets that are not enabled in this paas
+// finalizeAppSetCaps removes this paas from all capability appsets that are not enabled in this paas, removing
+// dependant capabilities before the capabilities they require
 func (r *PaasReconciler) finalizeDisabledAppSetCaps(
 	ctx context.Context,
 	paas *v1alpha2.Paas,
 ) error {
 	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerCapabilitiesComponent)
-	for capName := range config.GetConfig().Spec.Capabilities {
+	capabilities := config.GetConfigForPaas(paas).Spec.Capabilities
+	capNames, err := capabilities.DependencyOrder(slices.Collect(maps.Keys(capabilities)))
+	if err != nil {
+		return err
+	}
+	slices.Reverse(capNames)
+	for _, capName := range capNames {
 		logger.Info().Msgf("reconciling %s Applicationset", capName)
 		if _, exists := paas.Spec.Capabilities[capName]; exists {
 			continue
 		}
-		err := r.finalizeAppSetCap(ctx, paas.Name, capName)
-		if err != nil {
+		if err = r.finalizeAppSetCap(ctx, paas.Name, capName); err != nil {
 			return err
 		}
 	}
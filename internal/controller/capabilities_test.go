@@ -8,6 +8,7 @@ package controller
 
 import (
 	"context"
+	"testing"
 
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v3/internal/argocd-plugin-generator/fields"
@@ -15,6 +16,7 @@ import (
 	appv1 "github.com/belastingdienst/opr-paas/v3/internal/stubs/argoproj/v1alpha1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -56,10 +58,10 @@ var _ = Describe("Capabilities controller", Ordered, func() {
 				Requestor: capName,
 				Capabilities: v1alpha2.PaasCapabilities{
 					capName: v1alpha2.PaasCapability{
-						CustomFields: map[string]string{
+						CustomFields: v1alpha2.PaasCustomFieldsFromStrings(map[string]string{
 							customField1Key: customField1Value,
 							customField2Key: customField2Value,
-						},
+						}),
 					},
 				},
 				Groups: v1alpha2.PaasGroups{
@@ -199,3 +201,56 @@ g, ` + group2 + `, role:admin`
 		})
 	})
 })
+
+func TestCapabilities_sortedCapabilities(t *testing.T) {
+	capabilities := v1alpha2.ConfigCapabilities{
+		"argocd": {},
+		"tekton": {Requires: []string{"argocd"}},
+	}
+	paas := &v1alpha2.Paas{Spec: v1alpha2.PaasSpec{Capabilities: v1alpha2.PaasCapabilities{
+		"tekton": {},
+		"argocd": {},
+	}}}
+	capNames, err := sortedCapabilities(capabilities, paas)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"argocd", "tekton"}, capNames)
+
+	delete(paas.Spec.Capabilities, "argocd")
+	_, err = sortedCapabilities(capabilities, paas)
+	assert.EqualError(t, err, "capability tekton requires capability argocd")
+
+	paas.Spec.Capabilities["unknown"] = v1alpha2.PaasCapability{}
+	delete(paas.Spec.Capabilities, "tekton")
+	_, err = sortedCapabilities(capabilities, paas)
+	assert.EqualError(t, err, "capability not configured")
+}
+
+func TestCapabilities_capElementsFromPaasVersion(t *testing.T) {
+	config.SetConfig(v1alpha2.PaasConfig{Spec: v1alpha2.PaasConfigSpec{Capabilities: v1alpha2.ConfigCapabilities{
+		"argocd": {
+			Versions:       map[string]v1alpha2.ConfigCapabilityVersion{"2.13": {}, "2.14": {}},
+			DefaultVersion: "2.14",
+		},
+		"tekton": {},
+	}}})
+	paas := &v1alpha2.Paas{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-paas"},
+		Spec: v1alpha2.PaasSpec{Capabilities: v1alpha2.PaasCapabilities{
+			"argocd": {},
+			"tekton": {},
+		}},
+	}
+
+	elements, err := capElementsFromPaas(paas, "argocd")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.14", elements["version"], "the default version should be used")
+
+	paas.Spec.Capabilities["argocd"] = v1alpha2.PaasCapability{Version: "2.13"}
+	elements, err = capElementsFromPaas(paas, "argocd")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.13", elements["version"])
+
+	elements, err = capElementsFromPaas(paas, "tekton")
+	assert.NoError(t, err)
+	assert.NotContains(t, elements, "version", "capabilities without versions should not get a version")
+}
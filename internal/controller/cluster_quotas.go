@@ -9,14 +9,12 @@ package controller
 import (
 	"context"
 	"errors"
-	"maps"
 
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v3/internal/config"
 	"github.com/belastingdienst/opr-paas/v3/internal/logging"
 	"github.com/belastingdienst/opr-paas/v3/internal/templating"
 	paasquota "github.com/belastingdienst/opr-paas/v3/pkg/quota"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	quotav1 "github.com/openshift/api/quota/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -27,36 +25,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+// ensureQuota ensures ClusterResourceQuota presence and spec, labels and annotations as desired, using server-side
+// apply.
 func (r *PaasReconciler) ensureQuota(
 	ctx context.Context,
 	quota *quotav1.ClusterResourceQuota,
 ) error {
-	// See if quota already exists and create if it doesn't
-	found := &quotav1.ClusterResourceQuota{}
-	err := r.Get(ctx, client.ObjectKeyFromObject(quota), found)
-	if err != nil && k8serrors.IsNotFound(err) {
-		// Create the quota
-		if err = r.Create(ctx, quota); err != nil {
-			// creating the quota failed
-			return err
-		}
-		// creating the quota was successful
-		return nil
-	} else if err != nil {
-		// Error that isn't due to the quota not existing
-		return err
-	}
-	// Update the quota
-	found.OwnerReferences = quota.OwnerReferences
-	found.Spec = quota.Spec
-	if err = r.Update(ctx, found); err != nil {
-		// updating the quota failed
-		return err
-	}
-	return nil
+	return applyObject(ctx, r, quota)
 }
 
-// backendQuota is a code for Creating Quota
 func (r *PaasReconciler) backendQuota(
 	ctx context.Context,
 	paas *v1alpha2.Paas, suffix string,
@@ -72,28 +49,29 @@ func (r *PaasReconciler) backendQuota(
 	_, logger := logging.GetLogComponent(ctx, logging.ControllerClusterQuotaComponent)
 	logger.Info().Msg("defining quota")
 
-	labels := map[string]string{}
-	myConfig := config.GetConfig()
-	labelTemplater := templating.NewTemplater(*paas, myConfig)
-	for name, tpl := range myConfig.Spec.Templating.ClusterQuotaLabels {
-		result, err := labelTemplater.TemplateToMap(name, tpl)
-		if err != nil {
-			return nil, err
-		}
-		maps.Copy(labels, result)
+	myConfig := config.GetConfigForPaas(paas)
+	templater := templating.NewTemplater(*paas, myConfig)
+	labels, err := templateItemsToMap(templater, myConfig.Spec.Templating.ClusterQuotaLabels)
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := templateItemsToMap(templater, myConfig.Spec.Templating.ClusterQuotaAnnotations)
+	if err != nil {
+		return nil, err
 	}
 
 	// matchLabels := map[string]string{"dcs.itsmoplosgroep": paas.Name}
 	quota := &quotav1.ClusterResourceQuota{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:   quotaName,
-			Labels: labels,
+			Name:        quotaName,
+			Labels:      labels,
+			Annotations: annotations,
 		},
 		Spec: quotav1.ClusterResourceQuotaSpec{
 			Selector: quotav1.ClusterResourceQuotaSelector{
 				LabelSelector: &metav1.LabelSelector{
 					MatchLabels: map[string]string{
-						config.GetConfig().Spec.QuotaLabel: quotaName,
+						config.GetConfigForPaas(paas).Spec.QuotaLabel: quotaName,
 					},
 				},
 			},
@@ -105,7 +83,7 @@ func (r *PaasReconciler) backendQuota(
 
 	logger.Info().Msg("setting owner")
 
-	if err := controllerutil.SetControllerReference(paas, quota, r.Scheme); err != nil {
+	if err = controllerutil.SetControllerReference(paas, quota, r.Scheme); err != nil {
 		logger.Err(err).Msg("error setting owner")
 	}
 
@@ -116,7 +94,7 @@ func (r *PaasReconciler) backendEnabledQuotas(
 	ctx context.Context,
 	paas *v1alpha2.Paas,
 ) (quotas []*quotav1.ClusterResourceQuota, err error) {
-	paasConfigSpec := config.GetConfig().Spec
+	paasConfigSpec := config.GetConfigForPaas(paas).Spec
 	quota, err := r.backendQuota(ctx, paas, "", paas.Spec.Quota)
 	if err != nil {
 		return nil, err
@@ -145,7 +123,7 @@ type PaasQuotas map[string]paasquota.Quota
 func (r *PaasReconciler) backendUnneededQuotas(
 	paas *v1alpha2.Paas,
 ) (quotas []string) {
-	paasConfigSpec := config.GetConfig().Spec
+	paasConfigSpec := config.GetConfigForPaas(paas).Spec
 	for name, capConfig := range paasConfigSpec.Capabilities {
 		if _, exists := paas.Spec.Capabilities[name]; !exists {
 			quotas = append(quotas, join(paas.Name, name))
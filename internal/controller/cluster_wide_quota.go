@@ -10,7 +10,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"reflect"
 	"strings"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -146,7 +145,7 @@ func (r *PaasReconciler) finalizeClusterWideQuotas(ctx context.Context, paas *v1
 }
 
 func (r *PaasReconciler) reconcileClusterWideQuota(ctx context.Context, paas *v1alpha2.Paas) error {
-	myconfig := config.GetConfig()
+	myconfig := config.GetConfigForPaas(paas)
 
 	for capabilityName := range myconfig.Spec.Capabilities {
 		if _, exists := paas.Spec.Capabilities[capabilityName]; exists {
@@ -170,43 +169,12 @@ func (r *PaasReconciler) reconcileClusterWideQuota(ctx context.Context, paas *v1
 	return nil
 }
 
-// needsUpdate compares the current and desired ClusterResourceQuota
-func (r *PaasReconciler) needsUpdate(current, desired *quotav1.ClusterResourceQuota,
-	paas *v1alpha2.Paas) (bool, error) {
-	changed := false
-
-	// Owner reference
-	if !paas.AmIOwner(current.OwnerReferences) {
-		if err := controllerutil.SetOwnerReference(paas, current, r.Scheme); err != nil {
-			return false, err
-		}
-		changed = true
-	}
-
-	// Labels
-	if !reflect.DeepEqual(current.Labels, desired.Labels) {
-		current.Labels = desired.Labels
-		changed = true
-	}
-
-	// Selector
-	if !reflect.DeepEqual(current.Spec.Selector, desired.Spec.Selector) {
-		current.Spec.Selector = desired.Spec.Selector
-		changed = true
-	}
-
-	// Quotas
-	if !reflect.DeepEqual(current.Spec.Quota.Hard, desired.Spec.Quota.Hard) {
-		current.Spec.Quota.Hard = desired.Spec.Quota.Hard
-		changed = true
-	}
-
-	return changed, nil
-}
-
+// addToClusterWideQuota adds an owner reference for the Paas to the clusterwide quota of a capability, and applies
+// the quota with resources for all Paas'es which own it. As the owner references are derived from the existing quota,
+// it is applied with the resource version of the existing quota as precondition.
 func (r *PaasReconciler) addToClusterWideQuota(ctx context.Context, paas *v1alpha2.Paas, capabilityName string) error {
 	quotaName := clusterWideQuotaName(capabilityName)
-	paasConfigSpec, exists := config.GetConfig().Spec.Capabilities[capabilityName]
+	paasConfigSpec, exists := config.GetConfigForPaas(paas).Spec.Capabilities[capabilityName]
 	if !exists {
 		return fmt.Errorf("capability %s does not exist in configuration", capabilityName)
 	}
@@ -215,41 +183,23 @@ func (r *PaasReconciler) addToClusterWideQuota(ctx context.Context, paas *v1alph
 	}
 
 	desired := backendClusterWideQuota(quotaName, paasConfigSpec.QuotaSettings.MinQuotas)
-
-	// Try to fetch existing quota
 	current := &quotav1.ClusterResourceQuota{}
 	err := r.Get(ctx, client.ObjectKeyFromObject(desired), current)
-	if err != nil && !k8serrors.IsNotFound(err) {
+	if err == nil {
+		desired.ResourceVersion = current.ResourceVersion
+		desired.OwnerReferences = current.OwnerReferences
+	} else if !k8serrors.IsNotFound(err) {
 		return err
 	}
-
-	// If not found → create it
-	if k8serrors.IsNotFound(err) {
+	if !paas.AmIOwner(desired.OwnerReferences) {
 		if err = controllerutil.SetOwnerReference(paas, desired, r.Scheme); err != nil {
 			return err
 		}
-		if err = r.updateClusterWideQuotaResources(ctx, desired); err != nil {
-			return err
-		}
-		return r.Create(ctx, desired)
 	}
-
-	// Found → check if anything changed
-	var changed bool
-	changed, err = r.needsUpdate(current, desired, paas)
-	if err != nil {
+	if err = r.updateClusterWideQuotaResources(ctx, desired); err != nil {
 		return err
 	}
-
-	// Update if changed
-	if changed {
-		if err = r.updateClusterWideQuotaResources(ctx, current); err != nil {
-			return err
-		}
-		return r.Update(ctx, current)
-	}
-
-	return nil
+	return applySharedObject(ctx, r, desired, FieldManager)
 }
 
 func (r *PaasReconciler) removeFromClusterWideQuota(
@@ -261,7 +211,7 @@ func (r *PaasReconciler) removeFromClusterWideQuota(
 	quotaName := clusterWideQuotaName(capabilityName)
 	var capConfig v1alpha2.ConfigCapability
 	var exists bool
-	if capConfig, exists = config.GetConfig().Spec.Capabilities[capabilityName]; !exists {
+	if capConfig, exists = config.GetConfigForPaas(paas).Spec.Capabilities[capabilityName]; !exists {
 		// If a Paas was created with a capability that was nog yet configured, we should be able to delete it.
 		// Returning an error would block deletion.
 		return nil
@@ -282,10 +232,11 @@ func (r *PaasReconciler) removeFromClusterWideQuota(
 	if len(quota.OwnerReferences) < 1 {
 		return r.Delete(ctx, quota)
 	}
-	if err = r.updateClusterWideQuotaResources(ctx, quota); err != nil {
-		return err
-	} else if err = r.Update(ctx, quota); err != nil {
+	desired := backendClusterWideQuota(quotaName, capConfig.QuotaSettings.MinQuotas)
+	desired.ResourceVersion = quota.ResourceVersion
+	desired.OwnerReferences = quota.OwnerReferences
+	if err = r.updateClusterWideQuotaResources(ctx, desired); err != nil {
 		return err
 	}
-	return nil
+	return applySharedObject(ctx, r, desired, FieldManager)
 }
@@ -41,21 +41,24 @@ func getClusterRoleBinding(
 	return found, nil
 }
 
+// updateClusterRoleBinding deletes crb when it no longer has subjects, and applies it otherwise. As the subjects are
+// derived from the existing ClusterRoleBinding (which is shared by all Paas'es), it is applied with the resource
+// version of the existing ClusterRoleBinding as precondition.
 func updateClusterRoleBinding(
 	ctx context.Context,
-	r client.Client,
+	r Reconciler,
 	crb *rbac.ClusterRoleBinding,
 ) (err error) {
 	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerClusterRoleBindingsComponent)
 	if len(crb.Subjects) == 0 && crb.ResourceVersion != "" {
 		logger.Info().Msgf("cleaning empty ClusterRoleBinding %s", crb.Name)
 		return r.Delete(ctx, crb)
-	} else if len(crb.Subjects) != 0 && crb.ResourceVersion == "" {
-		logger.Info().Msgf("creating new ClusterRoleBinding %s", crb.Name)
-		return r.Create(ctx, crb)
 	} else if len(crb.Subjects) != 0 {
-		logger.Info().Msgf("updating existing ClusterRoleBinding %s", crb.Name)
-		return r.Update(ctx, crb)
+		logger.Info().Msgf("applying ClusterRoleBinding %s", crb.Name)
+		desired := backendClusterRoleBinding(crb.RoleRef.Name)
+		desired.ResourceVersion = crb.ResourceVersion
+		desired.Subjects = crb.Subjects
+		return applySharedObject(ctx, r, desired, FieldManager)
 	}
 	return nil
 }
@@ -157,7 +160,7 @@ func (r *PaasReconciler) reconcileClusterRoleBinding(
 ) (err error) {
 	var crb *rbac.ClusterRoleBinding
 	capability, capExists := paas.Spec.Capabilities[capName]
-	capConfig, capConfigExists := config.GetConfig().Spec.Capabilities[capName]
+	capConfig, capConfigExists := config.GetConfigForPaas(paas).Spec.Capabilities[capName]
 	if !capConfigExists && !capExists {
 		return err
 	}
@@ -170,7 +173,7 @@ func (r *PaasReconciler) reconcileClusterRoleBinding(
 			return err
 		}
 		if addOrUpdateCrb(ctx, crb, nsName, sas) {
-			if err = updateClusterRoleBinding(ctx, r.Client, crb); err != nil {
+			if err = updateClusterRoleBinding(ctx, r, crb); err != nil {
 				return err
 			}
 		}
@@ -219,11 +222,11 @@ func (r *PaasReconciler) finalizeClusterRoleBinding(
 		return nil
 	}
 	logger.Info().Msgf("updating rolebinding %s after cleaning SA's for '%s'", role, nsRegularExpression.String())
-	return updateClusterRoleBinding(ctx, r.Client, crb)
+	return updateClusterRoleBinding(ctx, r, crb)
 }
 
 func (r *PaasReconciler) finalizeCapClusterRoleBindings(ctx context.Context, paas *v1alpha2.Paas) error {
-	for capName, capConfig := range config.GetConfig().Spec.Capabilities {
+	for capName, capConfig := range config.GetConfigForPaas(paas).Spec.Capabilities {
 		nsRE := regexp.MustCompile(fmt.Sprintf("^%s-%s$", paas.Name, capName))
 		if _, isDefined := paas.Spec.Capabilities[capName]; isDefined {
 			continue
@@ -250,7 +253,7 @@ func (r *PaasReconciler) finalizePaasClusterRoleBindings(
 	paas *v1alpha2.Paas,
 ) (err error) {
 	var capRoles []string
-	for _, capConfig := range config.GetConfig().Spec.Capabilities {
+	for _, capConfig := range config.GetConfigForPaas(paas).Spec.Capabilities {
 		capRoles = append(capRoles, capConfig.ExtraPermissions.Roles()...)
 		capRoles = append(capRoles, capConfig.DefaultPermissions.Roles()...)
 	}
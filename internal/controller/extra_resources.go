@@ -0,0 +1,219 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/config"
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	"github.com/belastingdienst/opr-paas/v3/internal/templating"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ExtraResourceLabelKey is the key of the label that is set on extra resources, with the key of the template in
+// the PaasConfig which rendered the resource as value.
+const ExtraResourceLabelKey = "cpet.belastingdienst.nl/extra-resource"
+
+func extraResourceRef(obj *unstructured.Unstructured) v1alpha2.PaasExtraResource {
+	return v1alpha2.PaasExtraResource{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+}
+
+func compareExtraResourceRefs(a, b v1alpha2.PaasExtraResource) int {
+	return strings.Compare(
+		strings.Join([]string{a.APIVersion, a.Kind, a.Namespace, a.Name}, "/"),
+		strings.Join([]string{b.APIVersion, b.Kind, b.Namespace, b.Name}, "/"),
+	)
+}
+
+// renderExtraResource renders an extra resource template for a namespace and sets the namespace, labels and owner
+// reference. It returns nil when the template renders an empty string.
+func (r *PaasReconciler) renderExtraResource(
+	paas *v1alpha2.Paas,
+	templater paasTemplater,
+	name string,
+	template string,
+) (*unstructured.Unstructured, error) {
+	obj, err := templater.TemplateToUnstructured(name, template)
+	if err != nil || obj == nil {
+		return nil, err
+	}
+	if nsName := templater.Namespace.Name; nsName != "" {
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(nsName)
+		} else if obj.GetNamespace() != nsName {
+			return nil, fmt.Errorf("extra resource %s renders namespace %s instead of %s",
+				name, obj.GetNamespace(), nsName)
+		}
+	} else if obj.GetNamespace() == "" {
+		return nil, fmt.Errorf("extra resource %s with scope %s should set metadata.namespace",
+			name, v1alpha2.ExtraResourceScopePaas)
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[ManagedByLabelKey] = paas.Name
+	labels[ExtraResourceLabelKey] = name
+	obj.SetLabels(labels)
+	if err = controllerutil.SetControllerReference(paas, obj, r.Scheme); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// backendExtraResources renders all extra resources from the PaasConfig for a Paas
+func (r *PaasReconciler) backendExtraResources(
+	paas *v1alpha2.Paas,
+	nsDefs namespaceDefs,
+) (resources []*unstructured.Unstructured, err error) {
+	myConfig := config.GetConfigForPaas(paas)
+	templater := templating.NewTemplater(*paas, myConfig)
+	extraResources := myConfig.Spec.Templating.ExtraResources
+	tplNames := make([]string, 0, len(extraResources))
+	for name := range extraResources {
+		tplNames = append(tplNames, name)
+	}
+	slices.Sort(tplNames)
+	nsNames := make([]string, 0, len(nsDefs))
+	for nsName := range nsDefs {
+		nsNames = append(nsNames, nsName)
+	}
+	slices.Sort(nsNames)
+
+	seen := map[v1alpha2.PaasExtraResource]string{}
+	add := func(name string, obj *unstructured.Unstructured) error {
+		if obj == nil {
+			return nil
+		}
+		ref := extraResourceRef(obj)
+		if other, exists := seen[ref]; exists {
+			return fmt.Errorf("extra resources %s and %s both render %s %s/%s",
+				other, name, ref.Kind, ref.Namespace, ref.Name)
+		}
+		seen[ref] = name
+		resources = append(resources, obj)
+		return nil
+	}
+
+	for _, name := range tplNames {
+		extraResource := extraResources[name]
+		switch extraResource.Scope {
+		case v1alpha2.ExtraResourceScopePaas:
+			obj, renderErr := r.renderExtraResource(paas, templater, name, extraResource.Template)
+			if renderErr != nil {
+				return nil, renderErr
+			}
+			if err = add(name, obj); err != nil {
+				return nil, err
+			}
+		default:
+			for _, nsName := range nsNames {
+				nsDef := nsDefs[nsName]
+				if extraResource.Scope == v1alpha2.ExtraResourceScopeCapability && nsDef.capName == "" {
+					continue
+				}
+				obj, renderErr := r.renderExtraResource(paas, templater.WithNamespace(nsDef.nsName, nsDef.capName),
+					name, extraResource.Template)
+				if renderErr != nil {
+					return nil, renderErr
+				}
+				if err = add(name, obj); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return resources, nil
+}
+
+// pruneExtraResource deletes an extra resource which is no longer rendered, but only when it is owned by the Paas
+func (r *PaasReconciler) pruneExtraResource(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	ref v1alpha2.PaasExtraResource,
+) error {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerExtraResourceComponent)
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+	err := r.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, obj)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !paas.AmIOwner(obj.GetOwnerReferences()) {
+		logger.Info().Msgf("not pruning %s %s/%s, which is not owned by this Paas", ref.Kind, ref.Namespace, ref.Name)
+		return nil
+	}
+	logger.Info().Msgf("pruning extra resource %s %s/%s", ref.Kind, ref.Namespace, ref.Name)
+	if err = r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// reconcileExtraResources applies all extra resources as templated in the PaasConfig and prunes extra resources
+// which were applied before, but are no longer rendered. The applied resources are tracked in the Paas status.
+// The kinds of extra resources are not watched, so drift is only corrected on the next reconcile of the Paas.
+func (r *PaasReconciler) reconcileExtraResources(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	nsDefs namespaceDefs,
+) error {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerExtraResourceComponent)
+	desired, err := r.backendExtraResources(paas, nsDefs)
+	if err != nil {
+		return err
+	}
+	var desiredRefs []v1alpha2.PaasExtraResource
+	for _, obj := range desired {
+		desiredRefs = append(desiredRefs, extraResourceRef(obj))
+	}
+	slices.SortFunc(desiredRefs, compareExtraResourceRefs)
+
+	// Track both old and new resources until pruning succeeded, so that nothing is lost when applying fails halfway
+	var obsoleteRefs []v1alpha2.PaasExtraResource
+	for _, ref := range paas.Status.ExtraResources {
+		if !slices.Contains(desiredRefs, ref) {
+			obsoleteRefs = append(obsoleteRefs, ref)
+		}
+	}
+	paas.Status.ExtraResources = append(slices.Clone(desiredRefs), obsoleteRefs...)
+	slices.SortFunc(paas.Status.ExtraResources, compareExtraResourceRefs)
+
+	for _, obj := range desired {
+		logger.Debug().Msgf("applying extra resource %s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+		if err = applyObject(ctx, r, obj); err != nil {
+			return fmt.Errorf("failure while applying extra resource %s %s/%s: %w",
+				obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+		}
+	}
+	for _, ref := range obsoleteRefs {
+		if err = r.pruneExtraResource(ctx, paas, ref); err != nil {
+			return fmt.Errorf("failure while pruning extra resource %s %s/%s: %w",
+				ref.Kind, ref.Namespace, ref.Name, err)
+		}
+	}
+	paas.Status.ExtraResources = desiredRefs
+	return nil
+}
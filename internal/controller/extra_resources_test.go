@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/config"
+	paasquota "github.com/belastingdienst/opr-paas/v3/pkg/quota"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("ExtraResources", Ordered, func() {
+	const (
+		paasName   = "extra-paas"
+		capName    = "argocd"
+		nsName     = "ns1"
+		cmTplName  = "settings"
+		saTplName  = "deployer"
+		paasCmName = "paas-settings"
+		paasCmNs   = "default"
+	)
+	var (
+		paas       *v1alpha2.Paas
+		reconciler *PaasReconciler
+		myConfig   v1alpha2.PaasConfig
+		nsDefs     namespaceDefs
+	)
+	ctx := context.Background()
+
+	BeforeAll(func() {
+		paas = &v1alpha2.Paas{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: paasName,
+			},
+			Spec: v1alpha2.PaasSpec{
+				Requestor: paasName,
+				Capabilities: v1alpha2.PaasCapabilities{
+					capName: v1alpha2.PaasCapability{},
+				},
+				Quota: paasquota.Quota{
+					"cpu": resourcev1.MustParse("1"),
+				},
+				Namespaces: v1alpha2.PaasNamespaces{
+					nsName: {},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, paas)).To(Succeed())
+		myConfig = v1alpha2.PaasConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "paas-config",
+			},
+			Spec: v1alpha2.PaasConfigSpec{
+				Capabilities: map[string]v1alpha2.ConfigCapability{
+					capName: {
+						QuotaSettings: v1alpha2.ConfigQuotaSettings{
+							DefQuota: map[corev1.ResourceName]resourcev1.Quantity{
+								corev1.ResourceLimitsCPU: resourcev1.MustParse("5"),
+							},
+						},
+					},
+				},
+				Templating: v1alpha2.ConfigTemplatingItems{
+					ExtraResources: v1alpha2.ConfigExtraResources{
+						cmTplName: {
+							Scope: v1alpha2.ExtraResourceScopeNamespace,
+							Template: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: paas-settings
+data:
+  paas: {{ .Paas.Name }}
+  namespace: {{ .Namespace.Name }}
+`,
+						},
+						saTplName: {
+							Scope: v1alpha2.ExtraResourceScopeCapability,
+							Template: `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: {{ .Namespace.Capability }}-deployer
+`,
+						},
+						"paas": {
+							Scope: v1alpha2.ExtraResourceScopePaas,
+							Template: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ` + paasCmName + `
+  namespace: ` + paasCmNs + `
+data:
+  requestor: {{ .Paas.Spec.Requestor }}
+`,
+						},
+					},
+				},
+			},
+		}
+		config.SetConfig(myConfig)
+		reconciler = &PaasReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+		var err error
+		nsDefs, err = reconciler.nsDefsFromPaas(ctx, paas)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.reconcileNamespaces(ctx, paas, nsDefs)).To(Succeed())
+	})
+
+	When("reconciling extra resources for a paas", func() {
+		It("reconciles successfully", func() {
+			Expect(reconciler.reconcileExtraResources(ctx, paas, nsDefs)).To(Succeed())
+		})
+		It("applies namespace scoped resources in every namespace", func() {
+			for nsFullName := range nsDefs {
+				var cm corev1.ConfigMap
+				Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: nsFullName, Name: "paas-settings"}, &cm)).
+					To(Succeed())
+				Expect(cm.Data).To(HaveKeyWithValue("paas", paasName))
+				Expect(cm.Data).To(HaveKeyWithValue("namespace", nsFullName))
+				Expect(cm.Labels).To(HaveKeyWithValue(ManagedByLabelKey, paasName))
+				Expect(cm.Labels).To(HaveKeyWithValue(ExtraResourceLabelKey, cmTplName))
+				Expect(paas.AmIOwner(cm.OwnerReferences)).To(BeTrue())
+			}
+		})
+		It("applies capability scoped resources only in capability namespaces", func() {
+			var sa corev1.ServiceAccount
+			Expect(reconciler.Get(ctx, types.NamespacedName{
+				Namespace: join(paasName, capName),
+				Name:      join(capName, "deployer"),
+			}, &sa)).To(Succeed())
+			err := reconciler.Get(ctx, types.NamespacedName{
+				Namespace: join(paasName, nsName),
+				Name:      join(capName, "deployer"),
+			}, &sa)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+		It("applies paas scoped resources in the rendered namespace", func() {
+			var cm corev1.ConfigMap
+			Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: paasCmNs, Name: paasCmName}, &cm)).
+				To(Succeed())
+			Expect(cm.Data).To(HaveKeyWithValue("requestor", paasName))
+		})
+		It("tracks the applied resources in the paas status", func() {
+			Expect(paas.Status.ExtraResources).To(HaveLen(len(nsDefs) + 2))
+			Expect(paas.Status.ExtraResources).To(ContainElement(v1alpha2.PaasExtraResource{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Namespace:  paasCmNs,
+				Name:       paasCmName,
+			}))
+		})
+	})
+
+	When("a template is removed from the PaasConfig", func() {
+		It("prunes the resources it rendered", func() {
+			delete(myConfig.Spec.Templating.ExtraResources, saTplName)
+			config.SetConfig(myConfig)
+			Expect(reconciler.reconcileExtraResources(ctx, paas, nsDefs)).To(Succeed())
+			var sa corev1.ServiceAccount
+			err := reconciler.Get(ctx, types.NamespacedName{
+				Namespace: join(paasName, capName),
+				Name:      join(capName, "deployer"),
+			}, &sa)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			Expect(paas.Status.ExtraResources).To(HaveLen(len(nsDefs) + 1))
+		})
+	})
+
+	When("a paas scoped template does not set a namespace", func() {
+		It("returns an error", func() {
+			myConfig.Spec.Templating.ExtraResources["paas"] = v1alpha2.ConfigExtraResource{
+				Scope:    v1alpha2.ExtraResourceScopePaas,
+				Template: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: no-namespace\n",
+			}
+			config.SetConfig(myConfig)
+			err := reconciler.reconcileExtraResources(ctx, paas, nsDefs)
+			Expect(err).To(MatchError(ContainSubstring("should set metadata.namespace")))
+		})
+	})
+})
@@ -8,8 +8,7 @@ package controller
 
 import (
 	"context"
-	"maps"
-	"reflect"
+	"fmt"
 
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v3/internal/config"
@@ -28,57 +27,70 @@ const (
 	// application. For more info, see
 	// https://kubernetes.io/docs/concepts/overview/working-with-objects/common-labels/
 	ManagedByLabelKey = "cpet.belastingdienst.nl/managed-by-paas"
+	// SharedGroupLabelKey is the key of the label that is set on groups which are defined in the PaasConfig group
+	// definitions. These groups can be shared by multiple Paas'es and have an owner reference to each of them.
+	SharedGroupLabelKey = "cpet.belastingdienst.nl/shared-group"
+	// SharedGroupFieldManager is the field manager the Paas operator uses when applying shared groups with
+	// server-side apply. It only manages the owner references, users and SharedGroupLabelKey label of shared groups.
+	SharedGroupFieldManager = "opr-paas-shared-group"
 )
 
+// ensureGroup ensures Group presence and users, labels and annotations as desired, using server-side apply.
+// Shared groups (see SharedGroupLabelKey) can be owned by multiple Paas'es and are handled by ensureSharedGroup.
 func (r *PaasReconciler) ensureGroup(
 	ctx context.Context,
 	paas *v1alpha2.Paas,
 	group *userv1.Group,
 ) error {
-	var (
-		changed   bool
-		groupName = group.GetName()
-	)
+	if group.Labels[SharedGroupLabelKey] == "true" {
+		return r.ensureSharedGroup(ctx, paas, group)
+	}
 	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerGroupComponent)
-	// See if group already exists and create if it doesn't
-	found := &userv1.Group{}
-	err := r.Get(ctx, client.ObjectKeyFromObject(group), found)
-	if err != nil && errors.IsNotFound(err) {
-		logger.Info().Msg("creating group " + groupName)
-		// Create the group
-		if err = r.Create(ctx, group); err != nil {
-			// creating the group failed
+	if !paas.AmIOwner(group.OwnerReferences) {
+		if err := controllerutil.SetOwnerReference(paas, group, r.Scheme); err != nil {
+			logger.Err(err).Msg("error while setting owner reference on group " + group.Name)
 			return err
 		}
-		return nil
-	} else if err != nil {
-		// Error that isn't due to the group not existing
-		logger.Err(err).Msg("could not retrieve group " + groupName)
+	}
+	logger.Debug().Msg("applying group " + group.Name)
+	return applyObject(ctx, r, group)
+}
+
+// ensureSharedGroup ensures presence of a shared group, and adds an owner reference for this Paas. Shared groups are
+// applied with SharedGroupFieldManager, and only with the owner references, users and SharedGroupLabelKey label, so
+// that labels and annotations of other field managers are left alone. As other Paas'es also add their owner
+// references, the owner references are derived from the existing group, which is used as precondition.
+func (r *PaasReconciler) ensureSharedGroup(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	group *userv1.Group,
+) error {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerGroupComponent)
+	desired := &userv1.Group{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   group.Name,
+			Labels: map[string]string{SharedGroupLabelKey: "true"},
+		},
+		Users: group.Users,
+	}
+	found := &userv1.Group{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(group), found)
+	if err == nil {
+		desired.ResourceVersion = found.ResourceVersion
+		desired.OwnerReferences = found.OwnerReferences
+	} else if !errors.IsNotFound(err) {
+		logger.Err(err).Msg("could not retrieve group " + group.Name)
 		return err
 	}
-	if !paas.AmIOwner(found.OwnerReferences) {
-		logger.Info().Msg("setting owner reference on group " + groupName)
-		if err = controllerutil.SetOwnerReference(paas, found, r.Scheme); err != nil {
-			logger.Err(err).Msg("error while setting owner reference on group " + groupName)
+	if !paas.AmIOwner(desired.OwnerReferences) {
+		logger.Info().Msg("setting owner reference on group " + group.Name)
+		if err = controllerutil.SetOwnerReference(paas, desired, r.Scheme); err != nil {
+			logger.Err(err).Msg("error while setting owner reference on group " + group.Name)
 			return err
 		}
-		changed = true
-	}
-	if reflect.DeepEqual(group.Users, found.Users) {
-		logger.Debug().Msg("users for group " + groupName + " are as expected")
-	} else {
-		found.Users = group.Users
-		changed = true
-	}
-	if !reflect.DeepEqual(found.Labels, group.Labels) {
-		logger.Debug().Msg("group " + groupName + " labels changed")
-		found.Labels = group.Labels
-		changed = true
 	}
-	if changed {
-		return r.Update(ctx, found)
-	}
-	return nil
+	logger.Debug().Msg("applying shared group " + group.Name)
+	return applySharedObject(ctx, r, desired, SharedGroupFieldManager)
 }
 
 // backendGroup returns the desired group, based in the paasGroupKey and the group defined in that key.
@@ -91,9 +103,12 @@ func (r *PaasReconciler) backendGroup(
 	paasGroupKey string,
 	group v1alpha2.PaasGroup,
 ) (*userv1.Group, error) {
-	block := config.GetConfig().Spec.FeatureFlags.GroupUserManagement == "block"
+	block := config.GetConfigForPaas(paas).Spec.FeatureFlags.GroupUserManagement == "block"
 	_, logger := logging.GetLogComponent(ctx, logging.ControllerGroupComponent)
 	logger.Debug().Msg("defining group")
+	if group.Definition != "" {
+		return r.backendSharedGroup(paas, group.Definition, block)
+	}
 	// We don't manage groups with a query
 	if len(group.Query) != 0 {
 		return nil, nil
@@ -104,26 +119,56 @@ func (r *PaasReconciler) backendGroup(
 		return nil, nil
 	}
 
-	labels := map[string]string{}
-	myConfig := config.GetConfig()
-	labelTemplater := templating.NewTemplater(*paas, myConfig)
-	for name, tpl := range myConfig.Spec.Templating.GroupLabels {
-		result, err := labelTemplater.TemplateToMap(name, tpl)
-		if err != nil {
-			return nil, err
-		}
-		maps.Copy(labels, result)
+	myConfig := config.GetConfigForPaas(paas)
+	templater := templating.NewTemplater(*paas, myConfig)
+	labels, err := templateItemsToMap(templater, myConfig.Spec.Templating.GroupLabels)
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := templateItemsToMap(templater, myConfig.Spec.Templating.GroupAnnotations)
+	if err != nil {
+		return nil, err
 	}
 
 	g := &userv1.Group{}
 	groupName := paas.GroupKey2GroupName(paasGroupKey)
 	g.ObjectMeta = metav1.ObjectMeta{
-		Name:   groupName,
-		Labels: labels,
+		Name:        groupName,
+		Labels:      labels,
+		Annotations: annotations,
 	}
 	g.Users = group.Users
 	g.Labels[ManagedByLabelKey] = paas.Name
 
+	if err = controllerutil.SetOwnerReference(paas, g, r.Scheme); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// backendSharedGroup returns the desired group for a group definition in the PaasConfig.
+// The group is named after the definition and is not templated with Paas specific labels, as it is shared between
+// all Paas'es referencing the same definition.
+func (r *PaasReconciler) backendSharedGroup(
+	paas *v1alpha2.Paas,
+	definitionName string,
+	block bool,
+) (*userv1.Group, error) {
+	definition, exists := config.GetConfigForPaas(paas).Spec.GroupDefinitions[definitionName]
+	if !exists {
+		return nil, fmt.Errorf("group definition %s does not exist in PaasConfig", definitionName)
+	}
+	// We don't manage groups with a query
+	if len(definition.Query) != 0 || (block && len(definition.Users) > 0) {
+		return nil, nil
+	}
+	g := &userv1.Group{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   definitionName,
+			Labels: map[string]string{SharedGroupLabelKey: "true"},
+		},
+		Users: definition.Users,
+	}
 	if err := controllerutil.SetOwnerReference(paas, g, r.Scheme); err != nil {
 		return nil, err
 	}
@@ -161,7 +206,7 @@ func (r *PaasReconciler) finalizeGroups(
 	if err != nil {
 		return err
 	}
-	return nil
+	return r.releaseObsoleteSharedGroups(ctx, paas, []*userv1.Group{})
 }
 
 func (r *PaasReconciler) reconcileGroups(
@@ -182,6 +227,10 @@ func (r *PaasReconciler) reconcileGroups(
 	if err != nil {
 		return err
 	}
+	err = r.releaseObsoleteSharedGroups(ctx, paas, desiredGroups)
+	if err != nil {
+		return err
+	}
 	for _, group := range desiredGroups {
 		if err = r.ensureGroup(ctx, paas, group); err != nil {
 			logger.Err(err).Msgf("failure while reconciling group %s", group.Name)
@@ -209,6 +258,47 @@ func (r *PaasReconciler) deleteObsoleteGroups(
 	return nil
 }
 
+// releaseObsoleteSharedGroups removes the owner reference of the Paas from shared groups which are no longer desired
+// for this Paas. Shared groups without any remaining owner references are deleted, similar to clusterwide quotas.
+func (r *PaasReconciler) releaseObsoleteSharedGroups(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	desiredGroups []*userv1.Group,
+) error {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerGroupComponent)
+	var groups userv1.GroupList
+	if err := r.List(ctx, &groups, client.MatchingLabels{SharedGroupLabelKey: "true"}); err != nil {
+		return err
+	}
+	for _, group := range groups.Items {
+		if !paas.AmIOwner(group.OwnerReferences) || isGroupInGroups(&group, desiredGroups) {
+			continue
+		}
+		group.OwnerReferences = paas.WithoutMe(group.OwnerReferences)
+		if len(group.OwnerReferences) == 0 {
+			logger.Info().Msg("deleting shared group " + group.Name + " which is no longer used")
+			if err := r.Delete(ctx, &group); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			continue
+		}
+		logger.Info().Msg("removing owner reference from shared group " + group.Name)
+		released := &userv1.Group{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            group.Name,
+				ResourceVersion: group.ResourceVersion,
+				Labels:          map[string]string{SharedGroupLabelKey: "true"},
+				OwnerReferences: group.OwnerReferences,
+			},
+			Users: group.Users,
+		}
+		if err := applySharedObject(ctx, r, released, SharedGroupFieldManager); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // isGroupInGroups determines whether a list of groups contains a specified group, based on it's name
 func isGroupInGroups(group *userv1.Group, groups []*userv1.Group) bool {
 	for _, desiredGroup := range groups {
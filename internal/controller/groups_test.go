@@ -17,6 +17,7 @@ import (
 	userv1 "github.com/openshift/api/user/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("Group controller", Ordered, func() {
@@ -176,4 +177,75 @@ var _ = Describe("Group controller", Ordered, func() {
 			}
 		})
 	})
+	Context("When multiple Paas'es reference a shared group definition", func() {
+		const sharedGroupName = "shared-team"
+		var paas1, paas2 *v1alpha2.Paas
+		BeforeAll(func() {
+			for _, name := range []string{"shared-paas1", "shared-paas2"} {
+				assurePaas(ctx, v1alpha2.Paas{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Spec: v1alpha2.PaasSpec{
+						Groups: v1alpha2.PaasGroups{
+							"team": {Definition: sharedGroupName},
+						},
+					},
+				})
+			}
+			paas1 = getPaas(ctx, "shared-paas1")
+			paas2 = getPaas(ctx, "shared-paas2")
+		})
+		BeforeEach(func() {
+			myConfig.Spec.GroupDefinitions = v1alpha2.ConfigGroupDefinitions{
+				sharedGroupName: {Users: []string{"usr1", "usr2"}},
+			}
+			config.SetConfig(*myConfig)
+		})
+		It("should derive the group name from the definition", func() {
+			Expect(paas1.GroupKey2GroupName("team")).To(Equal(sharedGroupName))
+		})
+		It("should manage the group once with an owner reference to each Paas", func() {
+			Expect(reconciler.reconcileGroups(ctx, paas1)).To(Succeed())
+			Expect(reconciler.reconcileGroups(ctx, paas2)).To(Succeed())
+
+			found := &userv1.Group{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: sharedGroupName}, found)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.Users).To(Equal(userv1.OptionalNames{"usr1", "usr2"}))
+			Expect(found.Labels).To(HaveKeyWithValue(SharedGroupLabelKey, "true"))
+			Expect(found.Labels).NotTo(HaveKey(ManagedByLabelKey))
+			Expect(paas1.AmIOwner(found.OwnerReferences)).To(BeTrue())
+			Expect(paas2.AmIOwner(found.OwnerReferences)).To(BeTrue())
+		})
+		It("should leave labels of other field managers alone", func() {
+			found := &userv1.Group{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: sharedGroupName}, found)).To(Succeed())
+			found.Labels["other-label"] = "value"
+			Expect(k8sClient.Update(ctx, found, client.FieldOwner("someone-else"))).To(Succeed())
+
+			Expect(reconciler.reconcileGroups(ctx, paas1)).To(Succeed())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: sharedGroupName}, found)).To(Succeed())
+			Expect(found.Labels).To(HaveKeyWithValue("other-label", "value"))
+			Expect(found.Labels).To(HaveKeyWithValue(SharedGroupLabelKey, "true"))
+			Expect(found.ManagedFields).To(ContainElement(HaveField("Manager", SharedGroupFieldManager)))
+			Expect(paas2.AmIOwner(found.OwnerReferences)).To(BeTrue())
+		})
+		It("should only delete the group after the last Paas has released it", func() {
+			Expect(reconciler.finalizeGroups(ctx, paas1)).To(Succeed())
+			found := &userv1.Group{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: sharedGroupName}, found)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(paas1.AmIOwner(found.OwnerReferences)).To(BeFalse())
+			Expect(paas2.AmIOwner(found.OwnerReferences)).To(BeTrue())
+
+			Expect(reconciler.finalizeGroups(ctx, paas2)).To(Succeed())
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: sharedGroupName}, found)
+			Expect(err).To(HaveOccurred())
+		})
+		It("should fail for a definition which does not exist", func() {
+			myConfig.Spec.GroupDefinitions = nil
+			config.SetConfig(*myConfig)
+			_, err := reconciler.backendGroups(ctx, paas1)
+			Expect(err).To(MatchError(ContainSubstring("group definition shared-team does not exist")))
+		})
+	})
 })
@@ -7,13 +7,35 @@ See LICENSE.md for details.
 package controller
 
 import (
+	"maps"
+	"slices"
 	"strings"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/templating"
 )
 
+// paasTemplater is the Templater used by the Paas controller to render templates from the PaasConfig
+type paasTemplater = templating.Templater[v1alpha2.Paas, v1alpha2.PaasConfig, v1alpha2.PaasConfigSpec]
+
 func join(argv ...string) string {
 	return strings.Join(argv, "-")
 }
 
+// templateItemsToMap renders all templates in a templating item (in order of their keys) and merges the results
+// into one map, which can be used as labels or annotations.
+func templateItemsToMap(templater paasTemplater, items v1alpha2.ConfigTemplatingItem) (map[string]string, error) {
+	result := map[string]string{}
+	for _, name := range slices.Sorted(maps.Keys(items)) {
+		templated, err := templater.TemplateToMap(name, items[name])
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(result, templated)
+	}
+	return result, nil
+}
+
 // intersect finds the intersection of 2 lists of strings
 func intersect(l1 []string, l2 []string) (li []string) {
 	s := map[string]bool{}
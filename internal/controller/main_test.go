@@ -9,7 +9,10 @@ package controller
 import (
 	"testing"
 
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/templating"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestMain_intersection(t *testing.T) {
@@ -20,3 +23,22 @@ func TestMain_intersection(t *testing.T) {
 	lExpected := []string{"v2", "v3"}
 	assert.ElementsMatch(t, li, lExpected, "result of intersection not as expected")
 }
+
+func TestMain_templateItemsToMap(t *testing.T) {
+	paas := v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{Name: "my-paas"}}
+	templater := templating.NewTemplater(paas, v1alpha2.PaasConfig{})
+	result, err := templateItemsToMap(templater, v1alpha2.ConfigTemplatingItem{
+		"":       "key1: {{ .Paas.Name }}\nkey2: a",
+		"prefix": "key2: b",
+		"key1":   "{{ .Paas.Name }}-value",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"key1":        "my-paas-value",
+		"key2":        "a",
+		"prefix_key2": "b",
+	}, result, "templates should be merged in order of their keys")
+
+	_, err = templateItemsToMap(templater, v1alpha2.ConfigTemplatingItem{"invalid": "{{ .Paas.Name }"})
+	assert.Error(t, err)
+}
@@ -158,7 +158,7 @@ func (r *PaasReconciler) paasCapabilityNss(
 	paasGroups []string,
 ) (namespaceDefs, error) {
 	result := namespaceDefs{}
-	capsConfig := config.GetConfig().Spec.Capabilities
+	capsConfig := config.GetConfigForPaas(paas).Spec.Capabilities
 
 	for capName, capDef := range paas.Spec.Capabilities {
 		capConfig, ok := capsConfig[capName]
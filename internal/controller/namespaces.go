@@ -9,7 +9,6 @@ package controller
 import (
 	"context"
 	"fmt"
-	"maps"
 
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v3/internal/config"
@@ -17,45 +16,19 @@ import (
 	"github.com/belastingdienst/opr-paas/v3/internal/templating"
 
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-// ensureNamespace ensures Namespace presence in given namespace.
+// ensureNamespace ensures Namespace presence and labels / annotations as desired, using server-side apply.
 func ensureNamespace(
 	ctx context.Context,
-	r client.Client,
-	paas *v1alpha2.Paas,
+	r Reconciler,
 	ns *corev1.Namespace,
-	scheme *runtime.Scheme,
 ) error {
-	// See if namespace exists and create if it doesn't
-	found := &corev1.Namespace{}
-	err := r.Get(ctx, client.ObjectKeyFromObject(ns), found)
-	if err != nil && errors.IsNotFound(err) {
-		return r.Create(ctx, ns)
-	} else if err != nil {
-		// Error that isn't due to the namespace not existing
-		return err
-	} else if !paas.AmIOwner(found.OwnerReferences) {
-		if err = controllerutil.SetControllerReference(paas, found, scheme); err != nil {
-			return err
-		}
-	}
-	var changed bool
-	for key, value := range ns.Labels {
-		if orgValue, exists := found.Labels[key]; !exists || orgValue != value {
-			changed = true
-			found.Labels[key] = value
-		}
-	}
-	if changed {
-		return r.Update(ctx, found)
-	}
-	return nil
+	return applyObject(ctx, r, ns)
 }
 
 // backendNamespace is a code for defining Namespaces
@@ -70,30 +43,31 @@ func backendNamespace(
 	_, logger := logging.GetLogComponent(ctx, logging.ControllerNamespaceComponent)
 	logger.Info().Msgf("defining %s Namespace", name)
 
-	labels := map[string]string{}
-	myConfig := config.GetConfig()
-	labelTemplater := templating.NewTemplater(*paas, myConfig)
-	for tplName, tpl := range myConfig.Spec.Templating.NamespaceLabels {
-		result, err := labelTemplater.TemplateToMap(tplName, tpl)
-		if err != nil {
-			return nil, err
-		}
-		maps.Copy(labels, result)
+	myConfig := config.GetConfigForPaas(paas)
+	templater := templating.NewTemplater(*paas, myConfig)
+	labels, err := templateItemsToMap(templater, myConfig.Spec.Templating.NamespaceLabels)
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := templateItemsToMap(templater, myConfig.Spec.Templating.NamespaceAnnotations)
+	if err != nil {
+		return nil, err
 	}
 
 	ns := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:   name,
-			Labels: labels,
+			Name:        name,
+			Labels:      labels,
+			Annotations: annotations,
 		},
 		Spec: corev1.NamespaceSpec{},
 	}
 	logger.Info().Msgf("setting Quotagroup %s", quota)
-	ns.Labels[config.GetConfig().Spec.QuotaLabel] = quota
+	ns.Labels[config.GetConfigForPaas(paas).Spec.QuotaLabel] = quota
 	ns.Labels[ManagedByLabelKey] = paas.Name
 
 	logger.Info().Str("Paas", paas.Name).Str("namespace", ns.Name).Msg("setting Owner")
-	if err := controllerutil.SetControllerReference(paas, ns, scheme); err != nil {
+	if err = controllerutil.SetControllerReference(paas, ns, scheme); err != nil {
 		logger.Err(err).Msg("setControllerReference failure")
 		return nil, err
 	}
@@ -113,7 +87,7 @@ func (r *PaasReconciler) reconcileNamespaces(
 		var ns *corev1.Namespace
 		if ns, err = backendNamespace(ctx, paas, nsDef.nsName, nsDef.quotaName, r.Scheme); err != nil {
 			return fmt.Errorf("failure while defining namespace %s: %s", nsDef.nsName, err.Error())
-		} else if err = ensureNamespace(ctx, r.Client, paas, ns, r.Scheme); err != nil {
+		} else if err = ensureNamespace(ctx, r, ns); err != nil {
 			return fmt.Errorf("failure while creating namespace %s: %s", nsDef.nsName, err.Error())
 		}
 		logger.Debug().Msgf("namespace %s successfully created with quotaName %s", nsDef.nsName, nsDef.quotaName)
@@ -31,6 +31,7 @@ var _ = Describe("Namespace", Ordered, func() {
 		reqLbl        = "requestor-label"
 		qtaLbl        = "quota-label"
 		kubeInstLabel = "app.kubernetes.io/instance"
+		nodeSelAnn    = "openshift.io/node-selector"
 	)
 	var (
 		paas       *v1alpha2.Paas
@@ -91,6 +92,9 @@ var _ = Describe("Namespace", Ordered, func() {
 						"":       "{{ range $key, $value := .Paas.Labels }}{{ if ne $key \"" + kubeInstLabel + "\" }}{{$key}}: {{$value}}\n{{end}}{{end}}",
 						manByLbl: "{{ .Paas.Spec.ManagedByPaas }}-" + manBySuffix,
 					},
+					NamespaceAnnotations: v1alpha2.ConfigTemplatingItem{
+						nodeSelAnn: "paas={{ .Paas.Name }}",
+					},
 				},
 			},
 		}
@@ -145,5 +149,25 @@ var _ = Describe("Namespace", Ordered, func() {
 				Expect(ns.ObjectMeta.Labels).NotTo(HaveKey(kubeInstLabel))
 			}
 		})
+		It("have set all expected annotations", func() {
+			for nsName := range nsDefs {
+				var ns corev1.Namespace
+				err := reconciler.Get(ctx, types.NamespacedName{Name: nsName}, &ns)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ns.ObjectMeta.Annotations).To(HaveKeyWithValue(nodeSelAnn, "paas="+paasName))
+			}
+		})
+		It("prunes labels which are no longer templated", func() {
+			delete(myConfig.Spec.Templating.NamespaceLabels, manByLbl)
+			config.SetConfig(myConfig)
+			Expect(reconciler.reconcileNamespaces(ctx, paas, nsDefs)).To(Succeed())
+			for nsName := range nsDefs {
+				var ns corev1.Namespace
+				err := reconciler.Get(ctx, types.NamespacedName{Name: nsName}, &ns)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ns.ObjectMeta.Labels).NotTo(HaveKey(manByLbl))
+				Expect(ns.ObjectMeta.Labels).To(HaveKeyWithValue(ManagedByLabelKey, paasName))
+			}
+		})
 	})
 })
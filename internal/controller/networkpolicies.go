@@ -0,0 +1,119 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/config"
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	"github.com/belastingdienst/opr-paas/v3/internal/templating"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+const networkPolicyPrefix = "paas"
+
+// backendNetworkPolicies renders the NetworkPolicy templates from the PaasConfig for a namespace of the Paas.
+// Templates rendering an empty string are skipped.
+func (r *PaasReconciler) backendNetworkPolicies(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	nsDef namespaceDef,
+) (policies []*networkingv1.NetworkPolicy, err error) {
+	_, logger := logging.GetLogComponent(ctx, logging.ControllerNetworkPolicyComponent)
+	myConfig := config.GetConfigForPaas(paas)
+	templater := templating.NewTemplater(*paas, myConfig).WithNamespace(nsDef.nsName, nsDef.capName)
+	tplNames := make([]string, 0, len(myConfig.Spec.Templating.NetworkPolicies))
+	for name := range myConfig.Spec.Templating.NetworkPolicies {
+		tplNames = append(tplNames, name)
+	}
+	slices.Sort(tplNames)
+	for _, name := range tplNames {
+		var rendered string
+		rendered, err = templater.TemplateToString(name, myConfig.Spec.Templating.NetworkPolicies[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to render network policy %s: %w", name, err)
+		}
+		if strings.TrimSpace(rendered) == "" {
+			logger.Debug().Str("namespace", nsDef.nsName).Msgf("network policy %s renders empty, skipping", name)
+			continue
+		}
+		np := &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      join(networkPolicyPrefix, name),
+				Namespace: nsDef.nsName,
+				Labels:    map[string]string{ManagedByLabelKey: paas.Name},
+			},
+		}
+		if err = yaml.UnmarshalStrict([]byte(rendered), &np.Spec); err != nil {
+			return nil, fmt.Errorf("network policy %s does not render a valid NetworkPolicySpec: %w", name, err)
+		}
+		if err = controllerutil.SetControllerReference(paas, np, r.Scheme); err != nil {
+			return nil, err
+		}
+		policies = append(policies, np)
+	}
+	return policies, nil
+}
+
+// ensureNetworkPolicy ensures NetworkPolicy presence and spec as desired, using server-side apply.
+func (r *PaasReconciler) ensureNetworkPolicy(
+	ctx context.Context,
+	np *networkingv1.NetworkPolicy,
+) error {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerNetworkPolicyComponent)
+	logger.Debug().Str("namespace", np.Namespace).Msgf("applying network policy %s", np.Name)
+	return applyObject(ctx, r, np)
+}
+
+// reconcileNetworkPolicies creates and updates all NetworkPolicies as templated in the PaasConfig in every namespace
+// of the Paas, and deletes NetworkPolicies which the operator created before but are no longer rendered.
+func (r *PaasReconciler) reconcileNetworkPolicies(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	nsDefs namespaceDefs,
+) error {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerNetworkPolicyComponent)
+	for _, nsDef := range nsDefs {
+		desired, err := r.backendNetworkPolicies(ctx, paas, nsDef)
+		if err != nil {
+			return err
+		}
+		var existing networkingv1.NetworkPolicyList
+		if err = r.List(ctx, &existing,
+			client.InNamespace(nsDef.nsName),
+			client.MatchingLabels{ManagedByLabelKey: paas.Name},
+		); err != nil {
+			return err
+		}
+		for _, np := range existing.Items {
+			if slices.ContainsFunc(desired, func(d *networkingv1.NetworkPolicy) bool { return d.Name == np.Name }) {
+				continue
+			}
+			logger.Info().Str("namespace", np.Namespace).Msgf("deleting obsolete network policy %s", np.Name)
+			if err = r.Delete(ctx, &np); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+		for _, np := range desired {
+			if err = r.ensureNetworkPolicy(ctx, np); err != nil {
+				return fmt.Errorf("failure while reconciling network policy %s/%s: %w", np.Namespace, np.Name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/config"
+	paasquota "github.com/belastingdienst/opr-paas/v3/pkg/quota"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("NetworkPolicies", Ordered, func() {
+	const (
+		paasName    = "np-paas"
+		capName     = "argocd"
+		nsName      = "ns1"
+		denyAll     = "deny-all"
+		samePaas    = "same-paas"
+		argoIngress = "argocd-ingress"
+	)
+	var (
+		paas       *v1alpha2.Paas
+		reconciler *PaasReconciler
+		myConfig   v1alpha2.PaasConfig
+		nsDefs     namespaceDefs
+	)
+	ctx := context.Background()
+
+	BeforeAll(func() {
+		paas = &v1alpha2.Paas{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: paasName,
+			},
+			Spec: v1alpha2.PaasSpec{
+				Requestor: paasName,
+				Capabilities: v1alpha2.PaasCapabilities{
+					capName: v1alpha2.PaasCapability{},
+				},
+				Quota: paasquota.Quota{
+					"cpu": resourcev1.MustParse("1"),
+				},
+				Namespaces: v1alpha2.PaasNamespaces{
+					nsName: {},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, paas)).To(Succeed())
+		myConfig = v1alpha2.PaasConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "paas-config",
+			},
+			Spec: v1alpha2.PaasConfigSpec{
+				Capabilities: map[string]v1alpha2.ConfigCapability{
+					capName: {
+						QuotaSettings: v1alpha2.ConfigQuotaSettings{
+							DefQuota: map[corev1.ResourceName]resourcev1.Quantity{
+								corev1.ResourceLimitsCPU: resourcev1.MustParse("5"),
+							},
+						},
+					},
+				},
+				Templating: v1alpha2.ConfigTemplatingItems{
+					NetworkPolicies: v1alpha2.ConfigTemplatingItem{
+						denyAll: "podSelector: {}\npolicyTypes:\n- Ingress\n",
+						samePaas: `podSelector: {}
+ingress:
+- from:
+  - namespaceSelector:
+      matchLabels:
+        ` + ManagedByLabelKey + `: {{ .Paas.Name }}
+`,
+						argoIngress: `{{ if eq .Namespace.Capability "` + capName + `" }}podSelector: {}
+ingress:
+- from:
+  - namespaceSelector:
+      matchLabels:
+        kubernetes.io/metadata.name: openshift-gitops
+{{ end }}`,
+					},
+				},
+			},
+		}
+		config.SetConfig(myConfig)
+		reconciler = &PaasReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+		var err error
+		nsDefs, err = reconciler.nsDefsFromPaas(ctx, paas)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.reconcileNamespaces(ctx, paas, nsDefs)).To(Succeed())
+	})
+
+	getPolicies := func(namespace string) map[string]networkingv1.NetworkPolicy {
+		var list networkingv1.NetworkPolicyList
+		Expect(reconciler.List(ctx, &list,
+			client.InNamespace(namespace),
+			client.MatchingLabels{ManagedByLabelKey: paasName},
+		)).To(Succeed())
+		policies := map[string]networkingv1.NetworkPolicy{}
+		for _, np := range list.Items {
+			policies[np.Name] = np
+		}
+		return policies
+	}
+
+	When("reconciling network policies for a paas", func() {
+		It("reconciles successfully", func() {
+			Expect(reconciler.reconcileNetworkPolicies(ctx, paas, nsDefs)).To(Succeed())
+		})
+		It("creates the generic policies in every namespace", func() {
+			for nsFullName := range nsDefs {
+				policies := getPolicies(nsFullName)
+				Expect(policies).To(HaveKey(join("paas", denyAll)))
+				Expect(policies).To(HaveKey(join("paas", samePaas)))
+				np := policies[join("paas", samePaas)]
+				Expect(np.Spec.Ingress).To(HaveLen(1))
+				Expect(np.Spec.Ingress[0].From[0].NamespaceSelector.MatchLabels).
+					To(HaveKeyWithValue(ManagedByLabelKey, paasName))
+				Expect(paas.AmIOwner(np.OwnerReferences)).To(BeTrue())
+			}
+		})
+		It("only creates capability specific policies in the capability namespace", func() {
+			Expect(getPolicies(join(paasName, capName))).To(HaveKey(join("paas", argoIngress)))
+			Expect(getPolicies(join(paasName, nsName))).NotTo(HaveKey(join("paas", argoIngress)))
+		})
+	})
+
+	When("a template is removed from the PaasConfig", func() {
+		It("deletes the obsolete policies", func() {
+			delete(myConfig.Spec.Templating.NetworkPolicies, denyAll)
+			config.SetConfig(myConfig)
+			Expect(reconciler.reconcileNetworkPolicies(ctx, paas, nsDefs)).To(Succeed())
+			for nsFullName := range nsDefs {
+				Expect(getPolicies(nsFullName)).NotTo(HaveKey(join("paas", denyAll)))
+			}
+		})
+	})
+
+	When("a template renders an invalid NetworkPolicySpec", func() {
+		It("returns an error", func() {
+			myConfig.Spec.Templating.NetworkPolicies["invalid"] = "notAField: {{ .Paas.Name }}"
+			config.SetConfig(myConfig)
+			err := reconciler.reconcileNetworkPolicies(ctx, paas, nsDefs)
+			Expect(err).To(MatchError(ContainSubstring("does not render a valid NetworkPolicySpec")))
+		})
+	})
+})
@@ -11,9 +11,11 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	quotav1 "github.com/openshift/api/quota/v1"
 	userv1 "github.com/openshift/api/user/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -22,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/config"
 	"github.com/belastingdienst/opr-paas/v3/internal/logging"
 	"github.com/belastingdienst/opr-paas/v3/internal/paasresource"
 
@@ -30,7 +33,9 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
@@ -41,6 +46,12 @@ const paasFinalizer = "paas.cpet.belastingdienst.nl/finalizer"
 type PaasReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// MaxConcurrentReconciles is the maximum number of Paas'es which are reconciled in parallel (defaults to 1)
+	MaxConcurrentReconciles int
+	// Rollout defines how changes of the active PaasConfig are rolled out to all Paas'es
+	Rollout RolloutOptions
+	// Shard limits reconciliation to the Paas'es owned by this replica, when sharding is enabled
+	Shard ShardOptions
 }
 
 // GetScheme is a simple getter for the Scheme of the Paas Controller logic
@@ -55,6 +66,8 @@ type Reconciler interface {
 	Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error
 	getScheme() *runtime.Scheme
 	Delete(context.Context, client.Object, ...client.DeleteOption) error
+	Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error
+	Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error
 }
 
 //revive:disable:line-length-limit
@@ -67,6 +80,7 @@ type Reconciler interface {
 // +kubebuilder:rbac:groups=argoproj.io,resources=applicationsets,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=core,resources=secrets;namespaces,verbs=create;delete;get;list;patch;update;watch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings;clusterrolebindings,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=create;delete;get;list;patch;update;watch
 
 // +kubebuilder:rbac:groups=cpet.belastingdienst.nl,resources=paasns,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cpet.belastingdienst.nl,resources=paasns/status,verbs=get;update;patch
@@ -206,6 +220,12 @@ func (r *PaasReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resul
 	paas := &v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{Name: req.Name}}
 	ctx, logger := logging.SetControllerLogger(ctx, paas, r.Scheme, req)
 
+	if !r.Shard.Owns(req.Name) {
+		// Owned resources and PaasNs'es can trigger requests for Paas'es which are handled by another shard
+		logger.Debug().Msg("paas is owned by another shard, skipping")
+		return ctrl.Result{}, nil
+	}
+
 	if paas, err = r.getPaasFromRequest(ctx, req); err != nil {
 		logger.Err(err).Msg("could not get Paas from k8s")
 		return ctrl.Result{}, err
@@ -216,6 +236,21 @@ func (r *PaasReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resul
 		return ctrl.Result{}, nil
 	}
 
+	// Record which PaasConfig is used, so that the health of a candidate PaasConfig can be judged by canary Paas'es
+	paasConfig, err := config.GetConfigForPaasWithError(paas)
+	if err != nil {
+		logger.Err(err).Msg("could not determine PaasConfig for Paas")
+		return ctrl.Result{}, errors.Join(err, r.setErrorCondition(ctx, paas, err))
+	}
+	paas.Status.PaasConfig = &v1alpha2.PaasConfigReference{Name: paasConfig.Name, Generation: paasConfig.Generation}
+	if !paasConfig.IsCandidate() {
+		paas.Status.PaasConfig.Revision = paasConfig.RevisionName()
+	}
+	// A Paas which is pinned to a PaasConfigRevision moves on to the current PaasConfig when the pin expires
+	if _, until, _ := paas.PinnedRevision(time.Now()); time.Now().Before(until) {
+		result.RequeueAfter = time.Until(until)
+	}
+
 	paasReconcilers := []func(context.Context, *v1alpha2.Paas) error{
 		r.reconcileQuotas,
 		r.reconcileClusterWideQuota,
@@ -231,7 +266,7 @@ func (r *PaasReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resul
 		}
 	}
 	// Reconciling succeeded, set appropriate Condition
-	return ctrl.Result{}, r.setSuccessfulCondition(ctx, paas)
+	return result, r.setSuccessfulCondition(ctx, paas)
 }
 
 func (r *PaasReconciler) reconcileNamespacedResources(
@@ -251,6 +286,8 @@ func (r *PaasReconciler) reconcileNamespacedResources(
 		r.reconcilePaasRolebindings,
 		r.reconcilePaasSecrets,
 		r.reconcileClusterRoleBindings,
+		r.reconcileNetworkPolicies,
+		r.reconcileExtraResources,
 	}
 	for _, reconciler := range paasNsReconcilers {
 		if err = reconciler(ctx, paas, nsDefs); err != nil {
@@ -310,29 +347,26 @@ func paasFromNs(ns corev1.Namespace) (string, error) {
 	return paasName, nil
 }
 
-// allPaases is a simple wrapper to collect all Paas'es and created requests for them on PaasConfig changes
-// allPaases is not unittests ATM. We might add an e2e test for this instead.
-func allPaases(mgr ctrl.Manager) []reconcile.Request {
-	// Enqueue all Paas objects
-	var reqs []reconcile.Request
-	var paasList v1alpha2.PaasList
-	ctx := context.Background()
-	_, logger := logging.GetLogComponent(ctx, logging.ControllerPaasComponent)
-	if err := mgr.GetClient().List(ctx, &paasList); err != nil {
-		logger.Error().AnErr("error", err).Msg("unable to list paases")
-		return nil
-	}
+// shardPredicate returns a predicate which filters out all Paas'es which are not owned by this shard
+func (r *PaasReconciler) shardPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return r.Shard.Owns(obj.GetName())
+	})
+}
 
-	for _, p := range paasList.Items {
-		reqs = append(reqs, reconcile.Request{
-			NamespacedName: types.NamespacedName{
-				Namespace: p.Namespace,
-				Name:      p.Name,
-			},
-		})
+// pinAnnotationsChangedPredicate returns a predicate for changes of the annotations which pin a Paas to a
+// PaasConfigRevision
+func pinAnnotationsChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			for _, key := range []string{v1alpha2.PinRevisionAnnotation, v1alpha2.PinRevisionUntilAnnotation} {
+				if e.ObjectOld.GetAnnotations()[key] != e.ObjectNew.GetAnnotations()[key] {
+					return true
+				}
+			}
+			return false
+		},
 	}
-
-	return reqs
 }
 
 // specOrLabelsChangedPredicate returns a reusable predicate for spec or label changes
@@ -349,7 +383,11 @@ func specOrLabelsChangedPredicate() predicate.Predicate {
 // SetupWithManager is not unit-tested ATM. Mostly covered by e2e-tests.
 func (r *PaasReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha2.Paas{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
+		For(&v1alpha2.Paas{}, builder.WithPredicates(
+			predicate.Or(specOrLabelsChangedPredicate(), pinAnnotationsChangedPredicate()),
+			r.shardPredicate(),
+		)).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		// Reconcile on owned resources changes
 		Owns(&quotav1.ClusterResourceQuota{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
 		Owns(&userv1.Group{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
@@ -357,6 +395,7 @@ func (r *PaasReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.Namespace{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
 		Owns(&rbacv1.RoleBinding{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
 		Owns(&rbacv1.ClusterRoleBinding{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
+		Owns(&networkingv1.NetworkPolicy{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
 		// TODO(portly-halicore-76):We don't own PaasNS objects correctly yet
 		// Owns(&v1alpha2.PaasNS{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
 		// TODO(portly-halicore-76): We don't own Rolebinding objects correctly yet
@@ -376,11 +415,14 @@ func (r *PaasReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			), builder.WithPredicates(specOrLabelsChangedPredicate())).
 		Watches(
 			&v1alpha2.PaasConfig{},
-			handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
-				return allPaases(mgr)
-			}),
+			&paasConfigRolloutHandler{reader: mgr.GetClient(), options: r.Rollout, shard: r.Shard},
 			builder.WithPredicates(v1alpha2.ActivePaasConfigUpdated()),
 		).
+		Watches(
+			&v1alpha2.PaasConfig{},
+			candidateRolloutHandler(mgr.GetClient(), r.Shard),
+			builder.WithPredicates(v1alpha2.CandidatePaasConfigUpdated()),
+		).
 		Complete(r)
 }
 
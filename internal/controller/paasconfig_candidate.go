@@ -0,0 +1,189 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/config"
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// candidateRecheckInterval is the interval in which the canary Paas'es of a soaking candidate PaasConfig are checked
+const candidateRecheckInterval = 30 * time.Second
+
+// judgeCanaries determines which canary Paas'es failed with the candidate PaasConfig, and how many canary Paas'es
+// are not yet reconciled with the candidate PaasConfig.
+func judgeCanaries(
+	candidate *v1alpha2.PaasConfig,
+	canaries []v1alpha2.Paas,
+) (failed []v1alpha2.PaasConfigFailedPaas, pending int) {
+	for _, paas := range canaries {
+		if paas.Status.PaasConfig == nil || paas.Status.PaasConfig.Name != candidate.Name ||
+			paas.Status.PaasConfig.Generation != candidate.Generation {
+			pending++
+			continue
+		}
+		ready := meta.FindStatusCondition(paas.Status.Conditions, v1alpha2.TypeReadyPaas)
+		if ready == nil || ready.ObservedGeneration != paas.Generation || ready.Status == metav1.ConditionUnknown {
+			pending++
+			continue
+		}
+		if ready.Status == metav1.ConditionTrue {
+			continue
+		}
+		message := ready.Message
+		if hasErrors := meta.FindStatusCondition(paas.Status.Conditions, v1alpha2.TypeHasErrorsPaas); hasErrors != nil {
+			message = hasErrors.Message
+		}
+		failed = append(failed, v1alpha2.PaasConfigFailedPaas{Name: paas.Name, Message: message})
+	}
+	slices.SortFunc(failed, func(a, b v1alpha2.PaasConfigFailedPaas) int { return strings.Compare(a.Name, b.Name) })
+	return failed, pending
+}
+
+// setCandidateCondition sets the Candidate condition and the other conditions of a candidate PaasConfig
+func (pcr *PaasConfigReconciler) setCandidateCondition(
+	ctx context.Context,
+	candidate *v1alpha2.PaasConfig,
+	status metav1.ConditionStatus,
+	reason string,
+	message string,
+) error {
+	meta.SetStatusCondition(&candidate.Status.Conditions, metav1.Condition{
+		Type:   v1alpha2.TypeCandidatePaasConfig,
+		Status: status, Reason: reason, ObservedGeneration: candidate.Generation,
+		Message: message,
+	})
+	meta.SetStatusCondition(&candidate.Status.Conditions, metav1.Condition{
+		Type:   v1alpha2.TypeHasErrorsPaasConfig,
+		Status: metav1.ConditionFalse, Reason: "Reconciling", ObservedGeneration: candidate.Generation,
+		Message: fmt.Sprintf("Reconciled (%s) successfully", candidate.Name),
+	})
+	return pcr.Status().Update(ctx, candidate)
+}
+
+// rejectCandidate marks a candidate PaasConfig as rejected, after which canary Paas'es use the active PaasConfig again
+func (pcr *PaasConfigReconciler) rejectCandidate(
+	ctx context.Context,
+	candidate *v1alpha2.PaasConfig,
+	failed []v1alpha2.PaasConfigFailedPaas,
+	message string,
+) error {
+	_, logger := logging.GetLogComponent(ctx, logging.ControllerPaasConfigComponent)
+	logger.Warn().Msgf("rejecting candidate PaasConfig: %s", message)
+	config.ClearCandidateConfig(candidate.Name)
+	candidate.Status.FailedPaases = failed
+	return pcr.setCandidateCondition(ctx, candidate, metav1.ConditionFalse, v1alpha2.CandidateReasonRejected, message)
+}
+
+// promoteCandidate copies the spec of a candidate PaasConfig into the active PaasConfig
+func (pcr *PaasConfigReconciler) promoteCandidate(ctx context.Context, candidate *v1alpha2.PaasConfig) error {
+	_, logger := logging.GetLogComponent(ctx, logging.ControllerPaasConfigComponent)
+	var list v1alpha2.PaasConfigList
+	if err := pcr.List(ctx, &list); err != nil {
+		return err
+	}
+	idx := slices.IndexFunc(list.Items, func(cfg v1alpha2.PaasConfig) bool { return !cfg.IsCandidate() })
+	if idx < 0 {
+		return errors.New("no active PaasConfig to promote the candidate PaasConfig to")
+	}
+	active := &list.Items[idx]
+	logger.Info().Msgf("promoting candidate PaasConfig to active PaasConfig %s", active.Name)
+	active.Spec = *candidate.Spec.DeepCopy()
+	active.Spec.Candidate = nil
+	if err := pcr.Update(ctx, active); err != nil {
+		return fmt.Errorf("failed to promote candidate PaasConfig: %w", err)
+	}
+	// Switch the active config before clearing the candidate, so that canary Paas'es do not flip back
+	if !reflect.DeepEqual(active.Spec.DecryptKeysSecret, config.GetConfig().Spec.DecryptKeysSecret) {
+		resetCrypts()
+	}
+	config.SetConfig(*active)
+	config.ClearCandidateConfig(candidate.Name)
+	candidate.Status.FailedPaases = nil
+	return pcr.setCandidateCondition(ctx, candidate, metav1.ConditionFalse, v1alpha2.CandidateReasonPromoted,
+		fmt.Sprintf("Promoted to active PaasConfig %s", active.Name))
+}
+
+// reconcileCandidate rolls out a candidate PaasConfig to canary Paas'es. The candidate is rejected as soon as a canary
+// Paas fails, and promoted to the active PaasConfig when all canary Paas'es are Ready after the soak period.
+func (pcr *PaasConfigReconciler) reconcileCandidate(
+	ctx context.Context,
+	candidate *v1alpha2.PaasConfig,
+) (ctrl.Result, error) {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerPaasConfigComponent)
+	cond := meta.FindStatusCondition(candidate.Status.Conditions, v1alpha2.TypeCandidatePaasConfig)
+	if cond != nil && cond.ObservedGeneration == candidate.Generation &&
+		cond.Reason != v1alpha2.CandidateReasonSoaking {
+		logger.Debug().Msgf("candidate PaasConfig is %s", strings.ToLower(cond.Reason))
+		config.ClearCandidateConfig(candidate.Name)
+		return ctrl.Result{}, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&candidate.Spec.Candidate.CanarySelector)
+	if err != nil {
+		return ctrl.Result{}, pcr.rejectCandidate(ctx, candidate, nil,
+			fmt.Sprintf("invalid canary selector: %s", err.Error()))
+	}
+
+	if !candidate.IsSoaking() {
+		// (Re)start soaking, as this generation of the candidate was not applied to the canary Paas'es before
+		logger.Info().Msg("applying candidate PaasConfig to canary Paas'es")
+		meta.RemoveStatusCondition(&candidate.Status.Conditions, v1alpha2.TypeCandidatePaasConfig)
+		candidate.Status.FailedPaases = nil
+		if err = pcr.setCandidateCondition(ctx, candidate, metav1.ConditionTrue, v1alpha2.CandidateReasonSoaking,
+			"Candidate is applied to canary Paas'es"); err != nil {
+			return ctrl.Result{}, err
+		}
+		config.UpdateCandidateConfig(candidate)
+		return ctrl.Result{RequeueAfter: candidateRecheckInterval}, nil
+	}
+	config.UpdateCandidateConfig(candidate)
+
+	var canaries v1alpha2.PaasList
+	if err = pcr.List(ctx, &canaries, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return ctrl.Result{}, err
+	}
+	failed, pending := judgeCanaries(candidate, canaries.Items)
+	if len(failed) > 0 {
+		names := make([]string, 0, len(failed))
+		for _, paas := range failed {
+			names = append(names, paas.Name)
+		}
+		return ctrl.Result{}, pcr.rejectCandidate(ctx, candidate, failed,
+			fmt.Sprintf("Canary Paas'es failed with candidate: %s", strings.Join(names, ", ")))
+	}
+
+	soakStart := meta.FindStatusCondition(candidate.Status.Conditions, v1alpha2.TypeCandidatePaasConfig).
+		LastTransitionTime
+	remaining := time.Until(soakStart.Add(candidate.Spec.Candidate.SoakPeriod.Duration))
+	if pending > 0 || remaining > 0 {
+		logger.Debug().Msgf("candidate PaasConfig is soaking: %d canary Paas'es pending, %s remaining",
+			pending, remaining.Round(time.Second))
+		requeueAfter := candidateRecheckInterval
+		if pending == 0 && remaining < requeueAfter {
+			requeueAfter = remaining
+		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	if len(canaries.Items) == 0 {
+		logger.Warn().Msg("no canary Paas'es selected by candidate PaasConfig")
+	}
+	return ctrl.Result{}, pcr.promoteCandidate(ctx, candidate)
+}
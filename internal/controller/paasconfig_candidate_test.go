@@ -0,0 +1,51 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPaasConfigCandidate_judgeCanaries(t *testing.T) {
+	candidate := &v1alpha2.PaasConfig{ObjectMeta: metav1.ObjectMeta{Name: "candidate", Generation: 2}}
+	newCanary := func(name string, cfgGeneration int64, ready metav1.ConditionStatus) v1alpha2.Paas {
+		return v1alpha2.Paas{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 1},
+			Status: v1alpha2.PaasStatus{
+				PaasConfig: &v1alpha2.PaasConfigReference{Name: "candidate", Generation: cfgGeneration},
+				Conditions: []metav1.Condition{
+					{Type: v1alpha2.TypeReadyPaas, Status: ready, ObservedGeneration: 1},
+					{Type: v1alpha2.TypeHasErrorsPaas, Status: metav1.ConditionTrue, Message: name + " is broken"},
+				},
+			},
+		}
+	}
+
+	failed, pending := judgeCanaries(candidate, []v1alpha2.Paas{
+		newCanary("ready", 2, metav1.ConditionTrue),
+		newCanary("old-generation", 1, metav1.ConditionFalse),
+		newCanary("unknown", 2, metav1.ConditionUnknown),
+		{ObjectMeta: metav1.ObjectMeta{Name: "never-reconciled"}},
+	})
+	assert.Empty(t, failed)
+	assert.Equal(t, 3, pending)
+
+	failed, pending = judgeCanaries(candidate, []v1alpha2.Paas{
+		newCanary("z-failed", 2, metav1.ConditionFalse),
+		newCanary("a-failed", 2, metav1.ConditionFalse),
+		newCanary("ready", 2, metav1.ConditionTrue),
+	})
+	assert.Equal(t, []v1alpha2.PaasConfigFailedPaas{
+		{Name: "a-failed", Message: "a-failed is broken"},
+		{Name: "z-failed", Message: "z-failed is broken"},
+	}, failed)
+	assert.Equal(t, 0, pending)
+}
@@ -52,6 +52,7 @@ func (pcr PaasConfigReconciler) GetScheme() *runtime.Scheme {
 func (pcr *PaasConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha2.PaasConfig{}).
+		Owns(&v1alpha2.PaasConfigRevision{}).
 		WithEventFilter(
 			predicate.GenerationChangedPredicate{}, // Spec changed .
 		).
@@ -97,10 +98,18 @@ func (pcr *PaasConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, nil
 	}
 
+	if cfg.IsCandidate() {
+		return pcr.reconcileCandidate(ctx, cfg)
+	}
+
 	// As there can be reasons why we reconcile again, we check if there is a diff in the desired state vs GetConfig()
 	// when there is no change, we exit this function.
 	if reflect.DeepEqual(cfg.Spec, config.GetConfig().Spec) {
 		logger.Info().Msg("Cached config equals desired state")
+		if err := pcr.ensureRevision(ctx, cfg); err != nil {
+			logger.Err(err).Msg("failed to ensure PaasConfigRevision")
+			return ctrl.Result{}, err
+		}
 		// Reconciling succeeded, set appropriate Condition
 		err := pcr.setSuccessfulCondition(ctx, cfg)
 		if err != nil {
@@ -120,6 +129,10 @@ func (pcr *PaasConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// Update the shared configuration store
 	config.SetConfig(*cfg)
 	logger.Info().Msg("Set the cached config successfully")
+	if err := pcr.ensureRevision(ctx, cfg); err != nil {
+		logger.Err(err).Msg("failed to ensure PaasConfigRevision")
+		return ctrl.Result{}, err
+	}
 
 	// Reconciling succeeded, set appropriate Condition
 	err := pcr.setSuccessfulCondition(ctx, cfg)
@@ -154,6 +167,10 @@ func (pcr *PaasConfigReconciler) finalize(
 	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerPaasConfigComponent)
 	logger.Info().Msg("paasconfig marked for deletion")
 
+	if cfg.IsCandidate() {
+		config.ClearCandidateConfig(cfg.Name)
+	}
+
 	if controllerutil.ContainsFinalizer(cfg, paasconfigFinalizer) {
 		// Let's add here a status "Downgrade" to reflect that this resource began its process to be terminated.
 		meta.SetStatusCondition(&cfg.Status.Conditions, metav1.Condition{
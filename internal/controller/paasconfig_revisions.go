@@ -0,0 +1,107 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const defaultRevisionHistoryLimit = 10
+
+// +kubebuilder:rbac:groups=cpet.belastingdienst.nl,resources=paasconfigrevision,verbs=get;list;watch;create;delete
+
+// ensureRevision creates a PaasConfigRevision with a snapshot of the spec of the active PaasConfig (when it does not
+// exist yet), and prunes revisions exceeding the history limit.
+func (pcr *PaasConfigReconciler) ensureRevision(ctx context.Context, cfg *v1alpha2.PaasConfig) error {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerPaasConfigComponent)
+	revision := v1alpha2.NewPaasConfigRevision(*cfg)
+	if err := controllerutil.SetControllerReference(cfg, revision, pcr.Scheme); err != nil {
+		return err
+	}
+	err := pcr.Create(ctx, revision)
+	switch {
+	case errors.IsAlreadyExists(err):
+		logger.Debug().Msgf("PaasConfigRevision %s already exists", revision.Name)
+	case err != nil:
+		return err
+	default:
+		logger.Info().Msgf("created PaasConfigRevision %s", revision.Name)
+	}
+	return pcr.pruneRevisions(ctx, cfg, revision.Name)
+}
+
+// pinnedRevisions returns the names of all PaasConfigRevisions which Paas'es are currently pinned to
+func (pcr *PaasConfigReconciler) pinnedRevisions(ctx context.Context) (map[string]bool, error) {
+	var paasList v1alpha2.PaasList
+	if err := pcr.List(ctx, &paasList); err != nil {
+		return nil, err
+	}
+	pinned := map[string]bool{}
+	now := time.Now()
+	for _, paas := range paasList.Items {
+		if name, _, err := paas.PinnedRevision(now); err == nil && name != "" {
+			pinned[name] = true
+		}
+	}
+	return pinned, nil
+}
+
+// pruneRevisions deletes the oldest PaasConfigRevisions of a PaasConfig which exceed the history limit. The current
+// revision and revisions which Paas'es are pinned to are never deleted.
+func (pcr *PaasConfigReconciler) pruneRevisions(
+	ctx context.Context,
+	cfg *v1alpha2.PaasConfig,
+	currentRevision string,
+) error {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerPaasConfigComponent)
+	limit := int(cfg.Spec.Revisions.HistoryLimit)
+	if limit <= 0 {
+		limit = defaultRevisionHistoryLimit
+	}
+	var revisions v1alpha2.PaasConfigRevisionList
+	if err := pcr.List(ctx, &revisions,
+		client.MatchingLabels{v1alpha2.PaasConfigRevisionLabelKey: cfg.Name}); err != nil {
+		return err
+	}
+	if len(revisions.Items) <= limit {
+		return nil
+	}
+	pinned, err := pcr.pinnedRevisions(ctx)
+	if err != nil {
+		return err
+	}
+	sortRevisionsNewestFirst(revisions.Items)
+	for _, revision := range revisions.Items[limit:] {
+		if revision.Name == currentRevision || pinned[revision.Name] {
+			continue
+		}
+		logger.Info().Msgf("pruning PaasConfigRevision %s", revision.Name)
+		if err = pcr.Delete(ctx, &revision); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortRevisionsNewestFirst sorts PaasConfigRevisions by creation time, newest first (and by name for equal times)
+func sortRevisionsNewestFirst(revisions []v1alpha2.PaasConfigRevision) {
+	slices.SortFunc(revisions, func(a, b v1alpha2.PaasConfigRevision) int {
+		if c := b.CreationTimestamp.Compare(a.CreationTimestamp.Time); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPaasConfigRevisions_sortRevisionsNewestFirst(t *testing.T) {
+	now := time.Now()
+	newRevision := func(name string, age time.Duration) v1alpha2.PaasConfigRevision {
+		return v1alpha2.PaasConfigRevision{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(now.Add(-age)),
+		}}
+	}
+	revisions := []v1alpha2.PaasConfigRevision{
+		newRevision("old", time.Hour),
+		newRevision("b-new", 0),
+		newRevision("middle", time.Minute),
+		newRevision("a-new", 0),
+	}
+	sortRevisionsNewestFirst(revisions)
+	var names []string
+	for _, revision := range revisions {
+		names = append(names, revision.Name)
+	}
+	assert.Equal(t, []string{"a-new", "b-new", "middle", "old"}, names)
+}
+
+var _ = Describe("PaasConfig revisions", Ordered, func() {
+	var (
+		reconciler *PaasConfigReconciler
+		paasConfig *v1alpha2.PaasConfig
+	)
+	ctx := context.Background()
+
+	listRevisions := func() []v1alpha2.PaasConfigRevision {
+		var revisions v1alpha2.PaasConfigRevisionList
+		Expect(k8sClient.List(ctx, &revisions,
+			client.MatchingLabels{v1alpha2.PaasConfigRevisionLabelKey: paasConfig.Name})).To(Succeed())
+		return revisions.Items
+	}
+
+	BeforeAll(func() {
+		reconciler = &PaasConfigReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		paasConfig = genericConfig.DeepCopy()
+		paasConfig.ObjectMeta = metav1.ObjectMeta{Name: "revisions-config"}
+		paasConfig.Spec.Revisions.HistoryLimit = 2
+		Expect(k8sClient.Create(ctx, paasConfig)).To(Succeed())
+	})
+
+	It("creates a revision for the active PaasConfig spec only once", func() {
+		Expect(reconciler.ensureRevision(ctx, paasConfig)).To(Succeed())
+		Expect(reconciler.ensureRevision(ctx, paasConfig)).To(Succeed())
+		revisions := listRevisions()
+		Expect(revisions).To(HaveLen(1))
+		Expect(revisions[0].Name).To(Equal(paasConfig.RevisionName()))
+		Expect(revisions[0].Spec.Config.QuotaLabel).To(Equal(paasConfig.Spec.QuotaLabel))
+		Expect(revisions[0].OwnerReferences).To(ContainElement(HaveField("Name", paasConfig.Name)))
+	})
+
+	It("prunes revisions exceeding the history limit", func() {
+		for _, quotaLabel := range []string{"second", "third", "fourth"} {
+			paasConfig.Spec.QuotaLabel = quotaLabel
+			Expect(reconciler.ensureRevision(ctx, paasConfig)).To(Succeed())
+		}
+		revisions := listRevisions()
+		Expect(revisions).To(HaveLen(2))
+		Expect(revisions).To(ContainElement(HaveField("Name", paasConfig.RevisionName())))
+	})
+})
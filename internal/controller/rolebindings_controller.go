@@ -9,10 +9,6 @@ package controller
 import (
 	"context"
 	"fmt"
-	"maps"
-	"reflect"
-
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v3/internal/config"
@@ -26,7 +22,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-// ensureRoleBinding ensures RoleBinding presence in given rolebinding.
+// ensureRoleBinding ensures RoleBinding presence and subjects, labels and annotations as desired, using server-side
+// apply. RoleBindings without subjects are removed.
 func ensureRoleBinding(
 	ctx context.Context,
 	r Reconciler,
@@ -37,65 +34,22 @@ func ensureRoleBinding(
 	if len(rb.Subjects) < 1 {
 		return finalizeRoleBinding(ctx, r, rb)
 	}
-	// See if rolebinding exists and create if it doesn't
-	found := &rbac.RoleBinding{}
-	err := r.Get(ctx, client.ObjectKeyFromObject(rb), found)
-	if err != nil && errors.IsNotFound(err) {
-		return createRoleBinding(ctx, r, rb)
-	} else if err != nil {
-		// Error that isn't due to the rolebinding not existing
-		logger.Err(err).Msg("error getting rolebinding")
-		return err
-	}
-	var changed bool
-	if !paas.AmIOwner(found.OwnerReferences) {
-		if err = controllerutil.SetControllerReference(paas, found, r.getScheme()); err != nil {
+	if !paas.AmIOwner(rb.OwnerReferences) {
+		if err := controllerutil.SetControllerReference(paas, rb, r.getScheme()); err != nil {
 			logger.Err(err).Msg("error setting rolebinding owner")
 			return err
 		}
-		changed = true
-	}
-	if !reflect.DeepEqual(found.Subjects, rb.Subjects) {
-		found.Subjects = rb.Subjects
-		changed = true
 	}
-	if changed {
-		logger.Info().
-			Str("Namespace", rb.Namespace).
-			Str("Name", rb.Name).
-			Str("roleRef", rb.RoleRef.Name).
-			Any("subject", rb.Subjects).
-			Msg("updating RoleBinding")
-		if err = r.Update(ctx, found); err != nil {
-			logger.Err(err).Msg("error updating rolebinding")
-			return err
-		}
-	}
-	return nil
-}
-
-func createRoleBinding(
-	ctx context.Context,
-	r Reconciler,
-	rb *rbac.RoleBinding,
-) error {
-	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerRoleBindingComponent)
-	// Create the rolebinding
-	logger.Info().
+	logger.Debug().
 		Str("Namespace", rb.Namespace).
 		Str("Name", rb.Name).
 		Str("roleRef", rb.RoleRef.Name).
 		Any("subject", rb.Subjects).
-		Msg("creating RoleBinding")
-	err := r.Create(ctx, rb)
-	if err != nil {
-		// Creating the rolebinding failed
-		logger.Err(err).Msg("error creating rolebinding")
+		Msg("applying RoleBinding")
+	if err := applyObject(ctx, r, rb); err != nil {
+		logger.Err(err).Msg("error applying rolebinding")
 		return err
 	}
-
-	// Creating the rolebinding was successful and return
-	logger.Info().Msg("created rolebinding")
 	return nil
 }
 
@@ -120,15 +74,15 @@ func backendRoleBinding(
 			})
 	}
 
-	labels := map[string]string{}
-	myConfig := config.GetConfig()
-	labelTemplater := templating.NewTemplater(*paas, myConfig)
-	for name, tpl := range myConfig.Spec.Templating.RoleBindingLabels {
-		result, err := labelTemplater.TemplateToMap(name, tpl)
-		if err != nil {
-			return nil, err
-		}
-		maps.Copy(labels, result)
+	myConfig := config.GetConfigForPaas(paas)
+	templater := templating.NewTemplater(*paas, myConfig)
+	labels, err := templateItemsToMap(templater, myConfig.Spec.Templating.RoleBindingLabels)
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := templateItemsToMap(templater, myConfig.Spec.Templating.RoleBindingAnnotations)
+	if err != nil {
+		return nil, err
 	}
 	rb := &rbac.RoleBinding{
 		TypeMeta: metav1.TypeMeta{
@@ -136,9 +90,10 @@ func backendRoleBinding(
 			APIVersion: "rbac.authorization.k8s.io/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      name.Name,
-			Namespace: name.Namespace,
-			Labels:    labels,
+			Name:        name.Name,
+			Namespace:   name.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
 		},
 		Subjects: subjects,
 		RoleRef: rbac.RoleRef{
@@ -148,7 +103,7 @@ func backendRoleBinding(
 		},
 	}
 	logger.Info().Msg("setting Owner")
-	if err := controllerutil.SetControllerReference(paas, rb, r.getScheme()); err != nil {
+	if err = controllerutil.SetControllerReference(paas, rb, r.getScheme()); err != nil {
 		return rb, err
 	}
 
@@ -218,7 +173,7 @@ func (r *PaasReconciler) reconcileNamespaceRolebindings(
 	// Use a map of sets to avoid duplicates
 	roleGroups := map[string]map[string]struct{}{}
 
-	for _, roleList := range config.GetConfig().Spec.RoleMappings {
+	for _, roleList := range config.GetConfigForPaas(paas).Spec.RoleMappings {
 		for _, role := range roleList {
 			roleGroups[role] = map[string]struct{}{}
 		}
@@ -233,7 +188,7 @@ func (r *PaasReconciler) reconcileNamespaceRolebindings(
 		logger.Info().Msgf("defining Rolebindings for Group %s", groupKey)
 		// Convert the groupKey to a groupName to map the rolebinding subjects to a group
 		groupName := paas.GroupKey2GroupName(groupKey)
-		for _, mappedRole := range config.GetConfig().Spec.RoleMappings.Roles(groupRoles) {
+		for _, mappedRole := range config.GetConfigForPaas(paas).Spec.RoleMappings.Roles(groupRoles) {
 			if _, exists := roleGroups[mappedRole]; !exists {
 				roleGroups[mappedRole] = map[string]struct{}{}
 			}
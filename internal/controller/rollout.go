@@ -0,0 +1,222 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/config"
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ShardOptions can be used to run multiple replicas of the operator, where every replica only reconciles the Paas'es
+// with a name that hashes into its shard.
+type ShardOptions struct {
+	// Count is the total number of shards. Sharding is disabled when Count is lower than 2.
+	Count int
+	// Index is the shard (0 <= Index < Count) handled by this replica
+	Index int
+}
+
+// Enabled returns true when sharding is enabled
+func (s ShardOptions) Enabled() bool {
+	return s.Count > 1
+}
+
+// Owns returns true when the Paas with this name should be reconciled by this shard
+func (s ShardOptions) Owns(paasName string) bool {
+	if !s.Enabled() {
+		return true
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(paasName))
+	return int(h.Sum32()%uint32(s.Count)) == s.Index
+}
+
+// RolloutOptions defines how changes of the active PaasConfig are rolled out to all Paas'es
+type RolloutOptions struct {
+	// Rate is the number of Paas'es per second which are enqueued for reconciliation after a PaasConfig change.
+	// All Paas'es are enqueued at once when Rate is 0 (or lower).
+	Rate float64
+	// CanarySelector selects the Paas'es which are reconciled before all other Paas'es
+	CanarySelector labels.Selector
+}
+
+// delay returns the delay for enqueuing the n-th Paas of a rollout
+func (o RolloutOptions) delay(n int) time.Duration {
+	if o.Rate <= 0 {
+		return 0
+	}
+	return time.Duration(float64(n) / o.Rate * float64(time.Second))
+}
+
+// rolloutOrder returns the Paas'es in the order in which a PaasConfig change should be rolled out: canary Paas'es
+// first, and within both groups ordered by name. Paas'es which are not owned by this shard are left out.
+func rolloutOrder(paases []v1alpha2.Paas, options RolloutOptions, shard ShardOptions) []types.NamespacedName {
+	isCanary := func(paas v1alpha2.Paas) bool {
+		return options.CanarySelector != nil && !options.CanarySelector.Empty() &&
+			options.CanarySelector.Matches(labels.Set(paas.Labels))
+	}
+	var filtered []v1alpha2.Paas
+	for _, paas := range paases {
+		if shard.Owns(paas.Name) {
+			filtered = append(filtered, paas)
+		}
+	}
+	slices.SortFunc(filtered, func(a, b v1alpha2.Paas) int {
+		if aCanary, bCanary := isCanary(a), isCanary(b); aCanary != bCanary {
+			if aCanary {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	names := make([]types.NamespacedName, 0, len(filtered))
+	for _, paas := range filtered {
+		names = append(names, types.NamespacedName{Name: paas.Name})
+	}
+	return names
+}
+
+// paasConfigRolloutHandler enqueues all Paas'es when the active PaasConfig is changed, canary Paas'es first and
+// spread in time as configured in the RolloutOptions.
+type paasConfigRolloutHandler struct {
+	reader  client.Reader
+	options RolloutOptions
+	shard   ShardOptions
+}
+
+var _ handler.EventHandler = &paasConfigRolloutHandler{}
+
+func (h *paasConfigRolloutHandler) enqueueAll(
+	ctx context.Context,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	_, logger := logging.GetLogComponent(ctx, logging.ControllerPaasComponent)
+	var paasList v1alpha2.PaasList
+	if err := h.reader.List(ctx, &paasList); err != nil {
+		logger.Error().AnErr("error", err).Msg("unable to list paases")
+		return
+	}
+	order := rolloutOrder(paasList.Items, h.options, h.shard)
+	logger.Info().Msgf("rolling out PaasConfig change to %d Paas'es", len(order))
+	for i, name := range order {
+		req := reconcile.Request{NamespacedName: name}
+		if delay := h.options.delay(i); delay > 0 {
+			q.AddAfter(req, delay)
+		} else {
+			q.Add(req)
+		}
+	}
+}
+
+// Create implements handler.EventHandler
+func (h *paasConfigRolloutHandler) Create(
+	ctx context.Context,
+	_ event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	h.enqueueAll(ctx, q)
+}
+
+// Update implements handler.EventHandler
+func (h *paasConfigRolloutHandler) Update(
+	ctx context.Context,
+	_ event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	h.enqueueAll(ctx, q)
+}
+
+// Delete implements handler.EventHandler
+func (h *paasConfigRolloutHandler) Delete(
+	ctx context.Context,
+	_ event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	h.enqueueAll(ctx, q)
+}
+
+// Generic implements handler.EventHandler
+func (h *paasConfigRolloutHandler) Generic(
+	ctx context.Context,
+	_ event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	h.enqueueAll(ctx, q)
+}
+
+// enqueueCanaries enqueues all Paas'es owned by this shard which are selected as canary by a candidate PaasConfig
+func enqueueCanaries(
+	ctx context.Context,
+	reader client.Reader,
+	shard ShardOptions,
+	candidate *v1alpha2.PaasConfig,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	_, logger := logging.GetLogComponent(ctx, logging.ControllerPaasComponent)
+	selector, err := metav1.LabelSelectorAsSelector(&candidate.Spec.Candidate.CanarySelector)
+	if err != nil {
+		logger.Error().AnErr("error", err).Msg("invalid canary selector in candidate PaasConfig")
+		return
+	}
+	var paasList v1alpha2.PaasList
+	if err = reader.List(ctx, &paasList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		logger.Error().AnErr("error", err).Msg("unable to list canary paases")
+		return
+	}
+	for _, name := range rolloutOrder(paasList.Items, RolloutOptions{}, shard) {
+		q.Add(reconcile.Request{NamespacedName: name})
+	}
+}
+
+// candidateRolloutHandler returns an event handler which enqueues the canary Paas'es of a candidate PaasConfig when
+// it starts or stops soaking. The candidate is updated in the config store first, so that the canary Paas'es are
+// reconciled with the right PaasConfig, regardless of the order in which event handlers are called.
+func candidateRolloutHandler(reader client.Reader, shard ShardOptions) handler.EventHandler {
+	return handler.Funcs{
+		UpdateFunc: func(
+			ctx context.Context,
+			e event.UpdateEvent,
+			q workqueue.TypedRateLimitingInterface[reconcile.Request],
+		) {
+			if candidate, ok := e.ObjectNew.(*v1alpha2.PaasConfig); ok && candidate.IsCandidate() {
+				config.UpdateCandidateConfig(candidate)
+				enqueueCanaries(ctx, reader, shard, candidate, q)
+			}
+			if candidate, ok := e.ObjectOld.(*v1alpha2.PaasConfig); ok && candidate.IsCandidate() {
+				// The canary selector might have changed
+				enqueueCanaries(ctx, reader, shard, candidate, q)
+			}
+		},
+		DeleteFunc: func(
+			ctx context.Context,
+			e event.DeleteEvent,
+			q workqueue.TypedRateLimitingInterface[reconcile.Request],
+		) {
+			if candidate, ok := e.Object.(*v1alpha2.PaasConfig); ok && candidate.IsCandidate() {
+				config.ClearCandidateConfig(candidate.Name)
+				enqueueCanaries(ctx, reader, shard, candidate, q)
+			}
+		},
+	}
+}
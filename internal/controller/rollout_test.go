@@ -0,0 +1,79 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestRollout_ShardOwns(t *testing.T) {
+	assert.True(t, ShardOptions{}.Owns("any-paas"), "all paases should be owned when sharding is disabled")
+	assert.True(t, ShardOptions{Count: 1}.Owns("any-paas"), "all paases should be owned with a single shard")
+
+	shards := []ShardOptions{{Count: 3, Index: 0}, {Count: 3, Index: 1}, {Count: 3, Index: 2}}
+	for i := range 100 {
+		name := fmt.Sprintf("paas-%d", i)
+		owners := 0
+		for _, shard := range shards {
+			if shard.Owns(name) {
+				owners++
+			}
+		}
+		assert.Equal(t, 1, owners, "paas %s should be owned by exactly one shard", name)
+	}
+}
+
+func TestRollout_Delay(t *testing.T) {
+	assert.Equal(t, time.Duration(0), RolloutOptions{}.delay(10))
+	assert.Equal(t, time.Duration(0), RolloutOptions{Rate: 2}.delay(0))
+	assert.Equal(t, 5*time.Second, RolloutOptions{Rate: 2}.delay(10))
+	assert.Equal(t, 20*time.Second, RolloutOptions{Rate: 0.5}.delay(10))
+}
+
+func TestRollout_Order(t *testing.T) {
+	newPaas := func(name string, lbls map[string]string) v1alpha2.Paas {
+		return v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: lbls}}
+	}
+	canary := map[string]string{"canary": "true"}
+	paases := []v1alpha2.Paas{
+		newPaas("c", nil),
+		newPaas("b", canary),
+		newPaas("a", nil),
+		newPaas("d", canary),
+	}
+	names := func(reqs []types.NamespacedName) (result []string) {
+		for _, req := range reqs {
+			result = append(result, req.Name)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"a", "b", "c", "d"}, names(rolloutOrder(paases, RolloutOptions{}, ShardOptions{})),
+		"without canary selector, paases should be ordered by name")
+	assert.Equal(t, []string{"a", "b", "c", "d"},
+		names(rolloutOrder(paases, RolloutOptions{CanarySelector: labels.Everything()}, ShardOptions{})),
+		"an empty canary selector should not select any canaries")
+
+	selector, err := labels.Parse("canary=true")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "d", "a", "c"},
+		names(rolloutOrder(paases, RolloutOptions{CanarySelector: selector}, ShardOptions{})),
+		"canary paases should be rolled out first")
+
+	shard := ShardOptions{Count: 2, Index: 0}
+	for _, name := range rolloutOrder(paases, RolloutOptions{CanarySelector: selector}, shard) {
+		assert.True(t, shard.Owns(name.Name), "paas %s is not owned by this shard", name.Name)
+	}
+}
@@ -11,36 +11,27 @@ import (
 	"crypto/sha512"
 	"encoding/hex"
 	"fmt"
+	"maps"
 	"strings"
 
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/config"
 	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	"github.com/belastingdienst/opr-paas/v3/internal/templating"
 
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-// ensureSecret ensures Secret presence in given secret.
+// ensureSecret ensures Secret presence and data, labels and annotations as desired, using server-side apply.
 func (r *PaasReconciler) ensureSecret(
 	ctx context.Context,
 	secret *corev1.Secret,
 ) error {
-	// See if secret exists and create if it doesn't
-	found := &corev1.Secret{}
-	err := r.Get(ctx, client.ObjectKeyFromObject(secret), found)
-	if err != nil && errors.IsNotFound(err) {
-		// Create the secret
-		return r.Create(ctx, secret)
-	} else if err != nil {
-		// Error that isn't due to the secret not existing
-		return err
-	}
-
-	return r.Update(ctx, secret)
+	return applyObject(ctx, r, secret)
 }
 
 func hashData(original string) string {
@@ -89,12 +80,23 @@ func (r *PaasReconciler) backendSecret(
 		s.Labels = paasns.ClonedLabels()
 	}
 
+	myConfig := config.GetConfigForPaas(paas)
+	templater := templating.NewTemplater(*paas, myConfig).WithNamespace(namespacedName.Namespace, "")
+	labels, err := templateItemsToMap(templater, myConfig.Spec.Templating.SecretLabels)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(s.Labels, labels)
+	if s.Annotations, err = templateItemsToMap(templater, myConfig.Spec.Templating.SecretAnnotations); err != nil {
+		return nil, err
+	}
+
 	s.Labels["argocd.argoproj.io/secret-type"] = "repo-creds"
 	s.Labels[ManagedByLabelKey] = paas.Name
 
 	logger.Info().Msg("setting Owner")
 
-	err := controllerutil.SetControllerReference(paas, s, r.Scheme)
+	err = controllerutil.SetControllerReference(paas, s, r.Scheme)
 	if err != nil {
 		return s, err
 	}
@@ -38,17 +38,25 @@ const (
 
 	// ConfigComponent represents a logging component used by the config cache
 	ConfigComponent Component = iota
+	// AccessReportComponent represents a logging component used by the access report
+	AccessReportComponent Component = iota
 
+	// ControllerAuditComponent represents a logging component used by the audit controller
+	ControllerAuditComponent Component = iota
 	// ControllerCapabilitiesComponent represents a logging component used by the capabilities controller
 	ControllerCapabilitiesComponent Component = iota
 	// ControllerClusterQuotaComponent represents a logging component used by the cluster quota controller
 	ControllerClusterQuotaComponent Component = iota
 	// ControllerClusterRoleBindingsComponent represents a logging component used by the cluster rolebindings controller
 	ControllerClusterRoleBindingsComponent Component = iota
+	// ControllerExtraResourceComponent represents a logging component used by the extra resource controller
+	ControllerExtraResourceComponent Component = iota
 	// ControllerGroupComponent represents a logging component used by the group controller
 	ControllerGroupComponent Component = iota
 	// ControllerNamespaceComponent represents a logging component used by the namespace controller
 	ControllerNamespaceComponent Component = iota
+	// ControllerNetworkPolicyComponent represents a logging component used by the network policy controller
+	ControllerNetworkPolicyComponent Component = iota
 	// ControllerPaasComponent represents a logging component used by the paas controller
 	ControllerPaasComponent Component = iota
 	// ControllerPaasConfigComponent represents a logging component used by the paasConfig controller
@@ -78,11 +86,14 @@ var (
 		"paasns_webhook_v2":     WebhookPaasNSComponentV2,
 		"utils_webhook_v2":      WebhookUtilsComponentV2,
 
+		"audit_controller":                ControllerAuditComponent,
 		"capabilities_controller":         ControllerCapabilitiesComponent,
 		"cluster_quota_controller":        ControllerClusterQuotaComponent,
 		"cluster_role_binding_controller": ControllerClusterRoleBindingsComponent,
+		"extra_resource_controller":       ControllerExtraResourceComponent,
 		"group_controller":                ControllerGroupComponent,
 		"namespace_controller":            ControllerNamespaceComponent,
+		"network_policy_controller":       ControllerNetworkPolicyComponent,
 		"paas_controller":                 ControllerPaasComponent,
 		"paas_config_controller":          ControllerPaasConfigComponent,
 		"rolebinding_controller":          ControllerRoleBindingComponent,
@@ -90,6 +101,7 @@ var (
 
 		"plugin_generator": PluginGeneratorComponent,
 		"config_watcher":   ConfigComponent,
+		"access_report":    AccessReportComponent,
 
 		"undefined_component": UnknownComponent,
 		"unittest_component":  TestComponent,
@@ -2,10 +2,14 @@ package templating
 
 import (
 	"bytes"
+	"fmt"
+	"strings"
 	"text/template"
 
 	"github.com/go-sprout/sprout"
 	"github.com/go-sprout/sprout/group/all"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8syaml "sigs.k8s.io/yaml"
 
 	"github.com/belastingdienst/opr-paas/v3/api"
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha1"
@@ -17,20 +21,40 @@ type PaasUnion interface {
 	v1alpha1.Paas | v1alpha2.Paas
 }
 
+// TemplateNamespace holds information on the namespace a template is rendered for
+type TemplateNamespace struct {
+	// Name of the namespace
+	Name string
+	// Capability is the name of the capability when the namespace is a capability namespace, and empty otherwise
+	Capability string
+}
+
 // Templater is a struct that can hold a Paas and a PaasConfig and can run go-templates using these as input
 type Templater[P PaasUnion, C api.PaasConfig[S], S any] struct {
-	Paas   P
-	Config C
+	Paas      P
+	Config    C
+	Namespace TemplateNamespace
 }
 
-// NewTemplater returns an initialized Templater from a Paas and PaasConfig
+// NewTemplater returns an initialized Templater from a Paas and PaasConfig. For a v1alpha2 Paas the deprecated
+// requestor and managedByPaas fields are also available as the annotations which replace them in v1alpha3.
 func NewTemplater[P PaasUnion, C api.PaasConfig[S], S any](paas P, config C) Templater[P, C, S] {
+	if v2Paas, ok := any(paas).(v1alpha2.Paas); ok {
+		paas, _ = any(v2Paas.WithHubAnnotations()).(P)
+	}
 	return Templater[P, C, S]{
 		Paas:   paas,
 		Config: config,
 	}
 }
 
+// WithNamespace returns a copy of the Templater which can be used to render templates for a specific namespace.
+// The namespace info can be used in templates as `.Namespace.Name` and `.Namespace.Capability`.
+func (t Templater[P, C, S]) WithNamespace(name string, capability string) Templater[P, C, S] {
+	t.Namespace = TemplateNamespace{Name: name, Capability: capability}
+	return t
+}
+
 func (t Templater[P, C, S]) getSproutFuncs() (template.FuncMap, error) {
 	handler := sprout.New()
 	err := handler.AddGroups(all.RegistryGroup())
@@ -40,6 +64,15 @@ func (t Templater[P, C, S]) getSproutFuncs() (template.FuncMap, error) {
 	return handler.Build(), nil
 }
 
+// templateData returns the data templates are executed with. A v1alpha2 Paas is presented as a paasView.
+func (t Templater[P, C, S]) templateData() templateData[C] {
+	data := templateData[C]{Paas: t.Paas, Config: t.Config, Namespace: t.Namespace}
+	if v2Paas, ok := any(t.Paas).(v1alpha2.Paas); ok {
+		data.Paas = newPaasView(v2Paas)
+	}
+	return data
+}
+
 // Verify can verify a template (just parsing it, not running it against a Paas / PaasConfig)
 func (t Templater[P, C, S]) Verify(name string, templatedText string) error {
 	funcs, err := t.getSproutFuncs()
@@ -61,7 +94,7 @@ func (t Templater[P, C, S]) TemplateToString(name string, templatedText string)
 	if err != nil {
 		return "", err
 	}
-	err = tmpl.Execute(buf, t)
+	err = tmpl.Execute(buf, t.templateData())
 	if err != nil {
 		return "", err
 	}
@@ -87,3 +120,26 @@ func (t Templater[P, C, S]) TemplateToMap(name string, templatedText string) (re
 	}
 	return TemplateResult{name: yamlData}, nil
 }
+
+// TemplateToUnstructured can be used to parse a go-template rendering a Kubernetes manifest (as yaml).
+// It returns nil (without an error) when the template renders an empty string.
+func (t Templater[P, C, S]) TemplateToUnstructured(
+	name string,
+	templatedText string,
+) (*unstructured.Unstructured, error) {
+	yamlData, err := t.TemplateToString(name, templatedText)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(yamlData) == "" {
+		return nil, nil
+	}
+	obj := &unstructured.Unstructured{}
+	if err = k8syaml.Unmarshal([]byte(yamlData), &obj.Object); err != nil {
+		return nil, fmt.Errorf("template %s does not render valid yaml: %w", name, err)
+	}
+	if obj.GetAPIVersion() == "" || obj.GetKind() == "" || obj.GetName() == "" {
+		return nil, fmt.Errorf("template %s should render a manifest with apiVersion, kind and metadata.name", name)
+	}
+	return obj, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v3/internal/templating"
 	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -88,6 +89,8 @@ func TestValidTemplateToString(t *testing.T) {
 	}{
 		{template: "{{ .Paas.Name }}", expected: paasName},
 		{template: "{{ .Config.Name }}", expected: paasConfigName},
+		{template: `{{ index .Paas.Annotations "cpet.belastingdienst.nl/requestor" }}`, expected: capName},
+		{template: `{{ index .Paas.Annotations "cpet.belastingdienst.nl/managed-by-paas" }}`, expected: ""},
 	} {
 		tpl := templating.NewTemplater(paas, paasConfig)
 		templated, err := tpl.TemplateToString(fmt.Sprintf("%d", i), test.template)
@@ -96,6 +99,60 @@ func TestValidTemplateToString(t *testing.T) {
 	}
 }
 
+func TestNewTemplaterKeepsPaasUnchanged(t *testing.T) {
+	templating.NewTemplater(paas, paasConfig)
+	assert.Nil(t, paas.Annotations)
+}
+
+func TestTemplateWithNamespace(t *testing.T) {
+	tpl := templating.NewTemplater(paas, paasConfig)
+	templated, err := tpl.TemplateToString("no-namespace", "{{ .Namespace.Name }}")
+	assert.NoError(t, err)
+	assert.Empty(t, templated)
+
+	nsTpl := tpl.WithNamespace("my-ns", capName)
+	templated, err = nsTpl.TemplateToString("namespace", "{{ .Namespace.Name }}/{{ .Namespace.Capability }}")
+	assert.NoError(t, err)
+	assert.Equal(t, "my-ns/"+capName, templated)
+
+	// WithNamespace returns a copy and leaves the original templater untouched
+	assert.Empty(t, tpl.Namespace.Name)
+}
+
+func TestTemplateCustomFields(t *testing.T) {
+	cfPaas := *paas.DeepCopy()
+	cfPaas.Spec.Capabilities[capName] = v1alpha2.PaasCapability{CustomFields: v1alpha2.PaasCustomFields{
+		customField1Key: v1alpha2.NewCustomFieldValue("main"),
+		"replicas":      v1alpha2.CustomFieldValue{JSON: apiextensionsv1.JSON{Raw: []byte(`3`)}},
+		"repos": v1alpha2.CustomFieldValue{JSON: apiextensionsv1.JSON{
+			Raw: []byte(`[{"url": "ssh://git@scm/app1.git"}]`),
+		}},
+	}}
+	const cfPath = ".Paas.Spec.Capabilities." + capName + ".CustomFields."
+	for _, test := range []struct {
+		template string
+		expected string
+	}{
+		// String custom fields can be used as strings, as before custom fields could hold other values
+		{template: "{{ " + cfPath + customField1Key + " }}", expected: "main"},
+		{template: "{{ eq " + cfPath + customField1Key + ` "main" }}`, expected: "true"},
+		{template: "{{ " + cfPath + customField1Key + " | toUpper }}", expected: "MAIN"},
+		{template: `{{ hasPrefix "ma" ` + cfPath + customField1Key + " }}", expected: "true"},
+		// Other values are JSON decoded
+		{template: "{{ " + cfPath + "replicas }}", expected: "3"},
+		{template: "{{ (index " + cfPath + "repos 0).url }}", expected: "ssh://git@scm/app1.git"},
+		{template: "{{ " + cfPath + "repos | toJson }}", expected: `[{"url":"ssh://git@scm/app1.git"}]`},
+		// All other fields of the Paas remain available
+		{template: "{{ .Paas.Name }}/{{ .Paas.Spec.Requestor }}/{{ len .Paas.Spec.Groups }}", expected: paasName +
+			"/" + capName + "/2"},
+	} {
+		tpl := templating.NewTemplater(cfPaas, paasConfig)
+		templated, err := tpl.TemplateToString("customfields", test.template)
+		assert.NoError(t, err, test.template)
+		assert.Equal(t, test.expected, templated, test.template)
+	}
+}
+
 func TestInValidTemplateToString(t *testing.T) {
 	tpl := templating.NewTemplater(paas, paasConfig)
 	templated, err := tpl.TemplateToString("invalid", "{{ .NotAPaas.Name }")
@@ -152,3 +209,29 @@ func TestInValidTemplateToMap(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, templated)
 }
+
+func TestTemplateToUnstructured(t *testing.T) {
+	tpl := templating.NewTemplater(paas, paasConfig).WithNamespace("my-ns", capName)
+	obj, err := tpl.TemplateToUnstructured("cm", `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Paas.Name }}
+data:
+  capability: {{ .Namespace.Capability }}
+`)
+	assert.NoError(t, err)
+	assert.NotNil(t, obj)
+	assert.Equal(t, "ConfigMap", obj.GetKind())
+	assert.Equal(t, paasName, obj.GetName())
+	assert.Equal(t, map[string]any{"capability": capName}, obj.Object["data"])
+
+	obj, err = tpl.TemplateToUnstructured("empty", `{{ if eq .Namespace.Capability "other" }}kind: ConfigMap{{ end }}`)
+	assert.NoError(t, err)
+	assert.Nil(t, obj)
+
+	_, err = tpl.TemplateToUnstructured("incomplete", "kind: ConfigMap")
+	assert.ErrorContains(t, err, "should render a manifest with apiVersion, kind and metadata.name")
+
+	_, err = tpl.TemplateToUnstructured("invalid", "- just\n- a list")
+	assert.ErrorContains(t, err, "does not render valid yaml")
+}
@@ -0,0 +1,57 @@
+package templating
+
+import (
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+)
+
+// templateData is the data go-templates are executed with
+type templateData[C any] struct {
+	Paas      any
+	Config    C
+	Namespace TemplateNamespace
+}
+
+// paasView is how a v1alpha2 Paas is presented to go-templates: the Paas, of which the custom fields of the
+// capabilities hold their JSON decoded values. String custom fields are therefore strings in templates (e.g. for `eq`
+// and sprout string functions), like they were before custom fields could hold other values.
+type paasView struct {
+	v1alpha2.Paas
+	Spec paasSpecView
+}
+
+// paasSpecView is the spec of a paasView
+type paasSpecView struct {
+	v1alpha2.PaasSpec
+	Capabilities map[string]paasCapabilityView
+}
+
+// paasCapabilityView is a capability of a paasView
+type paasCapabilityView struct {
+	v1alpha2.PaasCapability
+	CustomFields map[string]any
+}
+
+// newPaasView returns the paasView of a v1alpha2 Paas
+func newPaasView(paas v1alpha2.Paas) paasView {
+	view := paasView{Paas: paas, Spec: paasSpecView{PaasSpec: paas.Spec}}
+	if paas.Spec.Capabilities == nil {
+		return view
+	}
+	view.Spec.Capabilities = make(map[string]paasCapabilityView, len(paas.Spec.Capabilities))
+	for name, capability := range paas.Spec.Capabilities {
+		capView := paasCapabilityView{PaasCapability: capability}
+		if capability.CustomFields != nil {
+			capView.CustomFields = make(map[string]any, len(capability.CustomFields))
+		}
+		for key, customField := range capability.CustomFields {
+			value, err := customField.Value()
+			if err != nil {
+				// Invalid JSON is rejected by the API server, but is rendered as is rather than failing the template
+				value = customField.String()
+			}
+			capView.CustomFields[key] = value
+		}
+		view.Spec.Capabilities[name] = capView
+	}
+	return view
+}
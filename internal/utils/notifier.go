@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"log"
+	"sync/atomic"
 
 	"github.com/fsnotify/fsnotify"
 )
@@ -11,26 +12,44 @@ import (
 type FileWatcher struct {
 	watcher   *fsnotify.Watcher
 	files     []string
-	count     int
-	lastCount int
+	onChange  func()
+	count     atomic.Int64
+	lastCount atomic.Int64
 }
 
 // NewFileWatcher creates a FileWatcher resource and runs the watch method in a separate thread
 func NewFileWatcher(paths ...string) *FileWatcher {
+	return newFileWatcher(nil, paths)
+}
+
+// NewFileWatcherWithCallback creates a FileWatcher which calls onChange (from the watch thread) for every change
+// which is noticed, so that changes can be processed without polling WasTriggered
+func NewFileWatcherWithCallback(onChange func(), paths ...string) *FileWatcher {
+	return newFileWatcher(onChange, paths)
+}
+
+func newFileWatcher(onChange func(), paths []string) *FileWatcher {
 	fw := &FileWatcher{
-		files: paths,
-		count: 0,
+		files:    paths,
+		onChange: onChange,
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("ERROR: issue %s while creating a watcher for these files: %v", err, paths)
+		return fw
+	}
+	fw.watcher = watcher
+	go fw.watchLoop()
+	if err = fw.Refresh(); err != nil {
+		log.Printf("ERROR: %s", err)
 	}
-	go func() {
-		_ = fw.watch()
-	}()
 	return fw
 }
 
 // WasTriggered is true when a filechange was noticed
 func (fw *FileWatcher) WasTriggered() bool {
-	if fw.lastCount != fw.count {
-		fw.lastCount = fw.count
+	count := fw.count.Load()
+	if fw.lastCount.Swap(count) != count {
 		// kubernetes removes and creates a file when a mounted secret or configmap is changed
 		// refresh will re-add the newly created files after they have been changed
 		_ = fw.Refresh()
@@ -46,6 +65,9 @@ func (fw *FileWatcher) Refresh() (err error) {
 	// - A path can only be watched once; watching it more than once is a no-op and will not return an error.
 	// - Paths that do not yet exist on the filesystem cannot be watched.
 	// - A watch will be automatically removed if the watched path is deleted or renamed. T
+	if fw.watcher == nil {
+		return fmt.Errorf("no watcher for these files: %v", fw.files)
+	}
 	for _, p := range fw.files {
 		err = fw.watcher.Add(p)
 		if err != nil {
@@ -55,22 +77,6 @@ func (fw *FileWatcher) Refresh() (err error) {
 	return nil
 }
 
-func (fw *FileWatcher) watch() (err error) {
-	fw.watcher, err = fsnotify.NewWatcher()
-	if err != nil {
-		return fmt.Errorf("issue %w while creating a watcher for these files: %v", err, fw.files)
-	}
-	defer fw.watcher.Close()
-
-	go fw.watchLoop()
-	if err = fw.Refresh(); err != nil {
-		return err
-	}
-
-	<-make(chan struct{}) // Block forever
-	return nil
-}
-
 // watchLoop is the inner function which loops until a change is noticed and then runs callBack func
 func (fw *FileWatcher) watchLoop() {
 	for {
@@ -87,8 +93,12 @@ func (fw *FileWatcher) watchLoop() {
 
 			// Just print the event nicely aligned, and keep track how many
 			// events we've seen.
-			fw.count++
-			log.Printf("secret notification: %3d/%3d %s", fw.count, fw.lastCount, e)
+			count := fw.count.Add(1)
+			log.Printf("secret notification: %3d/%3d %s", count, fw.lastCount.Load(), e)
+			if fw.onChange != nil {
+				_ = fw.Refresh()
+				fw.onChange()
+			}
 		}
 	}
 }
@@ -116,3 +116,27 @@ func Test_LinkChanged(t *testing.T) {
 	time.Sleep(timeout)
 	require.True(t, fw.WasTriggered(), "fileWatcher was triggered after recreating symlink")
 }
+
+func Test_FileChangedCallback(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "notifierCallbackTest")
+	if err != nil {
+		panic(fmt.Errorf("unable to create temp dir: %w", err))
+	}
+	filePath := filepath.Join(tmpDir, "extra")
+	writeFile(t, filePath, "initial file data")
+
+	changed := make(chan struct{}, 1)
+	NewFileWatcherWithCallback(func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}, filePath)
+
+	writeFile(t, filePath, "other file data")
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		require.Fail(t, "callback was not called after writing to file")
+	}
+}
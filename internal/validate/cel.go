@@ -0,0 +1,154 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/cel/library"
+)
+
+const (
+	// maxCachedRules limits the number of compiled rules which are kept in memory. The cache is cleared when it is
+	// full, which only happens when rules are changed very often.
+	maxCachedRules = 1024
+	// ruleCostLimit limits the cost of evaluating a single rule, so that a rule cannot stall the webhooks
+	ruleCostLimit = 1000000
+)
+
+// The variables which are available in CEL rules
+const (
+	// RuleVarObject is the object (Paas or PaasNS) which is validated
+	RuleVarObject = "object"
+	// RuleVarPaas is the Paas, which is the object itself for a Paas, or the Paas the PaasNS belongs to for a PaasNS
+	RuleVarPaas = "paas"
+	// RuleVarPaasConfig is the PaasConfig which applies to the Paas
+	RuleVarPaasConfig = "paasConfig"
+)
+
+var (
+	ruleCacheMutex sync.Mutex
+	ruleCache      = map[string]*Rule{}
+	ruleEnv        *cel.Env
+	ruleEnvErr     error
+	ruleEnvOnce    sync.Once
+)
+
+// Rule is a compiled CEL expression which evaluates to a boolean
+type Rule struct {
+	program cel.Program
+}
+
+// getRuleEnv returns the CEL environment rules are compiled in. On top of the standard CEL functions, the
+// environment has the string extensions and the Kubernetes libraries (e.g. quantity() and isSorted()) which are also
+// available in CustomResourceDefinition validation rules.
+func getRuleEnv() (*cel.Env, error) {
+	ruleEnvOnce.Do(func() {
+		ruleEnv, ruleEnvErr = cel.NewEnv(
+			cel.Variable(RuleVarObject, cel.DynType),
+			cel.Variable(RuleVarPaas, cel.DynType),
+			cel.Variable(RuleVarPaasConfig, cel.DynType),
+			cel.OptionalTypes(),
+			cel.HomogeneousAggregateLiterals(),
+			ext.Strings(),
+			library.Lists(),
+			library.Regex(),
+			library.URLs(),
+			library.Quantity(),
+		)
+	})
+	return ruleEnv, ruleEnvErr
+}
+
+// CompileRule compiles a CEL expression which should evaluate to a boolean. Compiled rules are cached, so that a
+// rule is only compiled once.
+func CompileRule(expression string) (*Rule, error) {
+	ruleCacheMutex.Lock()
+	defer ruleCacheMutex.Unlock()
+	if rule, exists := ruleCache[expression]; exists {
+		return rule, nil
+	}
+
+	env, err := getRuleEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid rule: %w", issues.Err())
+	}
+	if outputType := ast.OutputType(); outputType != cel.BoolType && outputType != cel.DynType {
+		return nil, fmt.Errorf("invalid rule: should evaluate to a bool, not %s", outputType)
+	}
+	program, err := env.Program(ast, cel.CostLimit(ruleCostLimit))
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule: %w", err)
+	}
+	rule := &Rule{program: program}
+
+	if len(ruleCache) >= maxCachedRules {
+		clear(ruleCache)
+	}
+	ruleCache[expression] = rule
+	return rule, nil
+}
+
+// RuleVars returns the variables to evaluate rules against for (pointers to) an object, Paas and PaasConfig. These are
+// converted to their unstructured form, so that rules reference fields by their json names. Fields which are not set
+// (null) are left out, so that rules can use has() to check if a field is set.
+func RuleVars(obj, paas, paasConfig any) (map[string]any, error) {
+	vars := map[string]any{}
+	for name, value := range map[string]any{
+		RuleVarObject:     obj,
+		RuleVarPaas:       paas,
+		RuleVarPaasConfig: paasConfig,
+	} {
+		unstructured, err := runtime.DefaultUnstructuredConverter.ToUnstructured(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %w", name, err)
+		}
+		vars[name] = pruneNulls(unstructured)
+	}
+	return vars, nil
+}
+
+// pruneNulls removes all null values from (nested maps and lists in) an unstructured object
+func pruneNulls(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, child := range typed {
+			if child == nil {
+				delete(typed, key)
+			} else {
+				typed[key] = pruneNulls(child)
+			}
+		}
+	case []any:
+		for i, child := range typed {
+			typed[i] = pruneNulls(child)
+		}
+	}
+	return value
+}
+
+// Evaluate evaluates the rule against the variables returned by RuleVars, and returns the outcome
+func (r *Rule) Evaluate(vars map[string]any) (bool, error) {
+	out, _, err := r.program.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, errors.New("rule did not evaluate to a bool")
+	}
+	return result, nil
+}
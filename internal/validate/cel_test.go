@@ -0,0 +1,70 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCompileRule_cached(t *testing.T) {
+	rule, err := CompileRule(`object.metadata.name != ""`)
+	require.NoError(t, err)
+	cached, err := CompileRule(`object.metadata.name != ""`)
+	require.NoError(t, err)
+	assert.Same(t, rule, cached, "compiled rules should be cached")
+}
+
+func TestCompileRule_invalid(t *testing.T) {
+	_, err := CompileRule(`object.metadata.name ==`)
+	require.ErrorContains(t, err, "invalid rule")
+	_, err = CompileRule(`unknown.metadata.name == ""`)
+	require.ErrorContains(t, err, "undeclared reference")
+	_, err = CompileRule(`"not a bool"`)
+	assert.ErrorContains(t, err, "should evaluate to a bool")
+}
+
+func TestRule_Evaluate(t *testing.T) {
+	obj := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cm", Labels: map[string]string{"env": "prod"}},
+		Data:       map[string]string{"memory": "32Gi"},
+	}
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner"}}
+	conf := &corev1.ConfigMap{Data: map[string]string{"maxMemory": "64Gi"}}
+	vars, err := RuleVars(obj, owner, conf)
+	require.NoError(t, err)
+
+	for expression, expected := range map[string]bool{
+		`object.metadata.name.startsWith("my-")`:                                       true,
+		`paas.metadata.name == "owner"`:                                                true,
+		`object.metadata.?labels.env.orValue("") != "prod" || !has(object.data.cpu)`:   true,
+		`quantity(object.data.memory).isLessThan(quantity(paasConfig.data.maxMemory))`: true,
+		`quantity(object.data.memory).compareTo(quantity("16Gi")) <= 0`:                false,
+		`object.metadata.name.split("-").size() == 3`:                                  false,
+	} {
+		rule, compileErr := CompileRule(expression)
+		require.NoError(t, compileErr, expression)
+		result, evalErr := rule.Evaluate(vars)
+		require.NoError(t, evalErr, expression)
+		assert.Equal(t, expected, result, expression)
+	}
+
+	rule, err := CompileRule(`object.data.missing == "value"`)
+	require.NoError(t, err)
+	_, err = rule.Evaluate(vars)
+	assert.ErrorContains(t, err, "no such key")
+}
+
+func TestPruneNulls(t *testing.T) {
+	value := map[string]any{
+		"null":   nil,
+		"string": "value",
+		"nested": map[string]any{"null": nil, "list": []any{map[string]any{"null": nil}, "item"}},
+	}
+	assert.Equal(t, map[string]any{
+		"string": "value",
+		"nested": map[string]any{"list": []any{map[string]any{}, "item"}},
+	}, pruneNulls(value))
+}
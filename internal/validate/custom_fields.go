@@ -0,0 +1,63 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package validate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/argocd-plugin-generator/fields"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// CapExtraFields returns all extra fields that are configured for a capability (see
+// v1alpha2.PaasCapability.CapExtraFields). Additionally, the values of custom fields with a schema are validated
+// against their schema, and the default from the schema is set for custom fields without a value.
+func CapExtraFields(
+	capability v1alpha2.PaasCapability,
+	fieldConfig map[string]v1alpha2.ConfigCustomField,
+) (fields.Elements, error) {
+	elements, err := capability.CapExtraFields(fieldConfig)
+	if err != nil {
+		return nil, err
+	}
+	var issues []error
+	for key, fieldConf := range fieldConfig {
+		if fieldConf.Schema != nil {
+			issues = append(issues, applyCustomFieldSchema(key, fieldConf, elements)...)
+		}
+	}
+	if len(issues) > 0 {
+		return nil, errors.Join(issues...)
+	}
+	return elements, nil
+}
+
+// applyCustomFieldSchema validates the value of a custom field against its schema, or sets the default value from the
+// schema when no value is set
+func applyCustomFieldSchema(key string, fieldConf v1alpha2.ConfigCustomField, elements fields.Elements) []error {
+	schema, err := CompileSchema(fieldConf.Schema.Raw)
+	if err != nil {
+		return []error{fmt.Errorf("custom field %s: %w", key, err)}
+	}
+	value, exists := elements[key]
+	if !exists {
+		if fieldConf.Required {
+			return []error{fmt.Errorf("value %s is required", key)}
+		}
+		// The default would override the value from the template
+		if value, exists = schema.Default(); exists && fieldConf.Template == "" {
+			elements[key] = value
+		}
+		return nil
+	}
+	if errs := schema.Validate(field.NewPath(key), value); len(errs) > 0 {
+		return []error{errs.ToAggregate()}
+	}
+	return nil
+}
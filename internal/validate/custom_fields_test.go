@@ -0,0 +1,51 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package validate
+
+import (
+	"testing"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func rawCustomFieldValue(raw string) v1alpha2.CustomFieldValue {
+	return v1alpha2.CustomFieldValue{JSON: apiextensionsv1.JSON{Raw: []byte(raw)}}
+}
+
+func TestCapExtraFields(t *testing.T) {
+	fieldConfig := map[string]v1alpha2.ConfigCustomField{
+		"replicas": {Schema: &apiextensionsv1.JSON{
+			Raw: []byte(`{"type": "integer", "minimum": 1, "maximum": 10, "default": 2}`),
+		}},
+		"repos": {Schema: &apiextensionsv1.JSON{
+			Raw: []byte(`{"type": "array", "items": {"type": "object", "properties": {"url": {"type": "string"}}}}`),
+		}},
+		"git_path": {Default: "."},
+	}
+
+	capability := v1alpha2.PaasCapability{CustomFields: v1alpha2.PaasCustomFields{
+		"repos": rawCustomFieldValue(`[{"url": "ssh://git@scm/repo.git"}]`),
+	}}
+	elements, err := CapExtraFields(capability, fieldConfig)
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{"url": "ssh://git@scm/repo.git"}}, elements["repos"],
+		"structured values should be passed as is")
+	assert.InDelta(t, 2, elements["replicas"], 0, "the default from the schema should be used")
+	assert.Equal(t, ".", elements["git_path"])
+
+	capability.CustomFields["replicas"] = rawCustomFieldValue("11")
+	_, err = CapExtraFields(capability, fieldConfig)
+	assert.ErrorContains(t, err, "replicas: Invalid value")
+
+	capability.CustomFields["replicas"] = rawCustomFieldValue("3")
+	capability.CustomFields["git_path"] = rawCustomFieldValue(`["not", "a", "string"]`)
+	_, err = CapExtraFields(capability, fieldConfig)
+	assert.ErrorContains(t, err, "value of custom field git_path should be a string")
+}
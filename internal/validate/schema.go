@@ -0,0 +1,88 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// maxCachedSchemas limits the number of compiled schemas which are kept in memory. The cache is cleared when it is
+// full, which only happens when schemas are changed very often.
+const maxCachedSchemas = 1024
+
+var (
+	schemaCacheMutex sync.Mutex
+	schemaCache      = map[string]*Schema{}
+)
+
+// Schema is a compiled JSON Schema, in the OpenAPI v3 dialect which is used by CustomResourceDefinitions
+type Schema struct {
+	validator  validation.SchemaValidator
+	defaultRaw []byte
+}
+
+// CompileSchema compiles a JSON Schema. Compiled schemas are cached, so that a schema is only compiled once.
+func CompileSchema(raw []byte) (*Schema, error) {
+	schemaCacheMutex.Lock()
+	defer schemaCacheMutex.Unlock()
+	if schema, exists := schemaCache[string(raw)]; exists {
+		return schema, nil
+	}
+
+	var props apiextensionsv1.JSONSchemaProps
+	if err := json.Unmarshal(raw, &props); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	var internalProps apiextensions.JSONSchemaProps
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(
+		&props, &internalProps, nil); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	validator, _, err := validation.NewSchemaValidator(&internalProps)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	schema := &Schema{validator: validator}
+	if props.Default != nil {
+		var defaultValue any
+		if err = json.Unmarshal(props.Default.Raw, &defaultValue); err != nil {
+			return nil, fmt.Errorf("invalid default in schema: %w", err)
+		}
+		if errs := schema.Validate(field.NewPath("default"), defaultValue); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid default in schema: %w", errs.ToAggregate())
+		}
+		schema.defaultRaw = props.Default.Raw
+	}
+
+	if len(schemaCache) >= maxCachedSchemas {
+		clear(schemaCache)
+	}
+	schemaCache[string(raw)] = schema
+	return schema, nil
+}
+
+// Default returns (a copy of) the default value defined in the schema, and whether the schema defines a default value
+func (s *Schema) Default() (value any, exists bool) {
+	if s.defaultRaw == nil {
+		return nil, false
+	}
+	// The default value was unmarshalled successfully while compiling the schema
+	_ = json.Unmarshal(s.defaultRaw, &value)
+	return value, true
+}
+
+// Validate returns all errors found when validating a value (as unmarshalled from JSON) against the schema
+func (s *Schema) Validate(fldPath *field.Path, value any) field.ErrorList {
+	return validation.ValidateCustomResource(fldPath, value, s.validator)
+}
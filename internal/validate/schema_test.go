@@ -0,0 +1,68 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const replicasSchema = `{"type": "integer", "minimum": 1, "maximum": 10, "default": 2}`
+
+func TestCompileSchema_cached(t *testing.T) {
+	schema, err := CompileSchema([]byte(replicasSchema))
+	require.NoError(t, err)
+	cached, err := CompileSchema([]byte(replicasSchema))
+	require.NoError(t, err)
+	assert.Same(t, schema, cached, "compiled schemas should be cached")
+}
+
+func TestCompileSchema_invalid(t *testing.T) {
+	_, err := CompileSchema([]byte(`{"type": `))
+	assert.ErrorContains(t, err, "invalid schema")
+	_, err = CompileSchema([]byte(`{"type": "integer", "default": "two"}`))
+	assert.ErrorContains(t, err, "invalid default in schema")
+}
+
+func TestSchema_Default(t *testing.T) {
+	schema, err := CompileSchema([]byte(replicasSchema))
+	require.NoError(t, err)
+	value, exists := schema.Default()
+	assert.True(t, exists)
+	assert.InDelta(t, 2, value, 0)
+
+	schema, err = CompileSchema([]byte(`{"type": "boolean"}`))
+	require.NoError(t, err)
+	_, exists = schema.Default()
+	assert.False(t, exists)
+}
+
+func TestSchema_Validate(t *testing.T) {
+	schema, err := CompileSchema([]byte(replicasSchema))
+	require.NoError(t, err)
+	path := field.NewPath("replicas")
+	assert.Empty(t, schema.Validate(path, float64(5)))
+	assert.NotEmpty(t, schema.Validate(path, float64(11)))
+	assert.NotEmpty(t, schema.Validate(path, "5"))
+
+	schema, err = CompileSchema([]byte(`{
+		"type": "array",
+		"items": {
+			"type": "object",
+			"required": ["url"],
+			"properties": {
+				"url": {"type": "string", "pattern": "^ssh://"},
+				"branch": {"type": "string", "enum": ["main", "develop"]}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+	path = field.NewPath("repos")
+	assert.Empty(t, schema.Validate(path, []any{
+		map[string]any{"url": "ssh://git@scm/repo.git", "branch": "main"},
+	}))
+	errs := schema.Validate(path, []any{map[string]any{"branch": "feature"}})
+	assert.ErrorContains(t, errs.ToAggregate(), "[0].url: Required value")
+	assert.ErrorContains(t, errs.ToAggregate(), `[0].branch: Unsupported value: "feature"`)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha1"
+	webhookv1alpha2 "github.com/belastingdienst/opr-paas/v3/internal/webhook/v1alpha2"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// cutPathPrefix returns the remainder of a field path after a prefix, when the path is the prefix or a subfield of it
+func cutPathPrefix(path string, prefix string) (string, bool) {
+	rest, found := strings.CutPrefix(path, prefix)
+	if !found || (rest != "" && !strings.HasPrefix(rest, ".") && !strings.HasPrefix(rest, "[")) {
+		return "", false
+	}
+	return rest, true
+}
+
+// paasFieldPaths maps the field paths of the v1alpha2 version of a Paas to the field paths of the v1alpha1 Paas:
+//   - secrets are named sshSecrets in v1alpha1
+//   - namespaces are a list in v1alpha1, and are referenced by their index instead of their name
+func paasFieldPaths(paas *v1alpha1.Paas) webhookv1alpha2.FieldPaths {
+	return func(path string) string {
+		if rest, ok := cutPathPrefix(path, field.NewPath("spec", "secrets").String()); ok {
+			return field.NewPath("spec", "sshSecrets").String() + rest
+		}
+		capsPath := field.NewPath("spec", "capabilities")
+		for name := range paas.Spec.Capabilities {
+			if rest, ok := cutPathPrefix(path, capsPath.Key(name).Child("secrets").String()); ok {
+				return capsPath.Key(name).Child("sshSecrets").String() + rest
+			}
+		}
+		nsPath := field.NewPath("spec", "namespaces")
+		for index, namespace := range paas.Spec.Namespaces {
+			if rest, ok := cutPathPrefix(path, nsPath.Key(namespace).String()); ok {
+				return nsPath.Index(index).String() + rest
+			}
+		}
+		return path
+	}
+}
+
+// paasNsFieldPaths maps the field paths of the v1alpha2 version of a PaasNS to the field paths of the v1alpha1 PaasNS,
+// in which secrets are named sshSecrets
+func paasNsFieldPaths(path string) string {
+	if rest, ok := cutPathPrefix(path, field.NewPath("spec", "secrets").String()); ok {
+		return field.NewPath("spec", "sshSecrets").String() + rest
+	}
+	return path
+}
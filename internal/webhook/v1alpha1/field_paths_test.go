@@ -0,0 +1,42 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaasFieldPaths(t *testing.T) {
+	paths := paasFieldPaths(&v1alpha1.Paas{
+		Spec: v1alpha1.PaasSpec{
+			Capabilities: v1alpha1.PaasCapabilities{"argocd": {}},
+			Namespaces:   []string{"ns1", "ns2"},
+		},
+	})
+	for hubPath, expected := range map[string]string{
+		"spec.secrets":                           "spec.sshSecrets",
+		"spec.secrets[my-secret]":                "spec.sshSecrets[my-secret]",
+		"spec.secretsandmore":                    "spec.secretsandmore",
+		"spec.capabilities[argocd].secrets[foo]": "spec.capabilities[argocd].sshSecrets[foo]",
+		"spec.capabilities[argocd].quota":        "spec.capabilities[argocd].quota",
+		"spec.capabilities[tekton].secrets[foo]": "spec.capabilities[tekton].secrets[foo]",
+		"spec.namespaces[ns2]":                   "spec.namespaces[1]",
+		"spec.namespaces[ns3]":                   "spec.namespaces[ns3]",
+		"spec.namespaces":                        "spec.namespaces",
+		"metadata[name]":                         "metadata[name]",
+	} {
+		assert.Equal(t, expected, paths(hubPath), "path %s", hubPath)
+	}
+}
+
+func TestPaasNsFieldPaths(t *testing.T) {
+	assert.Equal(t, "spec.sshSecrets[my-secret]", paasNsFieldPaths("spec.secrets[my-secret]"))
+	assert.Equal(t, "spec.groups[my-group]", paasNsFieldPaths("spec.groups[my-group]"))
+}
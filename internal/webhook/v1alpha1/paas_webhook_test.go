@@ -155,6 +155,15 @@ var _ = Describe("Paas Webhook", Ordered, func() {
 				}
 			}
 		})
+		It("Should report invalid namespace names by their index", func() {
+			conf.Spec.Validations = v1alpha1.PaasConfigValidations{"paas": {"namespaceName": "^[a-z]+$"}}
+			config.SetConfigV1(conf)
+			obj.Spec.Namespaces = []string{"valid", "in-valid"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			var serr *apierrors.StatusError
+			Expect(errors.As(err, &serr)).To(BeTrue())
+			Expect(serr.Status().Details.Causes).To(ConsistOf(HaveField("Field", "spec.namespaces[1]")))
+		})
 		It("Should warn when namespaces are not ordered properly", func() {
 			unorderedList := "spec.namespaces"
 			obj.Spec.Namespaces = []string{"ns2", "ns1"}
@@ -223,24 +232,23 @@ var _ = Describe("Paas Webhook", Ordered, func() {
 			Expect(causes).To(ContainElements(
 				metav1.StatusCause{
 					Type: metav1.CauseTypeFieldValueInvalid,
-					// revive:disable-next-line
-					Message: "Invalid value: \"invalid base64\": cannot be decrypted: illegal base64 data at input byte 8",
-					Field:   "spec.sshSecrets",
+					Message: "Invalid value: \"invalid base64\": cannot be decrypted: " +
+						"illegal base64 data at input byte 8",
+					Field: "spec.sshSecrets[invalid base64]",
 				},
 				metav1.StatusCause{
 					Type: metav1.CauseTypeFieldValueInvalid,
-					// revive:disable-next-line
-					Message: "Invalid value: \"invalid secret\": cannot be decrypted: unable to decrypt data with any of the private keys",
-					Field:   "spec.sshSecrets",
+					Message: "Invalid value: \"invalid secret\": cannot be decrypted: " +
+						"unable to decrypt data with any of the private keys",
+					Field: "spec.sshSecrets[invalid secret]",
 				},
 			))
 			Expect(causes).To(HaveLen(2))
 		})
 
 		It("Should deny creation when a capability custom field is not configured", func() {
-			v1conf := v1alpha1.PaasConfig{}
-			v2conf := config.GetConfig()
-			v1conf.ConvertFrom(&v2conf)
+			v1conf, err := config.GetConfigV1()
+			Expect(err).NotTo(HaveOccurred())
 			conf := v1conf.Spec
 
 			conf.Capabilities["foo"] = v1alpha1.ConfigCapability{
@@ -263,7 +271,7 @@ var _ = Describe("Paas Webhook", Ordered, func() {
 					},
 				},
 			}
-			_, err := validator.ValidateCreate(ctx, obj)
+			_, err = validator.ValidateCreate(ctx, obj)
 
 			var serr *apierrors.StatusError
 			Expect(errors.As(err, &serr)).To(BeTrue())
@@ -10,8 +10,10 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
 
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha1"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v3/internal/logging"
 	"github.com/belastingdienst/opr-paas/v3/internal/templating"
 	"github.com/belastingdienst/opr-paas/v3/internal/validate"
@@ -158,7 +160,9 @@ func validateNoPaasConfigExists(
 	ctx, logger := logging.GetLogComponent(ctx, logging.WebhookPaasConfigComponentV1)
 	childPath := field.NewPath("spec")
 
-	var list v1alpha1.PaasConfigList
+	// Listed as v1alpha2, as candidate PaasConfigs (which can exist besides the active PaasConfig) are not
+	// represented in v1alpha1
+	var list v1alpha2.PaasConfigList
 
 	if err := k8sClient.List(ctx, &list); err != nil {
 		err = fmt.Errorf("failed to retrieve PaasConfigList: %w", err)
@@ -167,7 +171,7 @@ func validateNoPaasConfigExists(
 		return nil, allErrs
 	}
 
-	if len(list.Items) > 0 {
+	if slices.ContainsFunc(list.Items, func(cfg v1alpha2.PaasConfig) bool { return !cfg.IsCandidate() }) {
 		allErrs = append(allErrs, field.Forbidden(childPath, "another PaasConfig resource already exists"))
 	}
 
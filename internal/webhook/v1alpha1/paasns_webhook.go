@@ -8,19 +8,17 @@ package v1alpha1
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"reflect"
-	"strings"
 
-	corev1 "k8s.io/api/core/v1"
+	admissionv1 "k8s.io/api/admission/v1"
 
-	"github.com/belastingdienst/opr-paas-crypttool/pkg/crypt"
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha1"
-	"github.com/belastingdienst/opr-paas/v3/internal/config"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
 	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	webhookv1alpha2 "github.com/belastingdienst/opr-paas/v3/internal/webhook/v1alpha2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -46,44 +44,11 @@ type PaasNSCustomValidator struct {
 
 //revive:enable:line-length-limit
 
-// TODO devotional-phoenix-97: combine with controller code
-
-// nssFromNs gets all PaasNs objects from a namespace and returns a list of all the corresponding namespaces
-// It also returns PaasNS in those namespaces recursively.
-func nssFromNs(ctx context.Context, c client.Client, ns string) (map[string]int, error) {
-	nss := map[string]int{}
-	pnsList := &v1alpha1.PaasNSList{}
-	if err := c.List(ctx, pnsList, &client.ListOptions{Namespace: ns}); err != nil {
-		return nil, err
-	}
-	for _, pns := range pnsList.Items {
-		nsName := pns.NamespaceName()
-		if value, exists := nss[nsName]; exists {
-			nss[nsName] = value + 1
-		} else {
-			nss[nsName] = 1
-		}
-		// Call myself (recursively)
-		subNss, err := nssFromNs(ctx, c, nsName)
-		if err != nil {
-			return nil, err
-		}
-		for key, value := range subNss {
-			nss[key] += value
-		}
-	}
-	return nss, nil
-}
-
-// nssFromPaas accepts a Paas and returns a list of all namespaces managed by this Paas
-// nssFromPaas uses nssFromNs which is recursive.
-
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type PaasNS.
 func (v *PaasNSCustomValidator) ValidateCreate(
 	ctx context.Context,
 	obj runtime.Object,
-) (w admission.Warnings, err error) {
-	var errs field.ErrorList
+) (admission.Warnings, error) {
 	paasns, ok := obj.(*v1alpha1.PaasNS)
 	ctx, _ = logging.SetWebhookLogger(ctx, paasns)
 	ctx, logger := logging.GetLogComponent(ctx, logging.WebhookPaasNSComponentV1)
@@ -96,36 +61,7 @@ func (v *PaasNSCustomValidator) ValidateCreate(
 		}
 	}
 
-	errs = append(errs, validatePaasNsName(paasns.Name)...)
-
-	paas, err := paasNStoPaas(ctx, v.client, paasns)
-	if err != nil {
-		// code to Err when the referenced Paas does not exist
-		errs = append(errs, &field.Error{
-			Type:     field.ErrorTypeTypeInvalid,
-			Field:    field.NewPath("spec").Child("paas").String(),
-			BadValue: paasns.Spec.Paas,
-			Detail:   err.Error(),
-		})
-		return w, errs.ToAggregate()
-	}
-
-	// Err when a sshSecret can't be decrypted
-	errs = append(errs, compareGroups(paasns.Spec.Groups, paas.Spec.Groups.Keys())...)
-
-	// Code to Err when an sshSecret can't be decrypted
-	var validated validatedSecrets
-	validated.appendFromPaas(*paas)
-	getRsaFunc := func() (cr *crypt.Crypt, err error) {
-		return getRsa(ctx, v.client, paasns.Spec.Paas)
-	}
-	errs = append(errs, validated.compareSecrets(paasns.Spec.SSHSecrets, getRsaFunc)...)
-
-	if len(errs) == 0 {
-		return nil, nil
-	}
-
-	return w, errs.ToAggregate()
+	return v.validate(ctx, paasns, nil, admissionv1.Create)
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type PaasNS.
@@ -133,8 +69,7 @@ func (v *PaasNSCustomValidator) ValidateUpdate(
 	ctx context.Context,
 	oldObj,
 	newObj runtime.Object,
-) (w admission.Warnings, err error) {
-	var errs field.ErrorList
+) (admission.Warnings, error) {
 	oldPaasns, ok := oldObj.(*v1alpha1.PaasNS)
 	if !ok {
 		return nil, &field.Error{
@@ -161,7 +96,7 @@ func (v *PaasNSCustomValidator) ValidateUpdate(
 	logger.Info().Msg("starting validation webhook for update")
 
 	if oldPaasns.Spec.Paas != newPaasns.Spec.Paas {
-		return w, &field.Error{
+		return nil, &field.Error{
 			Type:     field.ErrorTypeNotSupported,
 			Field:    field.NewPath("spec").Child("paas").String(),
 			BadValue: newPaasns.Spec.Paas,
@@ -169,39 +104,7 @@ func (v *PaasNSCustomValidator) ValidateUpdate(
 		}
 	}
 
-	paas, _ := paasNStoPaas(ctx, v.client, newPaasns)
-	// This will not occur.
-	// if err != nil {
-	// 	return w, &field.Error{
-	// 		Type:     field.ErrorTypeNotSupported,
-	// 		Field:    field.NewPath("spec").Child("paas").String(),
-	// 		BadValue: newPaasns.Spec.Paas,
-	// 		Detail:   fmt.Sprintf("paas %s does not exist", newPaasns.Spec.Paas),
-	// 	}
-	// }
-
-	if !reflect.DeepEqual(oldPaasns.Spec.Groups, newPaasns.Spec.Groups) {
-		// Raise errors when groups are not in paas
-		errs = append(errs, compareGroups(newPaasns.Spec.Groups, paas.Spec.Groups.Keys())...)
-	}
-
-	// Err when an sshSecret can't be decrypted
-	if !reflect.DeepEqual(oldPaasns.Spec.SSHSecrets, newPaasns.Spec.SSHSecrets) {
-		var validated validatedSecrets
-		// We don't have to validate what is in the Paas (already validated by Paas webhook)
-		validated.appendFromPaas(*paas)
-		// We don't have to validate what is in the previous PaasNs definition (already validated before)
-		validated.appendFromPaasNS(*oldPaasns)
-		getRsaFunc := func() (cr *crypt.Crypt, err error) {
-			return getRsa(ctx, v.client, newPaasns.Spec.Paas)
-		}
-		errs = append(errs, validated.compareSecrets(newPaasns.Spec.SSHSecrets, getRsaFunc)...)
-	}
-
-	if len(errs) > 0 {
-		return w, errs.ToAggregate()
-	}
-	return w, nil
+	return v.validate(ctx, newPaasns, oldPaasns, admissionv1.Update)
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type PaasNS.
@@ -209,97 +112,37 @@ func (*PaasNSCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object
 	return nil, nil
 }
 
-func paasNStoPaas(ctx context.Context, c client.Client, paasns *v1alpha1.PaasNS) (paas *v1alpha1.Paas, err error) {
-	var ns corev1.Namespace
-	ctx, logger := logging.GetLogComponent(ctx, logging.WebhookPaasNSComponentV1)
-	if err = c.Get(
-		context.Background(),
-		types.NamespacedName{Name: paasns.GetNamespace()},
-		&ns,
-	); err != nil {
-		logger.Error().Msgf("unable to get namespace where paasns resides: %s", err.Error())
-		return nil, err
-	}
-	var paasNames []string
-	for _, ref := range ns.OwnerReferences {
-		if ref.Kind == "Paas" && ref.Controller != nil && *ref.Controller {
-			paasNames = append(paasNames, ref.Name)
-		}
-	}
-	if len(paasNames) == 0 {
-		return nil, errors.New(
-			"failed to get owner reference with kind paas and controller=true from namespace resource")
-	} else if len(paasNames) > 1 {
-		return nil, fmt.Errorf("found %d owner references with kind paas and controller=true", len(paasNames))
-	}
-	paasName := paasNames[0]
-	if ns.Name != paasName && !strings.HasPrefix(ns.Name, paasName+"-") {
-		return nil, fmt.Errorf(
-			"namespace %s is not named after paas, and not prefixed with '%s-' (paasName from owner reference)",
-			ns.Name, paasName)
-	}
-	paas = &v1alpha1.Paas{}
-	err = c.Get(ctx, client.ObjectKey{
-		Name: paasName,
-	}, paas)
+// validate converts a PaasNS (and the PaasNS before an update) to v1alpha2 and validates it
+func (v *PaasNSCustomValidator) validate(
+	ctx context.Context,
+	paasns, oldPaasns *v1alpha1.PaasNS,
+	operation admissionv1.Operation,
+) (admission.Warnings, error) {
+	hubPaasns, err := toV1alpha2PaasNS(paasns)
 	if err != nil {
-		err = fmt.Errorf("failed to get paas: %w", err)
-		logger.Error().Msg(err.Error())
-		return nil, err
-	}
-	return paas, nil
-}
-
-// compareGroups is a helper function to compare the list of groups in a PaasNS against the list in the Paas
-func compareGroups(subGroups []string, superGroups []string) (errs field.ErrorList) {
-	uqSuperGroups := map[string]bool{}
-	for _, group := range superGroups {
-		uqSuperGroups[group] = true
-	}
-	// Err when the optional groupKey(s) don't exist in referenced Paas
-	for _, group := range subGroups {
-		if _, exists := uqSuperGroups[group]; !exists {
-			errs = append(errs, &field.Error{
-				Type:     field.ErrorTypeInvalid,
-				Field:    field.NewPath("spec").Child("groups").Key(group).String(),
-				BadValue: group,
-				Detail:   fmt.Errorf("group %s does not exist in paas groups (%v)", group, superGroups).Error(),
-			})
+		return nil, apierrors.NewInternalError(err)
+	}
+	// The deprecated paas field is not converted, but it is reported when the Paas of the PaasNS cannot be found
+	hubPaasns.Spec.Paas = paasns.Spec.Paas
+	var hubOldPaasns *v1alpha2.PaasNS
+	if oldPaasns != nil {
+		if hubOldPaasns, err = toV1alpha2PaasNS(oldPaasns); err != nil {
+			return nil, apierrors.NewInternalError(err)
 		}
 	}
-	return errs
-}
 
-// validatePaasNsName returns an error when the naam of the PaasNs does not meet validations RE
-func validatePaasNsName(name string) (errs field.ErrorList) {
-	conf, err := config.GetConfigV1()
-	if err != nil {
-		errs = append(errs, field.InternalError(
-			field.NewPath("paasconfig"),
-			fmt.Errorf("unable to retrieve paasconfig: %s", err),
-		))
-	}
+	return webhookv1alpha2.ValidatePaasNS(ctx, v.client, hubPaasns, hubOldPaasns, operation, paasNsFieldPaths)
+}
 
-	if strings.Contains(name, ".") {
-		errs = append(errs, field.Invalid(
-			field.NewPath("metadata").Key("name"),
-			name,
-			"paasns name should not contain dots",
-		))
-	}
-	nameValidationRE := conf.GetValidationRE("paasNs", "name")
-	if nameValidationRE == nil {
-		nameValidationRE = conf.GetValidationRE("paas", "namespaceName")
-	}
-	if nameValidationRE == nil {
-		return errs
+// toV1alpha2PaasNS converts a PaasNS to v1alpha2 (which is validated by the webhooks) through the hub version
+func toV1alpha2PaasNS(paasns *v1alpha1.PaasNS) (*v1alpha2.PaasNS, error) {
+	var hub v1alpha3.PaasNS
+	if err := paasns.ConvertTo(&hub); err != nil {
+		return nil, err
 	}
-	if !nameValidationRE.Match([]byte(name)) {
-		errs = append(errs, field.Invalid(
-			field.NewPath("metadata").Key("name"),
-			name,
-			fmt.Sprintf("paasns name does not match configured validation regex `%s`", nameValidationRE.String()),
-		))
+	v2Paasns := &v1alpha2.PaasNS{}
+	if err := v2Paasns.ConvertFrom(&hub); err != nil {
+		return nil, err
 	}
-	return errs
+	return v2Paasns, nil
 }
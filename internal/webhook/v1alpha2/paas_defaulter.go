@@ -0,0 +1,173 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/config"
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	"github.com/belastingdienst/opr-paas/v3/internal/templating"
+	"github.com/belastingdienst/opr-paas/v3/internal/validate"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// revive:disable:line-length-limit
+
+// +kubebuilder:webhook:path=/mutate-cpet-belastingdienst-nl-v1alpha2-paas,mutating=true,failurePolicy=fail,sideEffects=None,groups=cpet.belastingdienst.nl,resources=paas,verbs=create;update,versions=v1alpha2,name=mpaas-v1alpha2.kb.io,admissionReviewVersions=v1
+
+// revive:enable:line-length-limit
+
+// PaasCustomDefaulter struct is responsible for writing the defaults from the PaasConfig into the Paas resource when
+// it is created or updated, when the defaults feature flag in the PaasConfig is set to materialize.
+// +kubebuilder:object:generate=false
+type PaasCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &PaasCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type Paas.
+func (*PaasCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	paas, ok := obj.(*v1alpha2.Paas)
+	if !ok {
+		return fmt.Errorf("expected a Paas object but got %T", obj)
+	}
+	ctx, _ = logging.SetWebhookLogger(ctx, paas)
+	_, logger := logging.GetLogComponent(ctx, logging.WebhookPaasComponentV2)
+	if paas.GetDeletionTimestamp() != nil {
+		return nil
+	}
+	conf, err := config.GetConfigForPaasWithError(paas)
+	if err != nil {
+		// The validating webhook rejects Paas'es which are pinned to a revision which does not exist
+		logger.Info().Msgf("not applying defaults: %s", err.Error())
+		return nil
+	}
+	if !conf.Spec.FeatureFlags.MaterializeDefaults() {
+		return nil
+	}
+	logger.Info().Msg("applying defaults")
+	if err = defaultCapabilities(conf, paas); err != nil {
+		return err
+	}
+	defaultGroupRoles(conf, paas)
+	return defaultLabels(conf, *paas, paas)
+}
+
+// defaultCapabilities sets the default quota and custom fields of all capabilities of a Paas. Capabilities which are
+// not configured are left as is, as they are rejected by the validating webhook. The default version is not set, as
+// that would pin the Paas to the current default version of the capability.
+func defaultCapabilities(conf v1alpha2.PaasConfig, paas *v1alpha2.Paas) error {
+	for name, capability := range paas.Spec.Capabilities {
+		capConfig, exists := conf.Spec.Capabilities[name]
+		if !exists {
+			continue
+		}
+		if len(capConfig.QuotaSettings.DefQuota) > 0 {
+			capability.Quota = capability.Quota.MergeWith(capConfig.QuotaSettings.DefQuota)
+		}
+		customFields, err := defaultCustomFields(capConfig.CustomFields, capability.CustomFields)
+		if err != nil {
+			return fmt.Errorf("capability %s: %w", name, err)
+		}
+		capability.CustomFields = customFields
+		paas.Spec.Capabilities[name] = capability
+	}
+	return nil
+}
+
+// defaultCustomFields returns the custom fields with the defaults of all custom fields which are not set. Only static
+// defaults are set: custom fields which are set by a template are still rendered by the operator.
+func defaultCustomFields(
+	fieldConfig map[string]v1alpha2.ConfigCustomField,
+	customFields v1alpha2.PaasCustomFields,
+) (v1alpha2.PaasCustomFields, error) {
+	for key, fieldConf := range fieldConfig {
+		if _, exists := customFields[key]; exists || fieldConf.Required {
+			continue
+		}
+		var value v1alpha2.CustomFieldValue
+		switch {
+		case fieldConf.Schema != nil && fieldConf.Template == "":
+			schema, err := validate.CompileSchema(fieldConf.Schema.Raw)
+			if err != nil {
+				return nil, fmt.Errorf("custom field %s: %w", key, err)
+			}
+			defaultValue, hasDefault := schema.Default()
+			if !hasDefault {
+				continue
+			}
+			raw, err := json.Marshal(defaultValue)
+			if err != nil {
+				return nil, fmt.Errorf("custom field %s: %w", key, err)
+			}
+			value = v1alpha2.CustomFieldValue{JSON: apiextensionsv1.JSON{Raw: raw}}
+		case fieldConf.Schema == nil && fieldConf.Default != "":
+			// A default takes precedence over a template (see CapExtraFields)
+			value = v1alpha2.NewCustomFieldValue(fieldConf.Default)
+		default:
+			continue
+		}
+		if customFields == nil {
+			customFields = v1alpha2.PaasCustomFields{}
+		}
+		customFields[key] = value
+	}
+	return customFields, nil
+}
+
+// defaultGroupRoles sets the default role mapping on groups without roles, when the default role mapping exists
+func defaultGroupRoles(conf v1alpha2.PaasConfig, paas *v1alpha2.Paas) {
+	if _, exists := conf.Spec.RoleMappings[v1alpha2.DefaultRoleMapping]; !exists {
+		return
+	}
+	for key, group := range paas.Spec.Groups {
+		if len(group.Roles) == 0 {
+			group.Roles = []string{v1alpha2.DefaultRoleMapping}
+			paas.Spec.Groups[key] = group
+		}
+	}
+}
+
+// defaultLabels sets the labels rendered from the paasLabels templates of the PaasConfig on obj (a Paas or PaasNS),
+// when these labels are not set yet
+func defaultLabels(conf v1alpha2.PaasConfig, paas v1alpha2.Paas, obj metaObject) error {
+	templates := conf.Spec.Templating.PaasLabels
+	if len(templates) == 0 {
+		return nil
+	}
+	templater := templating.NewTemplater(paas, conf)
+	objLabels := obj.GetLabels()
+	if objLabels == nil {
+		objLabels = map[string]string{}
+	}
+	for _, name := range slices.Sorted(maps.Keys(templates)) {
+		result, err := templater.TemplateToMap(name, templates[name])
+		if err != nil {
+			return fmt.Errorf("failed to render paas label template %s: %w", name, err)
+		}
+		for key, value := range result {
+			if _, exists := objLabels[key]; !exists {
+				objLabels[key] = value
+			}
+		}
+	}
+	obj.SetLabels(objLabels)
+	return nil
+}
+
+// metaObject is an object with labels, like a Paas or PaasNS
+type metaObject interface {
+	GetLabels() map[string]string
+	SetLabels(labels map[string]string)
+}
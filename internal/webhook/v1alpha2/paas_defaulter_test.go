@@ -0,0 +1,184 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha2
+
+// Excuse Ginkgo use from revive errors
+// revive:disable:dot-imports
+
+import (
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/config"
+	"github.com/belastingdienst/opr-paas/v3/pkg/quota"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Paas and PaasNS defaulting Webhooks", Ordered, func() {
+	const (
+		paasName   = "defaulted-paas"
+		capName    = "argocd"
+		teamLabel  = "example.com/team"
+		otherLabel = "example.com/other"
+	)
+	var (
+		paas *v1alpha2.Paas
+		conf v1alpha2.PaasConfig
+	)
+
+	BeforeEach(func() {
+		paas = &v1alpha2.Paas{
+			ObjectMeta: metav1.ObjectMeta{Name: paasName, UID: paasName + "-uid"},
+			Spec: v1alpha2.PaasSpec{
+				Requestor: "my-team",
+				Capabilities: v1alpha2.PaasCapabilities{
+					capName: v1alpha2.PaasCapability{
+						CustomFields: v1alpha2.PaasCustomFieldsFromStrings(map[string]string{"set": "by-user"}),
+						Quota:        quota.Quota{corev1.ResourceLimitsCPU: resource.MustParse("3")},
+					},
+					"unconfigured": v1alpha2.PaasCapability{},
+				},
+				Groups: v1alpha2.PaasGroups{
+					"without-roles": v1alpha2.PaasGroup{Users: []string{"jdoe"}},
+					"with-roles":    v1alpha2.PaasGroup{Users: []string{"jdoe"}, Roles: []string{"viewer"}},
+				},
+			},
+		}
+		conf = v1alpha2.PaasConfig{
+			Spec: v1alpha2.PaasConfigSpec{
+				FeatureFlags: v1alpha2.ConfigFeatureFlags{Defaults: v1alpha2.DefaultsMaterialize},
+				RoleMappings: v1alpha2.ConfigRoleMappings{
+					v1alpha2.DefaultRoleMapping: {"admin"},
+					"viewer":                    {"view"},
+				},
+				Capabilities: v1alpha2.ConfigCapabilities{
+					capName: v1alpha2.ConfigCapability{
+						QuotaSettings: v1alpha2.ConfigQuotaSettings{
+							DefQuota: quota.Quota{
+								corev1.ResourceLimitsCPU:    resource.MustParse("5"),
+								corev1.ResourceLimitsMemory: resource.MustParse("5Gi"),
+							},
+						},
+						CustomFields: map[string]v1alpha2.ConfigCustomField{
+							"set":       {Default: "default"},
+							"static":    {Default: "default"},
+							"required":  {Required: true, Default: "default"},
+							"templated": {Template: "{{ .Paas.Name }}"},
+							"schema": {Schema: &apiextensionsv1.JSON{
+								Raw: []byte(`{"type": "integer", "default": 3}`),
+							}},
+						},
+						Versions:       map[string]v1alpha2.ConfigCapabilityVersion{"v1": {}, "v2": {}},
+						DefaultVersion: "v2",
+					},
+				},
+				Templating: v1alpha2.ConfigTemplatingItems{
+					PaasLabels: v1alpha2.ConfigTemplatingItem{
+						teamLabel:  "{{ .Paas.Spec.Requestor }}",
+						otherLabel: "templated",
+					},
+				},
+			},
+		}
+		config.SetConfig(conf)
+	})
+
+	Context("When defaulting a Paas", func() {
+		It("Should leave the Paas as is when defaults are implicit", func() {
+			conf.Spec.FeatureFlags.Defaults = v1alpha2.DefaultsImplicit
+			config.SetConfig(conf)
+			expected := paas.DeepCopy()
+
+			Expect((&PaasCustomDefaulter{}).Default(ctx, paas)).To(Succeed())
+			Expect(paas).To(Equal(expected))
+		})
+
+		It("Should materialize the defaults from the PaasConfig", func() {
+			paas.Labels = map[string]string{otherLabel: "set-by-user"}
+
+			Expect((&PaasCustomDefaulter{}).Default(ctx, paas)).To(Succeed())
+
+			capability := paas.Spec.Capabilities[capName]
+			Expect(capability.Version).To(BeEmpty(), "the Paas should follow changes of the default version")
+			Expect(capability.Quota).To(Equal(quota.Quota{
+				corev1.ResourceLimitsCPU:    resource.MustParse("3"),
+				corev1.ResourceLimitsMemory: resource.MustParse("5Gi"),
+			}))
+			Expect(capability.CustomFields.AsStrings()).To(Equal(map[string]string{
+				"set":    "by-user",
+				"static": "default",
+				"schema": "3",
+			}))
+			Expect(paas.Spec.Capabilities["unconfigured"]).To(Equal(v1alpha2.PaasCapability{}))
+			Expect(paas.Spec.Groups["without-roles"].Roles).To(Equal([]string{v1alpha2.DefaultRoleMapping}))
+			Expect(paas.Spec.Groups["with-roles"].Roles).To(Equal([]string{"viewer"}))
+			Expect(paas.Labels).To(Equal(map[string]string{
+				teamLabel:  "my-team",
+				otherLabel: "set-by-user",
+			}))
+		})
+
+		It("Should not set default roles without a default role mapping", func() {
+			delete(conf.Spec.RoleMappings, v1alpha2.DefaultRoleMapping)
+			config.SetConfig(conf)
+
+			Expect((&PaasCustomDefaulter{}).Default(ctx, paas)).To(Succeed())
+			Expect(paas.Spec.Groups["without-roles"].Roles).To(BeEmpty())
+		})
+
+		It("Should be idempotent", func() {
+			Expect((&PaasCustomDefaulter{}).Default(ctx, paas)).To(Succeed())
+			expected := paas.DeepCopy()
+			Expect((&PaasCustomDefaulter{}).Default(ctx, paas)).To(Succeed())
+			Expect(paas).To(Equal(expected))
+		})
+	})
+
+	Context("When defaulting a PaasNS", func() {
+		var defaulter PaasNSCustomDefaulter
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(v1alpha2.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(paas).Build()
+			createPaasNamespace(cl, *paas, paasName)
+			defaulter = PaasNSCustomDefaulter{client: cl}
+		})
+
+		It("Should set the labels from the PaasConfig", func() {
+			paasns := &v1alpha2.PaasNS{ObjectMeta: metav1.ObjectMeta{Name: "ns", Namespace: paasName}}
+
+			Expect(defaulter.Default(ctx, paasns)).To(Succeed())
+			Expect(paasns.Labels).To(Equal(map[string]string{
+				teamLabel:  "my-team",
+				otherLabel: "templated",
+			}))
+		})
+
+		It("Should leave the PaasNS as is when defaults are implicit", func() {
+			conf.Spec.FeatureFlags.Defaults = ""
+			config.SetConfig(conf)
+			paasns := &v1alpha2.PaasNS{ObjectMeta: metav1.ObjectMeta{Name: "ns", Namespace: paasName}}
+
+			Expect(defaulter.Default(ctx, paasns)).To(Succeed())
+			Expect(paasns.Labels).To(BeNil())
+		})
+
+		It("Should leave a PaasNS without a Paas to the validating webhook", func() {
+			paasns := &v1alpha2.PaasNS{ObjectMeta: metav1.ObjectMeta{Name: "ns", Namespace: "no-paas"}}
+
+			Expect(defaulter.Default(ctx, paasns)).To(Succeed())
+			Expect(paasns.Labels).To(BeNil())
+		})
+	})
+})
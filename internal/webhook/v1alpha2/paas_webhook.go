@@ -8,21 +8,22 @@ package v1alpha2
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"maps"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/belastingdienst/opr-paas-crypttool/pkg/crypt"
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v3/internal/config"
 	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	"github.com/belastingdienst/opr-paas/v3/internal/validate"
 	"github.com/belastingdienst/opr-paas/v3/pkg/quota"
+	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -31,11 +32,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
-// SetupPaasWebhookWithManager registers the webhook for Paas in the manager.
+// SetupPaasWebhookWithManager registers the webhooks for Paas in the manager.
 func SetupPaasWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&v1alpha2.Paas{}).
 		WithValidator(&PaasCustomValidator{client: mgr.GetClient()}).
+		WithDefaulter(&PaasCustomDefaulter{}).
 		Complete()
 }
 
@@ -63,13 +65,13 @@ func (v *PaasCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Ob
 	ctx, logger := logging.SetWebhookLogger(ctx, paas)
 	logger.Info().Msg("starting validation webhook for creation")
 
-	return v.validate(ctx, paas)
+	return ValidatePaas(ctx, v.client, paas, nil, admissionv1.Create, nil)
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Paas.
 func (v *PaasCustomValidator) ValidateUpdate(
 	ctx context.Context,
-	_, newObj runtime.Object,
+	oldObj, newObj runtime.Object,
 ) (admission.Warnings, error) {
 	paas, ok := newObj.(*v1alpha2.Paas)
 	if !ok {
@@ -81,8 +83,10 @@ func (v *PaasCustomValidator) ValidateUpdate(
 		return nil, nil
 	}
 	logger.Info().Msg("starting validation webhook for update")
+	// Without the old Paas all secrets are validated
+	oldPaas, _ := oldObj.(*v1alpha2.Paas)
 
-	return v.validate(ctx, paas)
+	return ValidatePaas(ctx, v.client, paas, oldPaas, admissionv1.Update, nil)
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Paas.
@@ -104,71 +108,66 @@ type paasSpecValidator func(
 	client.Client,
 	v1alpha2.PaasConfig,
 	*v1alpha2.Paas,
+	validatedSecrets,
 ) ([]*field.Error, error)
 
-func (v *PaasCustomValidator) validate(ctx context.Context, paas *v1alpha2.Paas) (admission.Warnings, error) {
-	var allErrs field.ErrorList
-	var warnings []string
-	ctx, logger := logging.GetLogComponent(ctx, logging.WebhookPaasComponentV2)
-	conf, err := config.GetConfigWithError()
+// defaultMaxPinDuration is used when the PaasConfig has no maximum pin duration set (e.g. when it was created with
+// the v1alpha1 api)
+const defaultMaxPinDuration = 7 * 24 * time.Hour
+
+// validatePaasRevisionPin returns an error if the Paas is pinned to a PaasConfigRevision which does not exist, or for
+// longer than the PaasConfig allows.
+func validatePaasRevisionPin(
+	ctx context.Context,
+	k8sClient client.Client,
+	conf v1alpha2.PaasConfig,
+	paas *v1alpha2.Paas,
+	_ validatedSecrets,
+) ([]*field.Error, error) {
+	annotationsPath := field.NewPath("metadata").Child("annotations")
+	now := time.Now()
+	revisionName, until, err := paas.PinnedRevision(now)
 	if err != nil {
-		return nil, err
-	}
-	// Check for uninitialized config
-	if conf.Spec.DecryptKeysSecret.Name == "" {
-		return nil, apierrors.NewInternalError(errors.New("uninitialized PaasConfig"))
-	}
-
-	for _, val := range []paasSpecValidator{
-		validatePaasName,
-		validatePaasRequestor,
-		validateCaps,
-		validatePaasallowedQuotas,
-		validatePaasSecrets,
-		validateCustomFields,
-		validateGroupNames,
-		validatePaasNamespaceNames,
-		validatePaasNamespaceGroups,
-	} {
-		if errs, validationErr := val(ctx, v.client, *conf, paas); validationErr != nil {
-			return nil, apierrors.NewInternalError(validationErr)
-		} else if errs != nil {
-			allErrs = append(allErrs, errs...)
-		}
+		return []*field.Error{field.Invalid(annotationsPath, paas.Annotations, err.Error())}, nil
 	}
-
-	groupWarnings, groupErrors := v.validateGroups(paas.Spec.Groups)
-	warnings = append(warnings, groupWarnings...)
-	allErrs = append(allErrs, groupErrors...)
-	warnings = append(warnings, v.validateQuota(paas)...)
-	warnings = append(warnings, v.validateExtraPerm(*conf, paas)...)
-
-	if len(allErrs) == 0 && len(warnings) == 0 {
-		logger.Info().Msg("validate ok")
+	if revisionName == "" {
 		return nil, nil
-	} else if len(allErrs) == 0 {
-		return warnings, nil
 	}
-
-	return warnings, apierrors.NewInvalid(
-		schema.GroupKind{Group: v1alpha2.GroupVersion.Group, Kind: "Paas"},
-		paas.Name,
-		allErrs,
-	)
+	// The maximum pin duration of the active PaasConfig applies, also when conf is the revision the Paas is pinned to
+	maxPinDuration := config.GetConfig().Spec.Revisions.MaxPinDuration.Duration
+	if maxPinDuration == 0 {
+		maxPinDuration = defaultMaxPinDuration
+	}
+	var errs []*field.Error
+	if until.After(now.Add(maxPinDuration)) {
+		errs = append(errs, field.Invalid(
+			annotationsPath.Key(v1alpha2.PinRevisionUntilAnnotation),
+			until.Format(time.RFC3339),
+			fmt.Sprintf("a Paas can be pinned for at most %s", maxPinDuration),
+		))
+	}
+	var revision v1alpha2.PaasConfigRevision
+	if err = k8sClient.Get(ctx, types.NamespacedName{Name: revisionName}, &revision); apierrors.IsNotFound(err) {
+		errs = append(errs, field.NotFound(annotationsPath.Key(v1alpha2.PinRevisionAnnotation), revisionName))
+	} else if err != nil {
+		return nil, err
+	}
+	return errs, nil
 }
 
-// validateCaps returns an error if any of the passed capabilities is not configured.
+// validateCaps returns an error if any of the passed capabilities is not configured, or when the requirements and
+// conflicts of the capabilities are not met.
 func validateCaps(
 	ctx context.Context,
 	k8sClient client.Client,
 	conf v1alpha2.PaasConfig,
 	paas *v1alpha2.Paas,
+	validated validatedSecrets,
 ) ([]*field.Error, error) {
 	var errs []*field.Error
 
-	rsa, err := getCryptInstance(ctx, k8sClient, conf, paas.Name)
-	if err != nil {
-		return nil, err
+	getRsaFunc := func() (*crypt.Crypt, error) {
+		return getCryptInstance(ctx, k8sClient, conf, paas.Name)
 	}
 
 	for name, capability := range paas.Spec.Capabilities {
@@ -179,23 +178,85 @@ func validateCaps(
 				"capability not configured",
 			))
 		} else {
-			errs = append(errs, validateSecrets(
+			secretErrs, err := validated.compareSecrets(
 				capability.Secrets,
-				rsa,
+				getRsaFunc,
 				field.NewPath("spec").Child("capabilities").Key(name).Child("secrets"),
+			)
+			if err != nil {
+				return nil, err
+			}
+			errs = append(errs, secretErrs...)
+			errs = append(errs, validateCapVersion(
+				conf.Spec.Capabilities[name],
+				capability.Version,
+				field.NewPath("spec").Child("capabilities").Key(name).Child("version"),
 			)...)
 		}
 	}
+	errs = append(errs, validateCapDependencies(conf.Spec.Capabilities, paas)...)
 
 	return errs, nil
 }
 
+// validateCapVersion returns an error when a version is selected which is not configured for the capability
+func validateCapVersion(capConfig v1alpha2.ConfigCapability, version string, versionPath *field.Path) []*field.Error {
+	if version == "" {
+		return nil
+	}
+	if len(capConfig.Versions) == 0 {
+		return []*field.Error{field.Invalid(versionPath, version, "capability has no versions configured")}
+	}
+	if _, ok := capConfig.Versions[version]; !ok {
+		return []*field.Error{field.NotSupported(versionPath, version, slices.Sorted(maps.Keys(capConfig.Versions)))}
+	}
+	return nil
+}
+
+// validateCapDependencies returns an error for every capability which is required by an enabled capability but is
+// not enabled itself (which also refuses removing a capability which other capabilities depend on), and for every pair
+// of enabled capabilities which conflict with each other.
+func validateCapDependencies(capabilities v1alpha2.ConfigCapabilities, paas *v1alpha2.Paas) []*field.Error {
+	var errs []*field.Error
+	capsPath := field.NewPath("spec").Child("capabilities")
+
+	enabled := make([]string, 0, len(paas.Spec.Capabilities))
+	for name := range paas.Spec.Capabilities {
+		if _, ok := capabilities[name]; ok {
+			enabled = append(enabled, name)
+		}
+	}
+	slices.Sort(enabled)
+	for i, name := range enabled {
+		for _, required := range capabilities[name].Requires {
+			if _, ok := paas.Spec.Capabilities[required]; !ok {
+				errs = append(errs, field.Required(
+					capsPath.Key(required),
+					fmt.Sprintf("capability %s is required by capability %s", required, name),
+				))
+			}
+		}
+		for _, other := range enabled[i+1:] {
+			if capabilities.Conflicts(name, other) {
+				errs = append(errs, field.Invalid(
+					capsPath.Key(other),
+					other,
+					fmt.Sprintf("capability %s conflicts with capability %s", other, name),
+				))
+			}
+		}
+	}
+
+	return errs
+}
+
 // validatePaasName returns an error if the name of the paas does not meet validations.
 func validatePaasName(
 	_ context.Context,
 	_ client.Client,
 	conf v1alpha2.PaasConfig,
 	paas *v1alpha2.Paas,
+	_ validatedSecrets,
 ) ([]*field.Error, error) {
 	var errs []*field.Error
 
@@ -220,6 +281,7 @@ func validatePaasallowedQuotas(
 	_ client.Client,
 	conf v1alpha2.PaasConfig,
 	paas *v1alpha2.Paas,
+	_ validatedSecrets,
 ) ([]*field.Error, error) {
 	var errs []*field.Error
 	nameValidationRE := conf.Spec.Validations.GetValidationRE("paas", "allowedQuotas")
@@ -255,6 +317,7 @@ func validatePaasNamespaceNames(
 	_ client.Client,
 	conf v1alpha2.PaasConfig,
 	paas *v1alpha2.Paas,
+	_ validatedSecrets,
 ) ([]*field.Error, error) {
 	var errs []*field.Error
 
@@ -270,7 +333,7 @@ func validatePaasNamespaceNames(
 	for namespace := range paas.Spec.Namespaces {
 		if !nameValidationRE.Match([]byte(namespace)) {
 			errs = append(errs, field.Invalid(
-				field.NewPath("spec").Child("namespaces"),
+				field.NewPath("spec").Child("namespaces").Key(namespace),
 				namespace,
 				fmt.Sprintf("paas name does not match configured validation regex `%s`", nameValidationRE.String()),
 			))
@@ -287,6 +350,7 @@ func validatePaasNamespaceGroups(
 	_ client.Client,
 	_ v1alpha2.PaasConfig,
 	paas *v1alpha2.Paas,
+	_ validatedSecrets,
 ) (ferrs []*field.Error, _ error) {
 	for nsname, ns := range paas.Spec.Namespaces {
 		for _, g := range ns.Groups {
@@ -312,6 +376,7 @@ func validatePaasRequestor(
 	_ client.Client,
 	conf v1alpha2.PaasConfig,
 	paas *v1alpha2.Paas,
+	_ validatedSecrets,
 ) ([]*field.Error, error) {
 	var errs []*field.Error
 
@@ -336,6 +401,7 @@ func validateGroupNames(
 	_ client.Client,
 	conf v1alpha2.PaasConfig,
 	paas *v1alpha2.Paas,
+	_ validatedSecrets,
 ) ([]*field.Error, error) {
 	var errs []*field.Error
 	groupNameValidationRE := conf.GetValidationRE("paas", "groupName")
@@ -357,18 +423,50 @@ func validateGroupNames(
 	return errs, nil
 }
 
+// validateGroupDefinitionReferences returns an error for every group which references a group definition that does not
+// exist in the PaasConfig, or which also defines a query or users.
+func validateGroupDefinitionReferences(
+	_ context.Context,
+	_ client.Client,
+	conf v1alpha2.PaasConfig,
+	paas *v1alpha2.Paas,
+	_ validatedSecrets,
+) ([]*field.Error, error) {
+	var errs []*field.Error
+	for key, group := range paas.Spec.Groups {
+		if group.Definition == "" {
+			continue
+		}
+		groupPath := field.NewPath("spec").Child("groups").Key(key)
+		if _, exists := conf.Spec.GroupDefinitions[group.Definition]; !exists {
+			errs = append(errs, field.NotFound(groupPath.Child("definition"), group.Definition))
+		}
+		if group.Query != "" || len(group.Users) > 0 {
+			errs = append(errs, field.Invalid(
+				groupPath,
+				key,
+				"definition is mutually exclusive with query and users",
+			))
+		}
+	}
+
+	return errs, nil
+}
+
+// validatePaasSecrets returns an error for every secret of the Paas which cannot be decrypted, skipping the secrets
+// which were validated before
 func validatePaasSecrets(
 	ctx context.Context,
 	k8sClient client.Client,
 	conf v1alpha2.PaasConfig,
 	paas *v1alpha2.Paas,
+	validated validatedSecrets,
 ) ([]*field.Error, error) {
-	rsa, err := getCryptInstance(ctx, k8sClient, conf, paas.Name)
-	if err != nil {
-		return nil, err
+	getRsaFunc := func() (*crypt.Crypt, error) {
+		return getCryptInstance(ctx, k8sClient, conf, paas.Name)
 	}
 
-	return validateSecrets(paas.Spec.Secrets, rsa, field.NewPath("spec").Child("secrets")), nil
+	return validated.compareSecrets(paas.Spec.Secrets, getRsaFunc, field.NewPath("spec").Child("secrets"))
 }
 
 // validateCustomFields ensures that for a given capability in the Paas:
@@ -381,12 +479,13 @@ func validateCustomFields(
 	_ client.Client,
 	conf v1alpha2.PaasConfig,
 	paas *v1alpha2.Paas,
+	_ validatedSecrets,
 ) ([]*field.Error, error) {
 	var errs []*field.Error
 
 	for cname, c := range paas.Spec.Capabilities {
 		// validateCaps() has already ensured the capability configuration exists
-		if _, err := c.CapExtraFields(conf.Spec.Capabilities[cname].CustomFields); err != nil {
+		if _, err := validate.CapExtraFields(c, conf.Spec.Capabilities[cname].CustomFields); err != nil {
 			errs = append(errs, field.Invalid(
 				field.NewPath("spec").Child("capabilities").Key(cname),
 				"custom_fields",
@@ -401,8 +500,8 @@ func validateCustomFields(
 }
 
 // validateGroups returns a warning for any of the passed groups which contain both users and a query.
-func (*PaasCustomValidator) validateGroups(groups v1alpha2.PaasGroups) (warnings []string, errs []*field.Error) {
-	groupUserFeatureFlag := config.GetConfig().Spec.FeatureFlags.GroupUserManagement
+func validateGroups(conf v1alpha2.PaasConfig, groups v1alpha2.PaasGroups) (warnings []string, errs []*field.Error) {
+	groupUserFeatureFlag := conf.Spec.FeatureFlags.GroupUserManagement
 	for key, grp := range groups {
 		if len(grp.Query) > 0 && len(grp.Users) > 0 {
 			warnings = append(warnings, fmt.Sprintf(
@@ -431,7 +530,7 @@ func (*PaasCustomValidator) validateGroups(groups v1alpha2.PaasGroups) (warnings
 }
 
 // validateQuota returns a warning when higher limits are configured than requests for the Paas / capability quotas.
-func (v *PaasCustomValidator) validateQuota(paas *v1alpha2.Paas) (warnings []string) {
+func validateQuota(paas *v1alpha2.Paas) (warnings []string) {
 	quotas := map[*field.Path]quota.Quota{
 		field.NewPath("spec", "quota"): paas.Spec.Quota,
 	}
@@ -462,7 +561,7 @@ func (v *PaasCustomValidator) validateQuota(paas *v1alpha2.Paas) (warnings []str
 }
 
 // validateExtraPerm returns a warning when extra permissions are requested for a capability that are not configured.
-func (v *PaasCustomValidator) validateExtraPerm(conf v1alpha2.PaasConfig, paas *v1alpha2.Paas) (warnings []string) {
+func validateExtraPerm(conf v1alpha2.PaasConfig, paas *v1alpha2.Paas) (warnings []string) {
 	for cname, c := range paas.Spec.Capabilities {
 		if c.ExtraPermissions && conf.Spec.Capabilities[cname].ExtraPermissions == nil {
 			warnings = append(warnings, fmt.Sprintf(
@@ -475,6 +574,33 @@ func (v *PaasCustomValidator) validateExtraPerm(conf v1alpha2.PaasConfig, paas *
 	return warnings
 }
 
+// validateCapVersions returns a warning for every capability which uses a deprecated version
+func validateCapVersions(conf v1alpha2.PaasConfig, paas *v1alpha2.Paas) (warnings []string) {
+	now := time.Now()
+	for cname, c := range paas.Spec.Capabilities {
+		capConfig := conf.Spec.Capabilities[cname]
+		version := capConfig.ResolveVersion(c.Version)
+		deprecation := capConfig.Versions[version].Deprecation
+		if deprecation == nil {
+			continue
+		}
+		reaches := "reaches"
+		if !now.Before(deprecation.EndOfLife.Time) {
+			reaches = "reached"
+		}
+		warning := fmt.Sprintf("%s version %s is deprecated and %s end of life on %s",
+			field.NewPath("spec", "capabilities").Key(cname), version, reaches,
+			deprecation.EndOfLife.Format(time.DateOnly))
+		if deprecation.Message != "" {
+			warning = fmt.Sprintf("%s: %s", warning, deprecation.Message)
+		}
+		warnings = append(warnings, warning)
+	}
+	slices.Sort(warnings)
+
+	return warnings
+}
+
 // getCryptInstance returns a crypt based on the provided config and paasName
 func getCryptInstance(
 	ctx context.Context,
@@ -502,22 +628,3 @@ func getCryptInstance(
 
 	return rsa, nil
 }
-
-// validateSecrets validates a map of Secrets based on a provided rsa
-func validateSecrets(
-	secrets map[string]string,
-	rsa *crypt.Crypt,
-	basePath *field.Path,
-) []*field.Error {
-	var errs []*field.Error
-	for name, secret := range secrets {
-		if _, err := rsa.Decrypt(secret); err != nil {
-			errs = append(errs, field.Invalid(
-				basePath.Key(name),
-				secret,
-				fmt.Sprintf("cannot be decrypted: %s", err),
-			))
-		}
-	}
-	return errs
-}
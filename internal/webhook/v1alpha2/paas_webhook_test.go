@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/belastingdienst/opr-paas-crypttool/pkg/crypt"
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
@@ -23,6 +24,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -167,6 +169,61 @@ var _ = Describe("Paas Webhook", Ordered, func() {
 				To(MatchError(ContainSubstring("capability not configured")))
 		})
 
+		It("Should validate capability versions and warn for deprecated versions", func() {
+			conf.Spec.Capabilities["argocd"] = v1alpha2.ConfigCapability{
+				Versions: map[string]v1alpha2.ConfigCapabilityVersion{
+					"2.13": {Deprecation: &v1alpha2.ConfigCapabilityDeprecation{
+						EndOfLife: metav1.NewTime(time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)),
+						Message:   "please upgrade to 2.14",
+					}},
+					"2.14": {},
+				},
+				DefaultVersion: "2.14",
+			}
+			config.SetConfig(conf)
+
+			obj = &v1alpha2.Paas{Spec: v1alpha2.PaasSpec{Capabilities: v1alpha2.PaasCapabilities{
+				"argocd": v1alpha2.PaasCapability{},
+			}}}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.Capabilities["argocd"] = v1alpha2.PaasCapability{Version: "2.13"}
+			warn, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warn).To(ConsistOf("spec.capabilities[argocd] version 2.13 is deprecated and reaches end of life " +
+				"on 2099-01-01: please upgrade to 2.14"))
+
+			obj.Spec.Capabilities["argocd"] = v1alpha2.PaasCapability{Version: "3.0"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring(
+				`spec.capabilities[argocd].version: Unsupported value: "3.0": supported values: "2.13", "2.14"`)))
+
+			obj.Spec.Capabilities = v1alpha2.PaasCapabilities{"cap5": v1alpha2.PaasCapability{Version: "1.0"}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring(
+				"capability has no versions configured")))
+		})
+
+		It("Should validate capability requirements and conflicts", func() {
+			conf.Spec.Capabilities["argocd"] = v1alpha2.ConfigCapability{}
+			conf.Spec.Capabilities["tekton"] = v1alpha2.ConfigCapability{Requires: []string{"argocd"}}
+			conf.Spec.Capabilities["sso"] = v1alpha2.ConfigCapability{ConflictsWith: []string{"sso-legacy"}}
+			conf.Spec.Capabilities["sso-legacy"] = v1alpha2.ConfigCapability{}
+			config.SetConfig(conf)
+
+			obj = &v1alpha2.Paas{Spec: v1alpha2.PaasSpec{Capabilities: v1alpha2.PaasCapabilities{
+				"tekton": v1alpha2.PaasCapability{},
+			}}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring(
+				"spec.capabilities[argocd]: Required value: capability argocd is required by capability tekton")))
+
+			obj.Spec.Capabilities["argocd"] = v1alpha2.PaasCapability{}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+
+			obj.Spec.Capabilities["sso"] = v1alpha2.PaasCapability{}
+			obj.Spec.Capabilities["sso-legacy"] = v1alpha2.PaasCapability{}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring(
+				"capability sso-legacy conflicts with capability sso")))
+		})
+
 		It(
 			"Should deny creation and return multiple field errors when multiple unconfigured capabilities are set",
 			func() {
@@ -221,25 +278,25 @@ var _ = Describe("Paas Webhook", Ordered, func() {
 			Expect(causes).To(ContainElements(
 				metav1.StatusCause{
 					Type: metav1.CauseTypeFieldValueInvalid,
-					Message: "Invalid value: \"foo bar baz\": cannot be decrypted: " +
+					Message: "Invalid value: \"invalid base64\": cannot be decrypted: " +
 						"illegal base64 data at input byte 8",
 					Field: "spec.capabilities[foo].secrets[invalid base64]",
 				},
 				metav1.StatusCause{
 					Type: metav1.CauseTypeFieldValueInvalid,
-					Message: "Invalid value: \"Zm9vIGJhciBiYXo=\": cannot be decrypted: " +
+					Message: "Invalid value: \"invalid secret\": cannot be decrypted: " +
 						"unable to decrypt data with any of the private keys",
 					Field: "spec.capabilities[foo].secrets[invalid secret]",
 				},
 				metav1.StatusCause{
 					Type: metav1.CauseTypeFieldValueInvalid,
-					Message: "Invalid value: \"foo bar baz\": cannot be decrypted: " +
+					Message: "Invalid value: \"invalid base64\": cannot be decrypted: " +
 						"illegal base64 data at input byte 8",
 					Field: "spec.secrets[invalid base64]",
 				},
 				metav1.StatusCause{
 					Type: metav1.CauseTypeFieldValueInvalid,
-					Message: "Invalid value: \"Zm9vIGJhciBiYXo=\": cannot be decrypted: " +
+					Message: "Invalid value: \"invalid secret\": cannot be decrypted: " +
 						"unable to decrypt data with any of the private keys",
 					Field: "spec.secrets[invalid secret]",
 				},
@@ -260,10 +317,10 @@ var _ = Describe("Paas Webhook", Ordered, func() {
 				Spec: v1alpha2.PaasSpec{
 					Capabilities: v1alpha2.PaasCapabilities{
 						"foo": v1alpha2.PaasCapability{
-							CustomFields: map[string]string{
+							CustomFields: v1alpha2.PaasCustomFieldsFromStrings(map[string]string{
 								"bar": "baz",
 								"baz": "qux",
-							},
+							}),
 						},
 					},
 				},
@@ -284,6 +341,35 @@ var _ = Describe("Paas Webhook", Ordered, func() {
 			))
 		})
 
+		It("Should validate structured custom fields against their schema", func() {
+			conf := config.GetConfig().Spec
+			conf.Capabilities["foo"] = v1alpha2.ConfigCapability{
+				CustomFields: map[string]v1alpha2.ConfigCustomField{
+					"replicas": {Schema: &apiextensionsv1.JSON{Raw: []byte(`{"type": "integer", "maximum": 10}`)}},
+				},
+			}
+			config.SetConfig(v1alpha2.PaasConfig{Spec: conf})
+
+			obj = &v1alpha2.Paas{
+				Spec: v1alpha2.PaasSpec{
+					Capabilities: v1alpha2.PaasCapabilities{
+						"foo": v1alpha2.PaasCapability{
+							CustomFields: v1alpha2.PaasCustomFields{
+								"replicas": {JSON: apiextensionsv1.JSON{Raw: []byte("5")}},
+							},
+						},
+					},
+				},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+
+			obj.Spec.Capabilities["foo"].CustomFields["replicas"] = v1alpha2.CustomFieldValue{
+				JSON: apiextensionsv1.JSON{Raw: []byte("11")},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().
+				To(MatchError(ContainSubstring("should be less than or equal to 10")))
+		})
+
 		It("Should deny creation when a capability is missing a required custom field", func() {
 			conf := config.GetConfig().Spec
 			conf.Capabilities["foo"] = v1alpha2.ConfigCapability{
@@ -330,10 +416,10 @@ var _ = Describe("Paas Webhook", Ordered, func() {
 				Spec: v1alpha2.PaasSpec{
 					Capabilities: v1alpha2.PaasCapabilities{
 						"foo": v1alpha2.PaasCapability{
-							CustomFields: map[string]string{
+							CustomFields: v1alpha2.PaasCustomFieldsFromStrings(map[string]string{
 								"bar": "notinteger123",
 								"baz": "word",
-							},
+							}),
 						},
 					},
 				},
@@ -417,6 +503,127 @@ var _ = Describe("Paas Webhook", Ordered, func() {
 			}
 		})
 
+		It("Should validate references to group definitions", func() {
+			conf.Spec.GroupDefinitions = v1alpha2.ConfigGroupDefinitions{
+				"shared-team": {Users: []string{"user1"}},
+			}
+			config.SetConfig(conf)
+			obj = &v1alpha2.Paas{
+				Spec: v1alpha2.PaasSpec{
+					Groups: v1alpha2.PaasGroups{
+						"valid":     {Definition: "shared-team", Roles: []string{"admin"}},
+						"missing":   {Definition: "no-such-team"},
+						"exclusive": {Definition: "shared-team", Users: []string{"user2"}},
+					},
+				},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+
+			var serr *apierrors.StatusError
+			Expect(errors.As(err, &serr)).To(BeTrue())
+			causes := serr.Status().Details.Causes
+			Expect(causes).To(HaveLen(2))
+			Expect(causes).To(ContainElements(
+				metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueNotFound,
+					Message: "Not found: \"no-such-team\"",
+					Field:   "spec.groups[missing].definition",
+				},
+				metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: "Invalid value: \"exclusive\": definition is mutually exclusive with query and users",
+					Field:   "spec.groups[exclusive]",
+				},
+			))
+		})
+
+		It("Should validate pinning to a PaasConfigRevision", func() {
+			revision := v1alpha2.NewPaasConfigRevision(v1alpha2.PaasConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "pinned-config"},
+				Spec:       conf.Spec,
+			})
+			Expect(k8sClient.Create(ctx, revision)).To(Succeed())
+			pinnedPaas := func(revisionName string, until time.Duration) *v1alpha2.Paas {
+				return &v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+					v1alpha2.PinRevisionAnnotation:      revisionName,
+					v1alpha2.PinRevisionUntilAnnotation: time.Now().Add(until).Format(time.RFC3339),
+				}}}
+			}
+
+			_, err := validator.ValidateCreate(ctx, pinnedPaas(revision.Name, time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = validator.ValidateCreate(ctx, pinnedPaas("no-such-revision", time.Hour))
+			Expect(err).To(MatchError(ContainSubstring(
+				`metadata.annotations[cpet.belastingdienst.nl/pin-paasconfig-revision]: Not found: "no-such-revision"`)))
+
+			_, err = validator.ValidateCreate(ctx, pinnedPaas(revision.Name, 30*24*time.Hour))
+			Expect(err).To(MatchError(ContainSubstring("a Paas can be pinned for at most 168h0m0s")))
+		})
+		It("Should validate a pinned Paas with the PaasConfigRevision it is pinned to", func() {
+			pinnedConf := conf.DeepCopy()
+			pinnedConf.Name = "pinned-caps-config"
+			pinnedConf.Spec.Capabilities["old-cap"] = v1alpha2.ConfigCapability{AppSet: "oldAppset"}
+			revision := v1alpha2.NewPaasConfigRevision(*pinnedConf)
+			Expect(k8sClient.Create(ctx, revision)).To(Succeed())
+			config.SetRevision(*revision)
+			defer config.DeleteRevision(revision.Name)
+			obj = &v1alpha2.Paas{
+				ObjectMeta: metav1.ObjectMeta{Name: paasName, Annotations: map[string]string{
+					v1alpha2.PinRevisionAnnotation:      revision.Name,
+					v1alpha2.PinRevisionUntilAnnotation: time.Now().Add(time.Hour).Format(time.RFC3339),
+				}},
+				Spec: v1alpha2.PaasSpec{
+					Capabilities: v1alpha2.PaasCapabilities{"old-cap": v1alpha2.PaasCapability{}},
+				},
+			}
+
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+			Expect(validator.ValidateUpdate(ctx, obj, obj)).Error().NotTo(HaveOccurred())
+			Expect(AuditPaas(ctx, k8sClient, conf, obj, nil)).To(BeEmpty())
+
+			unpinned := obj.DeepCopy()
+			unpinned.Annotations = nil
+			Expect(validator.ValidateUpdate(ctx, obj, unpinned)).Error().To(MatchError(ContainSubstring("old-cap")))
+		})
+		It("Should validate CEL validation rules", func() {
+			conf.Spec.ValidationRules = v1alpha2.ConfigValidationRules{
+				{
+					Resource: "paas",
+					Rule: `object.metadata.?labels.env.orValue("") != "prod" ||
+						object.spec.?groups.orValue({}).all(g, !has(object.spec.groups[g].users))`,
+					Message: "prod Paas'es should not have groups with users",
+				},
+				{
+					Resource: "paas",
+					Rule: `!has(object.spec.capabilities.cap5) ||
+						quantity(object.spec.capabilities.cap5.quota[?"limits.memory"].orValue("0"))
+							.compareTo(quantity("64Gi")) <= 0`,
+				},
+				{Resource: "paasNs", Rule: `false`},
+			}
+			config.SetConfig(conf)
+			newPaas := func(env string, groups v1alpha2.PaasGroups, memory string) *v1alpha2.Paas {
+				return &v1alpha2.Paas{
+					ObjectMeta: metav1.ObjectMeta{Name: paasName, Labels: map[string]string{"env": env}},
+					Spec: v1alpha2.PaasSpec{
+						Groups: groups,
+						Capabilities: v1alpha2.PaasCapabilities{"cap5": v1alpha2.PaasCapability{
+							Quota: quota.Quota{corev1.ResourceLimitsMemory: resource.MustParse(memory)},
+						}},
+					},
+				}
+			}
+			withUsers := v1alpha2.PaasGroups{"users": v1alpha2.PaasGroup{Users: []string{"jdoe"}}}
+
+			Expect(validator.ValidateCreate(ctx, newPaas("test", withUsers, "64Gi"))).Error().NotTo(HaveOccurred())
+			Expect(validator.ValidateCreate(ctx, newPaas("prod", nil, "1Gi"))).Error().NotTo(HaveOccurred())
+			Expect(validator.ValidateCreate(ctx, newPaas("prod", withUsers, "1Gi"))).Error().To(MatchError(
+				ContainSubstring("spec: Forbidden: prod Paas'es should not have groups with users")))
+			Expect(validator.ValidateCreate(ctx, newPaas("test", nil, "65Gi"))).Error().To(MatchError(
+				ContainSubstring("spec: Forbidden: does not meet validation rule")))
+		})
+
 		It("Should warn when a group contains both users and a query", func() {
 			obj = &v1alpha2.Paas{
 				Spec: v1alpha2.PaasSpec{
@@ -622,6 +829,25 @@ var _ = Describe("Paas Webhook", Ordered, func() {
 	})
 
 	Context("When updating a Paas under Validating Webhook", func() {
+		It("Should deny removing a capability which another capability requires", func() {
+			conf.Spec.Capabilities["argocd"] = v1alpha2.ConfigCapability{}
+			conf.Spec.Capabilities["tekton"] = v1alpha2.ConfigCapability{Requires: []string{"argocd"}}
+			config.SetConfig(conf)
+			oldObj = &v1alpha2.Paas{Spec: v1alpha2.PaasSpec{Capabilities: v1alpha2.PaasCapabilities{
+				"argocd": v1alpha2.PaasCapability{},
+				"tekton": v1alpha2.PaasCapability{},
+			}}}
+			obj = &v1alpha2.Paas{Spec: v1alpha2.PaasSpec{Capabilities: v1alpha2.PaasCapabilities{
+				"tekton": v1alpha2.PaasCapability{},
+			}}}
+
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().
+				To(MatchError(ContainSubstring("capability argocd is required by capability tekton")))
+
+			obj.Spec.Capabilities = v1alpha2.PaasCapabilities{"argocd": v1alpha2.PaasCapability{}}
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+		})
+
 		It("Should deny creation when a capability is set that is not configured", func() {
 			obj = &v1alpha2.Paas{Spec: v1alpha2.PaasSpec{
 				Capabilities: v1alpha2.PaasCapabilities{"foo": v1alpha2.PaasCapability{}},
@@ -666,6 +892,92 @@ var _ = Describe("Paas Webhook", Ordered, func() {
 			warnings, _ := validator.ValidateUpdate(ctx, nil, obj)
 			Expect(warnings).To(BeEmpty())
 		})
+
+		It("Should only decrypt secrets which were changed", func() {
+			undecryptable := base64.StdEncoding.EncodeToString([]byte("foo bar baz"))
+			oldObj = &v1alpha2.Paas{
+				ObjectMeta: metav1.ObjectMeta{Name: paasName},
+				Spec: v1alpha2.PaasSpec{
+					Secrets: map[string]string{"unchanged": undecryptable},
+					Capabilities: v1alpha2.PaasCapabilities{
+						"cap5": v1alpha2.PaasCapability{Secrets: map[string]string{"unchanged": undecryptable}},
+					},
+				},
+			}
+			obj = oldObj.DeepCopy()
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+
+			obj.Spec.Secrets["changed"] = "foo bar baz"
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			var serr *apierrors.StatusError
+			Expect(errors.As(err, &serr)).To(BeTrue())
+			Expect(serr.Status().Details.Causes).To(ConsistOf(HaveField("Field", "spec.secrets[changed]")))
+		})
+	})
+
+	Context("When validations have an enforcement mode", func() {
+		BeforeEach(func() {
+			conf.Spec.Validations = v1alpha2.PaasConfigValidations{"paas": {"groupName": "^[a-z]+$"}}
+			conf.Spec.ValidationRules = v1alpha2.ConfigValidationRules{{
+				Resource:    "paas",
+				Rule:        `has(object.metadata.labels) && "costcenter" in object.metadata.labels`,
+				Message:     "Paas'es should have a costcenter label",
+				Enforcement: v1alpha2.EnforcementWarn,
+			}}
+			conf.Spec.ValidationEnforcement = v1alpha2.ConfigValidationEnforcement{
+				"paas.groupName": v1alpha2.EnforcementBlockOnCreateOnly,
+			}
+			config.SetConfig(conf)
+			obj = &v1alpha2.Paas{
+				ObjectMeta: metav1.ObjectMeta{Name: paasName},
+				Spec: v1alpha2.PaasSpec{
+					Groups: v1alpha2.PaasGroups{"group-1": v1alpha2.PaasGroup{Users: []string{"jdoe"}}},
+				},
+			}
+		})
+
+		It("Should block validations in block-on-create-only mode on create only", func() {
+			warn, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.groups[group-1]")))
+			Expect(warn).NotTo(ContainElement(ContainSubstring("paas.groupName")))
+
+			warn, err = validator.ValidateUpdate(ctx, obj, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warn).To(ContainElement(And(
+				ContainSubstring("spec.groups[group-1]"),
+				ContainSubstring("(validation paas.groupName is in block-on-create-only mode)"),
+			)))
+		})
+
+		It("Should only warn for validations in warn mode", func() {
+			obj.Spec.Groups = nil
+			warn, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warn).To(ContainElement("spec: Forbidden: Paas'es should have a costcenter label " +
+				"(validation validationRules[0] is in warn mode)"))
+
+			obj.Labels = map[string]string{"costcenter": "1234"}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeEmpty())
+		})
+
+		It("Should audit validations which do not block updates", func() {
+			conf.Spec.Validations["paas"]["requestor"] = "^[a-z]+$"
+			obj.Spec.Requestor = "invalid-requestor"
+
+			violations := AuditPaas(ctx, k8sClient, conf, obj, nil)
+			Expect(violations).To(HaveLen(2))
+			Expect(violations[0]).To(And(
+				HaveField("Kind", "Paas"),
+				HaveField("Name", paasName),
+				HaveField("Validation", "paas.groupName"),
+				HaveField("Enforcement", v1alpha2.EnforcementBlockOnCreateOnly),
+			))
+			Expect(violations[1]).To(And(
+				HaveField("Validation", "validationRules[0]"),
+				HaveField("Enforcement", v1alpha2.EnforcementWarn),
+				HaveField("Message", ContainSubstring("Paas'es should have a costcenter label")),
+			))
+		})
 	})
 })
 
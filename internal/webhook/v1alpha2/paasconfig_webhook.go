@@ -9,22 +9,31 @@ package v1alpha2
 import (
 	"context"
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v3/internal/logging"
 	"github.com/belastingdienst/opr-paas/v3/internal/templating"
 	"github.com/belastingdienst/opr-paas/v3/internal/validate"
+	admissionv1 "k8s.io/api/admission/v1"
 	k8sv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/yaml"
 )
 
 // SetupPaasConfigWebhookWithManager registers the webhook for PaasConfig in the manager.
@@ -55,6 +64,12 @@ type PaasConfigCustomValidator struct {
 
 var _ webhook.CustomValidator = &PaasConfigCustomValidator{}
 
+// NewPaasConfigCustomValidator returns a PaasConfigCustomValidator, so that webhooks for other versions of PaasConfig
+// can validate the v1alpha2 version.
+func NewPaasConfigCustomValidator(k8sClient client.Client) *PaasConfigCustomValidator {
+	return &PaasConfigCustomValidator{client: k8sClient}
+}
+
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type PaasConfig.
 func (v *PaasConfigCustomValidator) ValidateCreate(
 	ctx context.Context,
@@ -71,7 +86,7 @@ func (v *PaasConfigCustomValidator) ValidateCreate(
 	logger.Info().Msgf("validation for creation of PaasConfig %s", paasconfig.GetName())
 
 	// Deny creation from secondary or more PaasConfig resources
-	if warnings, flderr := validateNoPaasConfigExists(ctx, v.client); flderr != nil {
+	if warnings, flderr := validateNoPaasConfigExists(ctx, v.client, paasconfig.IsCandidate()); flderr != nil {
 		warn = append(warn, warnings...)
 		allErrs = append(allErrs, flderr...)
 		return warn, apierrors.NewInvalid(
@@ -82,7 +97,9 @@ func (v *PaasConfigCustomValidator) ValidateCreate(
 	}
 
 	// Ensure all required fields and values are there
-	if warnings, flderr := validatePaasConfigSpec(ctx, v.client, paasconfig.Spec); flderr != nil || len(warnings) > 0 {
+	if warnings, flderr := validatePaasConfigSpec(
+		ctx, v.client, paasconfig.Spec, admissionv1.Create,
+	); flderr != nil || len(warnings) > 0 {
 		warn = append(warn, warnings...)
 		allErrs = append(allErrs, flderr...)
 	}
@@ -113,13 +130,20 @@ func (v *PaasConfigCustomValidator) ValidateUpdate(
 	logger.Info().Msgf("validation for updating of PaasConfig %s", paasconfig.GetName())
 
 	// Ensure all required fields and values are there
-	if warnings, flderr := validatePaasConfigSpec(ctx, v.client, paasconfig.Spec); flderr != nil || len(warnings) > 0 {
+	if warnings, flderr := validatePaasConfigSpec(
+		ctx, v.client, paasconfig.Spec, admissionv1.Update,
+	); flderr != nil || len(warnings) > 0 {
 		warn = append(warn, warnings...)
 		allErrs = append(allErrs, flderr...)
 	}
 
 	// TODO(hikarukin): figure out what we need to check on update specifically
 	logger.Debug().Msgf("old PaasConfig: %v", oldObj.(*v1alpha2.PaasConfig))
+	if oldPaasConfig, isPaasConfig := oldObj.(*v1alpha2.PaasConfig); isPaasConfig &&
+		oldPaasConfig.IsCandidate() != paasconfig.IsCandidate() {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "candidate"),
+			"candidate cannot be added to or removed from an existing PaasConfig"))
+	}
 
 	if len(allErrs) > 0 {
 		return warn, apierrors.NewInvalid(
@@ -151,9 +175,11 @@ func (*PaasConfigCustomValidator) ValidateDelete(
 
 // ----- actual checks
 
+// validateNoPaasConfigExists ensures there is only one active PaasConfig, and at most one candidate PaasConfig
 func validateNoPaasConfigExists(
 	ctx context.Context,
 	k8sClient client.Client,
+	candidate bool,
 ) (warn admission.Warnings, allErrs field.ErrorList) {
 	ctx, logger := logging.GetLogComponent(ctx, logging.WebhookPaasConfigComponentV2)
 	childPath := field.NewPath("spec")
@@ -167,8 +193,13 @@ func validateNoPaasConfigExists(
 		return nil, allErrs
 	}
 
-	if len(list.Items) > 0 {
-		allErrs = append(allErrs, field.Forbidden(childPath, "another PaasConfig resource already exists"))
+	if slices.ContainsFunc(list.Items, func(cfg v1alpha2.PaasConfig) bool { return cfg.IsCandidate() == candidate }) {
+		if candidate {
+			allErrs = append(allErrs, field.Forbidden(childPath.Child("candidate"),
+				"another candidate PaasConfig resource already exists"))
+		} else {
+			allErrs = append(allErrs, field.Forbidden(childPath, "another PaasConfig resource already exists"))
+		}
 	}
 
 	return nil, allErrs
@@ -178,15 +209,31 @@ func validatePaasConfigSpec(
 	ctx context.Context,
 	k8sClient client.Client,
 	spec v1alpha2.PaasConfigSpec,
+	operation admissionv1.Operation,
 ) (warn admission.Warnings, allErrs field.ErrorList) {
 	ctx, logger := logging.GetLogComponent(ctx, logging.WebhookPaasConfigComponentV2)
 	childPath := field.NewPath("spec")
 
 	allErrs = append(allErrs, validateDecryptKeysSecretExists(ctx, k8sClient, spec.DecryptKeysSecret, childPath)...)
 	allErrs = append(allErrs, validateValidationFields(spec.Validations, childPath)...)
-	allErrs = append(allErrs, validateConfigCapabilityNames(spec, childPath)...)
+	allErrs = append(allErrs, validateValidationRules(spec.ValidationRules, childPath)...)
+	allErrs = append(allErrs, validateValidationEnforcement(spec.ValidationEnforcement, childPath)...)
+	// The capability name validation has no errors other than field errors
+	warn, capNameErrs, _ := runValidations([]validation{{
+		name: validationPaasConfigCapabilityName,
+		mode: spec.ValidationEnforcement.Mode(validationPaasConfigCapabilityName),
+		validate: func() ([]*field.Error, error) {
+			return validateConfigCapabilityNames(spec, childPath), nil
+		},
+	}}, operation)
+	allErrs = append(allErrs, capNameErrs...)
 	allErrs = append(allErrs, validateConfigCapabilities(spec.Capabilities, spec.Validations, childPath)...)
+	allErrs = append(allErrs, validateConfigCapabilityDependencies(spec.Capabilities, childPath)...)
 	allErrs = append(allErrs, validateTemplatingFields(spec.Templating, childPath)...)
+	allErrs = append(allErrs, validateGroupDefinitions(spec.GroupDefinitions, childPath)...)
+	allErrs = append(allErrs, validateNetworkPolicies(spec, childPath.Child("templating"))...)
+	allErrs = append(allErrs, validateExtraResources(k8sClient, spec, childPath.Child("templating"))...)
+	allErrs = append(allErrs, validateCandidate(spec.Candidate, childPath)...)
 
 	if len(allErrs) > 0 {
 		logger.Error().Strs(
@@ -198,6 +245,54 @@ func validatePaasConfigSpec(
 	return warn, allErrs
 }
 
+// validateCandidate ensures that a candidate PaasConfig has a valid and non-empty canary selector
+func validateCandidate(candidate *v1alpha2.ConfigCandidate, rootPath *field.Path) field.ErrorList {
+	if candidate == nil {
+		return nil
+	}
+	childPath := rootPath.Child("candidate", "canarySelector")
+	selector, err := metav1.LabelSelectorAsSelector(&candidate.CanarySelector)
+	if err != nil {
+		return field.ErrorList{field.Invalid(childPath, candidate.CanarySelector, err.Error())}
+	}
+	if selector.Empty() {
+		return field.ErrorList{field.Required(childPath, "canary selector should select a subset of all Paas'es")}
+	}
+	return nil
+}
+
+// validateGroupDefinitions ensures that every group definition has either a query or users, and that groups with a
+// query are named after the CN of the query (as that is the name the Group Sync Operator will use).
+func validateGroupDefinitions(definitions v1alpha2.ConfigGroupDefinitions, rootPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	childPath := rootPath.Child("groupDefinitions")
+
+	for name, definition := range definitions {
+		switch {
+		case definition.Query != "" && len(definition.Users) > 0:
+			allErrs = append(allErrs, field.Invalid(
+				childPath.Key(name),
+				name,
+				"query and users are mutually exclusive",
+			))
+		case definition.Query == "" && len(definition.Users) == 0:
+			allErrs = append(allErrs, field.Required(
+				childPath.Key(name),
+				"either query or users should be set",
+			))
+		case definition.Query != "" && definition.QueryGroupName(name) != name:
+			allErrs = append(allErrs, field.Invalid(
+				childPath.Key(name).Child("query"),
+				definition.Query,
+				fmt.Sprintf("group name derived from query (%s) should match the name of the group definition",
+					definition.QueryGroupName(name)),
+			))
+		}
+	}
+
+	return allErrs
+}
+
 func validateConfigCapabilities(
 	capabilities v1alpha2.ConfigCapabilities,
 	validations v1alpha2.PaasConfigValidations,
@@ -214,6 +309,52 @@ func validateConfigCapabilities(
 	return allErrs
 }
 
+// validateConfigCapabilityDependencies ensures that capabilities only require and conflict with configured
+// capabilities, that no capability both requires and conflicts with another capability, and that requirements
+// contain no cycles.
+func validateConfigCapabilityDependencies(
+	capabilities v1alpha2.ConfigCapabilities,
+	rootPath *field.Path,
+) field.ErrorList {
+	var allErrs field.ErrorList
+	childPath := rootPath.Child("capabilities")
+
+	names := make([]string, 0, len(capabilities))
+	for name := range capabilities {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		capability := capabilities[name]
+		for i, required := range capability.Requires {
+			requiresPath := childPath.Key(name).Child("requires").Index(i)
+			if _, exists := capabilities[required]; !exists {
+				allErrs = append(allErrs, field.NotFound(requiresPath, required))
+			} else if required == name {
+				allErrs = append(allErrs, field.Invalid(requiresPath, required, "capability cannot require itself"))
+			}
+		}
+		for i, conflicting := range capability.ConflictsWith {
+			conflictsPath := childPath.Key(name).Child("conflictsWith").Index(i)
+			switch {
+			case conflicting == name:
+				allErrs = append(allErrs, field.Invalid(conflictsPath, conflicting,
+					"capability cannot conflict with itself"))
+			case slices.Contains(capability.Requires, conflicting):
+				allErrs = append(allErrs, field.Invalid(conflictsPath, conflicting,
+					"capability cannot both require and conflict with the same capability"))
+			}
+			// Conflicting capabilities which are not configured are allowed, so that a capability can be removed
+			// from the PaasConfig without having to update all other capabilities at the same time.
+		}
+	}
+	if _, err := capabilities.DependencyOrder(names); err != nil {
+		allErrs = append(allErrs, field.Forbidden(childPath, err.Error()))
+	}
+
+	return allErrs
+}
+
 func validateConfigCapabilityNames(spec v1alpha2.PaasConfigSpec, rootPath *field.Path) field.ErrorList {
 	var validationRE *regexp.Regexp
 	childPath := rootPath.Child("capabilities")
@@ -248,10 +389,30 @@ func validateConfigCapability(name string, capability v1alpha2.ConfigCapability,
 	allErrs = append(allErrs, validateAllowedQuotas(capability.QuotaSettings, validations, childPath)...)
 	allErrs = append(allErrs, validateConfigQuotaSettings(capability.QuotaSettings, childPath)...)
 	allErrs = append(allErrs, validateConfigCustomFields(capability.CustomFields, childPath)...)
+	allErrs = append(allErrs, validateConfigCapabilityVersions(capability, childPath)...)
 
 	return allErrs
 }
 
+// validateConfigCapabilityVersions ensures that a capability with versions has a default version which is one of
+// the configured versions
+func validateConfigCapabilityVersions(capability v1alpha2.ConfigCapability, rootPath *field.Path) field.ErrorList {
+	childPath := rootPath.Child("defaultVersion")
+	switch {
+	case len(capability.Versions) == 0 && capability.DefaultVersion != "":
+		return field.ErrorList{field.Invalid(childPath, capability.DefaultVersion, "no versions are configured")}
+	case len(capability.Versions) == 0:
+		return nil
+	case capability.DefaultVersion == "":
+		return field.ErrorList{field.Required(childPath, "a default version is required when versions are configured")}
+	}
+	if _, exists := capability.Versions[capability.DefaultVersion]; !exists {
+		return field.ErrorList{field.NotSupported(childPath, capability.DefaultVersion,
+			slices.Sorted(maps.Keys(capability.Versions)))}
+	}
+	return nil
+}
+
 func validateConfigQuotaSettings(
 	qs v1alpha2.ConfigQuotaSettings,
 	rootPath *field.Path,
@@ -459,6 +620,30 @@ func validateConfigCustomField(
 		}
 	}
 
+	allErrs = append(allErrs, validateConfigCustomFieldSchema(customfield, childPath)...)
+
+	return allErrs
+}
+
+// validateConfigCustomFieldSchema ensures that the schema of a custom field is a valid schema, and that it is not
+// combined with options which only apply to string values
+func validateConfigCustomFieldSchema(customfield v1alpha2.ConfigCustomField, childPath *field.Path) field.ErrorList {
+	if customfield.Schema == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if customfield.Validation != "" {
+		allErrs = append(allErrs, field.Forbidden(childPath.Child("validation"),
+			"validation cannot be combined with a schema, use a pattern in the schema instead"))
+	}
+	if customfield.Default != "" {
+		allErrs = append(allErrs, field.Forbidden(childPath.Child("default"),
+			"default cannot be combined with a schema, use a default in the schema instead"))
+	}
+	if _, err := validate.CompileSchema(customfield.Schema.Raw); err != nil {
+		allErrs = append(allErrs, field.Invalid(childPath.Child("schema"), string(customfield.Schema.Raw),
+			err.Error()))
+	}
 	return allErrs
 }
 
@@ -470,10 +655,17 @@ func validateTemplatingFields(
 	childPath := rootPath.Child("templating")
 	for name, resourceType := range map[string]v1alpha2.ConfigTemplatingItem{
 		"genericCapabilityFields": templatingConfig.GenericCapabilityFields,
+		"paasLabels":              templatingConfig.PaasLabels,
 		"clusterQuotaLabels":      templatingConfig.ClusterQuotaLabels,
 		"groupLabels":             templatingConfig.GroupLabels,
 		"namespaceLabels":         templatingConfig.NamespaceLabels,
 		"roleBindingLabels":       templatingConfig.RoleBindingLabels,
+		"clusterQuotaAnnotations": templatingConfig.ClusterQuotaAnnotations,
+		"groupAnnotations":        templatingConfig.GroupAnnotations,
+		"namespaceAnnotations":    templatingConfig.NamespaceAnnotations,
+		"roleBindingAnnotations":  templatingConfig.RoleBindingAnnotations,
+		"secretLabels":            templatingConfig.SecretLabels,
+		"secretAnnotations":       templatingConfig.SecretAnnotations,
 	} {
 		allErrs = append(allErrs, validateTemplatingField(resourceType, childPath.Child(name))...)
 	}
@@ -500,6 +692,78 @@ func validateTemplatingField(
 	return allErrs
 }
 
+// validateNetworkPolicies ensures that all NetworkPolicy templates have a key which can be used in the name of a
+// NetworkPolicy, can be parsed, and render a valid NetworkPolicySpec (or nothing) for an example Paas.
+func validateNetworkPolicies(spec v1alpha2.PaasConfigSpec, rootPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	examplePaas := v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{Name: "example-paas"}}
+	templater := templating.NewTemplater(examplePaas, v1alpha2.PaasConfig{Spec: spec}).
+		WithNamespace("example-paas-example", "example")
+	for name, template := range spec.Templating.NetworkPolicies {
+		childPath := rootPath.Child("networkPolicies").Key(name)
+		for _, msg := range k8svalidation.IsDNS1123Label(name) {
+			allErrs = append(allErrs, field.Invalid(childPath, name, msg))
+		}
+		if err := templater.Verify(name, template); err != nil {
+			allErrs = append(allErrs, field.Invalid(childPath.Child("template"), template, err.Error()))
+			continue
+		}
+		rendered, err := templater.TemplateToString(name, template)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(childPath.Child("template"), template, err.Error()))
+			continue
+		} else if strings.TrimSpace(rendered) == "" {
+			continue
+		}
+		var npSpec networkingv1.NetworkPolicySpec
+		if err = yaml.UnmarshalStrict([]byte(rendered), &npSpec); err != nil {
+			allErrs = append(allErrs, field.Invalid(childPath.Child("template"), template,
+				fmt.Sprintf("does not render a valid NetworkPolicySpec: %s", err.Error())))
+		}
+	}
+	return allErrs
+}
+
+// validateExtraResources ensures that all extra resource templates can be parsed, and render a manifest of a known,
+// namespaced kind for an example Paas.
+func validateExtraResources(
+	k8sClient client.Client,
+	spec v1alpha2.PaasConfigSpec,
+	rootPath *field.Path,
+) field.ErrorList {
+	var allErrs field.ErrorList
+	examplePaas := v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{Name: "example-paas"}}
+	templater := templating.NewTemplater(examplePaas, v1alpha2.PaasConfig{Spec: spec})
+	for name, extraResource := range spec.Templating.ExtraResources {
+		childPath := rootPath.Child("extraResources").Key(name).Child("template")
+		if err := templater.Verify(name, extraResource.Template); err != nil {
+			allErrs = append(allErrs, field.Invalid(childPath, extraResource.Template, err.Error()))
+			continue
+		}
+		exampleTemplater := templater
+		if extraResource.Scope != v1alpha2.ExtraResourceScopePaas {
+			exampleTemplater = templater.WithNamespace("example-paas-example", "example")
+		}
+		obj, err := exampleTemplater.TemplateToUnstructured(name, extraResource.Template)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(childPath, extraResource.Template, err.Error()))
+			continue
+		} else if obj == nil {
+			continue
+		}
+		gvk := obj.GroupVersionKind()
+		mapping, err := k8sClient.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(childPath, extraResource.Template,
+				fmt.Sprintf("renders an unknown resource %s: %s", gvk.String(), err.Error())))
+		} else if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+			allErrs = append(allErrs, field.Invalid(childPath, extraResource.Template,
+				fmt.Sprintf("renders %s, which is not a namespaced resource", gvk.String())))
+		}
+	}
+	return allErrs
+}
+
 // validateDecryptKeysSecret ensures that the referenced Secret exists in the cluster.
 func validateDecryptKeysSecretExists(
 	ctx context.Context,
@@ -554,6 +818,35 @@ func validateValidationFields(
 	return allErrs
 }
 
+// validateValidationRules ensures that all CEL validation rules compile and evaluate to a bool
+func validateValidationRules(rules v1alpha2.ConfigValidationRules, rootPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	childPath := rootPath.Child("validationRules")
+	for i, rule := range rules {
+		if _, err := validate.CompileRule(rule.Rule); err != nil {
+			allErrs = append(allErrs, field.Invalid(childPath.Index(i).Child("rule"), rule.Rule, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// validateValidationEnforcement ensures that enforcement modes are only configured for existing validations, as a typo
+// would otherwise silently leave a validation in block mode
+func validateValidationEnforcement(
+	enforcement v1alpha2.ConfigValidationEnforcement,
+	rootPath *field.Path,
+) field.ErrorList {
+	var allErrs field.ErrorList
+	childPath := rootPath.Child("validationEnforcement")
+	names := validationNames()
+	for _, name := range slices.Sorted(maps.Keys(enforcement)) {
+		if !slices.Contains(names, name) {
+			allErrs = append(allErrs, field.NotSupported(childPath.Key(name), name, names))
+		}
+	}
+	return allErrs
+}
+
 // Convert field.ErrorList to a slice of strings for logging purposes
 func formatFieldErrors(allErrs field.ErrorList) []string {
 	var errs []string
@@ -16,7 +16,9 @@ import (
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -86,6 +88,21 @@ var _ = Describe("Creating a PaasConfig", Ordered, func() {
 				Expect(err.Error()).To(ContainSubstring(`failed to compile validation regexp for paas.groupName`))
 			})
 		})
+		Context("with CEL validation rules", func() {
+			It("should verify that the rules compile to a bool", func() {
+				obj.Spec.ValidationRules = v1alpha2.ConfigValidationRules{
+					{Resource: "paas", Rule: `object.spec.requestor != ""`},
+					{Resource: "paasNs", Rule: `object.metadata.name ==`},
+					{Resource: "paas", Rule: `size(object.spec.requestor)`},
+				}
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(err).Error().To(HaveOccurred())
+				Expect(err.Error()).NotTo(ContainSubstring("spec.validationRules[0]"))
+				Expect(err.Error()).To(ContainSubstring("spec.validationRules[1].rule: Invalid value"))
+				Expect(err.Error()).To(ContainSubstring("spec.validationRules[2].rule: Invalid value"))
+				Expect(err.Error()).To(ContainSubstring("should evaluate to a bool"))
+			})
+		})
 		Context("and a PaasConfig resource already exists", func() {
 			It("should deny creation", func() {
 				existing := &v1alpha2.PaasConfig{}
@@ -110,6 +127,44 @@ var _ = Describe("Creating a PaasConfig", Ordered, func() {
 					Equal(`PaasConfig.cpet.belastingdienst.nl "newPaasConfig" is invalid: spec: Forbidden: another PaasConfig resource already exists`))
 			})
 		})
+		Context("as candidate while an active PaasConfig resource already exists", func() {
+			It("should allow one candidate", func() {
+				scheme = runtime.NewScheme()
+				Expect(v1alpha2.AddToScheme(scheme)).To(Succeed())
+				active := &v1alpha2.PaasConfig{ObjectMeta: metav1.ObjectMeta{Name: "active"}}
+				candidate := &v1alpha2.PaasConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: "candidate"},
+					Spec: v1alpha2.PaasConfigSpec{Candidate: &v1alpha2.ConfigCandidate{
+						CanarySelector: metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+					}},
+				}
+				cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(active).Build()
+				_, flderr := validateNoPaasConfigExists(ctx, cl, true)
+				Expect(flderr).To(BeEmpty())
+
+				cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(active, candidate).Build()
+				_, flderr = validateNoPaasConfigExists(ctx, cl, true)
+				Expect(flderr).To(HaveLen(1))
+				Expect(flderr[0].Error()).To(ContainSubstring("another candidate PaasConfig resource already exists"))
+			})
+			It("should require a canary selector", func() {
+				obj.Spec.Candidate = &v1alpha2.ConfigCandidate{}
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(err).Error().To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("spec.candidate.canarySelector: Required value"))
+			})
+		})
+	})
+
+	When("updating a PaasConfig", func() {
+		It("should not allow to turn a PaasConfig into a candidate", func() {
+			obj.Spec.Candidate = &v1alpha2.ConfigCandidate{
+				CanarySelector: metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+			}
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).Error().To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("candidate cannot be added to or removed from"))
+		})
 	})
 
 	When("creating a new PaasConfig", func() {
@@ -138,6 +193,48 @@ var _ = Describe("Creating a PaasConfig", Ordered, func() {
 			})
 		})
 		Context("having a capability defined with a custom_field", func() {
+			It("should verify the schema of a custom field", func() {
+				tests := []struct {
+					name        string
+					customField v1alpha2.ConfigCustomField
+					errMsg      string
+				}{
+					{
+						name: "valid",
+						customField: v1alpha2.ConfigCustomField{Schema: &apiextensionsv1.JSON{
+							Raw: []byte(`{"type": "integer", "minimum": 1, "default": 2}`),
+						}},
+					},
+					{
+						name: "invalid default",
+						customField: v1alpha2.ConfigCustomField{Schema: &apiextensionsv1.JSON{
+							Raw: []byte(`{"type": "integer", "minimum": 1, "default": 0}`),
+						}},
+						errMsg: "invalid default in schema",
+					},
+					{
+						name: "with validation",
+						customField: v1alpha2.ConfigCustomField{
+							Validation: "^[0-9]+$",
+							Schema:     &apiextensionsv1.JSON{Raw: []byte(`{"type": "integer"}`)},
+						},
+						errMsg: "validation cannot be combined with a schema",
+					},
+				}
+				for _, test := range tests {
+					fmt.Fprintf(GinkgoWriter, "DEBUG - Test: %s", test.name)
+					obj.Spec.Capabilities = v1alpha2.ConfigCapabilities{
+						"argocd": {CustomFields: map[string]v1alpha2.ConfigCustomField{"replicas": test.customField}},
+					}
+					_, err := validator.ValidateCreate(ctx, obj)
+					if test.errMsg == "" {
+						Expect(err).Error().NotTo(HaveOccurred())
+					} else {
+						Expect(err).Error().To(HaveOccurred())
+						Expect(err.Error()).To(ContainSubstring(test.errMsg))
+					}
+				}
+			})
 			It("should verify Validation field to be valid and default to meet validation", func() {
 				tests := []struct {
 					re        string
@@ -256,6 +353,12 @@ var _ = Describe("Creating a PaasConfig", Ordered, func() {
 						NamespaceLabels:         v1alpha2.ConfigTemplatingItem{keyName: test.template},
 						ClusterQuotaLabels:      v1alpha2.ConfigTemplatingItem{keyName: test.template},
 						RoleBindingLabels:       v1alpha2.ConfigTemplatingItem{keyName: test.template},
+						ClusterQuotaAnnotations: v1alpha2.ConfigTemplatingItem{keyName: test.template},
+						GroupAnnotations:        v1alpha2.ConfigTemplatingItem{keyName: test.template},
+						NamespaceAnnotations:    v1alpha2.ConfigTemplatingItem{keyName: test.template},
+						RoleBindingAnnotations:  v1alpha2.ConfigTemplatingItem{keyName: test.template},
+						SecretLabels:            v1alpha2.ConfigTemplatingItem{keyName: test.template},
+						SecretAnnotations:       v1alpha2.ConfigTemplatingItem{keyName: test.template},
 					}
 					_, err := validator.ValidateCreate(ctx, obj)
 					if test.valid {
@@ -268,6 +371,12 @@ var _ = Describe("Creating a PaasConfig", Ordered, func() {
 							"groupLabels",
 							"namespaceLabels",
 							"roleBindingLabels",
+							"clusterQuotaAnnotations",
+							"groupAnnotations",
+							"namespaceAnnotations",
+							"roleBindingAnnotations",
+							"secretLabels",
+							"secretAnnotations",
 						} {
 							Expect(err.Error()).To(ContainSubstring(
 								`spec.templating.%s[%s].template: Invalid value: "%s": template: %s`,
@@ -281,6 +390,239 @@ var _ = Describe("Creating a PaasConfig", Ordered, func() {
 				}
 			})
 		})
+		Context("having group definitions defined", func() {
+			It("should verify query and users", func() {
+				tests := []struct {
+					name       string
+					definition v1alpha2.ConfigGroupDefinition
+					errMsg     string
+				}{
+					{name: "users", definition: v1alpha2.ConfigGroupDefinition{Users: []string{"user1"}}},
+					{name: "ldapgroup", definition: v1alpha2.ConfigGroupDefinition{Query: "CN=ldapgroup,DC=example"}},
+					{
+						name:       "empty",
+						definition: v1alpha2.ConfigGroupDefinition{},
+						errMsg:     "spec.groupDefinitions[empty]: Required value: either query or users should be set",
+					},
+					{
+						name: "both",
+						definition: v1alpha2.ConfigGroupDefinition{
+							Query: "CN=both,DC=example",
+							Users: []string{"user1"},
+						},
+						errMsg: `spec.groupDefinitions[both]: Invalid value: "both": query and users are mutually exclusive`,
+					},
+					{
+						name:       "othername",
+						definition: v1alpha2.ConfigGroupDefinition{Query: "CN=ldapgroup,DC=example"},
+						errMsg:     "group name derived from query (ldapgroup) should match the name of the group definition",
+					},
+				}
+				for _, test := range tests {
+					obj.Spec.GroupDefinitions = v1alpha2.ConfigGroupDefinitions{test.name: test.definition}
+					_, err := validator.ValidateCreate(ctx, obj)
+					if test.errMsg == "" {
+						Expect(err).Error().NotTo(HaveOccurred())
+					} else {
+						Expect(err).Error().To(HaveOccurred())
+						Expect(err.Error()).To(ContainSubstring(test.errMsg))
+					}
+				}
+			})
+		})
+		Context("having capability versions defined", func() {
+			It("should verify the default version", func() {
+				versions := map[string]v1alpha2.ConfigCapabilityVersion{"v1": {}, "v2": {}}
+				tests := []struct {
+					name       string
+					capability v1alpha2.ConfigCapability
+					errMsg     string
+				}{
+					{name: "valid", capability: v1alpha2.ConfigCapability{Versions: versions, DefaultVersion: "v2"}},
+					{
+						name:       "missing default",
+						capability: v1alpha2.ConfigCapability{Versions: versions},
+						errMsg:     "spec.capabilities[argocd].defaultVersion: Required value",
+					},
+					{
+						name:       "unknown default",
+						capability: v1alpha2.ConfigCapability{Versions: versions, DefaultVersion: "v3"},
+						errMsg:     `spec.capabilities[argocd].defaultVersion: Unsupported value: "v3"`,
+					},
+					{
+						name:       "default without versions",
+						capability: v1alpha2.ConfigCapability{DefaultVersion: "v1"},
+						errMsg:     "no versions are configured",
+					},
+				}
+				for _, test := range tests {
+					fmt.Fprintf(GinkgoWriter, "DEBUG - Test: %s", test.name)
+					obj.Spec.Capabilities = v1alpha2.ConfigCapabilities{"argocd": test.capability}
+					_, err := validator.ValidateCreate(ctx, obj)
+					if test.errMsg == "" {
+						Expect(err).Error().NotTo(HaveOccurred())
+					} else {
+						Expect(err).Error().To(HaveOccurred())
+						Expect(err.Error()).To(ContainSubstring(test.errMsg))
+					}
+				}
+			})
+		})
+		Context("having capability requirements and conflicts defined", func() {
+			It("should verify the references and detect cycles", func() {
+				tests := []struct {
+					name         string
+					capabilities v1alpha2.ConfigCapabilities
+					errMsg       string
+				}{
+					{
+						name: "valid",
+						capabilities: v1alpha2.ConfigCapabilities{
+							"argocd":     {},
+							"tekton":     {Requires: []string{"argocd"}},
+							"sso":        {ConflictsWith: []string{"sso-legacy", "not-configured"}},
+							"sso-legacy": {},
+						},
+					},
+					{
+						name:         "unknown requirement",
+						capabilities: v1alpha2.ConfigCapabilities{"tekton": {Requires: []string{"argocd"}}},
+						errMsg:       `spec.capabilities[tekton].requires[0]: Not found: "argocd"`,
+					},
+					{
+						name:         "requires itself",
+						capabilities: v1alpha2.ConfigCapabilities{"tekton": {Requires: []string{"tekton"}}},
+						errMsg:       "capability cannot require itself",
+					},
+					{
+						name: "requires and conflicts",
+						capabilities: v1alpha2.ConfigCapabilities{
+							"argocd": {},
+							"tekton": {Requires: []string{"argocd"}, ConflictsWith: []string{"argocd"}},
+						},
+						errMsg: "capability cannot both require and conflict with the same capability",
+					},
+					{
+						name: "cycle",
+						capabilities: v1alpha2.ConfigCapabilities{
+							"argocd": {Requires: []string{"tekton"}},
+							"tekton": {Requires: []string{"argocd"}},
+						},
+						errMsg: "capability requirements contain a cycle: argocd -> tekton -> argocd",
+					},
+				}
+				for _, test := range tests {
+					fmt.Fprintf(GinkgoWriter, "DEBUG - Test: %s", test.name)
+					obj.Spec.Capabilities = test.capabilities
+					_, err := validator.ValidateCreate(ctx, obj)
+					if test.errMsg == "" {
+						Expect(err).Error().NotTo(HaveOccurred())
+					} else {
+						Expect(err).Error().To(HaveOccurred())
+						Expect(err.Error()).To(ContainSubstring(test.errMsg))
+					}
+				}
+			})
+		})
+		Context("having network policies defined", func() {
+			It("should verify that templates render a NetworkPolicySpec", func() {
+				tests := []struct {
+					name     string
+					template string
+					errMsg   string
+				}{
+					{name: "deny-all", template: "podSelector: {}\npolicyTypes: [Ingress]\n"},
+					{name: "empty", template: `{{ if eq .Namespace.Capability "other" }}podSelector: {}{{ end }}`},
+					{
+						name:     "Invalid_Key",
+						template: "podSelector: {}",
+						errMsg:   "spec.templating.networkPolicies[Invalid_Key]: Invalid value",
+					},
+					{
+						name:     "unparsable",
+						template: "{{ .Paas.Name }",
+						errMsg:   "spec.templating.networkPolicies[unparsable].template: Invalid value",
+					},
+					{
+						name:     "string",
+						template: "{{ .Paas.Name }}",
+						errMsg:   "does not render a valid NetworkPolicySpec",
+					},
+					{
+						name:     "unknown-field",
+						template: "podSelector: {}\ndoesNotExist: true\n",
+						errMsg:   "does not render a valid NetworkPolicySpec",
+					},
+				}
+				for _, test := range tests {
+					obj.Spec.Templating.NetworkPolicies = v1alpha2.ConfigTemplatingItem{test.name: test.template}
+					_, err := validator.ValidateCreate(ctx, obj)
+					if test.errMsg == "" {
+						Expect(err).Error().NotTo(HaveOccurred())
+					} else {
+						Expect(err).Error().To(HaveOccurred())
+						Expect(err.Error()).To(ContainSubstring(test.errMsg))
+					}
+				}
+				obj.Spec.Templating.NetworkPolicies = nil
+			})
+		})
+		Context("having extra resources defined", func() {
+			It("should verify that templates render a namespaced manifest", func() {
+				tests := []struct {
+					name     string
+					resource v1alpha2.ConfigExtraResource
+					errMsg   string
+				}{
+					{
+						name: "configmap",
+						resource: v1alpha2.ConfigExtraResource{
+							Template: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Paas.Name }}\n",
+						},
+					},
+					{
+						name: "empty",
+						resource: v1alpha2.ConfigExtraResource{
+							Template: `{{ if eq .Namespace.Capability "other" }}kind: ConfigMap{{ end }}`,
+						},
+					},
+					{
+						name:     "unparsable",
+						resource: v1alpha2.ConfigExtraResource{Template: "{{ .Paas.Name }"},
+						errMsg:   "spec.templating.extraResources[unparsable].template: Invalid value",
+					},
+					{
+						name:     "incomplete",
+						resource: v1alpha2.ConfigExtraResource{Template: "kind: ConfigMap"},
+						errMsg:   "should render a manifest with apiVersion, kind and metadata.name",
+					},
+					{
+						name: "unknown",
+						resource: v1alpha2.ConfigExtraResource{
+							Template: "apiVersion: example.com/v1\nkind: DoesNotExist\nmetadata:\n  name: x\n",
+						},
+						errMsg: "renders an unknown resource",
+					},
+					{
+						name: "clusterscoped",
+						resource: v1alpha2.ConfigExtraResource{
+							Template: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: x\n",
+						},
+						errMsg: "which is not a namespaced resource",
+					},
+				}
+				for _, test := range tests {
+					obj.Spec.Templating.ExtraResources = v1alpha2.ConfigExtraResources{test.name: test.resource}
+					_, err := validator.ValidateCreate(ctx, obj)
+					if test.errMsg == "" {
+						Expect(err).Error().NotTo(HaveOccurred())
+					} else {
+						Expect(err).Error().To(HaveOccurred())
+						Expect(err.Error()).To(ContainSubstring(test.errMsg))
+					}
+				}
+			})
+		})
 		Context("quota name validation", func() {
 			var (
 				validResourceKeys = []string{
@@ -448,6 +790,44 @@ var _ = Describe("Updating a PaasConfig", Ordered, func() {
 					ContainSubstring(`failed to compile validation regexp for paas.groupName`))
 			})
 		})
+		Context("with validation enforcement modes", func() {
+			It("should only warn for capability names in block-on-create-only mode", func() {
+				obj.Spec.Capabilities = v1alpha2.ConfigCapabilities{"invalid-name": v1alpha2.ConfigCapability{
+					AppSet: "my-appset",
+					QuotaSettings: v1alpha2.ConfigQuotaSettings{
+						DefQuota: map[corev1.ResourceName]resourcev1.Quantity{
+							corev1.ResourceCPU: resourcev1.MustParse("5000m"),
+						},
+					},
+				}}
+				obj.Spec.Validations["paasConfig"] = map[string]string{"capabilityName": "^[a-z]+$"}
+				obj.Spec.ValidationEnforcement = v1alpha2.ConfigValidationEnforcement{
+					"paasConfig.capabilityName": v1alpha2.EnforcementBlockOnCreateOnly,
+				}
+
+				_, errs := validatePaasConfigSpec(ctx, k8sClient, obj.Spec, admissionv1.Create)
+				Expect(errs.ToAggregate()).To(MatchError(ContainSubstring("spec.capabilities[invalid-name]")))
+
+				warn, err := validator.ValidateUpdate(ctx, oldObj, obj)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(warn).To(ConsistOf(And(
+					ContainSubstring("spec.capabilities[invalid-name]"),
+					ContainSubstring("(validation paasConfig.capabilityName is in block-on-create-only mode)"),
+				)))
+			})
+			It("should deny enforcement modes for unknown validations", func() {
+				obj.Spec.ValidationEnforcement = v1alpha2.ConfigValidationEnforcement{
+					"paas.groupName":  v1alpha2.EnforcementWarn,
+					"paas.groupNames": v1alpha2.EnforcementWarn,
+				}
+
+				_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+				Expect(err).To(MatchError(And(
+					ContainSubstring(`spec.validationEnforcement[paas.groupNames]: Unsupported value`),
+					Not(ContainSubstring("spec.validationEnforcement[paas.groupName]:")),
+				)))
+			})
+		})
 	})
 })
 
@@ -0,0 +1,68 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/config"
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+//revive:disable:line-length-limit
+
+// +kubebuilder:webhook:path=/mutate-cpet-belastingdienst-nl-v1alpha2-paasns,mutating=true,failurePolicy=fail,sideEffects=None,groups=cpet.belastingdienst.nl,resources=paasns,verbs=create;update,versions=v1alpha2,name=mpaasns-v1alpha2.kb.io,admissionReviewVersions=v1
+
+//revive:enable:line-length-limit
+
+// PaasNSCustomDefaulter struct is responsible for writing the defaults from the PaasConfig into the PaasNS resource
+// when it is created or updated, when the defaults feature flag in the PaasConfig is set to materialize.
+// +kubebuilder:object:generate=false
+type PaasNSCustomDefaulter struct {
+	client client.Client
+}
+
+var _ webhook.CustomDefaulter = &PaasNSCustomDefaulter{}
+
+// NewPaasNSCustomDefaulter returns a PaasNSCustomDefaulter, so that webhooks for other versions of PaasNS can apply
+// the defaults to the v1alpha2 version.
+func NewPaasNSCustomDefaulter(k8sClient client.Client) *PaasNSCustomDefaulter {
+	return &PaasNSCustomDefaulter{client: k8sClient}
+}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type PaasNS.
+func (d *PaasNSCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	paasns, ok := obj.(*v1alpha2.PaasNS)
+	if !ok {
+		return fmt.Errorf("expected a PaasNS object but got %T", obj)
+	}
+	ctx, _ = logging.SetWebhookLogger(ctx, paasns)
+	ctx, logger := logging.GetLogComponent(ctx, logging.WebhookPaasNSComponentV2)
+	if paasns.GetDeletionTimestamp() != nil {
+		return nil
+	}
+	paas, err := paasNStoPaas(ctx, d.client, paasns)
+	if err != nil {
+		// The validating webhook rejects a PaasNS without a Paas
+		logger.Info().Msgf("not applying defaults: %s", err.Error())
+		return nil
+	}
+	conf, err := config.GetConfigForPaasWithError(paas)
+	if err != nil {
+		logger.Info().Msgf("not applying defaults: %s", err.Error())
+		return nil
+	} else if !conf.Spec.FeatureFlags.MaterializeDefaults() {
+		return nil
+	}
+	logger.Info().Msg("applying defaults")
+	return defaultLabels(conf, *paas, paasns)
+}
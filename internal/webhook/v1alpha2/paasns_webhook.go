@@ -11,13 +11,14 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"slices"
 	"strings"
 
+	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/belastingdienst/opr-paas-crypttool/pkg/crypt"
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
-	"github.com/belastingdienst/opr-paas/v3/internal/config"
 	"github.com/belastingdienst/opr-paas/v3/internal/logging"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -27,10 +28,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
-// SetupPaasNsWebhookWithManager registers the webhook for PaasNs in the manager.
+// SetupPaasNsWebhookWithManager registers the webhooks for PaasNs in the manager.
 func SetupPaasNsWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).For(&v1alpha2.PaasNS{}).
 		WithValidator(&PaasNSCustomValidator{client: mgr.GetClient()}).
+		WithDefaulter(&PaasNSCustomDefaulter{client: mgr.GetClient()}).
 		Complete()
 }
 
@@ -52,14 +54,14 @@ type paasNsSpecValidator func(
 	v1alpha2.PaasConfig,
 	v1alpha2.Paas,
 	v1alpha2.PaasNS,
+	validatedSecrets,
 ) ([]*field.Error, error)
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type PaasNS.
 func (v *PaasNSCustomValidator) ValidateCreate(
 	ctx context.Context,
 	obj runtime.Object,
-) (w admission.Warnings, err error) {
-	var errs field.ErrorList
+) (admission.Warnings, error) {
 	paasns, ok := obj.(*v1alpha2.PaasNS)
 	ctx, _ = logging.SetWebhookLogger(ctx, paasns)
 	ctx, logger := logging.GetLogComponent(ctx, logging.WebhookPaasNSComponentV2)
@@ -72,44 +74,7 @@ func (v *PaasNSCustomValidator) ValidateCreate(
 		}
 	}
 
-	paas, err := paasNStoPaas(ctx, v.client, paasns)
-	if err != nil {
-		// code to Err when the referenced Paas does not exist
-		errs = append(errs, &field.Error{
-			Type:     field.ErrorTypeTypeInvalid,
-			Field:    field.NewPath("spec").Child("paas").String(),
-			BadValue: paasns.Spec.Paas,
-			Detail:   err.Error(),
-		})
-		return w, errs.ToAggregate()
-	}
-
-	myConfig, err := config.GetConfigWithError()
-	if err != nil {
-		errs = append(errs, field.InternalError(
-			field.NewPath("paasconfig"),
-			fmt.Errorf("unable to retrieve paasconfig: %s", err),
-		))
-	}
-
-	for _, validator := range []paasNsSpecValidator{
-		validatePaasNsName,
-		validatePaasNsGroups,
-		validatePaasNsSecrets,
-	} {
-		var fieldErrs []*field.Error
-		fieldErrs, err = validator(ctx, v.client, *myConfig, *paas, *paasns)
-		if err != nil {
-			return nil, err
-		}
-		errs = append(errs, fieldErrs...)
-	}
-
-	if len(errs) == 0 {
-		return nil, nil
-	}
-
-	return w, errs.ToAggregate()
+	return ValidatePaasNS(ctx, v.client, paasns, nil, admissionv1.Create, nil)
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type PaasNS.
@@ -117,8 +82,7 @@ func (v *PaasNSCustomValidator) ValidateUpdate(
 	ctx context.Context,
 	oldObj,
 	newObj runtime.Object,
-) (w admission.Warnings, err error) {
-	var errs field.ErrorList
+) (admission.Warnings, error) {
 	oldPaasns, ok := oldObj.(*v1alpha2.PaasNS)
 	if !ok {
 		return nil, &field.Error{
@@ -144,26 +108,7 @@ func (v *PaasNSCustomValidator) ValidateUpdate(
 	}
 	logger.Info().Msg("starting validation webhook for update")
 
-	// This will not occur.
-	paas, _ := paasNStoPaas(ctx, v.client, newPaasns)
-
-	for _, validator := range []paasNsSpecValidator{
-		validatePaasNsGroups,
-		validatePaasNsSecrets,
-	} {
-		myConfig := config.GetConfig()
-		var fieldErrs []*field.Error
-		fieldErrs, err = validator(ctx, v.client, myConfig, *paas, *newPaasns)
-		if err != nil {
-			return nil, err
-		}
-		errs = append(errs, fieldErrs...)
-	}
-
-	if len(errs) > 0 {
-		return w, errs.ToAggregate()
-	}
-	return w, nil
+	return ValidatePaasNS(ctx, v.client, newPaasns, oldPaasns, admissionv1.Update, nil)
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type PaasNS.
@@ -178,6 +123,7 @@ func validatePaasNsName(
 	conf v1alpha2.PaasConfig,
 	_ v1alpha2.Paas,
 	paasns v1alpha2.PaasNS,
+	_ validatedSecrets,
 ) ([]*field.Error, error) {
 	var fieldErrors []*field.Error
 	if strings.Contains(paasns.Name, ".") {
@@ -210,11 +156,12 @@ func validatePaasNsGroups(
 	_ v1alpha2.PaasConfig,
 	paas v1alpha2.Paas,
 	paasns v1alpha2.PaasNS,
+	_ validatedSecrets,
 ) ([]*field.Error, error) {
 	var errs []*field.Error
-	superGroups := maps.Keys(paas.Spec.Groups)
+	superGroups := slices.Sorted(maps.Keys(paas.Spec.Groups))
 	uqSuperGroups := map[string]bool{}
-	for group := range superGroups {
+	for _, group := range superGroups {
 		uqSuperGroups[group] = true
 	}
 	// Err when the optional groupKey(s) don't exist in referenced Paas
@@ -231,36 +178,21 @@ func validatePaasNsGroups(
 	return errs, nil
 }
 
+// validatePaasNsSecrets returns an error for every secret of the PaasNS which cannot be decrypted, skipping the
+// secrets which were validated before
 func validatePaasNsSecrets(
 	ctx context.Context,
 	k8sClient client.Client,
 	conf v1alpha2.PaasConfig,
 	paas v1alpha2.Paas,
 	paasns v1alpha2.PaasNS,
+	validated validatedSecrets,
 ) ([]*field.Error, error) {
-	decryptRes := &corev1.Secret{}
-	if err := k8sClient.Get(ctx, types.NamespacedName{
-		Name:      conf.Spec.DecryptKeysSecret.Name,
-		Namespace: conf.Spec.DecryptKeysSecret.Namespace,
-	}, decryptRes); err != nil {
-		return nil, fmt.Errorf("could not retrieve decryption secret: %w", err)
+	getRsaFunc := func() (*crypt.Crypt, error) {
+		return getCryptInstance(ctx, k8sClient, conf, paas.Name)
 	}
 
-	keys, _ := crypt.NewPrivateKeysFromSecretData(decryptRes.Data)
-	rsa, _ := crypt.NewCryptFromKeys(keys, "", paas.Name)
-
-	var errs []*field.Error
-	for name, secret := range paasns.Spec.Secrets {
-		if _, err := rsa.Decrypt(secret); err != nil {
-			errs = append(errs, field.Invalid(
-				field.NewPath("spec").Child("secrets"),
-				name,
-				fmt.Sprintf("cannot be decrypted: %s", err),
-			))
-		}
-	}
-
-	return errs, nil
+	return validated.compareSecrets(paasns.Spec.Secrets, getRsaFunc, field.NewPath("spec").Child("secrets"))
 }
 
 func paasNStoPaas(ctx context.Context, c client.Client, paasns *v1alpha2.PaasNS) (paas *v1alpha2.Paas, err error) {
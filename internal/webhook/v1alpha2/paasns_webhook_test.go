@@ -266,7 +266,95 @@ var _ = Describe("PaasNS Webhook", Ordered, func() {
 			Expect(warn, err).Error().To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("unable to decrypt data with any of the private keys"))
 		})
+		It("Should allow updating when the secret did not change", func() {
+			By("updating a PaasNs of which the sshSecret was validated before")
+
+			var err error
+			var invalidSecret1 string
+			mycrypt, privateKey, err = newGeneratedCrypt(paasName)
+			Expect(err).ToNot(HaveOccurred())
+			invalidSecret1, err = mycrypt.Encrypt([]byte("paasns_secret"))
+			Expect(err).ToNot(HaveOccurred())
+			oldObj.Spec.Secrets["invalidSecret1"] = invalidSecret1
+			obj.Spec.Secrets["invalidSecret1"] = invalidSecret1
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().ToNot(HaveOccurred())
+		})
+	})
+	Context("When creating or updating PaasNS with CEL validation rules", func() {
+		BeforeEach(func() {
+			conf.Spec.ValidationRules = v1alpha2.ConfigValidationRules{
+				{
+					Resource: "paasNs",
+					Rule:     `object.metadata.name != paas.metadata.name`,
+					Message:  "paasns should not be named after its paas",
+				},
+				{Resource: "paas", Rule: `false`},
+			}
+			config.SetConfig(conf)
+		})
+		It("Should allow creation and updating when the rules are met", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+		})
+		It("Should deny creation and updating when a rule is not met", func() {
+			obj.Name = paasName
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(
+				ContainSubstring("paasns should not be named after its paas")))
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().To(MatchError(
+				ContainSubstring("paasns should not be named after its paas")))
+		})
 	})
+
+	Context("When creating or updating PaasNS with validations that have an enforcement mode", func() {
+		BeforeEach(func() {
+			conf.Spec.Validations = v1alpha2.PaasConfigValidations{"paasNs": {"name": "^[a-z]+-[a-z]+$"}}
+			conf.Spec.ValidationEnforcement = v1alpha2.ConfigValidationEnforcement{
+				"paasNs.name":   v1alpha2.EnforcementWarn,
+				"paasNs.groups": v1alpha2.EnforcementBlockOnCreateOnly,
+			}
+			config.SetConfig(conf)
+		})
+		It("Should only warn for validations in warn mode", func() {
+			warn, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warn).To(ConsistOf(And(
+				ContainSubstring("paasns name does not match configured validation regex"),
+				ContainSubstring("(validation paasNs.name is in warn mode)"),
+			)))
+		})
+		It("Should block validations in block-on-create-only mode on create only", func() {
+			obj.Name = "my-paasns"
+			obj.Spec.Groups = []string{otherGroup}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(
+				ContainSubstring("group %s does not exist in paas", otherGroup)))
+
+			warn, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warn).To(ConsistOf(And(
+				ContainSubstring("group %s does not exist in paas", otherGroup),
+				ContainSubstring("(validation paasNs.groups is in block-on-create-only mode)"),
+			)))
+		})
+		It("Should audit validations which do not block updates", func() {
+			obj.Spec.Groups = []string{otherGroup}
+			violations := AuditPaasNS(ctx, validator.client, conf, obj, nil)
+			Expect(violations).To(ConsistOf(
+				And(
+					HaveField("Kind", "PaasNS"),
+					HaveField("Name", paasNsName),
+					HaveField("Namespace", paasName),
+					HaveField("Validation", "paasNs.name"),
+					HaveField("Enforcement", v1alpha2.EnforcementWarn),
+				),
+				HaveField("Validation", "paasNs.groups"),
+			))
+
+			By("skipping PaasNSs which do not belong to a Paas")
+			obj.Namespace = nsWithoutOwnerRef
+			Expect(AuditPaasNS(ctx, validator.client, conf, obj, nil)).To(BeEmpty())
+		})
+	})
+
 	Context("When deleting a PaasNs, ", func() {
 		It("Update webhook should not fail", func() {
 			By("checking deletion timestamp")
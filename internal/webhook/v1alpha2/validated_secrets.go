@@ -1,11 +1,12 @@
-package v1alpha1
+package v1alpha2
 
 import (
 	"crypto/sha512"
 	"fmt"
+	"sync"
 
 	"github.com/belastingdienst/opr-paas-crypttool/pkg/crypt"
-	"github.com/belastingdienst/opr-paas/v3/api/v1alpha1"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -22,28 +23,28 @@ type validatedSecrets struct {
 }
 
 // appendFromPaas appends validated secrets from a Paas
-func (vs *validatedSecrets) appendFromPaas(paas v1alpha1.Paas) {
+func (vs *validatedSecrets) appendFromPaas(paas v1alpha2.Paas) {
 	if vs.v == nil {
 		vs.v = map[validatedHash]bool{}
 	}
-	for _, secret := range paas.Spec.SSHSecrets {
+	for _, secret := range paas.Spec.Secrets {
 		hash := hashFromString(secret)
 		vs.v[hash] = true
 	}
 	for _, capability := range paas.Spec.Capabilities {
-		for _, secret := range capability.SSHSecrets {
-			hash := sha512.Sum512([]byte(secret))
+		for _, secret := range capability.Secrets {
+			hash := hashFromString(secret)
 			vs.v[hash] = true
 		}
 	}
 }
 
 // appendFromPaasNS appends validated secrets from a PaasNS
-func (vs *validatedSecrets) appendFromPaasNS(paasns v1alpha1.PaasNS) {
+func (vs *validatedSecrets) appendFromPaasNS(paasns v1alpha2.PaasNS) {
 	if vs.v == nil {
 		vs.v = make(map[validatedHash]bool)
 	}
-	for _, secret := range paasns.Spec.SSHSecrets {
+	for _, secret := range paasns.Spec.Secrets {
 		hash := hashFromString(secret)
 		vs.v[hash] = true
 	}
@@ -55,32 +56,36 @@ func (vs *validatedSecrets) Is(hash validatedHash) bool {
 	return exists
 }
 
-// compareSecrets can check all secrets from a PaasNS.
+// compareSecrets can check all secrets from a Paas, capability or PaasNS.
 // It first checks against the secrets in the validated struct,
-// and if not present in there it uses the getRsaFunc to get a crypt and try decrypting the secret
+// and if not present in there it uses the getRsaFunc to get a crypt and try decrypting the secret.
+// Secrets which can be decrypted are added to the validated struct (when its map is initialized).
+// The crypt is only retrieved (once) when there are secrets which are not validated yet, and an error is returned when
+// it cannot be retrieved. Errors report the key of a secret, and never its (possibly unencrypted) value.
 func (vs validatedSecrets) compareSecrets(
 	unvalidated map[string]string,
 	getRsaFunc func() (*crypt.Crypt, error),
-) (errs field.ErrorList) {
-	// Err when an sshSecret can't be decrypted
+	basePath *field.Path,
+) (errs field.ErrorList, err error) {
+	getRsa := sync.OnceValues(getRsaFunc)
 	for secretName, secret := range unvalidated {
-		if vs.Is(hashFromString(secret)) {
+		hash := hashFromString(secret)
+		if vs.Is(hash) {
 			continue
 		}
-		if cryptObj, err := getRsaFunc(); err != nil {
-			errs = append(errs, &field.Error{
-				Type:   field.ErrorTypeInvalid,
-				Field:  field.NewPath("spec").Child("sshSecrets").Key(secretName).String(),
-				Detail: fmt.Errorf("failed to get crypt: %w", err).Error(),
-			})
-		} else if _, err = cryptObj.Decrypt(secret); err != nil {
-			errs = append(errs, &field.Error{
-				Type:     field.ErrorTypeInvalid,
-				Field:    field.NewPath("spec").Child("sshSecrets").Key(secretName).String(),
-				BadValue: secret,
-				Detail:   fmt.Errorf("failed to decrypt secret: %w", err).Error(),
-			})
+		cryptObj, rsaErr := getRsa()
+		if rsaErr != nil {
+			return nil, fmt.Errorf("failed to get crypt: %w", rsaErr)
+		}
+		if _, err = cryptObj.Decrypt(secret); err != nil {
+			errs = append(errs, field.Invalid(
+				basePath.Key(secretName),
+				secretName,
+				fmt.Sprintf("cannot be decrypted: %s", err),
+			))
+		} else if vs.v != nil {
+			vs.v[hash] = true
 		}
 	}
-	return errs
+	return errs, nil
 }
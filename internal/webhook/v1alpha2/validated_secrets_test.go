@@ -0,0 +1,175 @@
+package v1alpha2
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/belastingdienst/opr-paas-crypttool/pkg/crypt"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	paasSecret1      = "paasSecret1"
+	paasSecret2      = "paasSecret2"
+	cap1secret1      = "cap1secret1"
+	cap1secret2      = "cap1secret2"
+	cap2secret1      = "cap2secret1"
+	cap2secret2      = "cap2secret2"
+	duplicatedSecret = "duplicatedSecret"
+)
+
+func TestValidatedSecretsFromPaas(t *testing.T) {
+	validated := validatedSecrets{}
+	paas := v1alpha2.Paas{
+		Spec: v1alpha2.PaasSpec{
+			Secrets: map[string]string{
+				paasSecret1:      paasSecret1,
+				paasSecret2:      paasSecret2,
+				duplicatedSecret: paasSecret2,
+			},
+			Capabilities: v1alpha2.PaasCapabilities{
+				"cap1": v1alpha2.PaasCapability{
+					Secrets: map[string]string{
+						paasSecret1: cap1secret1,
+						paasSecret2: cap1secret2,
+					},
+				},
+				"cap2": v1alpha2.PaasCapability{
+					Secrets: map[string]string{
+						paasSecret1:            cap2secret1,
+						paasSecret2:            cap2secret2,
+						duplicatedSecret:       paasSecret2,
+						"duplicatedPaasSecret": paasSecret2,
+						"duplicatedCap1Secret": cap1secret2,
+						"duplicatedCap2Secret": cap2secret2,
+					},
+				},
+			},
+		},
+	}
+	validated.appendFromPaas(paas)
+	assert.Len(t, validated.v, 6, "duplicated keys should be there only once")
+	for _, secret := range []string{paasSecret1, paasSecret2, cap1secret1, cap1secret2, cap2secret1, cap2secret2} {
+		assert.True(t, validated.Is(hashFromString(secret)), "secret '%s' should be validated", secret)
+	}
+	assert.False(t, validated.Is(hashFromString("invalid")), "secret 'invalid' should not be validated")
+}
+
+func TestValidatedSecretsFromPaasNS(t *testing.T) {
+	validated := validatedSecrets{}
+	paasns := v1alpha2.PaasNS{
+		Spec: v1alpha2.PaasNSSpec{
+			Secrets: map[string]string{
+				paasSecret1:        paasSecret1,
+				paasSecret2:        paasSecret2,
+				"duplicatedSecret": paasSecret2,
+			},
+		},
+	}
+	validated.appendFromPaasNS(paasns)
+	assert.Len(t, validated.v, 2, "duplicated keys should be there only once")
+	for _, secret := range []string{paasSecret1, paasSecret2} {
+		assert.True(t, validated.Is(hashFromString(secret)), "secret '%s' should be validated", secret)
+	}
+	assert.False(t, validated.Is(hashFromString("invalid")), "secret 'invalid' should not be validated")
+}
+
+// When the passed getRsaFunc fails, compareSecrets should return an error
+func TestValidatedSecretsCompareRsaError(t *testing.T) {
+	unvalidated := map[string]string{
+		paasSecret1: cap1secret1,
+		paasSecret2: cap1secret2,
+	}
+	calls := 0
+	rsaFn := func() (*crypt.Crypt, error) {
+		calls++
+		return nil, errors.New("crypt failure")
+	}
+	errs, err := validatedSecrets{}.compareSecrets(unvalidated, rsaFn, field.NewPath("spec", "secrets"))
+
+	assert.Empty(t, errs)
+	assert.EqualError(t, err, "failed to get crypt: crypt failure")
+	assert.Equal(t, 1, calls, "the crypt should only be retrieved once")
+}
+
+// Secrets which are already validated should not be decrypted, and the crypt should only be retrieved when needed
+func TestValidatedSecretsCompareSkipsValidated(t *testing.T) {
+	c, _, err := newGeneratedCrypt("my-paas")
+	require.NoError(t, err)
+	encrypted, err := c.Encrypt([]byte("some encrypted string"))
+	require.NoError(t, err)
+
+	var validated validatedSecrets
+	validated.appendFromPaasNS(v1alpha2.PaasNS{Spec: v1alpha2.PaasNSSpec{
+		Secrets: map[string]string{paasSecret1: "not encrypted but already validated"},
+	}})
+	calls := 0
+	rsaFn := func() (*crypt.Crypt, error) {
+		calls++
+		return c, nil
+	}
+	basePath := field.NewPath("spec", "secrets")
+
+	errs, err := validated.compareSecrets(
+		map[string]string{paasSecret1: "not encrypted but already validated"}, rsaFn, basePath)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+	assert.Zero(t, calls, "the crypt should not be retrieved when all secrets are validated")
+
+	errs, err = validated.compareSecrets(map[string]string{
+		paasSecret1: "not encrypted but already validated",
+		paasSecret2: encrypted,
+		cap1secret1: "invalid",
+	}, rsaFn, basePath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	require.Len(t, errs, 1)
+	assert.Equal(t, field.ErrorTypeInvalid, errs[0].Type)
+	assert.Equal(t, "spec.secrets[cap1secret1]", errs[0].Field)
+	assert.Equal(t, cap1secret1, errs[0].BadValue, "the value of a secret should not be reported")
+	assert.True(t, validated.Is(hashFromString(encrypted)), "decrypted secrets should be added to validated")
+	assert.False(t, validated.Is(hashFromString("invalid")))
+}
+
+// Secrets which were decrypted in an earlier audit should not be decrypted again, unless the decrypt keys changed
+func TestAuditSecrets(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	keys := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "keys", Namespace: "paas-system"}}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(keys).Build()
+	conf := v1alpha2.PaasConfig{Spec: v1alpha2.PaasConfigSpec{
+		DecryptKeysSecret: v1alpha2.NamespacedName{Name: "keys", Namespace: "paas-system"},
+	}}
+	hash := hashFromString(paasSecret1)
+
+	var nilSecrets *AuditSecrets
+	assert.Nil(t, nilSecrets.forPaas(ctx, k8sClient, conf, "my-paas").v)
+
+	var secrets AuditSecrets
+	secrets.forPaas(ctx, k8sClient, conf, "my-paas").v[hash] = true
+	assert.True(t, secrets.forPaas(ctx, k8sClient, conf, "my-paas").v[hash])
+	assert.False(t, secrets.forPaas(ctx, k8sClient, conf, "other-paas").v[hash], "secrets are kept per Paas")
+	secrets.Prune()
+	assert.True(t, secrets.forPaas(ctx, k8sClient, conf, "my-paas").v[hash])
+
+	keys.Data = map[string][]byte{"privateKey0": []byte("rotated")}
+	require.NoError(t, k8sClient.Update(ctx, keys))
+	assert.False(t, secrets.forPaas(ctx, k8sClient, conf, "my-paas").v[hash], "the decrypt keys changed")
+
+	secrets.Prune()
+	secrets.Prune()
+	assert.Empty(t, secrets.validated, "secrets which are no longer audited should be forgotten")
+
+	missing := conf
+	missing.Spec.DecryptKeysSecret.Name = "missing"
+	assert.Nil(t, secrets.forPaas(ctx, k8sClient, missing, "my-paas").v)
+}
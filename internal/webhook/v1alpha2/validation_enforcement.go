@@ -0,0 +1,289 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// validationPaasNsName is the name of the validation of the name of a PaasNS, which is only validated on creation
+	validationPaasNsName = "paasNs.name"
+	// validationPaasConfigCapabilityName is the name of the validation of the capability names of a PaasConfig
+	validationPaasConfigCapabilityName = "paasConfig.capabilityName"
+)
+
+// paasValidators are the built-in validations of a Paas, by the name with which their enforcement mode is configured
+var paasValidators = []struct {
+	name      string
+	validator paasSpecValidator
+}{
+	{name: "paas.name", validator: validatePaasName},
+	{name: "paas.requestor", validator: validatePaasRequestor},
+	{name: "paas.capabilities", validator: validateCaps},
+	{name: "paas.allowedQuotas", validator: validatePaasallowedQuotas},
+	{name: "paas.secrets", validator: validatePaasSecrets},
+	{name: "paas.customFields", validator: validateCustomFields},
+	{name: "paas.groupName", validator: validateGroupNames},
+	{name: "paas.groupDefinitions", validator: validateGroupDefinitionReferences},
+	{name: "paas.namespaceName", validator: validatePaasNamespaceNames},
+	{name: "paas.namespaceGroups", validator: validatePaasNamespaceGroups},
+	{name: "paas.revisionPin", validator: validatePaasRevisionPin},
+}
+
+// paasNsValidators are the built-in validations of a PaasNS, by the name with which their enforcement mode is
+// configured
+var paasNsValidators = []struct {
+	name      string
+	validator paasNsSpecValidator
+}{
+	{name: validationPaasNsName, validator: validatePaasNsName},
+	{name: "paasNs.groups", validator: validatePaasNsGroups},
+	{name: "paasNs.secrets", validator: validatePaasNsSecrets},
+}
+
+// validationNames returns the names of all built-in validations, for which an enforcement mode can be configured
+func validationNames() []string {
+	names := []string{validationPaasConfigCapabilityName}
+	for _, val := range paasValidators {
+		names = append(names, val.name)
+	}
+	for _, val := range paasNsValidators {
+		names = append(names, val.name)
+	}
+	return names
+}
+
+// validation is a validation of a Paas or PaasNS, with the name by which its enforcement mode is configured in the
+// PaasConfig
+type validation struct {
+	name     string
+	mode     v1alpha2.ValidationEnforcement
+	validate func() ([]*field.Error, error)
+}
+
+// blocks returns true when the errors of the validation reject the resource for an operation (create or update)
+func (v validation) blocks(operation admissionv1.Operation) bool {
+	switch v.mode {
+	case v1alpha2.EnforcementWarn:
+		return false
+	case v1alpha2.EnforcementBlockOnCreateOnly:
+		return operation == admissionv1.Create
+	default:
+		return true
+	}
+}
+
+// paasValidations returns all validations of a Paas. Secrets in validated are not decrypted again.
+func paasValidations(
+	ctx context.Context,
+	k8sClient client.Client,
+	conf v1alpha2.PaasConfig,
+	paas *v1alpha2.Paas,
+	validated validatedSecrets,
+) []validation {
+	var validations []validation
+	for _, val := range paasValidators {
+		validations = append(validations, validation{
+			name: val.name,
+			mode: conf.Spec.ValidationEnforcement.Mode(val.name),
+			validate: func() ([]*field.Error, error) {
+				return val.validator(ctx, k8sClient, conf, paas, validated)
+			},
+		})
+	}
+	return append(validations, ruleValidations(conf, "paas", paas, paas)...)
+}
+
+// paasNsValidations returns all validations of a PaasNS. Secrets in validated are not decrypted again.
+func paasNsValidations(
+	ctx context.Context,
+	k8sClient client.Client,
+	conf v1alpha2.PaasConfig,
+	paas v1alpha2.Paas,
+	paasns v1alpha2.PaasNS,
+	validated validatedSecrets,
+) []validation {
+	var validations []validation
+	for _, val := range paasNsValidators {
+		validations = append(validations, validation{
+			name: val.name,
+			mode: conf.Spec.ValidationEnforcement.Mode(val.name),
+			validate: func() ([]*field.Error, error) {
+				return val.validator(ctx, k8sClient, conf, paas, paasns, validated)
+			},
+		})
+	}
+	return append(validations, ruleValidations(conf, "paasNs", &paasns, &paas)...)
+}
+
+// runValidations runs validations for an operation (create or update), and returns the errors of the validations
+// which block the operation, and the errors of the other validations as warnings
+func runValidations(
+	validations []validation,
+	operation admissionv1.Operation,
+) (warnings admission.Warnings, errs field.ErrorList, err error) {
+	for _, val := range validations {
+		fieldErrs, validationErr := val.validate()
+		if validationErr != nil {
+			return nil, nil, validationErr
+		}
+		if val.blocks(operation) {
+			errs = append(errs, fieldErrs...)
+			continue
+		}
+		for _, fieldErr := range fieldErrs {
+			warnings = append(warnings, fmt.Sprintf("%s (validation %s is in %s mode)",
+				fieldErr.Error(), val.name, val.mode))
+		}
+	}
+	return warnings, errs, nil
+}
+
+// AuditSecrets remembers the secrets which could be decrypted in earlier audits, so that an audit only decrypts new
+// secrets, and the secrets of which the decrypt keys changed. The zero value is ready to use. An AuditSecrets should
+// only be used by one audit at a time.
+type AuditSecrets struct {
+	validated map[auditSecretsKey]validatedSecrets
+	used      map[auditSecretsKey]bool
+}
+
+// auditSecretsKey identifies the secrets of a Paas and its PaasNSs, which are decrypted with a version of the decrypt
+// keys
+type auditSecretsKey struct {
+	paasName    string
+	keys        v1alpha2.NamespacedName
+	keysVersion string
+}
+
+// forPaas returns the secrets of a Paas (and its PaasNSs) which could be decrypted with the current version of the
+// decrypt keys of a PaasConfig
+func (as *AuditSecrets) forPaas(
+	ctx context.Context,
+	k8sClient client.Client,
+	conf v1alpha2.PaasConfig,
+	paasName string,
+) validatedSecrets {
+	keys := &corev1.Secret{}
+	if as == nil || k8sClient.Get(ctx, types.NamespacedName{
+		Name:      conf.Spec.DecryptKeysSecret.Name,
+		Namespace: conf.Spec.DecryptKeysSecret.Namespace,
+	}, keys) != nil {
+		// The secrets validations report when the decrypt keys cannot be retrieved
+		return validatedSecrets{}
+	}
+	if as.validated == nil {
+		as.validated = map[auditSecretsKey]validatedSecrets{}
+		as.used = map[auditSecretsKey]bool{}
+	}
+	key := auditSecretsKey{paasName: paasName, keys: conf.Spec.DecryptKeysSecret, keysVersion: keys.ResourceVersion}
+	validated, exists := as.validated[key]
+	if !exists {
+		validated = validatedSecrets{v: map[validatedHash]bool{}}
+		as.validated[key] = validated
+	}
+	as.used[key] = true
+	return validated
+}
+
+// Prune forgets the secrets of Paas'es and decrypt keys which were not audited since the previous Prune. It should be
+// called after every audit.
+func (as *AuditSecrets) Prune() {
+	for key := range as.validated {
+		if !as.used[key] {
+			delete(as.validated, key)
+		}
+	}
+	as.used = map[auditSecretsKey]bool{}
+}
+
+// AuditPaas returns the violations of an existing Paas of the validations which do not block updates (the validations
+// in warn or block-on-create-only mode). conf is the audited (active) PaasConfig; Paas'es which use another config are
+// audited with that config (see configForPaas). Secrets which were decrypted in earlier audits are not decrypted again
+// (secrets can be nil).
+func AuditPaas(
+	ctx context.Context,
+	k8sClient client.Client,
+	conf v1alpha2.PaasConfig,
+	paas *v1alpha2.Paas,
+	secrets *AuditSecrets,
+) []v1alpha2.PaasConfigViolation {
+	paasConf := configForPaas(conf, paas)
+	violations := audit(paasValidations(ctx, k8sClient, paasConf, paas,
+		secrets.forPaas(ctx, k8sClient, paasConf, paas.Name)))
+	for i := range violations {
+		violations[i].Kind = "Paas"
+		violations[i].Name = paas.Name
+	}
+	return violations
+}
+
+// AuditPaasNS returns the violations of an existing PaasNS of the validations which do not block updates (the
+// validations in warn or block-on-create-only mode). PaasNSs are audited with the config of their Paas, like AuditPaas
+// does. PaasNSs which do not belong to a Paas are skipped, as these cannot be updated regardless of the enforcement
+// mode of validations.
+func AuditPaasNS(
+	ctx context.Context,
+	k8sClient client.Client,
+	conf v1alpha2.PaasConfig,
+	paasns *v1alpha2.PaasNS,
+	secrets *AuditSecrets,
+) []v1alpha2.PaasConfigViolation {
+	ctx, logger := logging.GetLogComponent(ctx, logging.WebhookPaasNSComponentV2)
+	paas, err := paasNStoPaas(ctx, k8sClient, paasns)
+	if err != nil {
+		logger.Debug().Msgf("not auditing PaasNS %s/%s: %s", paasns.Namespace, paasns.Name, err.Error())
+		return nil
+	}
+	paasConf := configForPaas(conf, paas)
+	violations := audit(paasNsValidations(ctx, k8sClient, paasConf, *paas, *paasns,
+		secrets.forPaas(ctx, k8sClient, paasConf, paas.Name)))
+	for i := range violations {
+		violations[i].Kind = "PaasNS"
+		violations[i].Name = paasns.Name
+		violations[i].Namespace = paasns.Namespace
+	}
+	return violations
+}
+
+// audit runs the validations which do not block updates, and returns their errors as violations. A validation which
+// cannot be run (e.g. because a resource cannot be retrieved) is reported as a violation as well, so that it does not
+// prevent auditing the other validations and resources.
+func audit(validations []validation) (violations []v1alpha2.PaasConfigViolation) {
+	for _, val := range validations {
+		if val.blocks(admissionv1.Update) {
+			continue
+		}
+		fieldErrs, validationErr := val.validate()
+		if validationErr != nil {
+			violations = append(violations, v1alpha2.PaasConfigViolation{
+				Validation:  val.name,
+				Enforcement: val.mode,
+				Message:     fmt.Sprintf("validation could not be run: %s", validationErr),
+			})
+			continue
+		}
+		for _, fieldErr := range fieldErrs {
+			violations = append(violations, v1alpha2.PaasConfigViolation{
+				Validation:  val.name,
+				Enforcement: val.mode,
+				Message:     fieldErr.Error(),
+			})
+		}
+	}
+	return violations
+}
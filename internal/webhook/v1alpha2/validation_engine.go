@@ -0,0 +1,178 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/config"
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// FieldPaths maps the path of a field in the validated (v1alpha2) version of a resource to the path of the same field
+// in the version of the resource which was requested, so that errors point at the fields as they were sent. Validating
+// a v1alpha2 resource requires no mapping, which is expressed by a nil FieldPaths.
+type FieldPaths func(path string) string
+
+// mapErrors maps the fields of field errors to the requested version
+func (fp FieldPaths) mapErrors(errs []*field.Error) []*field.Error {
+	if fp == nil {
+		return errs
+	}
+	for _, err := range errs {
+		err.Field = fp(err.Field)
+	}
+	return errs
+}
+
+// mapValidations returns validations of which the fields of the errors are mapped to the requested version
+func (fp FieldPaths) mapValidations(validations []validation) []validation {
+	if fp == nil {
+		return validations
+	}
+	for i, val := range validations {
+		validations[i].validate = func() ([]*field.Error, error) {
+			errs, err := val.validate()
+			return fp.mapErrors(errs), err
+		}
+	}
+	return validations
+}
+
+// configForPaas returns the PaasConfig a Paas (or a PaasNS of the Paas) is validated with. Like the defaulters and the
+// controllers do, Paas'es which are pinned to a PaasConfigRevision, and canary Paas'es of a candidate PaasConfig, use
+// that revision or candidate, and all other Paas'es use conf (the active PaasConfig). Paas'es with invalid pins, or
+// with pins to revisions which do not exist, also use conf, as validatePaasRevisionPin reports these pins.
+func configForPaas(conf v1alpha2.PaasConfig, paas *v1alpha2.Paas) v1alpha2.PaasConfig {
+	override, err := config.GetRevisionOrCandidateConfig(paas)
+	if err != nil || override == nil {
+		return conf
+	}
+	return *override
+}
+
+// ValidatePaas validates a Paas for an operation (create or update) against the PaasConfig it uses (see configForPaas).
+// All versions of the Paas are validated by this function: other versions are converted to v1alpha2 first, and paths
+// maps the fields in the errors back to the requested version. oldPaas is the Paas before an update (nil on create);
+// its secrets were validated before and are not decrypted again.
+func ValidatePaas(
+	ctx context.Context,
+	k8sClient client.Client,
+	paas, oldPaas *v1alpha2.Paas,
+	operation admissionv1.Operation,
+	paths FieldPaths,
+) (admission.Warnings, error) {
+	ctx, logger := logging.GetLogComponent(ctx, logging.WebhookPaasComponentV2)
+	active, err := config.GetConfigWithError()
+	if err != nil {
+		return nil, err
+	}
+	// Check for uninitialized config
+	if active.Spec.DecryptKeysSecret.Name == "" {
+		return nil, apierrors.NewInternalError(errors.New("uninitialized PaasConfig"))
+	}
+	conf := configForPaas(*active, paas)
+
+	var validated validatedSecrets
+	if oldPaas != nil {
+		// We don't have to validate what is in the previous Paas definition (already validated before)
+		validated.appendFromPaas(*oldPaas)
+	}
+	warnings, allErrs, err := runValidations(
+		paths.mapValidations(paasValidations(ctx, k8sClient, conf, paas, validated)),
+		operation,
+	)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	groupWarnings, groupErrors := validateGroups(conf, paas.Spec.Groups)
+	warnings = append(warnings, groupWarnings...)
+	allErrs = append(allErrs, paths.mapErrors(groupErrors)...)
+	warnings = append(warnings, validateQuota(paas)...)
+	warnings = append(warnings, validateExtraPerm(conf, paas)...)
+	warnings = append(warnings, validateCapVersions(conf, paas)...)
+
+	if len(allErrs) == 0 && len(warnings) == 0 {
+		logger.Info().Msg("validate ok")
+		return nil, nil
+	} else if len(allErrs) == 0 {
+		return warnings, nil
+	}
+
+	return warnings, apierrors.NewInvalid(
+		schema.GroupKind{Group: v1alpha2.GroupVersion.Group, Kind: "Paas"},
+		paas.Name,
+		allErrs,
+	)
+}
+
+// ValidatePaasNS validates a PaasNS for an operation (create or update) against the PaasConfig of its Paas (see
+// configForPaas). All versions of the PaasNS are validated by this function: other versions are converted to v1alpha2
+// first, and paths maps the fields in the errors back to the requested version. oldPaasns is the PaasNS before an
+// update (nil on create); its secrets, and the secrets of the Paas, were validated before and are not decrypted again.
+func ValidatePaasNS(
+	ctx context.Context,
+	k8sClient client.Client,
+	paasns, oldPaasns *v1alpha2.PaasNS,
+	operation admissionv1.Operation,
+	paths FieldPaths,
+) (admission.Warnings, error) {
+	ctx, _ = logging.GetLogComponent(ctx, logging.WebhookPaasNSComponentV2)
+	paas, err := paasNStoPaas(ctx, k8sClient, paasns)
+	if err != nil {
+		// code to Err when the referenced Paas does not exist
+		return nil, field.ErrorList(paths.mapErrors([]*field.Error{{
+			Type:     field.ErrorTypeTypeInvalid,
+			Field:    field.NewPath("spec").Child("paas").String(),
+			BadValue: paasns.Spec.Paas,
+			Detail:   err.Error(),
+		}})).ToAggregate()
+	}
+
+	active, err := config.GetConfigWithError()
+	if err != nil {
+		return nil, field.ErrorList{field.InternalError(
+			field.NewPath("paasconfig"),
+			fmt.Errorf("unable to retrieve paasconfig: %s", err),
+		)}.ToAggregate()
+	}
+	conf := configForPaas(*active, paas)
+
+	var validated validatedSecrets
+	// We don't have to validate what is in the Paas (already validated by Paas webhook)
+	validated.appendFromPaas(*paas)
+	if oldPaasns != nil {
+		// We don't have to validate what is in the previous PaasNs definition (already validated before)
+		validated.appendFromPaasNS(*oldPaasns)
+	}
+	var validations []validation
+	for _, val := range paasNsValidations(ctx, k8sClient, conf, *paas, *paasns, validated) {
+		// The name of a PaasNS cannot be changed, and is only validated on creation
+		if operation == admissionv1.Create || val.name != validationPaasNsName {
+			validations = append(validations, val)
+		}
+	}
+	warnings, errs, err := runValidations(paths.mapValidations(validations), operation)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(errs) > 0 {
+		return warnings, errs.ToAggregate()
+	}
+	return warnings, nil
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/config"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConfigForPaas(t *testing.T) {
+	active := v1alpha2.PaasConfig{Spec: v1alpha2.PaasConfigSpec{QuotaLabel: "active"}}
+	revision := v1alpha2.NewPaasConfigRevision(v1alpha2.PaasConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "paas-config"},
+		Spec:       v1alpha2.PaasConfigSpec{QuotaLabel: "pinned"},
+	})
+	config.SetRevision(*revision)
+	defer config.DeleteRevision(revision.Name)
+	pinned := func(name string) *v1alpha2.Paas {
+		return &v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			v1alpha2.PinRevisionAnnotation:      name,
+			v1alpha2.PinRevisionUntilAnnotation: time.Now().Add(time.Hour).Format(time.RFC3339),
+		}}}
+	}
+
+	assert.Equal(t, "pinned", configForPaas(active, pinned(revision.Name)).Spec.QuotaLabel)
+	assert.Equal(t, "active", configForPaas(active, &v1alpha2.Paas{}).Spec.QuotaLabel)
+	assert.Equal(t, "active", configForPaas(active, pinned("no-such-revision")).Spec.QuotaLabel,
+		"pins to revisions which do not exist are reported by validatePaasRevisionPin")
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha2
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/internal/validate"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ruleValidations returns a validation for every CEL validation rule in the PaasConfig for a resource (paas or paasNs),
+// which evaluates the rule against obj
+func ruleValidations(
+	conf v1alpha2.PaasConfig,
+	resource string,
+	obj any,
+	paas *v1alpha2.Paas,
+) (validations []validation) {
+	// The variables are only converted once, and only when there are rules for the resource
+	vars := sync.OnceValues(func() (map[string]any, error) {
+		return validate.RuleVars(obj, paas, &conf)
+	})
+	for i, rule := range conf.Spec.ValidationRules {
+		if rule.Resource != resource {
+			continue
+		}
+		validations = append(validations, validation{
+			name: fmt.Sprintf("validationRules[%d]", i),
+			mode: rule.Mode(),
+			validate: func() ([]*field.Error, error) {
+				ruleVars, err := vars()
+				if err != nil {
+					return nil, err
+				}
+				return validateRule(rule, ruleVars)
+			},
+		})
+	}
+	return validations
+}
+
+// validateRule returns an error when the object does not meet a CEL validation rule
+func validateRule(rule v1alpha2.ConfigValidationRule, vars map[string]any) ([]*field.Error, error) {
+	// Rules are compile checked by the PaasConfig webhook, and the compiled rules are cached
+	compiled, err := validate.CompileRule(rule.Rule)
+	if err != nil {
+		return nil, fmt.Errorf("validation rule `%s`: %w", rule.Rule, err)
+	}
+	valid, err := compiled.Evaluate(vars)
+	if err != nil {
+		return []*field.Error{field.Forbidden(
+			field.NewPath("spec"),
+			fmt.Sprintf("failed to evaluate validation rule `%s`: %s", rule.Rule, err.Error()),
+		)}, nil
+	} else if valid {
+		return nil, nil
+	}
+	message := rule.Message
+	if message == "" {
+		message = fmt.Sprintf("does not meet validation rule `%s`", rule.Rule)
+	}
+	return []*field.Error{field.Forbidden(field.NewPath("spec"), message)}, nil
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha3
+
+import (
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var annotationsPath = field.NewPath("metadata", "annotations")
+
+// paasFieldPaths maps the field paths of the v1alpha2 version of a Paas to the field paths of the v1alpha3 Paas, in
+// which the requestor and managedByPaas fields are replaced by annotations
+func paasFieldPaths(path string) string {
+	spec := field.NewPath("spec")
+	switch path {
+	case spec.Child("requestor").String(), spec.Key("requestor").String():
+		return annotationsPath.Key(v1alpha3.RequestorAnnotation).String()
+	case spec.Child("managedByPaas").String(), spec.Key("managedByPaas").String():
+		return annotationsPath.Key(v1alpha3.ManagedByPaasAnnotation).String()
+	}
+	return path
+}
+
+// paasNsFieldPaths maps the field paths of the v1alpha2 version of a PaasNS to the field paths of the v1alpha3
+// PaasNS, which has no paas field as the Paas is derived from the namespace of the PaasNS
+func paasNsFieldPaths(path string) string {
+	if path == field.NewPath("spec", "paas").String() {
+		return field.NewPath("metadata", "namespace").String()
+	}
+	return path
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaasFieldPaths(t *testing.T) {
+	for v2Path, expected := range map[string]string{
+		"spec.requestor":            "metadata.annotations[cpet.belastingdienst.nl/requestor]",
+		"spec[requestor]":           "metadata.annotations[cpet.belastingdienst.nl/requestor]",
+		"spec.managedByPaas":        "metadata.annotations[cpet.belastingdienst.nl/managed-by-paas]",
+		"spec.requestorandmore":     "spec.requestorandmore",
+		"spec.secrets[my-secret]":   "spec.secrets[my-secret]",
+		"spec.capabilities[argocd]": "spec.capabilities[argocd]",
+	} {
+		assert.Equal(t, expected, paasFieldPaths(v2Path), "path %s", v2Path)
+	}
+}
+
+func TestPaasNsFieldPaths(t *testing.T) {
+	assert.Equal(t, "metadata.namespace", paasNsFieldPaths("spec.paas"))
+	assert.Equal(t, "spec.secrets[my-secret]", paasNsFieldPaths("spec.secrets[my-secret]"))
+}
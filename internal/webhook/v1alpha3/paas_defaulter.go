@@ -0,0 +1,48 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha3
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
+	webhookv1alpha2 "github.com/belastingdienst/opr-paas/v3/internal/webhook/v1alpha2"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// revive:disable:line-length-limit
+
+// +kubebuilder:webhook:path=/mutate-cpet-belastingdienst-nl-v1alpha3-paas,mutating=true,failurePolicy=fail,sideEffects=None,groups=cpet.belastingdienst.nl,resources=paas,verbs=create;update,versions=v1alpha3,name=mpaas-v1alpha3.kb.io,admissionReviewVersions=v1
+
+// revive:enable:line-length-limit
+
+// PaasCustomDefaulter struct is responsible for writing the defaults from the PaasConfig into the Paas resource when
+// it is created or updated, when the defaults feature flag in the PaasConfig is set to materialize.
+// +kubebuilder:object:generate=false
+type PaasCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &PaasCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type Paas. The Paas is converted
+// to v1alpha2 to apply the defaults, and converted back.
+func (*PaasCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	paas, ok := obj.(*v1alpha3.Paas)
+	if !ok {
+		return fmt.Errorf("expected a Paas object but got %T", obj)
+	}
+	v2Paas := &v1alpha2.Paas{}
+	if err := v2Paas.ConvertFrom(paas); err != nil {
+		return err
+	}
+	if err := (&webhookv1alpha2.PaasCustomDefaulter{}).Default(ctx, v2Paas); err != nil {
+		return err
+	}
+	return v2Paas.ConvertTo(paas)
+}
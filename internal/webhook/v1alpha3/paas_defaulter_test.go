@@ -0,0 +1,45 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha3
+
+import (
+	"context"
+	"testing"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
+	"github.com/belastingdienst/opr-paas/v3/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPaasCustomDefaulter(t *testing.T) {
+	const teamLabel = "example.com/team"
+	config.SetConfig(v1alpha2.PaasConfig{
+		Spec: v1alpha2.PaasConfigSpec{
+			FeatureFlags: v1alpha2.ConfigFeatureFlags{Defaults: v1alpha2.DefaultsMaterialize},
+			Templating: v1alpha2.ConfigTemplatingItems{
+				PaasLabels: v1alpha2.ConfigTemplatingItem{
+					teamLabel: `{{ index .Paas.Annotations "cpet.belastingdienst.nl/requestor" }}`,
+				},
+			},
+		},
+	})
+	paas := &v1alpha3.Paas{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        paasName,
+			Annotations: map[string]string{v1alpha3.RequestorAnnotation: "my-team"},
+		},
+	}
+
+	require.NoError(t, (&PaasCustomDefaulter{}).Default(context.TODO(), paas))
+	assert.Equal(t, map[string]string{teamLabel: "my-team"}, paas.Labels)
+	assert.Equal(t, map[string]string{v1alpha3.RequestorAnnotation: "my-team"}, paas.Annotations)
+
+	assert.Error(t, (&PaasCustomDefaulter{}).Default(context.TODO(), &v1alpha2.Paas{}))
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+// Package v1alpha3 contains all webhook code for the v1alpha3 admission webhooks. The v1alpha3 resources are converted
+// to v1alpha2, which is validated and defaulted by the v1alpha2 webhook code.
+package v1alpha3
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	webhookv1alpha2 "github.com/belastingdienst/opr-paas/v3/internal/webhook/v1alpha2"
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupPaasWebhookWithManager registers the webhook for Paas in the manager.
+func SetupPaasWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha3.Paas{}).
+		WithValidator(&PaasCustomValidator{client: mgr.GetClient()}).
+		WithDefaulter(&PaasCustomDefaulter{}).
+		Complete()
+}
+
+// revive:disable:line-length-limit
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+// +kubebuilder:webhook:path=/validate-cpet-belastingdienst-nl-v1alpha3-paas,mutating=false,failurePolicy=fail,sideEffects=None,groups=cpet.belastingdienst.nl,resources=paas,verbs=create;update,versions=v1alpha3,name=vpaas-v1alpha3.kb.io,admissionReviewVersions=v1
+
+// revive:enable:line-length-limit
+
+// PaasCustomValidator struct is responsible for validating the Paas resource when it is created, updated, or deleted.
+// +kubebuilder:object:generate=false
+type PaasCustomValidator struct {
+	client client.Client
+}
+
+var _ webhook.CustomValidator = &PaasCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Paas.
+func (v *PaasCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	paas, ok := obj.(*v1alpha3.Paas)
+	if !ok {
+		return nil, fmt.Errorf("expected a Paas object but got %T", obj)
+	}
+	ctx, logger := logging.SetWebhookLogger(ctx, paas)
+	logger.Info().Msg("starting validation webhook for creation")
+
+	return v.validate(ctx, paas, nil, admissionv1.Create)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Paas.
+func (v *PaasCustomValidator) ValidateUpdate(
+	ctx context.Context,
+	oldObj, newObj runtime.Object,
+) (admission.Warnings, error) {
+	paas, ok := newObj.(*v1alpha3.Paas)
+	if !ok {
+		return nil, fmt.Errorf("expected a Paas object for the newObj but got %T", newObj)
+	}
+	ctx, logger := logging.SetWebhookLogger(ctx, paas)
+	if paas.GetDeletionTimestamp() != nil {
+		logger.Info().Msg("paas is being deleted")
+		return nil, nil
+	}
+	logger.Info().Msg("starting validation webhook for update")
+	// Without the old Paas all secrets are validated
+	oldPaas, _ := oldObj.(*v1alpha3.Paas)
+
+	return v.validate(ctx, paas, oldPaas, admissionv1.Update)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Paas.
+func (*PaasCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate converts a Paas (and the Paas before an update) to v1alpha2 and validates it
+func (v *PaasCustomValidator) validate(
+	ctx context.Context,
+	paas, oldPaas *v1alpha3.Paas,
+	operation admissionv1.Operation,
+) (admission.Warnings, error) {
+	v2Paas := &v1alpha2.Paas{}
+	if err := v2Paas.ConvertFrom(paas); err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	var v2OldPaas *v1alpha2.Paas
+	if oldPaas != nil {
+		v2OldPaas = &v1alpha2.Paas{}
+		if err := v2OldPaas.ConvertFrom(oldPaas); err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+	}
+
+	return webhookv1alpha2.ValidatePaas(ctx, v.client, v2Paas, v2OldPaas, operation, paasFieldPaths)
+}
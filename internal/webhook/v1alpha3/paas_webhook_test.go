@@ -0,0 +1,67 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha3
+
+import (
+	"context"
+	"testing"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
+	"github.com/belastingdienst/opr-paas/v3/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newFakeClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha2.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestPaasCustomValidator(t *testing.T) {
+	ctx := context.TODO()
+	config.SetConfig(v1alpha2.PaasConfig{
+		Spec: v1alpha2.PaasConfigSpec{
+			DecryptKeysSecret: v1alpha2.NamespacedName{Name: "keys", Namespace: "paas-system"},
+			Validations:       v1alpha2.PaasConfigValidations{"paas": {"requestor": "^[a-z]+$"}},
+		},
+	})
+	validator := &PaasCustomValidator{client: newFakeClient(t)}
+	paas := &v1alpha3.Paas{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        paasName,
+			Annotations: map[string]string{v1alpha3.RequestorAnnotation: "team"},
+		},
+	}
+
+	_, err := validator.ValidateCreate(ctx, paas)
+	require.NoError(t, err)
+
+	invalid := paas.DeepCopy()
+	invalid.Annotations[v1alpha3.RequestorAnnotation] = "my-team"
+	_, err = validator.ValidateCreate(ctx, invalid)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "metadata.annotations[cpet.belastingdienst.nl/requestor]")
+
+	_, err = validator.ValidateUpdate(ctx, paas, invalid)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "metadata.annotations[cpet.belastingdienst.nl/requestor]")
+
+	_, err = validator.ValidateCreate(ctx, &v1alpha2.Paas{})
+	assert.Error(t, err)
+	_, err = validator.ValidateUpdate(ctx, paas, &v1alpha2.Paas{})
+	assert.Error(t, err)
+	_, err = validator.ValidateDelete(ctx, paas)
+	assert.NoError(t, err)
+}
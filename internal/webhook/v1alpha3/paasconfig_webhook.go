@@ -0,0 +1,93 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha3
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
+	webhookv1alpha2 "github.com/belastingdienst/opr-paas/v3/internal/webhook/v1alpha2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupPaasConfigWebhookWithManager registers the webhook for PaasConfig in the manager.
+func SetupPaasConfigWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&v1alpha3.PaasConfig{}).
+		WithValidator(&PaasConfigCustomValidator{client: mgr.GetClient()}).
+		Complete()
+}
+
+//revive:line-length-limit:disable
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+// revive:disable-line
+// +kubebuilder:webhook:path=/validate-cpet-belastingdienst-nl-v1alpha3-paasconfig,mutating=false,failurePolicy=fail,sideEffects=None,groups=cpet.belastingdienst.nl,resources=paasconfig,verbs=create;update,versions=v1alpha3,name=vpaasconfig-v1alpha3.kb.io,admissionReviewVersions=v1
+
+//revive:line-length-limit:enable
+
+// PaasConfigCustomValidator struct is responsible for validating the PaasConfig resource
+// when it is created, updated, or deleted.
+// +kubebuilder:object:generate=false
+type PaasConfigCustomValidator struct {
+	client client.Client
+}
+
+var _ webhook.CustomValidator = &PaasConfigCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type PaasConfig.
+func (v *PaasConfigCustomValidator) ValidateCreate(
+	ctx context.Context,
+	obj runtime.Object,
+) (admission.Warnings, error) {
+	paasconfig, err := toV1alpha2PaasConfig(obj)
+	if err != nil {
+		return nil, err
+	}
+	return webhookv1alpha2.NewPaasConfigCustomValidator(v.client).ValidateCreate(ctx, paasconfig)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type PaasConfig.
+func (v *PaasConfigCustomValidator) ValidateUpdate(
+	ctx context.Context,
+	oldObj, newObj runtime.Object,
+) (admission.Warnings, error) {
+	paasconfig, err := toV1alpha2PaasConfig(newObj)
+	if err != nil {
+		return nil, err
+	}
+	oldPaasconfig, err := toV1alpha2PaasConfig(oldObj)
+	if err != nil {
+		return nil, err
+	}
+	return webhookv1alpha2.NewPaasConfigCustomValidator(v.client).ValidateUpdate(ctx, oldPaasconfig, paasconfig)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type PaasConfig.
+func (*PaasConfigCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// toV1alpha2PaasConfig converts a v1alpha3 PaasConfig to v1alpha2, which is validated by the v1alpha2 webhook code
+func toV1alpha2PaasConfig(obj runtime.Object) (*v1alpha2.PaasConfig, error) {
+	paasconfig, ok := obj.(*v1alpha3.PaasConfig)
+	if !ok {
+		return nil, fmt.Errorf("expected a PaasConfig object but got %T", obj)
+	}
+	v2Paasconfig := &v1alpha2.PaasConfig{}
+	if err := v2Paasconfig.ConvertFrom(paasconfig); err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	return v2Paasconfig, nil
+}
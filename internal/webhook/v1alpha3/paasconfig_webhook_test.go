@@ -0,0 +1,54 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha3
+
+import (
+	"context"
+	"testing"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPaasConfigCustomValidator(t *testing.T) {
+	ctx := context.TODO()
+	keys := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "keys", Namespace: "paas-system"}}
+	validator := &PaasConfigCustomValidator{client: newFakeClient(t, keys)}
+	paasConfig := &v1alpha3.PaasConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "paas-config"},
+		Spec: v1alpha3.PaasConfigSpec{
+			DecryptKeysSecret: v1alpha3.NamespacedName{Name: "keys", Namespace: "paas-system"},
+		},
+	}
+
+	_, err := validator.ValidateCreate(ctx, paasConfig)
+	require.NoError(t, err)
+	_, err = validator.ValidateUpdate(ctx, paasConfig, paasConfig)
+	require.NoError(t, err)
+
+	candidate := paasConfig.DeepCopy()
+	candidate.Spec.Candidate = &v1alpha3.ConfigCandidate{}
+	_, err = validator.ValidateUpdate(ctx, paasConfig, candidate)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spec.candidate")
+
+	invalid := paasConfig.DeepCopy()
+	invalid.Annotations = map[string]string{v1alpha3.V1alpha2FieldsAnnotation: "invalid"}
+	_, err = validator.ValidateCreate(ctx, invalid)
+	assert.Error(t, err)
+	_, err = validator.ValidateUpdate(ctx, invalid, paasConfig)
+	assert.Error(t, err)
+
+	_, err = validator.ValidateCreate(ctx, &v1alpha2.PaasConfig{})
+	assert.Error(t, err)
+	_, err = validator.ValidateDelete(ctx, paasConfig)
+	assert.NoError(t, err)
+}
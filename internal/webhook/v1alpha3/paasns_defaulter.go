@@ -0,0 +1,51 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha3
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
+	webhookv1alpha2 "github.com/belastingdienst/opr-paas/v3/internal/webhook/v1alpha2"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+//revive:disable:line-length-limit
+
+// +kubebuilder:webhook:path=/mutate-cpet-belastingdienst-nl-v1alpha3-paasns,mutating=true,failurePolicy=fail,sideEffects=None,groups=cpet.belastingdienst.nl,resources=paasns,verbs=create;update,versions=v1alpha3,name=mpaasns-v1alpha3.kb.io,admissionReviewVersions=v1
+
+//revive:enable:line-length-limit
+
+// PaasNSCustomDefaulter struct is responsible for writing the defaults from the PaasConfig into the PaasNS resource
+// when it is created or updated, when the defaults feature flag in the PaasConfig is set to materialize.
+// +kubebuilder:object:generate=false
+type PaasNSCustomDefaulter struct {
+	client client.Client
+}
+
+var _ webhook.CustomDefaulter = &PaasNSCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type PaasNS. The PaasNS is
+// converted to v1alpha2 to apply the defaults, and converted back.
+func (d *PaasNSCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	paasns, ok := obj.(*v1alpha3.PaasNS)
+	if !ok {
+		return fmt.Errorf("expected a PaasNS object but got %T", obj)
+	}
+	v2Paasns := &v1alpha2.PaasNS{}
+	if err := v2Paasns.ConvertFrom(paasns); err != nil {
+		return err
+	}
+	if err := webhookv1alpha2.NewPaasNSCustomDefaulter(d.client).Default(ctx, v2Paasns); err != nil {
+		return err
+	}
+	return v2Paasns.ConvertTo(paasns)
+}
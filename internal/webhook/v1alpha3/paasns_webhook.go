@@ -0,0 +1,105 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha3
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
+	"github.com/belastingdienst/opr-paas/v3/internal/logging"
+	webhookv1alpha2 "github.com/belastingdienst/opr-paas/v3/internal/webhook/v1alpha2"
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupPaasNsWebhookWithManager registers the webhook for PaasNS in the manager.
+func SetupPaasNsWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&v1alpha3.PaasNS{}).
+		WithValidator(&PaasNSCustomValidator{client: mgr.GetClient()}).
+		WithDefaulter(&PaasNSCustomDefaulter{client: mgr.GetClient()}).
+		Complete()
+}
+
+//revive:disable:line-length-limit
+
+// +kubebuilder:webhook:path=/validate-cpet-belastingdienst-nl-v1alpha3-paasns,mutating=false,failurePolicy=fail,sideEffects=None,groups=cpet.belastingdienst.nl,resources=paasns,verbs=create;update,versions=v1alpha3,name=vpaasns-v1alpha3.kb.io,admissionReviewVersions=v1
+
+//revive:enable:line-length-limit
+
+// PaasNSCustomValidator struct is responsible for validating the PaasNS resource when it is created, updated, or
+// deleted.
+// +kubebuilder:object:generate=false
+type PaasNSCustomValidator struct {
+	client client.Client
+}
+
+var _ webhook.CustomValidator = &PaasNSCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type PaasNS.
+func (v *PaasNSCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	paasns, ok := obj.(*v1alpha3.PaasNS)
+	if !ok {
+		return nil, fmt.Errorf("expected a PaasNS object but got %T", obj)
+	}
+	ctx, logger := logging.SetWebhookLogger(ctx, paasns)
+	logger.Info().Msg("starting validation webhook for creation")
+
+	return v.validate(ctx, paasns, nil, admissionv1.Create)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type PaasNS.
+func (v *PaasNSCustomValidator) ValidateUpdate(
+	ctx context.Context,
+	oldObj, newObj runtime.Object,
+) (admission.Warnings, error) {
+	paasns, ok := newObj.(*v1alpha3.PaasNS)
+	if !ok {
+		return nil, fmt.Errorf("expected a PaasNS object for the newObj but got %T", newObj)
+	}
+	ctx, logger := logging.SetWebhookLogger(ctx, paasns)
+	if paasns.GetDeletionTimestamp() != nil {
+		logger.Info().Msg("paasns is being deleted")
+		return nil, nil
+	}
+	logger.Info().Msg("starting validation webhook for update")
+	oldPaasns, _ := oldObj.(*v1alpha3.PaasNS)
+
+	return v.validate(ctx, paasns, oldPaasns, admissionv1.Update)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type PaasNS.
+func (*PaasNSCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate converts a PaasNS (and the PaasNS before an update) to v1alpha2 and validates it
+func (v *PaasNSCustomValidator) validate(
+	ctx context.Context,
+	paasns, oldPaasns *v1alpha3.PaasNS,
+	operation admissionv1.Operation,
+) (admission.Warnings, error) {
+	v2Paasns := &v1alpha2.PaasNS{}
+	if err := v2Paasns.ConvertFrom(paasns); err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	var v2OldPaasns *v1alpha2.PaasNS
+	if oldPaasns != nil {
+		v2OldPaasns = &v1alpha2.PaasNS{}
+		if err := v2OldPaasns.ConvertFrom(oldPaasns); err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+	}
+
+	return webhookv1alpha2.ValidatePaasNS(ctx, v.client, v2Paasns, v2OldPaasns, operation, paasNsFieldPaths)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha3
+
+import (
+	"context"
+	"testing"
+
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
+	"github.com/belastingdienst/opr-paas/v3/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const paasName = "my-paas"
+
+// newPaasClient returns a fake client with a Paas, and a namespace which belongs to the Paas
+func newPaasClient(t *testing.T) client.Client {
+	paas := &v1alpha2.Paas{
+		ObjectMeta: metav1.ObjectMeta{Name: paasName, UID: paasName + "-uid"},
+		Spec:       v1alpha2.PaasSpec{Requestor: "team"},
+	}
+	controller := true
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: paasName,
+			OwnerReferences: []metav1.OwnerReference{{
+				Name:       paasName,
+				APIVersion: v1alpha2.GroupVersion.String(),
+				Kind:       "Paas",
+				UID:        paas.UID,
+				Controller: &controller,
+			}},
+		},
+	}
+	return newFakeClient(t, paas, ns)
+}
+
+func TestPaasNSCustomValidator(t *testing.T) {
+	ctx := context.TODO()
+	config.SetConfig(v1alpha2.PaasConfig{
+		Spec: v1alpha2.PaasConfigSpec{
+			DecryptKeysSecret: v1alpha2.NamespacedName{Name: "keys", Namespace: "paas-system"},
+		},
+	})
+	validator := &PaasNSCustomValidator{client: newPaasClient(t)}
+	paasns := &v1alpha3.PaasNS{ObjectMeta: metav1.ObjectMeta{Name: "ns", Namespace: paasName}}
+
+	_, err := validator.ValidateCreate(ctx, paasns)
+	require.NoError(t, err)
+	_, err = validator.ValidateUpdate(ctx, paasns, paasns)
+	require.NoError(t, err)
+
+	orphan := &v1alpha3.PaasNS{ObjectMeta: metav1.ObjectMeta{Name: "ns", Namespace: "other-ns"}}
+	_, err = validator.ValidateCreate(ctx, orphan)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "metadata.namespace")
+
+	_, err = validator.ValidateCreate(ctx, &v1alpha2.PaasNS{})
+	assert.Error(t, err)
+	_, err = validator.ValidateUpdate(ctx, paasns, &v1alpha2.PaasNS{})
+	assert.Error(t, err)
+	_, err = validator.ValidateDelete(ctx, paasns)
+	assert.NoError(t, err)
+}
+
+func TestPaasNSCustomDefaulter(t *testing.T) {
+	const teamLabel = "example.com/team"
+	config.SetConfig(v1alpha2.PaasConfig{
+		Spec: v1alpha2.PaasConfigSpec{
+			FeatureFlags: v1alpha2.ConfigFeatureFlags{Defaults: v1alpha2.DefaultsMaterialize},
+			Templating: v1alpha2.ConfigTemplatingItems{
+				PaasLabels: v1alpha2.ConfigTemplatingItem{
+					teamLabel: `{{ index .Paas.Annotations "cpet.belastingdienst.nl/requestor" }}`,
+				},
+			},
+		},
+	})
+	defaulter := &PaasNSCustomDefaulter{client: newPaasClient(t)}
+	paasns := &v1alpha3.PaasNS{ObjectMeta: metav1.ObjectMeta{Name: "ns", Namespace: paasName}}
+
+	require.NoError(t, defaulter.Default(context.TODO(), paasns))
+	assert.Equal(t, map[string]string{teamLabel: "team"}, paasns.Labels)
+	assert.Nil(t, paasns.Annotations)
+
+	assert.Error(t, defaulter.Default(context.TODO(), &v1alpha2.PaasNS{}))
+}
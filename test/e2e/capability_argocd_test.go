@@ -37,11 +37,11 @@ func TestCapabilityArgoCD(t *testing.T) {
 		Quota:     quota.Quota{},
 		Capabilities: api.PaasCapabilities{
 			argoCapName: api.PaasCapability{
-				CustomFields: map[string]string{
+				CustomFields: api.PaasCustomFieldsFromStrings(map[string]string{
 					"git_path":     paasArgoGitPath,
 					"git_revision": paasArgoGitRevision,
 					"git_url":      paasArgoGitURL,
-				},
+				}),
 				Secrets:          map[string]string{paasArgoGitURL: paasArgoSecret},
 				ExtraPermissions: true,
 			},
@@ -118,11 +118,11 @@ func assertArgoCapUpdated(ctx context.Context, t *testing.T, cfg *envconf.Config
 		argoCapName: api.PaasCapability{
 			Secrets:          map[string]string{paasArgoGitURL: paasArgoSecret},
 			ExtraPermissions: true,
-			CustomFields: map[string]string{
+			CustomFields: api.PaasCustomFieldsFromStrings(map[string]string{
 				"git_path":     paasArgoGitPath,
 				"git_revision": updatedRevision,
 				"git_url":      paasArgoGitURL,
-			},
+			}),
 		},
 	}
 	paas.Spec.Groups = api.PaasGroups{
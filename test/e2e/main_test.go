@@ -19,6 +19,7 @@ import (
 
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha1"
 	"github.com/belastingdienst/opr-paas/v3/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v3/api/v1alpha3"
 
 	quotav1 "github.com/openshift/api/quota/v1"
 	userv1 "github.com/openshift/api/user/v1"
@@ -271,6 +272,7 @@ func registerSchemes(cfg *envconf.Config) error {
 	for _, install := range []func(*runtime.Scheme) error{
 		v1alpha1.AddToScheme,
 		v1alpha2.AddToScheme,
+		v1alpha3.AddToScheme,
 		quotav1.Install,
 		userv1.Install,
 		argoprojv1alpha1.AddToScheme,
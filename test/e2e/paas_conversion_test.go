@@ -113,11 +113,11 @@ func assertV2Created(ctx context.Context, t *testing.T, cfg *envconf.Config) con
 			Quota:     make(quota.Quota),
 			Capabilities: v1alpha2.PaasCapabilities{
 				"argocd": {
-					CustomFields: map[string]string{
+					CustomFields: v1alpha2.PaasCustomFieldsFromStrings(map[string]string{
 						"git_url":      "ssh://git@scm/repo.git",
 						"git_revision": "main",
 						"git_path":     ".",
-					},
+					}),
 				},
 				"sso": {},
 				"tekton": {